@@ -5,62 +5,291 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"runtime"
+	"runtime/pprof"
+	"sort"
 	"time"
+	"github.com/ruslano69/funcfinder/cmd/internal/cli"
 	"github.com/ruslano69/funcfinder/internal"
 )
 
 func main() {
-	iterations := flag.Int("n", 1000, "Number of iterations")
+	iterations := flag.Int("n", 1000, "Number of iterations (default 10 with -dir)")
+	dir := flag.String("dir", "", "Benchmark DirProcessor over a directory tree instead of a single file")
+	workers := flag.Int("workers", 0, "Number of parallel workers for -dir (default: number of CPU cores)")
+	cpuProfile := flag.String("cpuprofile", "", "Write a CPU profile (pprof) to this file")
+	memProfile := flag.String("memprofile", "", "Write a heap profile (pprof) to this file")
+	jsonOut := flag.Bool("json", false, "Print the results as JSON (for tracking performance in CI)")
+	flag.Func("error-format", cli.ErrorFormatUsage, internal.SetErrorFormat)
 	flag.Parse()
 
-	if flag.NArg() < 2 {
-		fmt.Fprintf(os.Stderr, "Usage: benchmark -n <iterations> <file> <lang>\n")
+	if *dir == "" && flag.NArg() < 2 {
+		fmt.Fprintf(os.Stderr, "Usage: benchmark [-json] -n <iterations> <file> <lang>\n")
+		fmt.Fprintf(os.Stderr, "       benchmark -dir <tree> [-json] [-n <iterations>] [-cpuprofile cpu.out] [-memprofile mem.out]\n")
 		os.Exit(1)
 	}
 
-	filename := flag.Arg(0)
-	lang := flag.Arg(1)
-
 	// Load config once
 	config, err := internal.LoadConfig()
 	if err != nil {
-		internal.FatalError("loading config: %v", err)
+		cli.FatalError("loading config: %v", err)
+	}
+
+	if *cpuProfile != "" {
+		f, err := os.Create(*cpuProfile)
+		if err != nil {
+			cli.FatalError("creating CPU profile: %v", err)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			cli.FatalError("starting CPU profile: %v", err)
+		}
+		defer pprof.StopCPUProfile()
 	}
 
+	if *dir != "" {
+		n := *iterations
+		if !flagSet("n") {
+			n = 10
+		}
+		result, err := runDirBenchmark(config, *dir, n, *workers)
+		if err != nil {
+			cli.FatalError("%v", err)
+		}
+		if *jsonOut {
+			printJSON(result)
+		} else {
+			printDirBenchmark(result)
+		}
+	} else {
+		result, err := runFileBenchmark(config, flag.Arg(0), flag.Arg(1), *iterations)
+		if err != nil {
+			cli.FatalError("%v", err)
+		}
+		if *jsonOut {
+			printJSON(result)
+		} else {
+			printFileBenchmark(result)
+		}
+	}
+
+	if *memProfile != "" {
+		writeMemProfile(*memProfile)
+	}
+}
+
+// flagSet reports whether the named flag was given on the command line
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// writeMemProfile writes a heap profile after a GC so it shows live memory
+func writeMemProfile(path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		cli.FatalError("creating memory profile: %v", err)
+	}
+	defer f.Close()
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		cli.FatalError("writing memory profile: %v", err)
+	}
+}
+
+// fileBenchmark holds the results of a single-file benchmark
+type fileBenchmark struct {
+	File       string        `json:"file"`
+	Iterations int           `json:"iterations"`
+	Elapsed    time.Duration `json:"total_ns"`
+	AvgMs      float64       `json:"avg_ms"`
+	Throughput float64       `json:"throughput"` // files/sec
+}
+
+func runFileBenchmark(config internal.Config, filename, lang string, iterations int) (*fileBenchmark, error) {
+	if iterations < 1 {
+		return nil, fmt.Errorf("-n must be at least 1")
+	}
 	langConfig, err := config.GetLanguageConfig(lang)
 	if err != nil {
-		internal.FatalError("language config: %v", err)
+		return nil, fmt.Errorf("language config: %w", err)
 	}
 
 	// Warm up
 	finder := internal.CreateFinder(langConfig, "", "map", false, false)
 	_, err = finder.FindFunctions(filename)
 	if err != nil {
-		internal.FatalError("warm up: %v", err)
+		return nil, fmt.Errorf("warm up: %w", err)
 	}
 
 	// Benchmark
 	start := time.Now()
-	for i := 0; i < *iterations; i++ {
+	for i := 0; i < iterations; i++ {
 		finder := internal.CreateFinder(langConfig, "", "map", false, false)
 		_, err := finder.FindFunctions(filename)
 		if err != nil {
-			internal.FatalError("iteration %d: %v", i, err)
+			return nil, fmt.Errorf("iteration %d: %w", i, err)
 		}
 	}
 	elapsed := time.Since(start)
 
-	avgMs := float64(elapsed.Microseconds()) / float64(*iterations) / 1000.0
-	throughput := float64(*iterations) / elapsed.Seconds()
+	return &fileBenchmark{
+		File:       filename,
+		Iterations: iterations,
+		Elapsed:    elapsed,
+		AvgMs:      float64(elapsed.Microseconds()) / float64(iterations) / 1000.0,
+		Throughput: float64(iterations) / elapsed.Seconds(),
+	}, nil
+}
 
+func printFileBenchmark(b *fileBenchmark) {
 	fmt.Printf("Benchmark Results\n")
 	fmt.Printf("=================\n")
-	fmt.Printf("File:            %s\n", filename)
-	fmt.Printf("Iterations:      %d\n", *iterations)
-	fmt.Printf("Total time:      %v\n", elapsed)
+	fmt.Printf("File:            %s\n", b.File)
+	fmt.Printf("Iterations:      %d\n", b.Iterations)
+	fmt.Printf("Total time:      %v\n", b.Elapsed)
+	fmt.Printf("Avg per iter:    %.3f ms\n", b.AvgMs)
+	fmt.Printf("Throughput:      %.1f files/sec\n", b.Throughput)
+}
+
+// printJSON prints a benchmark result for -json
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		cli.FatalError("encoding JSON: %v", err)
+	}
+	fmt.Println(string(data))
+}
+
+// langBenchmark holds per-language averages of a directory benchmark
+type langBenchmark struct {
+	LangKey    string        `json:"lang"`
+	Files      int           `json:"files"`
+	Functions  int           `json:"functions"`
+	AvgFileMs  float64       `json:"avg_file_ms"` // average sequential parse time per file
+	TotalParse time.Duration `json:"total_parse_ns"`
+}
+
+// dirBenchmark holds the results of a directory benchmark
+type dirBenchmark struct {
+	Dir         string          `json:"dir"`
+	Iterations  int             `json:"iterations"`
+	Files       int             `json:"files"`
+	Functions   int             `json:"functions"`
+	Elapsed     time.Duration   `json:"total_ns"`
+	FilesPerSec float64         `json:"files_per_sec"`
+	FuncsPerSec float64         `json:"functions_per_sec"`
+	Languages   []langBenchmark `json:"languages"`
+}
+
+// runDirBenchmark runs DirProcessor over dir iterations times and measures
+// throughput. Per-language averages come from an extra sequential pass that
+// times each file with its own finder, so a slow language regex stands out
+// regardless of how work was spread across workers.
+func runDirBenchmark(config internal.Config, dir string, iterations, workers int) (*dirBenchmark, error) {
+	if iterations < 1 {
+		return nil, fmt.Errorf("-n must be at least 1")
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("accessing directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path is not a directory: %s", dir)
+	}
+
+	processor := internal.NewDirProcessor(config, workers, true, true, "functions")
+
+	// Warm up (and collect the file list for the per-language pass)
+	results, err := processor.ProcessDirectory(dir)
+	if err != nil {
+		return nil, fmt.Errorf("warm up: %w", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < iterations; i++ {
+		if _, err := processor.ProcessDirectory(dir); err != nil {
+			return nil, fmt.Errorf("iteration %d: %w", i, err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	bench := &dirBenchmark{Dir: dir, Iterations: iterations, Elapsed: elapsed}
+	byLang := make(map[string]*langBenchmark)
+	for _, r := range results {
+		if r.Error != nil {
+			continue
+		}
+		bench.Files++
+		bench.Functions += len(r.Functions)
+
+		lb := byLang[r.LangKey]
+		if lb == nil {
+			lb = &langBenchmark{LangKey: r.LangKey}
+			byLang[r.LangKey] = lb
+		}
+		lb.Files++
+		lb.Functions += len(r.Functions)
+
+		langConfig, err := config.GetLanguageConfig(r.LangKey)
+		if err != nil {
+			return nil, err
+		}
+		fileStart := time.Now()
+		for i := 0; i < iterations; i++ {
+			finder := internal.CreateFinder(langConfig, "", "map", false, false)
+			if _, err := finder.FindFunctions(r.Path); err != nil {
+				return nil, fmt.Errorf("%s: %w", r.Path, err)
+			}
+		}
+		lb.TotalParse += time.Since(fileStart)
+	}
+
+	if secs := elapsed.Seconds(); secs > 0 {
+		bench.FilesPerSec = float64(bench.Files*iterations) / secs
+		bench.FuncsPerSec = float64(bench.Functions*iterations) / secs
+	}
+	for _, lb := range byLang {
+		lb.AvgFileMs = float64(lb.TotalParse.Microseconds()) / float64(lb.Files*iterations) / 1000.0
+		bench.Languages = append(bench.Languages, *lb)
+	}
+	// Slowest languages first
+	sort.Slice(bench.Languages, func(i, j int) bool {
+		if bench.Languages[i].AvgFileMs != bench.Languages[j].AvgFileMs {
+			return bench.Languages[i].AvgFileMs > bench.Languages[j].AvgFileMs
+		}
+		return bench.Languages[i].LangKey < bench.Languages[j].LangKey
+	})
+
+	return bench, nil
+}
+
+func printDirBenchmark(b *dirBenchmark) {
+	avgMs := float64(b.Elapsed.Microseconds()) / float64(b.Iterations) / 1000.0
+
+	fmt.Printf("Directory Benchmark Results\n")
+	fmt.Printf("===========================\n")
+	fmt.Printf("Directory:       %s\n", b.Dir)
+	fmt.Printf("Iterations:      %d\n", b.Iterations)
+	fmt.Printf("Files:           %d\n", b.Files)
+	fmt.Printf("Functions:       %d\n", b.Functions)
+	fmt.Printf("Total time:      %v\n", b.Elapsed)
 	fmt.Printf("Avg per iter:    %.3f ms\n", avgMs)
-	fmt.Printf("Throughput:      %.1f files/sec\n", throughput)
+	fmt.Printf("Throughput:      %.1f files/sec, %.1f functions/sec\n", b.FilesPerSec, b.FuncsPerSec)
+
+	if len(b.Languages) > 0 {
+		fmt.Printf("\n%-8s %6s %10s %14s\n", "LANG", "FILES", "FUNCTIONS", "AVG/FILE (ms)")
+		for _, lb := range b.Languages {
+			fmt.Printf("%-8s %6d %10d %14.3f\n", lb.LangKey, lb.Files, lb.Functions, lb.AvgFileMs)
+		}
+	}
 }
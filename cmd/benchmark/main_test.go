@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ruslano69/funcfinder/internal"
+)
+
+func TestRunDirBenchmark(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":        "package main\n\nfunc main() {\n}\n\nfunc helper() int {\n\treturn 1\n}\n",
+		"pkg/util.go":    "package pkg\n\nfunc Util() {\n}\n",
+		"scripts/run.py": "def run():\n    pass\n\n\ndef stop():\n    pass\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := internal.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	b, err := runDirBenchmark(config, dir, 2, 2)
+	if err != nil {
+		t.Fatalf("runDirBenchmark() error = %v", err)
+	}
+	if b.Files != 3 || b.Functions != 5 {
+		t.Errorf("got %d files / %d functions, want 3 / 5", b.Files, b.Functions)
+	}
+	if b.FilesPerSec <= 0 || b.FuncsPerSec <= 0 {
+		t.Errorf("expected nonzero throughput, got %.1f files/sec, %.1f functions/sec", b.FilesPerSec, b.FuncsPerSec)
+	}
+
+	langs := make(map[string]langBenchmark)
+	for _, lb := range b.Languages {
+		langs[lb.LangKey] = lb
+	}
+	if langs["go"].Files != 2 || langs["py"].Files != 1 {
+		t.Errorf("unexpected per-language breakdown: %+v", b.Languages)
+	}
+	for _, lb := range b.Languages {
+		if lb.AvgFileMs <= 0 {
+			t.Errorf("%s: expected positive average parse time, got %f", lb.LangKey, lb.AvgFileMs)
+		}
+	}
+}
+
+func TestRunDirBenchmarkErrors(t *testing.T) {
+	config, err := internal.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if _, err := runDirBenchmark(config, filepath.Join(t.TempDir(), "missing"), 1, 1); err == nil {
+		t.Error("expected error for missing directory")
+	}
+	if _, err := runDirBenchmark(config, t.TempDir(), 0, 1); err == nil {
+		t.Error("expected error for -n 0")
+	}
+}
+
+func TestRunFileBenchmarkJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("package main\n\nfunc main() {\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := internal.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	b, err := runFileBenchmark(config, path, "go", 5)
+	if err != nil {
+		t.Fatalf("runFileBenchmark() error = %v", err)
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got struct {
+		File       string  `json:"file"`
+		Iterations int     `json:"iterations"`
+		TotalNs    int64   `json:"total_ns"`
+		AvgMs      float64 `json:"avg_ms"`
+		Throughput float64 `json:"throughput"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", data, err)
+	}
+	if got.File != path || got.Iterations != 5 {
+		t.Errorf("file/iterations = %q/%d, want %q/5", got.File, got.Iterations, path)
+	}
+	if got.TotalNs <= 0 || got.AvgMs <= 0 || got.Throughput <= 0 {
+		t.Errorf("expected positive timings, got %s", data)
+	}
+
+	if _, err := runFileBenchmark(config, path, "cobol", 1); err == nil {
+		t.Error("expected error for unknown language")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/ruslano69/funcfinder/cmd/internal/cli"
 	"github.com/ruslano69/funcfinder/internal"
 )
 
@@ -54,18 +55,21 @@ func main() {
 			i++
 		case arg == "--no-gitignore":
 			noGitignore = true
+		case arg == "--error-format" && i+1 < len(os.Args):
+			cli.SetErrorFormat(os.Args[i+1])
+			i++
 		case !strings.HasPrefix(arg, "-") && dir == "" && inp == "":
 			dir = arg
 		}
 	}
 
 	if showVersion {
-		internal.PrintVersion("callgraph")
+		cli.PrintVersion("callgraph")
 	}
 
 	config, err := internal.LoadConfig()
 	if err != nil {
-		internal.FatalError("loading config: %v", err)
+		cli.FatalError("loading config: %v", err)
 	}
 
 	if inp != "" {
@@ -73,23 +77,23 @@ func main() {
 	} else if dir != "" {
 		runDirMode(config, dir, lang, jsonOut, reverseMode, funcFilter, depth, noGitignore)
 	} else {
-		internal.FatalError("either --dir or --inp must be specified")
+		cli.FatalError("either --dir or --inp must be specified")
 	}
 }
 
 func runFileMode(config internal.Config, inp, lang string, jsonOut, reverseMode bool, funcFilter string, depth int) {
 	if lang == "" {
-		internal.FatalError("--inp mode requires -l <lang>")
+		cli.FatalError("--inp mode requires -l <lang>")
 	}
 	langConfig, err := config.GetLanguageConfig(lang)
 	if err != nil {
-		internal.FatalError("%v", err)
+		cli.FatalError("%v", err)
 	}
 
 	aliases := collectImports(inp, langConfig)
 	fcg, err := internal.BuildFileCallGraph(inp, langConfig, nil, aliases)
 	if err != nil {
-		internal.FatalError("building call graph: %v", err)
+		cli.FatalError("building call graph: %v", err)
 	}
 
 	cg := &internal.CallGraphResult{Files: []internal.FileCallGraph{*fcg}, TotalCalls: len(fcg.Calls)}
@@ -102,7 +106,7 @@ func runDirMode(config internal.Config, dir, lang string, jsonOut, reverseMode b
 	if lang != "" {
 		langConfig, err = config.GetLanguageConfig(lang)
 		if err != nil {
-			internal.FatalError("%v", err)
+			cli.FatalError("%v", err)
 		}
 	}
 
@@ -118,7 +122,7 @@ func runDirMode(config internal.Config, dir, lang string, jsonOut, reverseMode b
 		}
 	}
 	if err != nil {
-		internal.FatalError("collecting files: %v", err)
+		cli.FatalError("collecting files: %v", err)
 	}
 
 	// Run funcfinder on each file to get function boundaries. When -l is set,
@@ -133,7 +137,7 @@ func runDirMode(config internal.Config, dir, lang string, jsonOut, reverseMode b
 	processor := internal.NewDirProcessor(procConfig, 0, true, !noGitignore, "functions")
 	results, err := processor.ProcessDirectory(dir)
 	if err != nil {
-		internal.FatalError("processing directory: %v", err)
+		cli.FatalError("processing directory: %v", err)
 	}
 
 	// Collect per-file import aliases
@@ -348,5 +352,6 @@ func printHelp() {
 	fmt.Println("  --func <name>      Focus on one function (with optional --depth)")
 	fmt.Println("  --depth <n>        Limit traversal depth (default: unlimited)")
 	fmt.Println("  --no-gitignore     Ignore .gitignore rules")
+	fmt.Println("  --error-format <f> Fatal errors as text (default) or json on stderr")
 	fmt.Println("  --version          Print version")
 }
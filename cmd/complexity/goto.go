@@ -0,0 +1,127 @@
+// goto.go - goto and label detection for -flag-goto
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ruslano69/funcfinder/internal"
+)
+
+// GotoUse is one goto, label or labeled break/continue inside a function
+type GotoUse struct {
+	Line  int    `json:"line"`  // absolute line number
+	Kind  string `json:"kind"`  // "goto", "label", "break" or "continue"
+	Label string `json:"label"` // label name
+}
+
+var (
+	// gotoPattern matches "goto label"; \b keeps identifiers such as
+	// "nogoto" or "goto_next" from matching
+	gotoPattern = regexp.MustCompile(`\bgoto\s+([A-Za-z_]\w*)`)
+	// labeledJumpPattern matches "break outer" / "continue outer" as the
+	// whole statement
+	labeledJumpPattern = regexp.MustCompile(`\b(break|continue)\s+([A-Za-z_]\w*)\s*;?\s*$`)
+	// labelPattern matches a label definition: "label:" alone on its line or
+	// labeling a loop or switch ("outer: for ...")
+	labelPattern = regexp.MustCompile(`^\s*([A-Za-z_]\w*):\s*$|^\s*([A-Za-z_]\w*)\s*:\s*(?:for|while|do|switch|select)\b`)
+)
+
+// notLabels are words that end with ":" at the start of a line without
+// being labels (switch cases, C++ access specifiers)
+var notLabels = map[string]bool{
+	"default": true, "case": true, "public": true, "private": true, "protected": true,
+}
+
+// findGotoUses scans sanitized function lines (strings and comments already
+// blanked) for goto statements, label definitions and labeled break/continue.
+// startLine is the line number of cleaned[0]. Indentation-based languages
+// have no labels, and "else:"-style lines would look like them, so only goto
+// is matched there.
+func findGotoUses(cleaned []string, startLine int, indentBased bool) []GotoUse {
+	var uses []GotoUse
+	for i, line := range cleaned {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		for _, m := range gotoPattern.FindAllStringSubmatch(line, -1) {
+			uses = append(uses, GotoUse{Line: startLine + i, Kind: "goto", Label: m[1]})
+		}
+		if indentBased {
+			continue
+		}
+		if m := labelPattern.FindStringSubmatch(line); m != nil {
+			label := m[1] + m[2]
+			if !notLabels[label] {
+				uses = append(uses, GotoUse{Line: startLine + i, Kind: "label", Label: label})
+			}
+		}
+		if m := labeledJumpPattern.FindStringSubmatch(line); m != nil {
+			uses = append(uses, GotoUse{Line: startLine + i, Kind: m[1], Label: m[2]})
+		}
+	}
+	return uses
+}
+
+// filterGotoFunctions keeps the functions that use goto or labels and drops
+// files left without functions
+func filterGotoFunctions(files []FileComplexity) []FileComplexity {
+	var kept []FileComplexity
+	for _, fc := range files {
+		var functions []ComplexityMetrics
+		for _, fn := range fc.Functions {
+			if len(fn.GotoUses) > 0 {
+				functions = append(functions, fn)
+			}
+		}
+		if len(functions) > 0 {
+			fc.Functions = functions
+			fc.TotalFunctions = len(functions)
+			kept = append(kept, fc)
+		}
+	}
+	return kept
+}
+
+// printGotoReport prints the -flag-goto result, one function per line with
+// its goto uses, and exits with code 1 when any function is flagged
+func printGotoReport(files []FileComplexity, langConfig *internal.LanguageConfig, totalFunctions int, jsonOut bool) {
+	flagged := 0
+	for _, fc := range files {
+		flagged += len(fc.Functions)
+	}
+
+	if jsonOut {
+		result := ComplexityResult{
+			Language:          langConfig.Name,
+			TotalFiles:        len(files),
+			TotalFunctions:    flagged,
+			LevelDistribution: levelDistribution(files),
+			Files:             files,
+		}
+		if result.Files == nil {
+			result.Files = []FileComplexity{}
+		}
+		jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(jsonBytes))
+	} else {
+		for _, fc := range files {
+			for _, fn := range fc.Functions {
+				var uses []string
+				for _, u := range fn.GotoUses {
+					uses = append(uses, fmt.Sprintf("%s %s (line %d)", u.Kind, u.Label, u.Line))
+				}
+				fmt.Printf("%s:%d %s(): %s\n", fc.Filename, fn.StartLine, fn.Name, strings.Join(uses, ", "))
+			}
+		}
+		internal.InfoMessage(fmt.Sprintf("%d of %d functions use goto or labels", flagged, totalFunctions))
+	}
+
+	if flagged > 0 {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -18,6 +18,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/ruslano69/funcfinder/cmd/internal/cli"
 	"github.com/ruslano69/funcfinder/internal"
 )
 
@@ -43,6 +44,10 @@ type ComplexityMetrics struct {
 	Level            string   `json:"level"`
 	MaxNestingDepth  int      `json:"max_nesting_depth"`
 	NestingHistory   []int    `json:"nesting_history"`
+	DeepestLine      int      `json:"deepest_line"` // First absolute line reaching MaxNestingDepth
+	GotoUses         []GotoUse `json:"goto_uses,omitempty"` // goto, labels and labeled break/continue
+	ParamCount       int      `json:"param_count,omitempty"`
+	Risk             float64  `json:"risk_score,omitempty"` // Weighted depth/size/params score (-risk)
 }
 
 // FileComplexity contains complexity metrics for a single file
@@ -61,6 +66,7 @@ type ComplexityResult struct {
 	TotalFiles        int             `json:"total_files"`
 	TotalFunctions    int             `json:"total_functions"`
 	AverageComplexity float64         `json:"average_complexity"`
+	LevelDistribution map[string]int  `json:"level_distribution,omitempty"` // Functions per level name, every level present
 	Files             []FileComplexity `json:"files"`
 }
 
@@ -73,16 +79,46 @@ const (
 	DepthCritical  = 6  // four or more levels
 )
 
+// depthThresholds holds the highest nesting depth of each level; deeper
+// than VeryHigh is critical
+type depthThresholds struct {
+	Simple   int
+	Moderate int
+	High     int
+	VeryHigh int
+}
+
+// thresholds are the levels in use, overridden by -simple, -moderate, -high
+// and -veryhigh
+var thresholds = depthThresholds{
+	Simple:   DepthSimple,
+	Moderate: DepthModerate,
+	High:     DepthHigh,
+	VeryHigh: DepthVeryHigh,
+}
+
+// validate checks that the thresholds are non-negative and strictly increasing
+func (t depthThresholds) validate() error {
+	if t.Simple < 0 {
+		return fmt.Errorf("-simple must not be negative, got %d", t.Simple)
+	}
+	if t.Moderate <= t.Simple || t.High <= t.Moderate || t.VeryHigh <= t.High {
+		return fmt.Errorf("thresholds must increase: -simple %d < -moderate %d < -high %d < -veryhigh %d",
+			t.Simple, t.Moderate, t.High, t.VeryHigh)
+	}
+	return nil
+}
+
 // getComplexityLevel returns the complexity level based on nesting depth
 func getComplexityLevel(maxDepth int) ComplexityLevel {
 	switch {
-	case maxDepth <= DepthSimple:
+	case maxDepth <= thresholds.Simple:
 		return LevelSimple
-	case maxDepth <= DepthModerate:
+	case maxDepth <= thresholds.Moderate:
 		return LevelModerate
-	case maxDepth <= DepthHigh:
+	case maxDepth <= thresholds.High:
 		return LevelHigh
-	case maxDepth <= DepthVeryHigh:
+	case maxDepth <= thresholds.VeryHigh:
 		return LevelVeryHigh
 	default:
 		return LevelCritical
@@ -99,6 +135,43 @@ func calculateNestingComplexity(maxDepth int) int {
 	return 1 << (maxDepth - 1) // 2^(maxDepth-1)
 }
 
+// levelOrder lists the levels from simplest to most complex
+var levelOrder = []ComplexityLevel{LevelSimple, LevelModerate, LevelHigh, LevelVeryHigh, LevelCritical}
+
+// countLevels counts the functions of files per complexity level
+func countLevels(files []FileComplexity) map[ComplexityLevel]int {
+	counts := make(map[ComplexityLevel]int)
+	for _, f := range files {
+		for _, fn := range f.Functions {
+			counts[getComplexityLevel(fn.MaxNestingDepth)]++
+		}
+	}
+	return counts
+}
+
+// levelDistribution is countLevels keyed by level name for JSON output;
+// levels without functions are 0, so every key is always present
+func levelDistribution(files []FileComplexity) map[string]int {
+	counts := countLevels(files)
+	distribution := make(map[string]int, len(levelOrder))
+	for _, level := range levelOrder {
+		distribution[getLevelName(level)] = counts[level]
+	}
+	return distribution
+}
+
+// newComplexityResult builds the -j document of the analyzed files
+func newComplexityResult(langConfig *internal.LanguageConfig, files []FileComplexity, totalFunctions int, avgComplexity float64) ComplexityResult {
+	return ComplexityResult{
+		Language:          langConfig.Name,
+		TotalFiles:        len(files),
+		TotalFunctions:    totalFunctions,
+		AverageComplexity: avgComplexity,
+		LevelDistribution: levelDistribution(files),
+		Files:             files,
+	}
+}
+
 // getComplexityColor returns ANSI color code for complexity level
 func getComplexityColor(level ComplexityLevel) string {
 	switch level {
@@ -169,7 +242,8 @@ var flatPatterns = map[string]*regexp.Regexp{
 // "complexity -l js file.go").
 func reorderArgs(args []string) []string {
 	// Flags that consume the next argument as their value.
-	valueFlags := map[string]bool{"l": true, "t": true, "n": true}
+	valueFlags := map[string]bool{"l": true, "t": true, "n": true, "simple": true, "moderate": true, "high": true, "veryhigh": true,
+		"w-depth": true, "w-loc": true, "w-params": true, "color": true, "error-format": true}
 
 	var flags []string
 	var positional []string
@@ -200,17 +274,50 @@ func main() {
 
 	// Define flags
 	showVersion := flag.Bool("version", false, "Show version")
+	flag.Func("error-format", cli.ErrorFormatUsage, internal.SetErrorFormat)
 	langFlag := flag.String("l", "", "Force language")
 	jsonOut := flag.Bool("j", false, "Output JSON")
 	thresholdFlag := flag.Int("t", 0, "Show only functions with nesting depth >= N (0 = show all)")
 	topN := flag.Int("n", 0, "Show top N most complex functions")
 	showDetails := flag.Bool("v", false, "Show detailed nesting analysis")
-	noSimple := flag.Bool("nosimple", false, "Hide SIMPLE level functions (depth <= 2)")
+	noSimple := flag.Bool("nosimple", false, "Hide SIMPLE level functions (depth <= -simple)")
+	flag.IntVar(&thresholds.Simple, "simple", DepthSimple, "Highest nesting depth rated SIMPLE")
+	flag.IntVar(&thresholds.Moderate, "moderate", DepthModerate, "Highest nesting depth rated MODERATE")
+	flag.IntVar(&thresholds.High, "high", DepthHigh, "Highest nesting depth rated HIGH")
+	flag.IntVar(&thresholds.VeryHigh, "veryhigh", DepthVeryHigh, "Highest nesting depth rated VERY_HIGH (deeper is CRITICAL)")
+	rle := flag.Bool("rle", false, "Print the nesting history run-length encoded ([depth x count, ...]); implies -v")
+	sparkline := flag.Bool("sparkline", false, "Print the nesting depth profile as a unicode sparkline; implies -v")
+	countCases := flag.Bool("count-cases", false, "Treat each switch case body as one extra nesting level")
+	flagGoto := flag.Bool("flag-goto", false, "List only functions using goto, labels or labeled break/continue (exit code 1 if any)")
+	risk := flag.Bool("risk", false, "Rank functions by a weighted score of nesting depth, lines of code and parameters (top -n, default 10)")
+	var weights riskWeights
+	flag.Float64Var(&weights.Depth, "w-depth", DefaultRiskDepth, "Risk weight of one nesting level")
+	flag.Float64Var(&weights.LOC, "w-loc", DefaultRiskLOC, "Risk weight of one line of code")
+	flag.Float64Var(&weights.Params, "w-params", DefaultRiskParams, "Risk weight of one parameter")
+	colorFlag := flag.String("color", "auto", "Color the output: always (also into pipes), auto (terminals only) or never")
+	noColor := flag.Bool("no-color", false, "Same as -color never")
 	flag.Parse()
+	if *rle || *sparkline {
+		*showDetails = true
+	}
 
 	// Handle version flag
 	if *showVersion {
-		internal.PrintVersion("complexity")
+		cli.PrintVersion("complexity")
+	}
+
+	if err := thresholds.validate(); err != nil {
+		cli.FatalError("%v", err)
+	}
+	if err := weights.validate(); err != nil {
+		cli.FatalError("%v", err)
+	}
+	colorMode, err := cli.ParseColorMode(*colorFlag)
+	if err != nil {
+		cli.FatalError("%v", err)
+	}
+	if *noColor {
+		colorMode = cli.ColorNever
 	}
 
 	// Check for positional args
@@ -223,14 +330,14 @@ func main() {
 	// Load configuration
 	config, err := internal.LoadConfig()
 	if err != nil {
-		internal.FatalError("loading config: %v", err)
+		cli.FatalError("loading config: %v", err)
 	}
 
 	var langConfig *internal.LanguageConfig
 	if *langFlag != "" {
 		langConfig, err = config.GetLanguageConfig(*langFlag)
 		if err != nil {
-			internal.FatalError("%v", err)
+			cli.FatalError("%v", err)
 		}
 	} else {
 		for _, l := range config {
@@ -243,7 +350,7 @@ func main() {
 	}
 
 	if langConfig == nil {
-		internal.FatalErrorMsg("No supported files found")
+		cli.FatalErrorMsg("No supported files found")
 	}
 
 	// Walk directory and analyze files
@@ -253,10 +360,10 @@ func main() {
 
 	dirFiles, walkErr := internal.CollectSourceFiles(dir, langConfig, true)
 	if walkErr != nil {
-		internal.FatalError("walking directory: %v", walkErr)
+		cli.FatalError("walking directory: %v", walkErr)
 	}
 	for _, path := range dirFiles {
-		fileComplexity := analyzeFileComplexity(path, langConfig)
+		fileComplexity := analyzeFileComplexity(path, langConfig, *countCases)
 		if fileComplexity.TotalFunctions > 0 {
 			allFiles = append(allFiles, fileComplexity)
 			totalFunctions += fileComplexity.TotalFunctions
@@ -265,7 +372,16 @@ func main() {
 	}
 
 	if len(allFiles) == 0 {
-		internal.FatalErrorMsg("No functions found")
+		cli.FatalErrorMsg("No functions found")
+	}
+
+	if *flagGoto {
+		printGotoReport(filterGotoFunctions(allFiles), langConfig, totalFunctions, *jsonOut)
+	}
+
+	if *risk {
+		printRiskReport(rankByRisk(allFiles, weights), langConfig, weights, *topN, *jsonOut)
+		return
 	}
 
 	// Calculate overall average (using max complexity per file)
@@ -277,13 +393,7 @@ func main() {
 	})
 
 	if *jsonOut {
-		result := ComplexityResult{
-			Language:          langConfig.Name,
-			TotalFiles:        len(allFiles),
-			TotalFunctions:    totalFunctions,
-			AverageComplexity: avgComplexity,
-			Files:             allFiles,
-		}
+		result := newComplexityResult(langConfig, allFiles, totalFunctions, avgComplexity)
 		jsonBytes, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(jsonBytes))
 		return
@@ -338,8 +448,8 @@ func main() {
 		printCount = *topN
 	}
 
-	// Get terminal color support
-	colorsEnabled := checkColorSupport()
+	// Get terminal color support, unless -color overrides it
+	colorsEnabled := colorMode.Enabled(checkColorSupport)
 
 	printFunc := func(metrics ComplexityMetrics, rank int) {
 		level := getComplexityLevel(metrics.MaxNestingDepth)
@@ -357,7 +467,17 @@ func main() {
 		}
 
 		if *showDetails && len(metrics.NestingHistory) > 0 {
-			fmt.Printf("  Nesting history: %v\n", metrics.NestingHistory)
+			if *rle {
+				fmt.Printf("  Nesting history: %s\n", encodeHistoryRLE(metrics.NestingHistory))
+			} else if !*sparkline {
+				fmt.Printf("  Nesting history: %v\n", metrics.NestingHistory)
+			}
+			if *sparkline {
+				fmt.Printf("  Nesting profile: %s\n", historySparkline(metrics.NestingHistory, sparklineWidth))
+			}
+		}
+		if hint := deepestNestingHint(metrics); *showDetails && hint != "" {
+			fmt.Println(hint)
 		}
 		fmt.Printf("  Lines: %d, File: %s\n", metrics.LinesOfCode, metrics.File)
 		fmt.Println()
@@ -371,15 +491,7 @@ func main() {
 	fmt.Println(strings.Repeat("=", 60))
 	fmt.Println("Complexity distribution (by nesting depth):")
 
-	levelCounts := make(map[ComplexityLevel]int)
-	for _, f := range allFiles {
-		for _, fn := range f.Functions {
-			level := getComplexityLevel(fn.MaxNestingDepth)
-			levelCounts[level]++
-		}
-	}
-
-	levelOrder := []ComplexityLevel{LevelSimple, LevelModerate, LevelHigh, LevelVeryHigh, LevelCritical}
+	levelCounts := countLevels(allFiles)
 	for _, level := range levelOrder {
 		count := levelCounts[level]
 		if count > 0 {
@@ -395,26 +507,87 @@ func main() {
 	}
 }
 
+// deepestNestingHint returns the -v refactoring hint pointing at the first
+// line of maximum depth; only CRITICAL functions get one
+func deepestNestingHint(metrics ComplexityMetrics) string {
+	if metrics.MaxNestingDepth == 0 || getComplexityLevel(metrics.MaxNestingDepth) != LevelCritical {
+		return ""
+	}
+	return fmt.Sprintf("  Deepest nesting at line %d", metrics.DeepestLine)
+}
+
+// encodeHistoryRLE run-length encodes a nesting history as "[depth x count, ...]":
+// [0 0 0 1 1] becomes "[0x3, 1x2]"
+func encodeHistoryRLE(history []int) string {
+	var runs []string
+	for i := 0; i < len(history); {
+		j := i
+		for j < len(history) && history[j] == history[i] {
+			j++
+		}
+		runs = append(runs, fmt.Sprintf("%dx%d", history[i], j-i))
+		i = j
+	}
+	return "[" + strings.Join(runs, ", ") + "]"
+}
+
+// sparklineWidth is the most characters historySparkline prints
+const sparklineWidth = 60
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// historySparkline renders a nesting history as one block character per
+// entry, scaled so the deepest level is a full block. Histories longer than
+// width are split into width buckets that show their deepest entry.
+func historySparkline(history []int, width int) string {
+	if len(history) == 0 || width <= 0 {
+		return ""
+	}
+	buckets := history
+	if len(history) > width {
+		buckets = make([]int, width)
+		for i, depth := range history {
+			b := i * width / len(history)
+			buckets[b] = max(buckets[b], depth)
+		}
+	}
+
+	maxDepth := 0
+	for _, depth := range buckets {
+		maxDepth = max(maxDepth, depth)
+	}
+	top := len(sparkBlocks) - 1
+	var sb strings.Builder
+	for _, depth := range buckets {
+		level := 0
+		if maxDepth > 0 {
+			level = depth * top / maxDepth
+		}
+		sb.WriteRune(sparkBlocks[level])
+	}
+	return sb.String()
+}
+
 // getDepthThreshold returns the minimum depth for a level
 func getDepthThreshold(level ComplexityLevel) int {
 	switch level {
 	case LevelSimple:
 		return 1
 	case LevelModerate:
-		return DepthSimple + 1
+		return thresholds.Simple + 1
 	case LevelHigh:
-		return DepthModerate + 1
+		return thresholds.Moderate + 1
 	case LevelVeryHigh:
-		return DepthHigh + 1
+		return thresholds.High + 1
 	case LevelCritical:
-		return DepthVeryHigh + 1
+		return thresholds.VeryHigh + 1
 	default:
 		return 1
 	}
 }
 
 // analyzeFileComplexity calculates nesting complexity for all functions in a file
-func analyzeFileComplexity(filename string, langConfig *internal.LanguageConfig) FileComplexity {
+func analyzeFileComplexity(filename string, langConfig *internal.LanguageConfig, countCases bool) FileComplexity {
 	file, err := os.Open(filename)
 	if err != nil {
 		return FileComplexity{Filename: filename}
@@ -434,6 +607,10 @@ func analyzeFileComplexity(filename string, langConfig *internal.LanguageConfig)
 	if err != nil {
 		return FileComplexity{Filename: filename}
 	}
+	internal.AttachParamCounts(result.Functions, lines, langConfig)
+
+	// Strings and comments blanked, for goto/label detection
+	cleaned := internal.NewSanitizer(langConfig, false).CleanLines(lines)
 
 	// Get patterns for language
 	nestingRe := getNestingPattern(langConfig.LangKey)
@@ -451,10 +628,15 @@ func analyzeFileComplexity(filename string, langConfig *internal.LanguageConfig)
 		}
 
 		funcBody := lines[startIdx:endIdx]
-		linesOfCode := countLinesOfCode(funcBody)
+		linesOfCode := internal.CountLineMetrics(funcBody, langConfig).Code
 
-		// Calculate nesting depth
-		nestingResult := calculateNestingDepth(funcBody, nestingRe, flatRe)
+		// Calculate nesting depth (indentation for Python, braces otherwise)
+		var nestingResult nestingResult
+		if langConfig.IndentBased {
+			nestingResult = calculateIndentDepth(funcBody, langConfig, countCases)
+		} else {
+			nestingResult = calculateNestingDepth(funcBody, nestingRe, flatRe, countCases)
+		}
 		maxDepth := nestingResult.maxDepth
 		complexity := calculateNestingComplexity(maxDepth)
 
@@ -468,6 +650,11 @@ func analyzeFileComplexity(filename string, langConfig *internal.LanguageConfig)
 			Level:           getLevelName(getComplexityLevel(maxDepth)),
 			MaxNestingDepth: maxDepth,
 			NestingHistory:  nestingResult.history,
+			ParamCount:      fn.ParamCount,
+			GotoUses:        findGotoUses(cleaned[startIdx:endIdx], fn.Start, langConfig.IndentBased),
+		}
+		if maxDepth > 0 {
+			metrics.DeepestLine = fn.Start + nestingResult.deepestIdx
 		}
 
 		functions = append(functions, metrics)
@@ -497,13 +684,23 @@ func analyzeFileComplexity(filename string, langConfig *internal.LanguageConfig)
 
 // nestingResult holds the result of nesting analysis
 type nestingResult struct {
-	maxDepth int
-	history  []int
+	maxDepth   int
+	history    []int
+	deepestIdx int // index into lines of the first line reaching maxDepth
 }
 
+// casePattern matches switch case labels for -count-cases mode
+var casePattern = regexp.MustCompile(`^\s*(case\b|default\s*:)`)
+
 // calculateNestingDepth computes maximum nesting depth and history
 // Uses BRACE-BASED depth tracking for accurate measurement
-func calculateNestingDepth(lines []string, nestingRe, flatRe *regexp.Regexp) nestingResult {
+//
+// By default case labels are flat: a switch costs one level no matter how many
+// branches it has. With countCases each case body counts as one extra level
+// (sibling cases share it), so logic inside a large switch scores as deeper.
+// This better reflects switch-heavy state machines, but it also penalizes
+// simple dispatch tables that are easy to read.
+func calculateNestingDepth(lines []string, nestingRe, flatRe *regexp.Regexp, countCases bool) nestingResult {
 	result := nestingResult{
 		maxDepth: 0,
 		history:  []int{},
@@ -511,6 +708,7 @@ func calculateNestingDepth(lines []string, nestingRe, flatRe *regexp.Regexp) nes
 
 	currentDepth := 0
 	inBlock := false // Track if we're inside a block that started with "{"
+	var caseBases []int // depth of each switch body with an open case (countCases)
 
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
@@ -539,6 +737,16 @@ func calculateNestingDepth(lines []string, nestingRe, flatRe *regexp.Regexp) nes
 			hasFlatKeyword = true
 		}
 
+		if countCases && casePattern.MatchString(codeLine) {
+			// First case of a switch opens a level; sibling cases reuse it
+			if len(caseBases) == 0 || caseBases[len(caseBases)-1] != currentDepth-1 {
+				caseBases = append(caseBases, currentDepth)
+				currentDepth++
+			}
+			hasNestingKeyword = false
+			hasFlatKeyword = true
+		}
+
 		// Handle nesting constructs
 		if hasNestingKeyword && !hasFlatKeyword {
 			// This line starts a new block
@@ -558,6 +766,11 @@ func calculateNestingDepth(lines []string, nestingRe, flatRe *regexp.Regexp) nes
 		// Handle closing braces
 		if closeBraces > 0 {
 			currentDepth -= closeBraces
+			// Closing the switch body also closes its case level
+			for len(caseBases) > 0 && currentDepth <= caseBases[len(caseBases)-1] {
+				caseBases = caseBases[:len(caseBases)-1]
+				currentDepth--
+			}
 			if currentDepth < 0 {
 				currentDepth = 0
 			}
@@ -574,6 +787,7 @@ func calculateNestingDepth(lines []string, nestingRe, flatRe *regexp.Regexp) nes
 
 		if currentDepth > result.maxDepth {
 			result.maxDepth = currentDepth
+			result.deepestIdx = len(result.history) - 1
 		}
 	}
 
@@ -585,6 +799,100 @@ func calculateNestingDepth(lines []string, nestingRe, flatRe *regexp.Regexp) nes
 	return result
 }
 
+// pyCasePattern matches Python match-statement case clauses
+var pyCasePattern = regexp.MustCompile(`^\s*case\b`)
+
+// indentBlock is a block header enclosing the current line
+type indentBlock struct {
+	indent int
+	flat   bool // case clause: no extra level unless countCases
+}
+
+// calculateIndentDepth computes nesting depth for IndentBased languages
+// (Python), where blocks have no braces. A line ending with ":" opens a
+// block; every following line indented deeper belongs to it. Depth is the
+// number of enclosing blocks including the def itself, so it lines up with
+// calculateNestingDepth: the function body is depth 1 and a header line
+// already counts its own block.
+//
+// Strings and comments are blanked first, so docstrings and "#" lines keep
+// the current depth, and lines continuing an open bracket belong to the
+// statement they continue. case clauses are flat unless countCases, like
+// switch cases in brace languages.
+func calculateIndentDepth(lines []string, langConfig *internal.LanguageConfig, countCases bool) nestingResult {
+	result := nestingResult{
+		maxDepth: 0,
+		history:  []int{},
+	}
+
+	cleaned := internal.NewSanitizer(langConfig, false).CleanLines(lines)
+	var stack []indentBlock
+	parens := 0       // brackets left open by the statement so far
+	stmtIndent := 0   // indent of the first line of the current statement
+	currentDepth := 0 // depth of the current line
+
+	for _, line := range cleaned {
+		code := strings.TrimSpace(line)
+		if code == "" {
+			result.history = append(result.history, currentDepth)
+			continue
+		}
+
+		if parens == 0 {
+			// A new statement: leave every block it is not indented into
+			stmtIndent = indentWidth(line)
+			for len(stack) > 0 && stack[len(stack)-1].indent >= stmtIndent {
+				stack = stack[:len(stack)-1]
+			}
+		}
+		parens += strings.Count(code, "(") + strings.Count(code, "[") + strings.Count(code, "{") -
+			strings.Count(code, ")") - strings.Count(code, "]") - strings.Count(code, "}")
+		if parens < 0 {
+			parens = 0
+		}
+		if parens == 0 && strings.HasSuffix(code, ":") {
+			flat := !countCases && pyCasePattern.MatchString(code)
+			stack = append(stack, indentBlock{indent: stmtIndent, flat: flat})
+		}
+
+		currentDepth = 0
+		for _, b := range stack {
+			if !b.flat {
+				currentDepth++
+			}
+		}
+		result.history = append(result.history, currentDepth)
+
+		if currentDepth > result.maxDepth {
+			result.maxDepth = currentDepth
+			result.deepestIdx = len(result.history) - 1
+		}
+	}
+
+	if result.maxDepth > 15 {
+		result.maxDepth = 15 // Same cap as calculateNestingDepth
+	}
+
+	return result
+}
+
+// indentWidth returns the width of a line's leading whitespace, with tabs
+// advancing to the next multiple of 8 as in the Python tokenizer
+func indentWidth(line string) int {
+	width := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 8 - width%8
+		default:
+			return width
+		}
+	}
+	return width
+}
+
 // getNestingPattern returns the nesting pattern for a language
 func getNestingPattern(langKey string) *regexp.Regexp {
 	if pattern, ok := nestingPatterns[langKey]; ok {
@@ -603,18 +911,6 @@ func getFlatPattern(langKey string) *regexp.Regexp {
 	return regexp.MustCompile(`\b(else|elif|case|default)\b`)
 }
 
-// countLinesOfCode counts non-empty, non-comment-only lines
-func countLinesOfCode(lines []string) int {
-	count := 0
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed != "" && !isCommentOnly(trimmed) {
-			count++
-		}
-	}
-	return count
-}
-
 // isCommentOnly checks if a line is only a comment
 func isCommentOnly(line string) bool {
 	trimmed := strings.TrimSpace(line)
@@ -0,0 +1,467 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ruslano69/funcfinder/cmd/internal/cli"
+	"github.com/ruslano69/funcfinder/internal"
+)
+
+func goLangConfig(t *testing.T) *internal.LanguageConfig {
+	config, err := internal.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	return config["go"]
+}
+
+func writeFixture(t *testing.T, name, code string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(code), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestAnalyzeFileComplexity_DeepestLine(t *testing.T) {
+	code := `package main
+
+func shallow() {
+	x := 1
+	_ = x
+}
+
+func deep(items []int) {
+	for _, a := range items {
+		if a > 0 {
+			for b := 0; b < a; b++ {
+				if b%2 == 0 {
+					println(b)
+				}
+			}
+		}
+	}
+}
+`
+	path := writeFixture(t, "deep.go", code)
+	fc := analyzeFileComplexity(path, goLangConfig(t), false)
+
+	var deep *ComplexityMetrics
+	for i := range fc.Functions {
+		if fc.Functions[i].Name == "deep" {
+			deep = &fc.Functions[i]
+		}
+	}
+	if deep == nil {
+		t.Fatalf("function deep not found: %+v", fc.Functions)
+	}
+	// Line 12 is "if b%2 == 0 {", the innermost block
+	if deep.DeepestLine != 12 {
+		t.Errorf("DeepestLine = %d, want 12 (max depth %d, history %v)",
+			deep.DeepestLine, deep.MaxNestingDepth, deep.NestingHistory)
+	}
+}
+
+func TestDeepestNestingHint_OnlyCritical(t *testing.T) {
+	critical := ComplexityMetrics{Name: "tangled", MaxNestingDepth: thresholds.VeryHigh + 1, DeepestLine: 42}
+	if got, want := deepestNestingHint(critical), "  Deepest nesting at line 42"; got != want {
+		t.Errorf("deepestNestingHint(critical) = %q, want %q", got, want)
+	}
+
+	high := ComplexityMetrics{Name: "deep", MaxNestingDepth: thresholds.VeryHigh, DeepestLine: 12}
+	if got := deepestNestingHint(high); got != "" {
+		t.Errorf("deepestNestingHint(%s) = %q, want no hint below CRITICAL", getLevelName(getComplexityLevel(high.MaxNestingDepth)), got)
+	}
+}
+
+func TestCalculateNestingDepth_CountCases(t *testing.T) {
+	lines := []string{
+		"func dispatch(op int) {",
+		"	switch op {",
+		"	case 1:",
+		"		if ready {",
+		"			run()",
+		"		}",
+		"	case 2:",
+		"		stop()",
+		"	default:",
+		"		idle()",
+		"	}",
+		"	done()",
+		"}",
+	}
+	nestingRe := getNestingPattern("go")
+	flatRe := getFlatPattern("go")
+
+	flat := calculateNestingDepth(lines, nestingRe, flatRe, false)
+	counted := calculateNestingDepth(lines, nestingRe, flatRe, true)
+
+	if flat.maxDepth != 3 {
+		t.Errorf("without -count-cases maxDepth = %d, want 3 (history %v)", flat.maxDepth, flat.history)
+	}
+	if counted.maxDepth != 4 {
+		t.Errorf("with -count-cases maxDepth = %d, want 4 (history %v)", counted.maxDepth, counted.history)
+	}
+	// Sibling cases share a level and the switch close restores the outer depth
+	if got := counted.history[6]; got != 3 {
+		t.Errorf("depth at second case = %d, want 3", got)
+	}
+	if got, want := counted.history[11], flat.history[11]; got != want {
+		t.Errorf("depth after switch = %d, want %d", got, want)
+	}
+}
+
+func TestThresholdsOverride(t *testing.T) {
+	saved := thresholds
+	defer func() { thresholds = saved }()
+
+	if got := getComplexityLevel(4); got != LevelHigh {
+		t.Fatalf("default level for depth 4 = %s, want HIGH", getLevelName(got))
+	}
+
+	// "depth 4 is already critical for us"
+	thresholds = depthThresholds{Simple: 0, Moderate: 1, High: 2, VeryHigh: 3}
+	if err := thresholds.validate(); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+	if got := getComplexityLevel(4); got != LevelCritical {
+		t.Errorf("level for depth 4 = %s, want CRITICAL", getLevelName(got))
+	}
+	if got := getComplexityLevel(2); got != LevelHigh {
+		t.Errorf("level for depth 2 = %s, want HIGH", getLevelName(got))
+	}
+	if got := getDepthThreshold(LevelCritical); got != 4 {
+		t.Errorf("getDepthThreshold(CRITICAL) = %d, want 4", got)
+	}
+}
+
+func TestThresholdsValidate(t *testing.T) {
+	invalid := []depthThresholds{
+		{Simple: -1, Moderate: 1, High: 2, VeryHigh: 3},
+		{Simple: 2, Moderate: 2, High: 4, VeryHigh: 5},
+		{Simple: 1, Moderate: 3, High: 2, VeryHigh: 5},
+		{Simple: 1, Moderate: 2, High: 3, VeryHigh: 3},
+	}
+	for _, th := range invalid {
+		if err := th.validate(); err == nil {
+			t.Errorf("validate(%+v) = nil, want error", th)
+		}
+	}
+}
+
+func TestEncodeHistoryRLE(t *testing.T) {
+	tests := []struct {
+		history []int
+		want    string
+	}{
+		{[]int{0, 0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2}, "[0x3, 1x10, 2x5]"},
+		{[]int{1, 2, 1}, "[1x1, 2x1, 1x1]"},
+		{[]int{3}, "[3x1]"},
+		{nil, "[]"},
+	}
+	for _, tt := range tests {
+		if got := encodeHistoryRLE(tt.history); got != tt.want {
+			t.Errorf("encodeHistoryRLE(%v) = %q, want %q", tt.history, got, tt.want)
+		}
+	}
+}
+
+func TestHistorySparkline(t *testing.T) {
+	if got, want := historySparkline([]int{0, 1, 2, 3, 2, 0}, sparklineWidth), "▁▃▅█▅▁"; got != want {
+		t.Errorf("historySparkline() = %q, want %q", got, want)
+	}
+	if got, want := historySparkline([]int{0, 0}, sparklineWidth), "▁▁"; got != want {
+		t.Errorf("historySparkline(flat) = %q, want %q", got, want)
+	}
+
+	// Long histories are bucketed, keeping each bucket's deepest entry
+	long := make([]int, 100)
+	long[99] = 4
+	got := []rune(historySparkline(long, 10))
+	if len(got) != 10 || got[9] != '█' || got[0] != '▁' {
+		t.Errorf("historySparkline(long, 10) = %q", string(got))
+	}
+}
+
+func TestAnalyzeFileComplexity_PythonIndentDepth(t *testing.T) {
+	code := `def shallow(x):
+    return x + 1
+
+
+def deep(items):
+    """Walk items.
+
+    Indented docstring text is not a block.
+    """
+    total = compute(
+        items,
+            0)
+    for a in items:
+        if a > 0:
+            for b in range(a):
+                # comment at depth 4
+                if b % 2 == 0:
+                    total += b
+                else:
+                    total -= b
+        elif a < 0:
+            total = -total
+    return total
+`
+	config, err := internal.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	path := writeFixture(t, "deep.py", code)
+	fc := analyzeFileComplexity(path, config["py"], false)
+
+	depths := make(map[string]ComplexityMetrics)
+	for _, fn := range fc.Functions {
+		depths[fn.Name] = fn
+	}
+	if got := depths["shallow"].MaxNestingDepth; got != 1 {
+		t.Errorf("shallow MaxNestingDepth = %d, want 1", got)
+	}
+	deep, ok := depths["deep"]
+	if !ok {
+		t.Fatalf("function deep not found: %+v", fc.Functions)
+	}
+	// def, for, if, for, if: same depth as the brace-counted Go fixture
+	if deep.MaxNestingDepth != 5 {
+		t.Errorf("deep MaxNestingDepth = %d, want 5 (history %v)", deep.MaxNestingDepth, deep.NestingHistory)
+	}
+	// Line 17 is "if b % 2 == 0:", the innermost block header
+	if deep.DeepestLine != 17 {
+		t.Errorf("DeepestLine = %d, want 17 (history %v)", deep.DeepestLine, deep.NestingHistory)
+	}
+	want := []int{1, 1, 1, 1, 1, 1, 1, 1, 2, 3, 4, 4, 5, 5, 5, 5, 3, 3, 1}
+	if fmt.Sprint(deep.NestingHistory) != fmt.Sprint(want) {
+		t.Errorf("NestingHistory = %v, want %v", deep.NestingHistory, want)
+	}
+}
+
+func TestCalculateIndentDepth_MatchCases(t *testing.T) {
+	lines := []string{
+		"def dispatch(op):",
+		"    match op:",
+		"        case 1:",
+		"            if ready:",
+		"                run()",
+		"        case _:",
+		"            idle()",
+	}
+	config, err := internal.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if got := calculateIndentDepth(lines, config["py"], false).maxDepth; got != 3 {
+		t.Errorf("without -count-cases maxDepth = %d, want 3", got)
+	}
+	if got := calculateIndentDepth(lines, config["py"], true).maxDepth; got != 4 {
+		t.Errorf("with -count-cases maxDepth = %d, want 4", got)
+	}
+}
+
+func TestAnalyzeFileComplexity_GotoUses(t *testing.T) {
+	code := `int cleanup(int fd) {
+    if (fd < 0)
+        goto fail;
+    close(fd);
+    return 0;
+fail:
+    return -1;
+}
+
+int plain(int x) {
+    int nogoto = x; // goto in a comment
+    char *s = "goto here";
+    switch (x) {
+    default:
+        return nogoto;
+    }
+}
+`
+	config, err := internal.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	fc := analyzeFileComplexity(writeFixture(t, "jump.c", code), config["c"], false)
+
+	uses := make(map[string][]GotoUse)
+	for _, fn := range fc.Functions {
+		uses[fn.Name] = fn.GotoUses
+	}
+	want := []GotoUse{{Line: 3, Kind: "goto", Label: "fail"}, {Line: 6, Kind: "label", Label: "fail"}}
+	if fmt.Sprint(uses["cleanup"]) != fmt.Sprint(want) {
+		t.Errorf("cleanup GotoUses = %v, want %v", uses["cleanup"], want)
+	}
+	if len(uses["plain"]) != 0 {
+		t.Errorf("plain GotoUses = %v, want none", uses["plain"])
+	}
+
+	flagged := filterGotoFunctions([]FileComplexity{fc})
+	if len(flagged) != 1 || len(flagged[0].Functions) != 1 || flagged[0].Functions[0].Name != "cleanup" {
+		t.Errorf("filterGotoFunctions() = %+v, want only cleanup", flagged)
+	}
+}
+
+func TestFindGotoUses_GoLabeledBreak(t *testing.T) {
+	lines := []string{
+		"func scan(rows [][]int) {",
+		"outer:",
+		"	for _, row := range rows {",
+		"		for _, v := range row {",
+		"			if v < 0 {",
+		"				break outer",
+		"			}",
+		"			continue",
+		"		}",
+		"	}",
+		"}",
+	}
+	got := findGotoUses(lines, 10, false)
+	want := []GotoUse{{Line: 11, Kind: "label", Label: "outer"}, {Line: 15, Kind: "break", Label: "outer"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("findGotoUses() = %v, want %v", got, want)
+	}
+}
+
+func TestRankByRisk_WeightsChangeRanking(t *testing.T) {
+	code := "package main\n\n" +
+		"func deep(items []int) {\n" +
+		"\tfor _, a := range items {\n" +
+		"\t\tif a > 0 {\n" +
+		"\t\t\tfor b := 0; b < a; b++ {\n" +
+		"\t\t\t\tprintln(b)\n" +
+		"\t\t\t}\n" +
+		"\t\t}\n" +
+		"\t}\n" +
+		"}\n\n" +
+		"func long() {\n" + strings.Repeat("\tprintln(1)\n", 60) + "}\n\n" +
+		"func wide(a, b, c, d, e, f, g, h int) {\n\tprintln(a)\n}\n"
+	path := writeFixture(t, "risk.go", code)
+	files := []FileComplexity{analyzeFileComplexity(path, goLangConfig(t), false)}
+
+	names := func(w riskWeights) []string {
+		var out []string
+		for _, fn := range rankByRisk(files, w) {
+			out = append(out, fn.Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		w    riskWeights
+		want []string
+	}{
+		{"defaults", riskWeights{DefaultRiskDepth, DefaultRiskLOC, DefaultRiskParams}, []string{"deep", "long", "wide"}},
+		{"size heavy", riskWeights{Depth: 1, LOC: 1}, []string{"long", "deep", "wide"}},
+		{"params heavy", riskWeights{Depth: 1, Params: 5}, []string{"wide", "deep", "long"}},
+	}
+	for _, tt := range tests {
+		if got := names(tt.w); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: ranking = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, fn := range rankByRisk(files, riskWeights{Depth: 2, LOC: 0.5, Params: 1}) {
+		if fn.Name == "wide" && (fn.ParamCount != 8 || fn.Risk != 2*1+0.5*3+1*8) {
+			t.Errorf("wide = %d params, risk %v; want 8 params, risk 11.5", fn.ParamCount, fn.Risk)
+		}
+	}
+}
+
+func TestRiskWeightsValidate(t *testing.T) {
+	if err := (riskWeights{Depth: 1}).validate(); err != nil {
+		t.Errorf("validate() error = %v, want nil", err)
+	}
+	if err := (riskWeights{Depth: 1, LOC: -0.5}).validate(); err == nil {
+		t.Error("validate() accepted a negative weight")
+	}
+}
+
+func TestColorMode_OverridesDetection(t *testing.T) {
+	tests := []struct {
+		value    string
+		terminal bool
+		want     bool
+	}{
+		{"auto", true, true},
+		{"auto", false, false},
+		{"", false, false},
+		{"always", false, true},
+		{"always", true, true},
+		{"never", true, false},
+		{"never", false, false},
+	}
+	for _, tt := range tests {
+		mode, err := cli.ParseColorMode(tt.value)
+		if err != nil {
+			t.Fatalf("ParseColorMode(%q) error = %v", tt.value, err)
+		}
+		detected := func() bool { return tt.terminal }
+		if colorsEnabled := mode.Enabled(detected); colorsEnabled != tt.want {
+			t.Errorf("-color %q on terminal=%v: colorsEnabled = %v, want %v", tt.value, tt.terminal, colorsEnabled, tt.want)
+		}
+	}
+
+	if _, err := cli.ParseColorMode("sometimes"); err == nil {
+		t.Error("ParseColorMode accepted an unknown mode")
+	}
+}
+
+func TestComplexityResult_LevelDistributionSumsToTotal(t *testing.T) {
+	code := `package main
+
+func flat() {
+	println(1)
+}
+
+func nested(items []int) {
+	for _, a := range items {
+		if a > 0 {
+			for b := 0; b < a; b++ {
+				if b%2 == 0 {
+					println(b)
+				}
+			}
+		}
+	}
+}
+
+func alsoFlat() {}
+`
+	langConfig := goLangConfig(t)
+	fc := analyzeFileComplexity(writeFixture(t, "levels.go", code), langConfig, false)
+	data, err := json.Marshal(newComplexityResult(langConfig, []FileComplexity{fc}, fc.TotalFunctions, 0))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var decoded struct {
+		TotalFunctions    int            `json:"total_functions"`
+		LevelDistribution map[string]int `json:"level_distribution"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(decoded.LevelDistribution) != len(levelOrder) {
+		t.Errorf("level_distribution = %v, want all %d levels", decoded.LevelDistribution, len(levelOrder))
+	}
+	sum := 0
+	for _, n := range decoded.LevelDistribution {
+		sum += n
+	}
+	if sum != decoded.TotalFunctions || sum != 3 {
+		t.Errorf("level_distribution %v sums to %d, total_functions = %d, want 3", decoded.LevelDistribution, sum, decoded.TotalFunctions)
+	}
+	if decoded.LevelDistribution["SIMPLE"] != 2 {
+		t.Errorf("SIMPLE = %d, want 2", decoded.LevelDistribution["SIMPLE"])
+	}
+}
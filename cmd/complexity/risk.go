@@ -0,0 +1,100 @@
+// risk.go - Composite refactoring risk score (-risk)
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+
+	"github.com/ruslano69/funcfinder/internal"
+)
+
+// Default weights of -w-depth, -w-loc and -w-params: one nesting level
+// weighs as much as 20 lines of code or 4 parameters
+const (
+	DefaultRiskDepth  = 2.0
+	DefaultRiskLOC    = 0.1
+	DefaultRiskParams = 0.5
+)
+
+// defaultRiskTop is how many functions -risk prints when -n is not given
+const defaultRiskTop = 10
+
+// riskWeights are the factors of riskScore
+type riskWeights struct {
+	Depth  float64 `json:"depth"`
+	LOC    float64 `json:"loc"`
+	Params float64 `json:"params"`
+}
+
+// validate rejects negative weights; a zero weight drops its metric
+func (w riskWeights) validate() error {
+	if w.Depth < 0 || w.LOC < 0 || w.Params < 0 {
+		return fmt.Errorf("risk weights must not be negative: -w-depth %g, -w-loc %g, -w-params %g", w.Depth, w.LOC, w.Params)
+	}
+	return nil
+}
+
+// riskScore combines nesting depth, lines of code and parameter count into
+// one number, rounded to two decimals
+func riskScore(m ComplexityMetrics, w riskWeights) float64 {
+	score := w.Depth*float64(m.MaxNestingDepth) + w.LOC*float64(m.LinesOfCode) + w.Params*float64(m.ParamCount)
+	return math.Round(score*100) / 100
+}
+
+// rankByRisk scores every function of files and returns them highest score
+// first; equal scores keep file and line order
+func rankByRisk(files []FileComplexity, w riskWeights) []ComplexityMetrics {
+	var ranked []ComplexityMetrics
+	for _, fc := range files {
+		for _, fn := range fc.Functions {
+			fn.Risk = riskScore(fn, w)
+			ranked = append(ranked, fn)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Risk > ranked[j].Risk
+	})
+	return ranked
+}
+
+// RiskResult is the -risk output with -j
+type RiskResult struct {
+	Language       string              `json:"language"`
+	TotalFunctions int                 `json:"total_functions"`
+	Weights        riskWeights         `json:"weights"`
+	Functions      []ComplexityMetrics `json:"functions"`
+}
+
+// printRiskReport prints the top functions of ranked, one per line with the
+// metrics behind their score
+func printRiskReport(ranked []ComplexityMetrics, langConfig *internal.LanguageConfig, w riskWeights, top int, jsonOut bool) {
+	total := len(ranked)
+	if top <= 0 {
+		top = defaultRiskTop
+	}
+	if top < len(ranked) {
+		ranked = ranked[:top]
+	}
+
+	if jsonOut {
+		result := RiskResult{
+			Language:       langConfig.Name,
+			TotalFunctions: total,
+			Weights:        w,
+			Functions:      ranked,
+		}
+		jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(jsonBytes))
+		return
+	}
+
+	internal.InfoMessage(fmt.Sprintf("Risk = %g*depth + %g*loc + %g*params", w.Depth, w.LOC, w.Params))
+	for i, fn := range ranked {
+		fmt.Printf("#%d %s:%d %s() risk=%.2f depth=%d loc=%d params=%d\n",
+			i+1, filepath.Base(fn.File), fn.StartLine, fn.Name, fn.Risk, fn.MaxNestingDepth, fn.LinesOfCode, fn.ParamCount)
+	}
+	internal.InfoMessage(fmt.Sprintf("Top %d of %d functions by risk", len(ranked), total))
+}
@@ -12,11 +12,13 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/ruslano69/funcfinder/cmd/internal/cli"
 	"github.com/ruslano69/funcfinder/internal"
 )
 
 type DepInfo struct {
 	Module string   `json:"module"`
+	Kind   string   `json:"kind"` // std, ext or int (see importKind)
 	Count  int      `json:"count"`
 	Files  []string `json:"files"`
 }
@@ -31,31 +33,26 @@ type DepResult struct {
 
 type fileSet map[string]bool
 
-// Stdlib detection for common languages
-var stdlibPrefixes = map[string][]string{
-	"py":    {"", "builtins.", "sys.", "os.", "json.", "re.", "collections.", "typing.", "__future__."},
-	"go":    {"fmt", "os", "io", "strings", "math", "regexp", "encoding/json", "testing", "bytes", "errors"},
-	"rs":    {"std::", "core::"},
-	"js":    {"assert", "buffer", "crypto", "fs", "http", "path", "url"},
-	"ts":    {"assert", "buffer", "crypto", "fs", "http", "path", "url"},
-	"java":  {"java.", "javax."},
-	"cs":    {"System.", "Microsoft."},
-	"c":     {"stdio", "stdlib", "string", "math"},
-	"cpp":   {"iostream", "vector", "string", "algorithm"},
-	"d":     {"std."},
-	"swift": {"Swift", "Foundation"},
-}
-
-func isStdlib(module, langKey string) bool {
-	prefixes := stdlibPrefixes[langKey]
-	for _, p := range prefixes {
-		if strings.HasPrefix(module, p) {
-			return true
-		}
+// importKind classifies module as "std", "int" (vendored/internal) or "ext"
+func importKind(module string, config *internal.LanguageConfig) string {
+	switch {
+	case strings.Contains("/"+module+"/", "/internal/") || strings.Contains("/"+module+"/", "/vendor/"):
+		return "int"
+	case config.IsStdlib(module):
+		return "std"
+	case strings.Contains(module, "/") || strings.Contains(module, "."):
+		return "ext"
+	default:
+		return "int"
 	}
-	return false
 }
 
+// blockImportRe matches one entry of a Go import block, with optional alias
+var blockImportRe = regexp.MustCompile(`^\s*(?:[\w.]+\s+)?"([^"]+)"`)
+
+// pyImportListRe matches Python "import a, b.c as d" (several modules per line)
+var pyImportListRe = regexp.MustCompile(`^\s*import\s+(.+)$`)
+
 // collectFileImports returns all imports per file as map[absPath][]importedModule
 func collectFileImports(filename string, config *internal.LanguageConfig, excludeREs []*regexp.Regexp) []string {
 	var imports []string
@@ -71,7 +68,6 @@ func collectFileImports(filename string, config *internal.LanguageConfig, exclud
 		return imports
 	}
 
-	blockImportRe := regexp.MustCompile(`^\s*"([^"]+)"`)
 	inBlock := false
 
 	scanner := bufio.NewScanner(file)
@@ -85,7 +81,7 @@ func collectFileImports(filename string, config *internal.LanguageConfig, exclud
 			continue
 		}
 		if inBlock {
-			if trimmed == ")" {
+			if strings.HasPrefix(trimmed, ")") {
 				inBlock = false
 			} else if match := blockImportRe.FindStringSubmatch(line); len(match) >= 2 {
 				dep := match[1]
@@ -107,6 +103,17 @@ func collectFileImports(filename string, config *internal.LanguageConfig, exclud
 			continue
 		}
 
+		if config.LangKey == "py" {
+			if match := pyImportListRe.FindStringSubmatch(line); match != nil {
+				for _, part := range strings.Split(match[1], ",") {
+					if fields := strings.Fields(part); len(fields) > 0 {
+						imports = append(imports, fields[0])
+					}
+				}
+				continue
+			}
+		}
+
 		if match := importRe.FindStringSubmatch(line); len(match) >= 2 {
 			for i := 1; i < len(match); i++ {
 				if match[i] != "" && !strings.Contains(match[i], "://") {
@@ -131,10 +138,62 @@ func collectFileImports(filename string, config *internal.LanguageConfig, exclud
 	return imports
 }
 
+// collectNormalizedImports is collectFileImports for --normalize-imports:
+// the imports of filename in canonical form (internal.ImportCollector), Go
+// aliases and Python "from x import y" resolved to package paths, sorted
+// and deduplicated. ExcludePatterns apply to the resulting paths.
+func collectNormalizedImports(filename string, config *internal.LanguageConfig, excludeREs []*regexp.Regexp) []string {
+	var imports []string
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return imports
+	}
+	defer file.Close()
+
+	collector := internal.NewImportCollector(config)
+	sanitizer := internal.NewSanitizer(config, false)
+	state := internal.StateNormal
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		var cleaned string
+		cleaned, state = sanitizer.CleanLine(line, state)
+		collector.Add(line, cleaned)
+	}
+
+	for _, dep := range collector.Imports() {
+		if strings.Contains(dep, "://") || strings.HasSuffix(dep, "/") || matchesAny(excludeREs, dep) {
+			continue
+		}
+		imports = append(imports, dep)
+	}
+	return imports
+}
+
+// matchesAny reports whether one of res matches s
+func matchesAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileImportsFunc returns collectNormalizedImports with --normalize-imports,
+// collectFileImports otherwise
+func fileImportsFunc(normalize bool) func(string, *internal.LanguageConfig, []*regexp.Regexp) []string {
+	if normalize {
+		return collectNormalizedImports
+	}
+	return collectFileImports
+}
+
 // analyzeDeps returns aggregated deps map (used by standard flat mode).
-func analyzeDeps(filename string, config *internal.LanguageConfig, excludeREs []*regexp.Regexp) map[string]fileSet {
+func analyzeDeps(filename string, config *internal.LanguageConfig, excludeREs []*regexp.Regexp, normalize bool) map[string]fileSet {
 	deps := make(map[string]fileSet)
-	for _, imp := range collectFileImports(filename, config, excludeREs) {
+	for _, imp := range fileImportsFunc(normalize)(filename, config, excludeREs) {
 		if deps[imp] == nil {
 			deps[imp] = make(fileSet)
 		}
@@ -153,12 +212,13 @@ func main() {
 	splitBy := "dir"
 	updateManifest := ""
 	noGitignore := false
+	normalizeImports := false
 
 	for i := 1; i < len(os.Args); i++ {
 		arg := os.Args[i]
 		switch {
 		case arg == "-h" || arg == "--help":
-			fmt.Println("Usage: deps [OPTIONS] <dir>")
+			fmt.Println("Usage: deps [OPTIONS] <dir|file>")
 			fmt.Println("  --version              Show version and exit")
 			fmt.Println("  -l <lang>              Force language (py, go, rs, js, ts, java, cs, swift, c, cpp, d)")
 			fmt.Println("  -n <num>               Show top N dependencies")
@@ -167,6 +227,8 @@ func main() {
 			fmt.Println("  --split-by dir|file    Shard granularity (default: dir)")
 			fmt.Println("  --update-manifest <p>  Write depends_on into existing manifest.json")
 			fmt.Println("  --no-gitignore         Do not respect .gitignore rules")
+			fmt.Println("  --normalize-imports    Canonical imports: aliases resolved, Python from-imports as pkg.name")
+			fmt.Println("  --error-format <f>     Fatal errors as text (default) or json on stderr")
 			return
 		case arg == "--version":
 			showVersion = true
@@ -188,27 +250,40 @@ func main() {
 			i++
 		case arg == "--no-gitignore":
 			noGitignore = true
+		case arg == "-normalize-imports" || arg == "--normalize-imports":
+			normalizeImports = true
+		case arg == "--error-format" && i+1 < len(os.Args):
+			cli.SetErrorFormat(os.Args[i+1])
+			i++
 		case !strings.HasPrefix(arg, "-"):
 			dir = arg
 		}
 	}
 
 	if showVersion {
-		internal.PrintVersion("deps")
+		cli.PrintVersion("deps")
 	}
 
 	// Load shared configuration
 	config, err := internal.LoadConfig()
 	if err != nil {
-		internal.FatalError("loading config: %v", err)
+		cli.FatalError("loading config: %v", err)
 	}
 
+	info, err := os.Stat(dir)
+	if err != nil {
+		cli.FatalError("%v", err)
+	}
+	singleFile := !info.IsDir()
+
 	var langConfig *internal.LanguageConfig
 	if lang != "" {
 		langConfig, err = config.GetLanguageConfig(lang)
 		if err != nil {
-			internal.FatalError("%v\nSupported languages: %s", err, strings.Join(config.GetSupportedLanguages(), ", "))
+			cli.FatalError("%v\nSupported languages: %s", err, strings.Join(config.GetSupportedLanguages(), ", "))
 		}
+	} else if singleFile {
+		langConfig = config.GetLanguageByExtension(dir)
 	} else {
 		for _, lc := range config {
 			for _, ext := range lc.Extensions {
@@ -225,7 +300,7 @@ func main() {
 	}
 
 	if langConfig == nil {
-		internal.FatalError("no supported files found in directory\nSupported languages: %s", strings.Join(config.GetSupportedLanguages(), ", "))
+		cli.FatalError("no supported files found in directory\nSupported languages: %s", strings.Join(config.GetSupportedLanguages(), ", "))
 	}
 
 	// Pre-compile ExcludePatterns
@@ -234,23 +309,32 @@ func main() {
 		excludeREs = append(excludeREs, regexp.MustCompile(pattern))
 	}
 
-	dirFiles, walkErr := internal.CollectSourceFiles(dir, langConfig, true, !noGitignore)
-	if walkErr != nil {
-		internal.FatalError("walking directory: %v", walkErr)
+	var dirFiles []string
+	if singleFile {
+		if shardsMode || updateManifest != "" {
+			cli.FatalError("--shards and --update-manifest need a directory")
+		}
+		dirFiles = []string{dir}
+	} else {
+		var walkErr error
+		dirFiles, walkErr = internal.CollectSourceFiles(dir, langConfig, true, !noGitignore)
+		if walkErr != nil {
+			cli.FatalError("walking directory: %v", walkErr)
+		}
 	}
 
 	// ── Shard dependency graph mode ─────────────────────────────────────────
 	if shardsMode || updateManifest != "" {
 		absDir, err := filepath.Abs(dir)
 		if err != nil {
-			internal.FatalError("resolving directory: %v", err)
+			cli.FatalError("resolving directory: %v", err)
 		}
 
 		// Collect per-file imports
 		fileImports := make(map[string][]string, len(dirFiles))
 		for _, path := range dirFiles {
 			abs, _ := filepath.Abs(path)
-			fileImports[abs] = collectFileImports(path, langConfig, excludeREs)
+			fileImports[abs] = fileImportsFunc(normalizeImports)(path, langConfig, excludeREs)
 		}
 
 		// Auto-detect module prefix / aliases per language
@@ -280,7 +364,7 @@ func main() {
 
 		if updateManifest != "" {
 			if err := applyGraphToManifest(updateManifest, graph); err != nil {
-				internal.FatalError("updating manifest: %v", err)
+				cli.FatalError("updating manifest: %v", err)
 			}
 			fmt.Fprintf(os.Stderr, "INFO: Updated %s with depends_on for %d shards\n", updateManifest, len(graph))
 			if !jsonOut {
@@ -306,8 +390,10 @@ func main() {
 
 	// ── Standard flat deps mode ──────────────────────────────────────────────
 	allDeps := make(map[string]fileSet)
+	totalImports := 0
 	for _, path := range dirFiles {
-		fileDeps := analyzeDeps(path, langConfig, excludeREs)
+		fileDeps := analyzeDeps(path, langConfig, excludeREs, normalizeImports)
+		totalImports += len(fileDeps)
 		for dep, files := range fileDeps {
 			if allDeps[dep] == nil {
 				allDeps[dep] = make(fileSet)
@@ -326,26 +412,30 @@ func main() {
 		for f := range files {
 			fileList = append(fileList, f)
 		}
-		info := DepInfo{Module: dep, Count: len(fileList), Files: fileList}
+		info := DepInfo{Module: dep, Kind: importKind(dep, langConfig), Count: len(fileList), Files: fileList}
 		deps = append(deps, info)
 
-		if isStdlib(dep, langConfig.LangKey) {
+		switch info.Kind {
+		case "std":
 			stdlib++
-		} else if strings.Contains(dep, "internal/") || strings.Contains(dep, "vendor/") {
-			internalCount++
-		} else if strings.Contains(dep, "/") || strings.Contains(dep, ".") {
+		case "ext":
 			external++
-		} else {
+		default:
 			internalCount++
 		}
 	}
 
-	sort.Slice(deps, func(i, j int) bool { return deps[i].Count > deps[j].Count })
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Count != deps[j].Count {
+			return deps[i].Count > deps[j].Count
+		}
+		return deps[i].Module < deps[j].Module
+	})
 
 	if jsonOut {
 		result := DepResult{
 			Language:      langConfig.Name,
-			TotalImports:  len(allDeps),
+			TotalImports:  totalImports,
 			UniqueModules: len(deps),
 			Dependencies:  deps,
 			ExternalVsInternal: map[string]int{
@@ -360,24 +450,35 @@ func main() {
 	}
 
 	fmt.Printf("Language: %s\n", langConfig.Name)
-	fmt.Printf("Total imports: %d\n", len(allDeps))
+	fmt.Printf("Total imports: %d\n", totalImports)
 	fmt.Printf("Unique modules: %d\n", len(deps))
 	fmt.Println(strings.Repeat("-", 35))
 	fmt.Printf("stdlib: %d, external: %d, internal: %d\n", stdlib, external, internalCount)
 	fmt.Println(strings.Repeat("-", 35))
 
-	printCount := len(deps)
-	if topN > 0 && topN < printCount {
-		printCount = topN
+	// Grouped by classification; -n limits each group
+	groups := []struct{ kind, title string }{
+		{"ext", "Third-party"},
+		{"int", "Internal"},
+		{"std", "Standard library"},
 	}
-	for i := 0; i < printCount; i++ {
-		kind := "ext"
-		if isStdlib(deps[i].Module, langConfig.LangKey) {
-			kind = "std"
-		} else if strings.Contains(deps[i].Module, "internal/") || strings.Contains(deps[i].Module, "vendor/") {
-			kind = "int"
+	for _, group := range groups {
+		var members []DepInfo
+		for _, dep := range deps {
+			if dep.Kind == group.kind {
+				members = append(members, dep)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		fmt.Printf("%s (%d):\n", group.title, len(members))
+		if topN > 0 && topN < len(members) {
+			members = members[:topN]
+		}
+		for _, dep := range members {
+			fmt.Printf("  %-30s %3d\n", dep.Module, dep.Count)
 		}
-		fmt.Printf("%-30s %3d (%s)\n", deps[i].Module, deps[i].Count, kind)
 	}
 }
 
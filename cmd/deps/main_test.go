@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ruslano69/funcfinder/internal"
+)
+
+func langConfig(t *testing.T, lang string) (*internal.LanguageConfig, []*regexp.Regexp) {
+	config, err := internal.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	lc := config[lang]
+	var excludeREs []*regexp.Regexp
+	for _, pattern := range lc.ExcludePatterns {
+		excludeREs = append(excludeREs, regexp.MustCompile(pattern))
+	}
+	return lc, excludeREs
+}
+
+func writeFixture(t *testing.T, name, code string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(code), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestCollectFileImports_GoGroupedImports(t *testing.T) {
+	lc, excludeREs := langConfig(t, "go")
+	path := writeFixture(t, "main.go", `package main
+
+import "os"
+
+import (
+	"fmt"
+	str "strings"
+	_ "embed"
+
+	"github.com/ruslano69/funcfinder/internal"
+)
+
+func main() {
+	fmt.Println("not an import")
+}
+`)
+	got := collectFileImports(path, lc, excludeREs)
+	want := []string{"os", "fmt", "strings", "embed", "github.com/ruslano69/funcfinder/internal"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("imports = %q, want %q", got, want)
+	}
+}
+
+func TestCollectFileImports_PythonFromImports(t *testing.T) {
+	lc, excludeREs := langConfig(t, "py")
+	path := writeFixture(t, "app.py", `import os, sys as system
+from collections import OrderedDict
+from requests.adapters import HTTPAdapter
+from . import sibling
+from __future__ import annotations
+`)
+	got := collectFileImports(path, lc, excludeREs)
+	want := []string{"os", "sys", "collections", "requests.adapters"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("imports = %q, want %q", got, want)
+	}
+}
+
+func TestImportKind(t *testing.T) {
+	tests := []struct {
+		module, lang, want string
+	}{
+		{"fmt", "go", "std"},
+		{"encoding/json", "go", "std"},
+		{"github.com/ruslano69/funcfinder/internal", "go", "int"},
+		{"github.com/spf13/cobra", "go", "ext"},
+		{"os", "py", "std"},
+		{"collections.abc", "py", "std"},
+		{"osmosis", "py", "int"},
+		{"requests.adapters", "py", "ext"},
+		{"std::collections::HashMap", "rust", "std"},
+	}
+	for _, tt := range tests {
+		lc, _ := langConfig(t, tt.lang)
+		if got := importKind(tt.module, lc); got != tt.want {
+			t.Errorf("importKind(%q, %q) = %q, want %q", tt.module, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestCollectNormalizedImports(t *testing.T) {
+	goLC, goExclude := langConfig(t, "go")
+	goPath := writeFixture(t, "main.go", "package main\n\nimport (\n\tf \"fmt\"\n\t\"fmt\"\n\t_ \"embed\"\n)\n\nimport str \"strings\"\n")
+	if got, want := collectNormalizedImports(goPath, goLC, goExclude), []string{"embed", "fmt", "strings"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Go imports = %q, want %q", got, want)
+	}
+
+	pyLC, pyExclude := langConfig(t, "py")
+	pyPath := writeFixture(t, "app.py", "import os.path\nfrom os import path\nimport sys as system\nfrom . import sibling\nfrom __future__ import annotations\n")
+	if got, want := collectNormalizedImports(pyPath, pyLC, pyExclude), []string{"os.path", "sys"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Python imports = %q, want %q", got, want)
+	}
+
+	deps := analyzeDeps(pyPath, pyLC, pyExclude, true)
+	if len(deps) != 2 || !deps["os.path"][pyPath] {
+		t.Errorf("analyzeDeps(normalize) = %v, want os.path and sys", deps)
+	}
+}
@@ -5,139 +5,668 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"text/template"
+	"time"
 
+	"github.com/ruslano69/funcfinder/cmd/internal/cli"
 	"github.com/ruslano69/funcfinder/internal"
 )
 
 func main() {
 	// Парсинг аргументов командной строки
 	version := flag.Bool("version", false, "print version and exit")
+	flag.Func("error-format", cli.ErrorFormatUsage, internal.SetErrorFormat)
+	validateConfig := flag.Bool("validate-config", false, "check every language in languages.json (required fields, pattern compilation, canonical snippets) and exit; exit code 1 on issues")
 
 	// Режим файла
-	inp := flag.String("inp", "", "input file with source code")
-	source := flag.String("source", "", "source language: go/c/cpp/cs/java/d/js/ts/py/rust/swift/kotlin/php/ruby/scala")
+	var inputs stringList
+	flag.Var(&inputs, "inp", "input file with source code (repeatable, or a space-separated list: several files are merged like --dir output)")
+	source := flag.String("source", "", "source language: go/c/cpp/cs/java/d/js/ts/py/rust/swift/kotlin/php/ruby/scala/hs/dart, or data format yaml/json/toml (keys as --struct types)")
 
 	// Режим каталога
 	dir := flag.String("dir", "", "directory to scan for source files (auto-detects language by extension)")
 	workers := flag.Int("workers", 0, "number of parallel workers (default: number of CPU cores)")
+	parallelFile := flag.Bool("parallel-file", false, "scan one large --inp file in top-level regions on --workers goroutines (brace languages without nested functions: C, C++, Java, C#, ...)")
 	recursive := flag.Bool("recursive", true, "scan directories recursively")
+	maxDepth := flag.Int("max-depth", -1, "limit --dir recursion to N directory levels below the root (0 = root only, -1 = unlimited)")
+	excludeTests := flag.Bool("exclude-tests", false, "skip test files (test_file_patterns: *_test.go, test_*.py, *.spec.ts, ...) in --dir/--archive mode")
+	testsOnly := flag.Bool("tests-only", false, "scan only test files (test_file_patterns) in --dir/--archive mode")
 	noGitignore := flag.Bool("no-gitignore", false, "ignore .gitignore files")
+	gitignoreMode := flag.String("gitignore-mode", "", "ignore sources in --dir mode: repo (.gitignore files, default), global (.git/info/exclude and git's core.excludesFile), all (both) or none (same as --no-gitignore)")
+	var ignoreFiles stringList
+	flag.Var(&ignoreFiles, "ignore-file", "additional ignore file (.ignore, .dockerignore, ...) with gitignore syntax, relative to the scanned root (repeatable)")
+	archive := flag.String("archive", "", "scan sources inside a .tar, .tar.gz/.tgz or .zip archive without extracting")
+	filesFrom := flag.String("files-from", "", "scan the newline-separated paths listed in this file ('-' for stdin), e.g. git ls-files output; merged like --dir output")
 
 	// Function/Type finding flags
 	funcStr := flag.String("func", "", "function names to find (comma-separated)")
 	structMode := flag.Bool("struct", false, "find structs/classes/types instead of functions")
 	typeStr := flag.String("type", "", "type names to find (comma-separated)")
 	allMode := flag.Bool("all", false, "find both functions and structs")
+	extMap := flag.String("ext-map", "", "override extension-to-language mapping, e.g. '.inc=php,.tmpl=go' (also adds extensions the config doesn't know)")
+	ignoreGenerated := flag.Bool("ignore-generated", false, "skip generated files: a \"Code generated\", \"DO NOT EDIT\", \"@generated\" or \"Generated by\" marker in the first 5 lines (--dir, --archive, several --inp)")
+	var generatedMarkers stringList
+	flag.Var(&generatedMarkers, "generated-marker", "header marker of generated files, replaces the defaults of --ignore-generated and implies it (repeatable)")
+	var extraTypes stringList
+	flag.Var(&extraTypes, "extra-type", "custom type kind 'kind:regex' for --struct/--all, e.g. 'entity:^@Entity\\s+class\\s+(\\w+)' (repeatable; applies to --source, or to every language)")
 
 	// Output mode flags
 	mapMode := flag.Bool("map", false, "map all functions/types in file(s)")
 	treeMode := flag.Bool("tree", false, "output in tree format")
 	treeFull := flag.Bool("tree-full", false, "output in tree format with signatures")
+	noLangLabel := flag.Bool("no-lang-label", false, "do not append the language (\"main.go [go]\") to file names in --dir --tree output")
+	unifiedTree := flag.Bool("unified-tree", false, "with --all: one tree of types with their fields and methods nested by line")
 	jsonOut := flag.Bool("json", false, "output in JSON format")
+	contextJSON := flag.Bool("context-json", false, "--json with each function's dotted enclosing scope path (\"scope_path\": Outer.Inner.method, outer.inner) (single --inp)")
+	envelope := flag.Bool("envelope", false, "with --json: wrap the functions in an object with tool, version, language, lang_key, generated_at and classes (single --inp)")
+	jsonStream := flag.Bool("json-stream", false, "--dir mode: write one JSON object per file as it is processed (NDJSON), then a totals object")
 	extract := flag.Bool("extract", false, "extract function/type bodies")
+	sortOrder := flag.String("sort", "", "order functions by line, size (longest first) or name; without it the finder's order is kept; with --json a \"functions\" array in that order (single --inp)")
+	groupByClass := flag.Bool("group-by-class", false, "list functions in sections per class, methods indented, functions without a class under \"(top-level)\" (single --inp)")
+	flatten := flag.Bool("flatten", false, "list every function and closure at every nesting level with its depth, one per line (with --json: a \"functions\" array) (single --inp)")
+	topN := flag.Int("top", 0, "keep only the first N functions after --sort, e.g. --sort size --top 10 for the 10 largest (single --inp)")
+	getSel := flag.String("get", "", "select functions by path: 'Class/method' or a bare name; overloads are all returned (with --extract: their bodies)")
+	withImports := flag.Bool("extract-with-imports", false, "with --extract: prepend the file's import block to each extracted function")
+	warnLength := flag.Int("warn-length", 0, "warn about functions spanning more than N lines (End-Start+1) with the overage; exit code 3 with --strict")
+	maxFunctions := flag.Int("max-functions", 0, "flag files defining more than N functions and types together (god-files): warnings, \"oversized_files\" in --summary-only, exit code 3 with --strict (--dir/--archive, several --inp)")
+	summaryOnly := flag.Bool("summary-only", false, "print only totals and a per-language breakdown (--dir/--archive mode)")
+	since := flag.String("since", "", "--dir mode: scan only files changed since a git ref (git diff --name-only <ref>...HEAD); scans everything outside a git repo")
+	visibility := flag.Bool("visibility-summary", false, "print public/private counts of functions and types (by the --exported-only rules) instead of the listing")
+	profileLangs := flag.Bool("profile-languages", false, "append a per-language files/functions/classes table to directory output (\"language_stats\" with --json)")
+	changedFrom := flag.String("changed-functions", "", "compare the functions of --inp with this older version of the file: modified (body changed, comments and whitespace ignored), moved, added, removed and unchanged, modified listed first (single --inp)")
+	directives := flag.Bool("directives", false, "Go: list build constraints (//go:build, // +build) above the package clause and //go: directives anywhere, with line numbers (single --inp)")
+	dotMode := flag.Bool("dot", false, "output a GraphViz DOT digraph of classes/types and their methods")
+	strict := flag.Bool("strict", false, "fail (exit code 3) on parse ambiguities: missing body brace, unclosed function/class, overlapping ranges")
+	category := flag.String("category", "", "keep only Go functions of this category: test/benchmark/init/example/fuzz")
+	generatorsOnly := flag.Bool("generators-only", false, "keep only generator functions (Python yield, JS/TS function*); emitted as \"generator\" in --json")
+	deprecatedOnly := flag.Bool("deprecated-only", false, "keep only functions marked deprecated (Go \"// Deprecated:\", @Deprecated, @deprecated, [Obsolete], warnings.warn(DeprecationWarning)); emitted as \"deprecated\" in --json")
+	statsMode := flag.Bool("stats", false, "print code/comment/blank line counts per function; emitted as \"line_stats\" in --json")
+	firstLine := flag.Bool("first-line", false, "show each function's opening line as written, with its name and range (cheaper than --extract)")
+	withDocs := flag.Bool("with-docs", false, "attach the doc comment above each function (Python: its docstring); emitted as \"doc\" in --json")
+	fieldTypesOnly := flag.Bool("field-types-only", false, "with --struct: flat 'Type.Field: FieldType' listing of every field")
+	typesOnly := flag.Bool("types-only", false, "with --struct: list type names and kinds only, without fields")
+	withMethods := flag.Bool("with-methods", false, "with --struct: list each type's methods (functions whose class is the type) after its fields")
+	fieldsDepth := flag.Int("fields-depth", 0, "with --struct/--all: list fields of nested anonymous structs/unions down to N levels as 'Inner.x' (1 = direct fields only; 0 = flat listing)")
+	maxParams := flag.Int("max-params", -1, "keep only functions with more than N parameters (code smell check); emitted as \"param_count\" in --json")
+	byteOffsets := flag.Bool("byte-offsets", false, "with --json: absolute byte offsets of each function, \"byte_start\" and \"byte_end\" (exclusive), for tools that index by byte (single --inp)")
+	assertions := flag.Bool("assertions", false, "count assertion calls (assertion_pattern: t.Errorf, assert.*, self.assert*, expect(...)) in each test function (Go TestXxx, test* functions, Jest/Mocha test()/it() blocks) and warn about tests without any; exit code 3 with --strict; bodies are read from the file, with --extract they are printed (single --inp)")
+	assertionPattern := flag.String("assertion-pattern", "", "with --assertions: regex of one assertion call, replacing the language's assertion_pattern")
+	literals := flag.Bool("literals", false, "with --json: count string and numeric literals (magic numbers) in each function, outside comments; emitted as \"string_literals\"/\"numeric_literals\" (single --inp)")
+	returns := flag.Bool("returns", false, "with --json: count the values each Go/Python function returns (Go: result list, Python: widest return statement, heuristic); emitted as \"return_count\" (single --inp)")
+	noClasses := flag.Bool("no-classes", false, "skip class discovery: functions only, with empty class association (faster)")
+	flag.BoolVar(noClasses, "functions-only", false, "alias for --no-classes")
+	includeMacros := flag.Bool("include-macros", false, "also report C/C++ function-like macros (\"#define MAX(a, b) ...\", with \\ continuation lines) as functions; emitted as \"kind\": \"macro\" in --json")
+	excludeAnon := flag.Bool("exclude-anonymous", false, "skip unnamed functions and IIFEs (JS/TS \"const api = (function () {...})()\"); named declarations and \"const f = () => {}\" are kept")
+	exportedOnly := flag.Bool("exported-only", false, "keep only exported/public functions and types (per-language rule: Go capitalization, Rust pub, Java/C# public, ...)")
+	annotate := flag.String("annotate", "", "one CI annotation per function/type: github (::notice workflow commands for PRs) or grep (path:line: name)")
+	formatStr := flag.String("format", "", "Go text/template applied per function, e.g. '{{.Path}}:{{.Start}} {{.Name}}' (fields: Path, Name, Start, End, ClassName, Signature)")
 
 	// Advanced flags
 	rawMode := flag.Bool("raw", false, "include raw strings in brace counting")
 	linesRange := flag.String("lines", "", "extract specific line range (format: start:end, :end, start:, or single line)")
+	snapLines := flag.Bool("snap-lines", false, "with --lines: expand the range so it does not cut functions in half (always on for Python)")
 
 	// Split output flags (for --dir mode)
 	splitMode := flag.Bool("split", false, "split output into manifest + shard files (--dir mode only)")
 	splitBy := flag.String("split-by", "dir", "split granularity: 'dir' (one shard per directory) or 'file' (one shard per file)")
 	outDir := flag.String("out", ".codemap", "output directory for split files")
 	incMode := flag.Bool("inc", false, "incremental split update using shard checksums (--split --json --dir)")
+	splitOutput := flag.String("split-output", "", "--dir mode: write each file's JSON to <dir>/<relpath>.json, mirroring the scanned tree")
+	indexOut := flag.String("index", "", "--dir mode: write one flat JSON index of every symbol (path, line/col, kind, scope path) to this file, e.g. --all --index index.json")
 
 	// Pre-process args to support --struct "TypeA,TypeB" syntax:
 	// transforms "--struct Names --extract" into "--struct --type Names --extract"
 	// before standard flag parsing (flag package stops at first non-flag positional arg).
-	flag.CommandLine.Parse(preprocessStructArg(os.Args[1:])) //nolint:errcheck
+	// Positional file arguments are moved behind the flags, so "funcfinder foo.go --map"
+	// parses --map as well.
+	flag.CommandLine.Parse(hoistPositionalArgs(preprocessStructArg(os.Args[1:]), flag.CommandLine)) //nolint:errcheck
 
 	// Обработка флага --version
 	if *version {
-		internal.PrintVersion("funcfinder")
+		cli.PrintVersion("funcfinder")
 	}
 
-	// Валидация: либо -inp либо -dir должно быть указано
-	if *inp == "" && *dir == "" {
-		internal.FatalError("either --inp (single file) or --dir (directory) parameter is required")
+	// Проверка languages.json без обработки файлов
+	if *validateConfig {
+		runValidateConfig()
 	}
 
-	if *inp != "" && *dir != "" {
-		internal.FatalError("--inp and --dir are mutually exclusive")
+	// Позиционные аргументы — файлы, как --inp ("funcfinder foo.go --map")
+	if flag.NArg() > 0 {
+		if len(inputs) > 0 || *dir != "" || *archive != "" {
+			cli.FatalError("unexpected arguments %q: files are given either as --inp or positionally", flag.Args())
+		}
+		inputs = append(inputs, flag.Args()...)
+	}
+
+	// --files-from: список путей из файла или stdin, без обхода каталога
+	if *filesFrom != "" && (len(inputs) > 0 || *dir != "" || *archive != "") {
+		cli.FatalError("--files-from, --inp, --dir and --archive are mutually exclusive")
+	}
+
+	// Несколько --inp: файлы обрабатываются как список, вывод как в --dir
+	files := expandInputs(inputs)
+	if *filesFrom != "" {
+		files = readFilesFrom(*filesFrom)
+	}
+	listMode := len(files) > 1 || *filesFrom != ""
+	inp := ""
+	if len(files) == 1 && !listMode {
+		inp = files[0]
+	}
+
+	// Валидация: либо -inp либо -dir (или --archive) должно быть указано
+	if len(files) == 0 && *dir == "" && *archive == "" {
+		cli.FatalError("either a file (--inp or positional argument) or --dir (directory) parameter is required")
+	}
+
+	if (len(files) > 0 && *dir != "") || (*archive != "" && (len(files) > 0 || *dir != "")) {
+		cli.FatalError("--inp, --dir and --archive are mutually exclusive")
+	}
+
+	// --format проверяем сразу, до сканирования
+	var formatTmpl *template.Template
+	if *formatStr != "" {
+		tmpl, err := internal.ParseOutputTemplate(*formatStr)
+		if err != nil {
+			cli.FatalError("%v", err)
+		}
+		formatTmpl = tmpl
+	}
+
+	var annotateStyle internal.AnnotateStyle
+	if *annotate != "" {
+		style, err := internal.ParseAnnotateStyle(*annotate)
+		if err != nil {
+			cli.FatalError("%v", err)
+		}
+		annotateStyle = style
+	}
+
+	if *category != "" {
+		if _, err := internal.ParseCategory(*category); err != nil {
+			cli.FatalError("%v", err)
+		}
+	}
+
+	// Без --sort функции остаются в порядке поиска (вложенные - после внешних)
+	order := ""
+	if *sortOrder != "" {
+		parsed, err := internal.ParseSortOrder(*sortOrder)
+		if err != nil {
+			cli.FatalError("%v", err)
+		}
+		order = parsed
+	}
+	if *topN < 0 {
+		cli.FatalError("--top must be a positive number")
+	}
+
+	var selector *internal.Selector
+	if *getSel != "" {
+		sel, err := internal.ParseSelector(*getSel)
+		if err != nil {
+			cli.FatalError("%v", err)
+		}
+		if *funcStr != "" {
+			cli.FatalError("--get and --func are mutually exclusive")
+		}
+		selector = &sel
 	}
 
 	// Загружаем конфигурацию языков
 	config, err := internal.LoadConfig()
 	if err != nil {
-		internal.FatalError("loading config: %v", err)
+		cli.FatalError("loading config: %v", err)
+	}
+	if err := applyExtraTypes(config, *source, extraTypes); err != nil {
+		cli.FatalError("%v", err)
+	}
+	if err := applyExtMap(config, *extMap); err != nil {
+		cli.FatalError("%v", err)
+	}
+
+	// В списке --files-from файлы неизвестных языков пропускаются
+	if *filesFrom != "" && *source == "" {
+		var skipped int
+		files, skipped = internal.SupportedFiles(config, files)
+		if skipped > 0 {
+			internal.InfoMessage("Skipped %d listed file(s) of unsupported languages", skipped)
+		}
+		if len(files) == 0 {
+			cli.FatalErrorMsg("No supported files in --files-from list")
+		}
+	}
+
+	// --generated-marker заменяет маркеры по умолчанию и включает --ignore-generated
+	var skipMarkers []string
+	if len(generatedMarkers) > 0 {
+		skipMarkers = generatedMarkers
+	} else if *ignoreGenerated {
+		skipMarkers = internal.DefaultGeneratedMarkers
+	}
+	if skipMarkers != nil && *dir == "" && *archive == "" && !listMode {
+		cli.FatalError("--ignore-generated is supported in --dir and --archive mode and with several --inp files only")
+	}
+
+	if *warnLength < 0 {
+		cli.FatalError("--warn-length must be positive, got %d", *warnLength)
+	}
+
+	if *maxFunctions > 0 && *dir == "" && *archive == "" && !listMode {
+		cli.FatalError("--max-functions is supported in --dir and --archive mode and with several --inp files only")
+	}
+
+	ignoreMode, err := internal.ParseGitignoreMode(*gitignoreMode)
+	if err != nil {
+		cli.FatalError("%v", err)
+	}
+	if *noGitignore {
+		if *gitignoreMode != "" && ignoreMode != internal.GitignoreNone {
+			cli.FatalError("--no-gitignore conflicts with --gitignore-mode %s", ignoreMode)
+		}
+		ignoreMode = internal.GitignoreNone
+	}
+
+	if *since != "" && *dir == "" {
+		cli.FatalError("--since is supported in --dir mode only")
+	}
+
+	if *splitOutput != "" && *dir == "" {
+		cli.FatalError("--split-output is supported in --dir mode only")
 	}
 
-	// Режим обработки каталога
-	if *dir != "" {
+	if *indexOut != "" && *dir == "" {
+		cli.FatalError("--index is supported in --dir mode only")
+	}
+
+	// Отбор тестовых файлов работает только при обходе каталога или архива
+	testFiles := internal.TestFilesAll
+	if *excludeTests && *testsOnly {
+		cli.FatalError("--exclude-tests and --tests-only are mutually exclusive")
+	}
+	if *excludeTests || *testsOnly {
+		if *dir == "" && *archive == "" {
+			cli.FatalError("--exclude-tests and --tests-only are supported in --dir and --archive mode only")
+		}
+		testFiles = internal.TestFilesExclude
+		if *testsOnly {
+			testFiles = internal.TestFilesOnly
+		}
+	}
+
+	// Режим обработки каталога (или архива, или списка файлов)
+	if *dir != "" || *archive != "" || listMode {
+		if *unifiedTree {
+			cli.FatalError("--unified-tree is supported with a single --inp file only")
+		}
+		if *statsMode {
+			cli.FatalError("--stats is supported with a single --inp file only")
+		}
+		if *fieldTypesOnly || *typesOnly {
+			cli.FatalError("--field-types-only and --types-only are supported with a single --inp file only")
+		}
+		if *fieldsDepth != 0 {
+			cli.FatalError("--fields-depth is supported with a single --inp file only")
+		}
+		if *withMethods {
+			cli.FatalError("--with-methods is supported with a single --inp file only")
+		}
+		if *withImports {
+			cli.FatalError("--extract-with-imports is supported with a single --inp file only")
+		}
+		if selector != nil {
+			cli.FatalError("--get is supported with a single --inp file only")
+		}
+		if *sortOrder != "" || *topN > 0 {
+			cli.FatalError("--sort and --top are supported with a single --inp file only")
+		}
+		if *groupByClass {
+			cli.FatalError("--group-by-class is supported with a single --inp file only")
+		}
+		if *flatten {
+			cli.FatalError("--flatten is supported with a single --inp file only")
+		}
+		if *envelope {
+			cli.FatalError("--envelope is supported with a single --inp file only")
+		}
+		if *changedFrom != "" {
+			cli.FatalError("--changed-functions is supported with a single --inp file only")
+		}
+		if *directives {
+			cli.FatalError("--directives is supported with a single --inp file only")
+		}
+		if *returns {
+			cli.FatalError("--returns is supported with a single --inp file only")
+		}
+		if *byteOffsets {
+			cli.FatalError("--byte-offsets is supported with a single --inp file only")
+		}
+		if *assertions {
+			cli.FatalError("--assertions is supported with a single --inp file only")
+		}
+		if *literals {
+			cli.FatalError("--literals is supported with a single --inp file only")
+		}
+		if *contextJSON {
+			cli.FatalError("--context-json is supported with a single --inp file only")
+		}
+		if *parallelFile {
+			cli.FatalError("--parallel-file is supported with a single --inp file only (--dir already scans files in parallel)")
+		}
 		// Автоматически включаем --map если не указан другой режим вывода
 		autoMapMode := *mapMode
 		if !*mapMode && !*treeMode && !*treeFull {
 			autoMapMode = true
 		}
-		handleDirectoryMode(config, *dir, *workers, *recursive, !*noGitignore, *funcStr, autoMapMode, *treeMode, *treeFull, *jsonOut, *extract, *structMode, *allMode, *splitMode, *splitBy, *outDir, *incMode)
+		opts := dirOptions{
+			workers:      *workers,
+			recursive:    *recursive,
+			maxDepth:     *maxDepth,
+			useGitignore: ignoreMode.GitignoreFiles(),
+			gitExcludes:  ignoreMode.GitExcludes(),
+			funcStr:      *funcStr,
+			mapMode:      autoMapMode,
+			treeMode:     *treeMode,
+			treeFull:     *treeFull,
+			noLangLabel:  *noLangLabel,
+			jsonOut:      *jsonOut,
+			jsonStream:   *jsonStream,
+			extract:      *extract,
+			structMode:   *structMode,
+			allMode:      *allMode,
+			splitMode:    *splitMode,
+			splitBy:      *splitBy,
+			outDir:       *outDir,
+			incMode:      *incMode,
+			splitDir:     *splitOutput,
+			indexPath:    *indexOut,
+			summaryOnly:  *summaryOnly,
+			maxFuncs:     *maxFunctions,
+			warnLen:      *warnLength,
+			profileLangs: *profileLangs,
+			visibility:   *visibility,
+			since:        *since,
+			skipMarkers:  skipMarkers,
+			dotMode:      *dotMode,
+			formatTmpl:   formatTmpl,
+			annotate:     annotateStyle,
+			category:     *category,
+			generators:   *generatorsOnly,
+			deprecated:   *deprecatedOnly,
+			strict:       *strict,
+			exportedOnly: *exportedOnly,
+			ignoreFiles:  ignoreFiles,
+			testFiles:    testFiles,
+			withDocs:     *withDocs,
+			firstLine:    *firstLine,
+			noClasses:    *noClasses,
+			anonymous:    *excludeAnon,
+			macros:       *includeMacros,
+			maxParams:    *maxParams,
+		}
+		if listMode {
+			handleFilesMode(config, files, *source, *linesRange, opts)
+		} else if *archive != "" {
+			handleArchiveMode(config, *archive, opts)
+		} else {
+			handleDirectoryMode(config, *dir, opts)
+		}
 		return
 	}
 
 	// Режим обработки одного файла (существующая логика)
-	handleFileMode(config, *inp, *source, *funcStr, *typeStr, *structMode, *allMode, *mapMode, *treeMode, *treeFull, *jsonOut, *extract, *rawMode, *linesRange)
+	handleFileMode(config, fileOptions{
+		inp:        inp,
+		source:     *source,
+		funcStr:    *funcStr,
+		typeStr:    *typeStr,
+		structMode: *structMode,
+		allMode:    *allMode,
+		mapMode:    *mapMode || ((*dotMode || formatTmpl != nil || annotateStyle != "" || *category != "" || *generatorsOnly || *deprecatedOnly || *statsMode || *firstLine || *maxParams >= 0 || *returns || *byteOffsets || *literals || *assertions || *changedFrom != "" || *contextJSON || selector != nil || *visibility || *topN > 0 || *sortOrder != "" || *groupByClass || *flatten) && *funcStr == "") || ((*fieldTypesOnly || *typesOnly) && *typeStr == ""),
+		treeMode:   *treeMode,
+		treeFull:   *treeFull,
+		unified:    *unifiedTree,
+		jsonOut:    *jsonOut || *contextJSON,
+		scopePaths: *contextJSON,
+		envelope:   *envelope,
+		directives: *directives,
+		changedVs:  *changedFrom,
+		extract:    *extract,
+		imports:    *withImports,
+		visibility: *visibility,
+		selector:   selector,
+		sortOrder:  order,
+		top:        *topN,
+		byClass:    *groupByClass,
+		flatten:    *flatten,
+		rawMode:    *rawMode,
+		linesRange: *linesRange,
+		snapLines:  *snapLines,
+		dotMode:    *dotMode,
+		formatTmpl: formatTmpl,
+		annotate:   annotateStyle,
+		category:   *category,
+		generators: *generatorsOnly,
+		deprecated: *deprecatedOnly,
+		strict:     *strict,
+		exported:   *exportedOnly,
+		withDocs:   *withDocs,
+		stats:      *statsMode,
+		firstLine:  *firstLine,
+		noClasses:  *noClasses,
+		anonymous:  *excludeAnon,
+		macros:     *includeMacros,
+		maxParams:  *maxParams,
+		warnLen:    *warnLength,
+		returns:    *returns,
+		byteOffs:   *byteOffsets,
+		literals:   *literals,
+		assertions: *assertions,
+		assertPat:  *assertionPattern,
+		parallel:   *parallelFile,
+		workers:    *workers,
+		fieldTypes: *fieldTypesOnly,
+		typesOnly:  *typesOnly,
+		fieldDepth: *fieldsDepth,
+		methods:    *withMethods,
+	})
 }
 
-func handleDirectoryMode(config internal.Config, dirPath string, workers int, recursive, useGitignore bool, funcStr string, mapMode, treeMode, treeFull, jsonOut, extract, structMode, allMode, splitMode bool, splitBy, outDir string, incMode bool) {
-	// Проверяем существование директории
-	info, err := os.Stat(dirPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			internal.FatalError("directory does not exist: %s", dirPath)
-		}
-		internal.FatalError("accessing directory: %v", err)
-	}
-
-	if !info.IsDir() {
-		internal.FatalError("path is not a directory: %s", dirPath)
-	}
+// dirOptions bundles the flags shared by --dir and --archive modes
+type dirOptions struct {
+	workers      int
+	recursive    bool
+	maxDepth     int
+	useGitignore bool
+	gitExcludes  bool
+	funcStr      string
+	mapMode      bool
+	treeMode     bool
+	treeFull     bool
+	noLangLabel  bool
+	jsonOut      bool
+	jsonStream   bool
+	extract      bool
+	structMode   bool
+	allMode      bool
+	splitMode    bool
+	splitBy      string
+	outDir       string
+	incMode      bool
+	splitDir     string
+	indexPath    string
+	summaryOnly  bool
+	maxFuncs     int
+	warnLen      int
+	profileLangs bool
+	visibility   bool
+	since        string
+	skipMarkers  []string
+	dotMode      bool
+	formatTmpl   *template.Template
+	annotate     internal.AnnotateStyle
+	category     string
+	generators   bool
+	deprecated   bool
+	strict       bool
+	exportedOnly bool
+	ignoreFiles  []string
+	testFiles    internal.TestFileMode
+	withDocs     bool
+	firstLine    bool
+	noClasses    bool
+	anonymous    bool
+	macros       bool
+	maxParams    int
+}
 
+// resolveDirWorkMode validates output flags and returns the DirProcessor work mode
+func resolveDirWorkMode(opts dirOptions) string {
 	// Определяем режим работы
 	workMode := "functions"
-	if structMode && allMode {
-		internal.FatalError("--struct and --all are mutually exclusive")
+	if opts.structMode && opts.allMode {
+		cli.FatalError("--struct and --all are mutually exclusive")
 	}
-	if structMode {
+	if opts.structMode {
 		workMode = "structs"
-	} else if allMode {
+	} else if opts.allMode {
 		workMode = "all"
 	}
 
 	// Валидация параметров
 	// Note: --map is now default, so no error if none specified
-	if funcStr != "" && (mapMode || treeMode || treeFull) {
-		internal.FatalError("--func is mutually exclusive with --map and --tree")
+	if opts.funcStr != "" && (opts.mapMode || opts.treeMode || opts.treeFull) {
+		cli.FatalError("--func is mutually exclusive with --map and --tree")
 	}
 
-	if treeMode && treeFull {
-		internal.FatalError("--tree and --tree-full are mutually exclusive")
+	if opts.treeMode && opts.treeFull {
+		cli.FatalError("--tree and --tree-full are mutually exclusive")
+	}
+
+	if opts.formatTmpl != nil && workMode == "structs" {
+		cli.FatalError("--format applies to functions and cannot be used with --struct")
+	}
+
+	if opts.category != "" && workMode == "structs" {
+		cli.FatalError("--category applies to functions and cannot be used with --struct")
+	}
+
+	if opts.generators && workMode == "structs" {
+		cli.FatalError("--generators-only applies to functions and cannot be used with --struct")
+	}
+
+	if opts.deprecated && workMode == "structs" {
+		cli.FatalError("--deprecated-only applies to functions and cannot be used with --struct")
+	}
+
+	if opts.firstLine && workMode != "functions" {
+		cli.FatalError("--first-line applies to functions and cannot be used with --struct or --all")
+	}
+
+	if opts.noClasses && workMode != "functions" {
+		cli.FatalError("--no-classes cannot be used with --struct or --all")
+	}
+
+	if opts.maxParams >= 0 && workMode == "structs" {
+		cli.FatalError("--max-params applies to functions and cannot be used with --struct")
+	}
+
+	if opts.profileLangs && (opts.summaryOnly || opts.splitMode || opts.jsonStream || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "" || opts.firstLine) {
+		cli.FatalError("--profile-languages cannot be combined with --summary-only (which already has the breakdown), --split, --json-stream, --dot, --format, --annotate or --first-line")
+	}
+
+	if opts.visibility && (opts.summaryOnly || opts.profileLangs || opts.splitMode || opts.jsonStream || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "" || opts.firstLine) {
+		cli.FatalError("--visibility-summary replaces the listing and cannot be combined with --summary-only, --profile-languages, --split, --json-stream, --dot, --format, --annotate or --first-line")
 	}
+	if opts.visibility && (opts.exportedOnly || opts.category != "" || opts.generators || opts.maxParams >= 0) {
+		cli.FatalError("--visibility-summary counts every symbol and cannot be combined with --exported-only, --category, --generators-only or --max-params")
+	}
+
+	return workMode
+}
+
+func handleDirectoryMode(config internal.Config, dirPath string, opts dirOptions) {
+	workers, recursive, useGitignore := opts.workers, opts.recursive, opts.useGitignore
+	jsonOut := opts.jsonOut
+	splitMode, splitBy, outDir, incMode := opts.splitMode, opts.splitBy, opts.outDir, opts.incMode
+
+	// Проверяем существование директории
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			cli.FatalError("directory does not exist: %s", dirPath)
+		}
+		cli.FatalError("accessing directory: %v", err)
+	}
+
+	if !info.IsDir() {
+		cli.FatalError("path is not a directory: %s", dirPath)
+	}
+
+	workMode := resolveDirWorkMode(opts)
 
 	// Validate split parameters
 	if splitMode {
 		if !jsonOut {
-			internal.FatalError("--split requires --json output mode")
+			cli.FatalError("--split requires --json output mode")
 		}
 		if splitBy != "dir" && splitBy != "file" {
-			internal.FatalError("--split-by must be 'dir' or 'file'")
+			cli.FatalError("--split-by must be 'dir' or 'file'")
 		}
 		if incMode {
 			internal.InfoMessage("Incremental split mode enabled (--inc)")
 		}
 	}
 
+	if opts.indexPath != "" && (splitMode || opts.splitDir != "" || opts.jsonStream || opts.summaryOnly || opts.profileLangs || opts.visibility || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "" || opts.treeMode || opts.treeFull) {
+		cli.FatalError("--index writes a JSON file and cannot be combined with --split, --split-output, --json-stream, --summary-only, --profile-languages, --visibility-summary, --dot, --format, --annotate or --tree")
+	}
+
+	if opts.splitDir != "" && (splitMode || opts.jsonStream || opts.summaryOnly || opts.profileLangs || opts.visibility || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "" || opts.treeMode || opts.treeFull) {
+		cli.FatalError("--split-output writes JSON files and cannot be combined with --split, --json-stream, --summary-only, --profile-languages, --visibility-summary, --dot, --format, --annotate or --tree")
+	}
+
 	internal.InfoMessage("Scanning directory: %s (mode=%s, recursive=%v, workers=%d, gitignore=%v)", dirPath, workMode, recursive, workers, useGitignore)
 
 	// Создаем процессор директорий
 	processor := internal.NewDirProcessor(config, workers, recursive, useGitignore, workMode)
+	processor.SetExportedOnly(opts.exportedOnly)
+	processor.SetIgnoreFiles(opts.ignoreFiles)
+	processor.SetGitExcludes(opts.gitExcludes)
+	processor.SetMaxDepth(opts.maxDepth)
+	processor.SetTestFiles(opts.testFiles)
+	processor.SetWithDocs(opts.withDocs)
+	processor.SetNoClasses(opts.noClasses)
+	processor.SetExcludeAnonymous(opts.anonymous)
+	processor.SetIncludeMacros(opts.macros)
+	processor.SetMaxParams(opts.maxParams)
+	processor.SetDeprecatedOnly(opts.deprecated)
+	processor.SetSignatures(opts.formatTmpl != nil)
+	processor.SetVisibility(opts.visibility)
+	processor.SetGeneratedMarkers(opts.skipMarkers)
+
+	// --since: только файлы, изменённые относительно ref; вне git-репозитория — все
+	if opts.since != "" {
+		changed, err := internal.ChangedFiles(dirPath, opts.since)
+		if err != nil {
+			internal.WarnError("--since %s: %v; scanning all files", opts.since, err)
+		} else {
+			internal.InfoMessage("--since %s: %d changed file(s) in the repository", opts.since, len(changed))
+			processor.SetOnlyFiles(changed)
+		}
+	}
+
+	// --json-stream: результаты пишутся по мере готовности, без накопления
+	if opts.jsonStream {
+		if splitMode || opts.strict || opts.maxFuncs > 0 || opts.warnLen > 0 || opts.category != "" || opts.generators || opts.firstLine || opts.summaryOnly || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "" || opts.treeMode || opts.treeFull {
+			cli.FatalError("--json-stream cannot be combined with --split, --strict, --max-functions, --warn-length, --category, --generators-only, --first-line, --summary-only, --dot, --format, --annotate or --tree")
+		}
+		if err := processor.ProcessDirectoryStream(dirPath, os.Stdout, internal.FormatNDJSON); err != nil {
+			cli.FatalError("processing directory: %v", err)
+		}
+		return
+	}
 
 	// Обрабатываем директорию
 	var results []internal.DirResult
@@ -147,8 +676,13 @@ func handleDirectoryMode(config internal.Config, dirPath string, workers int, re
 		results, err = processor.ProcessDirectory(dirPath)
 	}
 	if err != nil {
-		internal.FatalError("processing directory: %v", err)
+		cli.FatalError("processing directory: %v", err)
 	}
+	reportDirAmbiguities(results, opts.strict)
+	oversized := internal.OversizedFiles(results, opts.maxFuncs)
+	filterDirCategory(results, opts.category)
+	filterDirGenerators(results, opts.generators)
+	attachDirFirstLines(results, opts.firstLine)
 
 	// Handle split output mode
 	if splitMode {
@@ -159,24 +693,409 @@ func handleDirectoryMode(config internal.Config, dirPath string, workers int, re
 			manifest, err = internal.WriteSplitOutput(results, outDir, dirPath, splitBy)
 		}
 		if err != nil {
-			internal.FatalError("writing split output: %v", err)
+			cli.FatalError("writing split output: %v", err)
+		}
+		fmt.Println(manifest)
+		reportOversizedFiles(oversized, opts)
+		reportDirLongFunctions(results, opts)
+		return
+	}
+
+	// --split-output: по JSON-файлу на исходник, дерево повторяет каталог
+	if opts.splitDir != "" {
+		written, err := internal.WriteMirroredOutput(results, opts.splitDir, dirPath)
+		if err != nil {
+			cli.FatalError("writing split output: %v", err)
+		}
+		fmt.Printf("Per-file output written to %s/: %d files\n", opts.splitDir, written)
+		reportOversizedFiles(oversized, opts)
+		reportDirLongFunctions(results, opts)
+		return
+	}
+
+	// --index: один плоский список всех символов для "перейти к символу"
+	if opts.indexPath != "" {
+		index, err := internal.BuildSymbolIndex(results, config, dirPath)
+		if err != nil {
+			cli.FatalError("building index: %v", err)
+		}
+		if err := internal.WriteSymbolIndex(index, opts.indexPath); err != nil {
+			cli.FatalError("writing index: %v", err)
+		}
+		fmt.Printf("Symbol index written to %s: %d symbols in %d files\n", opts.indexPath, index.TotalSymbols, index.TotalFiles)
+		reportOversizedFiles(oversized, opts)
+		reportDirLongFunctions(results, opts)
+		return
+	}
+
+	printDirResults(results, oversized, workMode, opts)
+	reportOversizedFiles(oversized, opts)
+	reportDirLongFunctions(results, opts)
+}
+
+// handleArchiveMode сканирует исходники внутри tar/tar.gz/zip архива
+func handleArchiveMode(config internal.Config, archivePath string, opts dirOptions) {
+	if !internal.IsArchive(archivePath) {
+		cli.FatalError("unsupported archive format: %s (expected .tar, .tar.gz, .tgz or .zip)", archivePath)
+	}
+	if opts.splitMode {
+		cli.FatalError("--split is not supported with --archive")
+	}
+	if opts.jsonStream {
+		cli.FatalError("--json-stream is supported in --dir mode only")
+	}
+	if opts.firstLine {
+		cli.FatalError("--first-line is not supported with --archive")
+	}
+
+	workMode := resolveDirWorkMode(opts)
+	internal.InfoMessage("Scanning archive: %s (mode=%s)", archivePath, workMode)
+
+	processor := internal.NewDirProcessor(config, opts.workers, true, false, workMode)
+	processor.SetExportedOnly(opts.exportedOnly)
+	processor.SetTestFiles(opts.testFiles)
+	processor.SetWithDocs(opts.withDocs)
+	processor.SetNoClasses(opts.noClasses)
+	processor.SetExcludeAnonymous(opts.anonymous)
+	processor.SetIncludeMacros(opts.macros)
+	processor.SetMaxParams(opts.maxParams)
+	processor.SetDeprecatedOnly(opts.deprecated)
+	processor.SetSignatures(opts.formatTmpl != nil)
+	processor.SetVisibility(opts.visibility)
+	processor.SetGeneratedMarkers(opts.skipMarkers)
+	results, err := processor.ProcessArchive(archivePath)
+	if err != nil {
+		cli.FatalError("processing archive: %v", err)
+	}
+	reportDirAmbiguities(results, opts.strict)
+	oversized := internal.OversizedFiles(results, opts.maxFuncs)
+	filterDirCategory(results, opts.category)
+	filterDirGenerators(results, opts.generators)
+
+	printDirResults(results, oversized, workMode, opts)
+	reportOversizedFiles(oversized, opts)
+	reportDirLongFunctions(results, opts)
+}
+
+// handleFilesMode обрабатывает явный список файлов (--inp a --inp b):
+// результаты объединяются и выводятся так же, как в режиме --dir
+func handleFilesMode(config internal.Config, files []string, source, linesRange string, opts dirOptions) {
+	if opts.splitMode || opts.jsonStream {
+		cli.FatalError("--split and --json-stream are supported in --dir mode only")
+	}
+	if linesRange != "" {
+		cli.FatalError("--lines is supported with a single --inp file only")
+	}
+	if source != "" {
+		if _, err := config.GetLanguageConfig(source); err != nil {
+			cli.FatalError("%v", err)
+		}
+	}
+	for _, f := range files {
+		if _, err := os.Stat(f); err != nil {
+			cli.FatalError("accessing file: %v", err)
+		}
+	}
+
+	workMode := resolveDirWorkMode(opts)
+	processor := internal.NewDirProcessor(config, opts.workers, false, false, workMode)
+	processor.SetExportedOnly(opts.exportedOnly)
+	processor.SetWithDocs(opts.withDocs)
+	processor.SetNoClasses(opts.noClasses)
+	processor.SetExcludeAnonymous(opts.anonymous)
+	processor.SetIncludeMacros(opts.macros)
+	processor.SetMaxParams(opts.maxParams)
+	processor.SetDeprecatedOnly(opts.deprecated)
+	processor.SetSignatures(opts.formatTmpl != nil)
+	processor.SetVisibility(opts.visibility)
+	processor.SetGeneratedMarkers(opts.skipMarkers)
+	results, err := processor.ProcessFiles(files, source)
+	if err != nil {
+		cli.FatalError("%v", err)
+	}
+	for _, r := range results {
+		if r.Error != nil {
+			cli.FatalError("%s: %v", r.Path, r.Error)
+		}
+	}
+	reportDirAmbiguities(results, opts.strict)
+	oversized := internal.OversizedFiles(results, opts.maxFuncs)
+	filterDirCategory(results, opts.category)
+	filterDirGenerators(results, opts.generators)
+	attachDirFirstLines(results, opts.firstLine)
+
+	printDirResults(results, oversized, workMode, opts)
+	reportOversizedFiles(oversized, opts)
+	reportDirLongFunctions(results, opts)
+}
+
+// applyExtraTypes регистрирует --extra-type виды типов для языка --source,
+// а без него — для всех языков
+func applyExtraTypes(config internal.Config, source string, specs []string) error {
+	for _, spec := range specs {
+		kind, pattern, err := internal.ParseExtraType(spec)
+		if err != nil {
+			return err
+		}
+		if source != "" {
+			langConfig, err := config.GetLanguageConfig(source)
+			if err != nil {
+				return err
+			}
+			if err := langConfig.AddStructPattern(kind, pattern); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, langConfig := range config {
+			if err := langConfig.AddStructPattern(kind, pattern); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyExtMap переопределяет соответствие расширений языкам (--ext-map)
+func applyExtMap(config internal.Config, spec string) error {
+	if spec == "" {
+		return nil
+	}
+	mapping, err := internal.ParseExtMap(spec)
+	if err != nil {
+		return err
+	}
+	for ext, lang := range mapping {
+		if err := config.MapExtension(ext, lang); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandInputs разворачивает значения --inp: значение, не являющееся
+// существующим файлом, разбивается по пробелам ("a.go b.go")
+func expandInputs(values []string) []string {
+	var files []string
+	for _, v := range values {
+		if _, err := os.Stat(v); err == nil {
+			files = append(files, v)
+			continue
+		}
+		files = append(files, strings.Fields(v)...)
+	}
+	return files
+}
+
+// readFilesFrom читает список путей --files-from из файла или из stdin ("-")
+func readFilesFrom(name string) []string {
+	r := os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			cli.FatalError("reading --files-from: %v", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	files, err := internal.ReadFileList(r)
+	if err != nil {
+		cli.FatalError("reading --files-from: %v", err)
+	}
+	if len(files) == 0 {
+		cli.FatalError("--files-from %s lists no files", name)
+	}
+	return files
+}
+
+// reportAmbiguities выводит неоднозначности разбора как предупреждения,
+// а в режиме --strict завершает работу с кодом 3
+func reportAmbiguities(filename string, ambiguities []internal.ParseAmbiguity, strict bool) {
+	if len(ambiguities) == 0 {
+		return
+	}
+	if strict {
+		cli.FatalErrorWithCode(3, "%s", internal.FormatAmbiguityReport(filename, ambiguities))
+	}
+	for _, a := range ambiguities {
+		internal.WarnError("%s:%d: %s", filename, a.Line, a.Message)
+	}
+}
+
+// reportDirAmbiguities собирает неоднозначности по всем файлам
+func reportDirAmbiguities(results []internal.DirResult, strict bool) {
+	var reports []string
+	for _, r := range results {
+		if len(r.Ambiguities) == 0 {
+			continue
+		}
+		if !strict {
+			reportAmbiguities(r.Path, r.Ambiguities, false)
+			continue
+		}
+		reports = append(reports, internal.FormatAmbiguityReport(r.Path, r.Ambiguities))
+	}
+	if len(reports) > 0 {
+		cli.FatalErrorWithCode(3, "%s", strings.Join(reports, "\n"))
+	}
+}
+
+// reportOversizedFiles предупреждает о файлах сверх --max-functions (в
+// --summary-only они уже в отчёте), а с --strict завершает работу с кодом 3
+func reportOversizedFiles(oversized []internal.OversizedFile, opts dirOptions) {
+	if len(oversized) == 0 {
+		return
+	}
+	if !opts.summaryOnly {
+		for _, f := range oversized {
+			internal.WarnError("%s: %d functions/types, more than --max-functions %d", f.Path, f.Count(), opts.maxFuncs)
+		}
+	}
+	if opts.strict {
+		cli.FatalErrorWithCode(3, "%d file(s) exceed --max-functions %d", len(oversized), opts.maxFuncs)
+	}
+}
+
+// reportLongFunctions предупреждает о функциях длиннее --warn-length,
+// а с --strict завершает работу с кодом 3
+func reportLongFunctions(long []internal.LongFunction, limit int, strict bool) {
+	for _, f := range long {
+		internal.WarnError("%s:%d: %s is %d lines, %d over --warn-length %d", f.Path, f.Start, f.Name, f.Length, f.Over, limit)
+	}
+	if strict && len(long) > 0 {
+		cli.FatalErrorWithCode(3, "%d function(s) exceed --warn-length %d", len(long), limit)
+	}
+}
+
+// reportNoAssertionTests предупреждает о тестах без утверждений (--assertions);
+// с --strict код выхода 3
+func reportNoAssertionTests(inp string, empty []internal.FunctionBounds, strict bool) {
+	for _, fn := range empty {
+		internal.WarnError("%s:%d: %s has no assertions", inp, fn.Start, fn.Name)
+	}
+	if strict && len(empty) > 0 {
+		cli.FatalErrorWithCode(3, "%d test function(s) without assertions", len(empty))
+	}
+}
+
+// reportDirLongFunctions проверяет --warn-length по всем файлам
+func reportDirLongFunctions(results []internal.DirResult, opts dirOptions) {
+	var long []internal.LongFunction
+	for _, r := range results {
+		long = append(long, internal.LongFunctions(r.Path, r.Functions, opts.warnLen)...)
+	}
+	reportLongFunctions(long, opts.warnLen, opts.strict)
+}
+
+// filterDirGenerators оставляет только генераторы (--generators-only)
+func filterDirGenerators(results []internal.DirResult, generatorsOnly bool) {
+	if !generatorsOnly {
+		return
+	}
+	for i := range results {
+		results[i].Functions = internal.FilterGenerators(results[i].Functions)
+	}
+}
+
+// attachDirFirstLines читает первые строки функций каждого файла (--first-line)
+func attachDirFirstLines(results []internal.DirResult, firstLine bool) {
+	if !firstLine {
+		return
+	}
+	for i := range results {
+		if results[i].Error != nil {
+			continue
+		}
+		if err := internal.ReadFirstLines(results[i].Path, results[i].Functions); err != nil {
+			cli.FatalError("%v", err)
+		}
+	}
+}
+
+// filterDirCategory оставляет только функции заданной категории (--category)
+func filterDirCategory(results []internal.DirResult, category string) {
+	if category == "" {
+		return
+	}
+	for i := range results {
+		results[i].Functions = internal.FilterByCategory(results[i].Functions, category)
+	}
+}
+
+// printDirResults выводит агрегированный результат и статистику;
+// oversized (--max-functions) попадают в отчёт --summary-only
+func printDirResults(results []internal.DirResult, oversized []internal.OversizedFile, workMode string, opts dirOptions) {
+	// --summary-only: только итоги и разбивка по языкам
+	if opts.summaryOnly {
+		summary := internal.SummarizeDirResults(results)
+		if opts.maxFuncs > 0 {
+			summary.MaxFunctions, summary.Oversized = opts.maxFuncs, oversized
+		}
+		fmt.Println(internal.FormatDirSummary(summary, opts.jsonOut))
+		return
+	}
+
+	// --visibility-summary: только счётчики public/private
+	if opts.visibility {
+		fmt.Println(internal.FormatVisibilitySummary(internal.SummarizeVisibility(results), opts.jsonOut))
+		return
+	}
+
+	if opts.dotMode {
+		fmt.Println(internal.FormatDirResultsDot(results))
+		return
+	}
+
+	if opts.annotate != "" {
+		if output := internal.FormatAnnotations(results, opts.annotate); output != "" {
+			fmt.Println(output)
+		}
+		return
+	}
+
+	if opts.formatTmpl != nil {
+		output, err := internal.FormatDirResultsTemplate(opts.formatTmpl, results)
+		if err != nil {
+			cli.FatalError("%v", err)
+		}
+		if output != "" {
+			fmt.Println(output)
+		}
+		return
+	}
+
+	if opts.firstLine && !opts.jsonOut {
+		if output := internal.FormatDirResultsFirstLines(results); output != "" {
+			fmt.Println(output)
 		}
-		fmt.Println(manifest)
 		return
 	}
 
 	// Выводим результат
-	output := internal.AggregateDirResults(results, jsonOut, treeMode, treeFull)
-	fmt.Println(output)
+	if opts.profileLangs && opts.jsonOut {
+		fmt.Println(internal.FormatDirResultsJSONWithLanguageStats(results))
+	} else if opts.noLangLabel && !opts.jsonOut && (opts.treeMode || opts.treeFull) {
+		fmt.Println(internal.FormatDirResultsTree(results, opts.treeFull, false))
+	} else {
+		fmt.Println(internal.AggregateDirResults(results, opts.jsonOut, opts.treeMode, opts.treeFull))
+	}
+	if opts.profileLangs && !opts.jsonOut {
+		// the listing already ends with a newline: one blank line before the table
+		fmt.Println(internal.FormatLanguageStats(results))
+	}
 
 	// Статистика
 	totalFuncs := 0
 	totalClasses := 0
-	totalFiles := len(results)
+	generated := internal.CountGenerated(results)
+	totalFiles := len(results) - generated
 	for _, r := range results {
 		totalFuncs += len(r.Functions)
 		totalClasses += len(r.Classes)
 	}
+	if generated > 0 {
+		internal.InfoMessage("Skipped %d generated file(s)", generated)
+	}
 
 	if workMode == "all" || workMode == "structs" {
 		internal.InfoMessage("Processed %d files, found %d functions, %d classes/types", totalFiles, totalFuncs, totalClasses)
@@ -185,24 +1104,94 @@ func handleDirectoryMode(config internal.Config, dirPath string, workers int, re
 	}
 }
 
-func handleFileMode(config internal.Config, inp, source, funcStr, typeStr string, structMode, allMode, mapMode, treeMode, treeFull, jsonOut, extract, rawMode bool, linesRange string) {
+// fileOptions bundles the flags of single-file (--inp) mode
+type fileOptions struct {
+	inp        string
+	source     string
+	funcStr    string
+	typeStr    string
+	structMode bool
+	allMode    bool
+	mapMode    bool
+	treeMode   bool
+	treeFull   bool
+	unified    bool
+	jsonOut    bool
+	scopePaths bool
+	envelope   bool
+	directives bool
+	changedVs  string
+	extract    bool
+	imports    bool
+	visibility bool
+	selector   *internal.Selector
+	sortOrder  string
+	top        int
+	byClass    bool
+	flatten    bool
+	rawMode    bool
+	linesRange string
+	snapLines  bool
+	dotMode    bool
+	formatTmpl *template.Template
+	annotate   internal.AnnotateStyle
+	category   string
+	generators bool
+	deprecated bool
+	strict     bool
+	exported   bool
+	withDocs   bool
+	stats      bool
+	firstLine  bool
+	noClasses  bool
+	anonymous  bool
+	macros     bool
+	maxParams  int
+	warnLen    int
+	returns    bool
+	byteOffs   bool
+	literals   bool
+	assertions bool
+	assertPat  string
+	parallel   bool
+	workers    int
+	fieldTypes bool
+	typesOnly  bool
+	fieldDepth int
+	methods    bool
+}
+
+func handleFileMode(config internal.Config, opts fileOptions) {
+	inp, source, funcStr, typeStr := opts.inp, opts.source, opts.funcStr, opts.typeStr
+	structMode, allMode, mapMode, treeMode, treeFull := opts.structMode, opts.allMode, opts.mapMode, opts.treeMode, opts.treeFull
+	jsonOut, extract, linesRange := opts.jsonOut, opts.extract, opts.linesRange
+
+	// Без --source язык определяется по расширению или shebang-строке
+	if source == "" && linesRange == "" {
+		if langConfig := config.DetectLanguage(inp); langConfig != nil {
+			source = langConfig.LangKey
+		} else {
+			source = internal.DetectDataFormat(inp)
+		}
+	}
+
 	// --source не обязателен если используется только --lines (standalone mode)
 	standaloneLines := linesRange != "" && source == ""
 
 	if source == "" && !standaloneLines {
-		internal.FatalError("--source parameter is required (or use --lines alone for plain text extraction)")
+		cli.FatalError("--source parameter is required: language of %s is not detected by extension or shebang (or use --lines alone for plain text extraction)", inp)
 	}
 
 	// Standalone --lines mode: просто вывести строки без парсинга
 	if standaloneLines {
 		lineRange, err := internal.ParseLineRange(linesRange)
 		if err != nil {
-			internal.FatalError("parsing line range: %v", err)
+			cli.FatalError("parsing line range: %v", err)
 		}
 
 		lines, startLine, err := internal.ReadFileLines(inp, lineRange)
 		if err != nil {
-			internal.FatalError("reading lines: %v", err)
+			cli.FatalError("reading lines: %v", err)
 		}
 
 		// JSON output или plain
@@ -214,10 +1203,22 @@ func handleFileMode(config internal.Config, inp, source, funcStr, typeStr string
 		os.Exit(0)
 	}
 
+	// YAML/JSON/TOML: ключи документа выводятся как типы (--struct подразумевается)
+	if internal.IsDataFormat(source) {
+		processDataKeys(source, opts)
+		return
+	}
+
+	// --directives: директивы читаются из исходных строк, без парсинга функций
+	if opts.directives {
+		printDirectives(inp, source, opts)
+		return
+	}
+
 	// Валидация режимов работы
 	workMode := "functions"
 	if structMode && allMode {
-		internal.FatalError("--struct and --all are mutually exclusive")
+		cli.FatalError("--struct and --all are mutually exclusive")
 	}
 	if structMode {
 		workMode = "structs"
@@ -228,69 +1229,222 @@ func handleFileMode(config internal.Config, inp, source, funcStr, typeStr string
 	// Взаимоисключающие режимы
 	if workMode == "functions" {
 		if funcStr == "" && !mapMode && !treeMode && !treeFull {
-			internal.FatalError("either --func, --map, or --tree must be specified")
+			cli.FatalError("either --func, --map, or --tree must be specified")
 		}
 		if funcStr != "" && (mapMode || treeMode || treeFull) {
-			internal.FatalError("--func is mutually exclusive with --map and --tree")
+			cli.FatalError("--func is mutually exclusive with --map and --tree")
 		}
 		if typeStr != "" {
-			internal.FatalError("--type can only be used with --struct or --all")
+			cli.FatalError("--type can only be used with --struct or --all")
 		}
 	} else if workMode == "structs" {
 		if typeStr == "" && !mapMode && !treeMode && !treeFull && !extract {
-			internal.FatalError("either --type, --map, --tree, or --extract must be specified with --struct")
+			cli.FatalError("either --type, --map, --tree, or --extract must be specified with --struct")
 		}
 		if typeStr != "" && (mapMode || treeMode || treeFull) {
-			internal.FatalError("--type is mutually exclusive with --map and --tree")
+			cli.FatalError("--type is mutually exclusive with --map and --tree")
 		}
 		if funcStr != "" {
-			internal.FatalError("--func cannot be used with --struct")
+			cli.FatalError("--func cannot be used with --struct")
 		}
 	} else if workMode == "all" {
-		if !mapMode && !treeMode && !treeFull && !jsonOut {
-			internal.FatalError("--all requires --map, --tree, or --json output mode")
+		if !mapMode && !treeMode && !treeFull && !jsonOut && !opts.unified {
+			cli.FatalError("--all requires --map, --tree, or --json output mode")
 		}
 		if funcStr != "" || typeStr != "" {
-			internal.FatalError("--func and --type cannot be used with --all (use --map instead)")
+			cli.FatalError("--func and --type cannot be used with --all (use --map instead)")
 		}
 	}
 
 	if treeMode && treeFull {
-		internal.FatalError("--tree and --tree-full are mutually exclusive")
+		cli.FatalError("--tree and --tree-full are mutually exclusive")
+	}
+
+	if opts.unified && workMode != "all" {
+		cli.FatalError("--unified-tree requires --all")
+	}
+
+	if opts.formatTmpl != nil && workMode != "functions" {
+		cli.FatalError("--format applies to functions and cannot be used with --struct or --all")
+	}
+
+	if opts.warnLen > 0 && workMode != "functions" {
+		cli.FatalError("--warn-length applies to functions and cannot be used with --struct or --all")
+	}
+
+	if (opts.sortOrder != "" || opts.top > 0) && workMode != "functions" {
+		cli.FatalError("--sort and --top apply to functions and cannot be used with --struct or --all")
+	}
+
+	if opts.byClass && (workMode != "functions" || opts.noClasses) {
+		cli.FatalError("--group-by-class groups functions by class and cannot be used with --struct, --all or --no-classes")
+	}
+	if opts.byClass && (jsonOut || extract || treeMode || treeFull || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "" || opts.stats || opts.firstLine) {
+		cli.FatalError("--group-by-class is a text listing and cannot be combined with --json, --extract, --tree, --tree-full, --dot, --format, --annotate, --stats or --first-line")
+	}
+
+	if opts.flatten && (workMode != "functions" || extract || treeMode || treeFull || opts.byClass || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "" || opts.stats || opts.firstLine || opts.envelope) {
+		cli.FatalError("--flatten lists functions and cannot be combined with --struct, --all, --extract, --tree, --tree-full, --group-by-class, --dot, --format, --annotate, --stats, --first-line or --envelope")
+	}
+
+	if opts.envelope && (!jsonOut || workMode != "functions" || extract || opts.visibility || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "") {
+		cli.FatalError("--envelope requires --json with functions output (not --struct/--all, --extract, --visibility-summary, --dot, --format or --annotate)")
+	}
+
+	if opts.selector != nil && workMode != "functions" {
+		cli.FatalError("--get selects functions and cannot be used with --struct or --all")
+	}
+
+	if opts.visibility && workMode != "functions" {
+		cli.FatalError("--visibility-summary cannot be used with --struct or --all in --inp mode")
+	}
+	if opts.visibility && (opts.exported || opts.category != "" || opts.generators || opts.maxParams >= 0 || opts.selector != nil) {
+		cli.FatalError("--visibility-summary counts every symbol and cannot be combined with --exported-only, --category, --generators-only, --max-params or --get")
+	}
+
+	if opts.imports && (!extract || workMode != "functions") {
+		cli.FatalError("--extract-with-imports requires --extract and applies to functions only")
+	}
+
+	if opts.category != "" && workMode == "structs" {
+		cli.FatalError("--category applies to functions and cannot be used with --struct")
+	}
+
+	if opts.generators && workMode == "structs" {
+		cli.FatalError("--generators-only applies to functions and cannot be used with --struct")
+	}
+
+	if opts.deprecated && workMode == "structs" {
+		cli.FatalError("--deprecated-only applies to functions and cannot be used with --struct")
+	}
+
+	if opts.stats && workMode != "functions" {
+		cli.FatalError("--stats applies to functions and cannot be used with --struct or --all")
+	}
+
+	if opts.firstLine && workMode != "functions" {
+		cli.FatalError("--first-line applies to functions and cannot be used with --struct or --all")
+	}
+
+	if opts.noClasses && workMode != "functions" {
+		cli.FatalError("--no-classes cannot be used with --struct or --all")
+	}
+
+	if opts.maxParams >= 0 && workMode == "structs" {
+		cli.FatalError("--max-params applies to functions and cannot be used with --struct")
+	}
+
+	if opts.parallel && (workMode != "functions" || linesRange != "") {
+		cli.FatalError("--parallel-file scans functions of the whole file and cannot be used with --struct, --all or --lines")
+	}
+
+	if opts.scopePaths && (workMode != "functions" || extract || opts.visibility || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "") {
+		cli.FatalError("--context-json is JSON output of functions and cannot be used with --struct, --all, --extract, --visibility-summary, --dot, --format or --annotate")
+	}
+
+	if opts.returns && (!jsonOut || workMode != "functions" || extract || opts.selector != nil || opts.visibility || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "") {
+		cli.FatalError("--returns is emitted as \"return_count\" and requires --json with functions output (not --struct/--all, --extract, --get, --visibility-summary, --dot, --format or --annotate)")
+	}
+	if opts.byteOffs && (!jsonOut || workMode != "functions" || extract || opts.visibility || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "") {
+		cli.FatalError("--byte-offsets is emitted as \"byte_start\"/\"byte_end\" and requires --json with functions output (not --struct/--all, --extract, --visibility-summary, --dot, --format or --annotate)")
+	}
+	if opts.literals && (!jsonOut || workMode != "functions" || extract || opts.visibility || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "") {
+		cli.FatalError("--literals is emitted as \"string_literals\"/\"numeric_literals\" and requires --json with functions output (not --struct/--all, --extract, --visibility-summary, --dot, --format or --annotate)")
+	}
+	if opts.assertPat != "" && !opts.assertions {
+		cli.FatalError("--assertion-pattern requires --assertions")
+	}
+	if opts.assertions && (workMode != "functions" || opts.visibility || opts.dotMode || opts.formatTmpl != nil || opts.annotate != "" || opts.stats || opts.firstLine || opts.byClass || opts.flatten) {
+		cli.FatalError("--assertions reports test functions and cannot be combined with --struct, --all, --visibility-summary, --dot, --format, --annotate, --stats, --first-line, --group-by-class or --flatten")
+	}
+
+	if (opts.fieldTypes || opts.typesOnly) && workMode != "structs" {
+		cli.FatalError("--field-types-only and --types-only require --struct")
+	}
+	if opts.fieldTypes && opts.typesOnly {
+		cli.FatalError("--field-types-only and --types-only are mutually exclusive")
+	}
+	if opts.fieldDepth < 0 {
+		cli.FatalError("--fields-depth must be positive, got %d", opts.fieldDepth)
+	}
+	if opts.methods && (workMode != "structs" || opts.fieldTypes || opts.typesOnly || opts.dotMode || opts.annotate != "") {
+		cli.FatalError("--with-methods requires --struct and cannot be combined with --field-types-only, --types-only, --dot or --annotate")
+	}
+	if opts.fieldDepth > 0 && workMode != "structs" && workMode != "all" {
+		cli.FatalError("--fields-depth requires --struct or --all")
 	}
 
 	// Получаем конфигурацию для выбранного языка
 	langConfig, err := config.GetLanguageConfig(source)
 	if err != nil {
-		internal.FatalError("%v\nSupported languages: %s", err, strings.Join(config.GetSupportedLanguages(), ", "))
+		cli.FatalError("%v\nSupported languages: %s", err, strings.Join(config.GetSupportedLanguages(), ", "))
+	}
+
+	// --assertions: шаблон вызова утверждения из languages.json или --assertion-pattern
+	if opts.assertPat != "" {
+		if err := langConfig.SetAssertionPattern(opts.assertPat); err != nil {
+			cli.FatalError("%v", err)
+		}
+	}
+	if opts.assertions && langConfig.AssertionRegex() == nil {
+		cli.FatalError("--assertions is not supported for %s: it has no assertion_pattern (use --assertion-pattern)", langConfig.Name)
 	}
 
 	// Определяем режим работы
 	mode := "func"
-	if mapMode || treeMode || treeFull {
+	if mapMode || treeMode || treeFull || opts.unified {
 		mode = "map"
 	}
 
-	// Для --tree-full нужны тела функций/типов
-	extractMode := extract || treeFull
+	// --changed-functions: тела функций сравниваются со старой версией файла
+	if opts.changedVs != "" {
+		printChangedFunctions(langConfig, mode, opts)
+		return
+	}
+
+	// Для --tree-full (и .Signature в --format, --stats, --returns) нужны тела функций/типов
+	extractMode := extract || treeFull || opts.formatTmpl != nil || opts.stats || opts.returns
 
 	// Обработка в зависимости от workMode
 	switch workMode {
 	case "functions":
-		processFunctions(langConfig, funcStr, mode, extractMode, rawMode, inp, linesRange, mapMode, treeMode, treeFull, jsonOut, extract)
+		processFunctions(langConfig, mode, extractMode, opts)
 
 	case "structs":
-		processStructs(langConfig, typeStr, mode, extractMode, inp, linesRange, mapMode, treeMode, treeFull, jsonOut, extract)
+		processStructs(langConfig, mode, extractMode, opts)
 
 	case "all":
-		processAll(langConfig, mode, extractMode, rawMode, inp, linesRange, mapMode, treeMode, treeFull, jsonOut, extract)
+		processAll(langConfig, mode, extractMode, opts)
 	}
 }
 
 // processFunctions обрабатывает режим поиска функций (по умолчанию)
-func processFunctions(langConfig *internal.LanguageConfig, funcStr, mode string, extractMode, rawMode bool, inp, linesRange string, mapMode, treeMode, treeFull, jsonOut, extract bool) {
-	// Создаем подходящий парсер в зависимости от языка
+// newFunctionFinder создает парсер функций с настройками --no-classes,
+// --exclude-anonymous, --include-macros и пометкой генераторов
+func newFunctionFinder(langConfig *internal.LanguageConfig, funcStr, mode string, extractMode, rawMode bool, opts fileOptions) internal.LanguageFinder {
 	finder := internal.CreateFinder(langConfig, funcStr, mode, extractMode, rawMode)
+	if skipper, ok := finder.(internal.ClassSkipper); ok {
+		skipper.SetNoClasses(opts.noClasses)
+	}
+	if skipper, ok := finder.(internal.AnonymousSkipper); ok {
+		skipper.SetExcludeAnonymous(opts.anonymous)
+	}
+	if macroFinder, ok := finder.(internal.MacroFinder); ok {
+		macroFinder.SetIncludeMacros(opts.macros)
+	}
+	// IsGenerator читают только --generators-only и JSON-вывод
+	if skipper, ok := finder.(internal.GeneratorSkipper); ok {
+		skipper.SetSkipGenerators(!opts.generators && !opts.jsonOut)
+	}
+	return finder
+}
+
+func processFunctions(langConfig *internal.LanguageConfig, mode string, extractMode bool, opts fileOptions) {
+	funcStr, rawMode, inp, linesRange := opts.funcStr, opts.rawMode, opts.inp, opts.linesRange
+	mapMode, treeMode, treeFull, jsonOut, extract := opts.mapMode, opts.treeMode, opts.treeFull, opts.jsonOut, opts.extract
+
+	// Создаем подходящий парсер в зависимости от языка
+	finder := newFunctionFinder(langConfig, funcStr, mode, extractMode, rawMode, opts)
 
 	var result *internal.FindResult
 	var err error
@@ -299,7 +1453,7 @@ func processFunctions(langConfig *internal.LanguageConfig, funcStr, mode string,
 	if linesRange != "" {
 		lineRange, err := internal.ParseLineRange(linesRange)
 		if err != nil {
-			internal.FatalError("parsing line range: %v", err)
+			cli.FatalError("parsing line range: %v", err)
 		}
 
 		// Для Python: умный анализ scope областей видимости
@@ -307,7 +1461,7 @@ func processFunctions(langConfig *internal.LanguageConfig, funcStr, mode string,
 			// Pass 1: Анализ scope областей видимости
 			scopes, err := internal.AnalyzePythonScopes(inp)
 			if err != nil {
-				internal.FatalError("analyzing Python scopes: %v", err)
+				cli.FatalError("analyzing Python scopes: %v", err)
 			}
 
 			// Pass 2: Валидация и коррекция диапазона
@@ -323,11 +1477,27 @@ func processFunctions(langConfig *internal.LanguageConfig, funcStr, mode string,
 			lineRange.Start = fixedStart
 			lineRange.End = fixedEnd
 			linesRange = fmt.Sprintf("%d:%d", fixedStart, fixedEnd)
+		} else if opts.snapLines {
+			// --snap-lines: границы функций по всему файлу, затем расширение диапазона
+			mapFinder := internal.CreateFinder(langConfig, "", "map", false, rawMode)
+			fullResult, err := mapFinder.FindFunctions(inp)
+			if err != nil {
+				cli.FatalError("%v", err)
+			}
+
+			fixedStart, fixedEnd, adjustments := internal.ValidateBraceLineRange(fullResult.Functions, lineRange.Start, lineRange.End)
+			if len(adjustments) > 0 {
+				report := internal.FormatBraceLineAdjustmentReport(adjustments, lineRange.Start, lineRange.End, fixedStart, fixedEnd)
+				fmt.Println(report)
+			}
+
+			lineRange.Start = fixedStart
+			lineRange.End = fixedEnd
 		}
 
 		lines, startLine, err := internal.ReadFileLines(inp, lineRange)
 		if err != nil {
-			internal.FatalError("reading lines: %v", err)
+			cli.FatalError("reading lines: %v", err)
 		}
 
 		// Предупреждение: --lines может разрезать тела функций
@@ -341,13 +1511,13 @@ func processFunctions(langConfig *internal.LanguageConfig, funcStr, mode string,
 		if stdFinder, ok := finder.(*internal.Finder); ok {
 			result, err = stdFinder.FindFunctionsInLines(lines, startLine, inp)
 			if err != nil {
-				internal.FatalError("%v", err)
+				cli.FatalError("%v", err)
 			}
 		} else {
 			// Python finder - используем тот же метод для консистентности
 			result, err = finder.FindFunctions(inp)
 			if err != nil {
-				internal.FatalError("%v", err)
+				cli.FatalError("%v", err)
 			}
 			// Фильтруем результаты по запрошенному диапазону
 			filtered := make([]internal.FunctionBounds, 0)
@@ -358,32 +1528,192 @@ func processFunctions(langConfig *internal.LanguageConfig, funcStr, mode string,
 			}
 			result.Functions = filtered
 		}
+	} else if opts.parallel {
+		// --parallel-file: регионы верхнего уровня разбираются параллельно
+		stdFinder, ok := finder.(*internal.Finder)
+		if !ok || !internal.ParallelFileSupported(langConfig) {
+			cli.FatalError("--parallel-file is not supported for %s: it needs a brace language without nested functions", langConfig.Name)
+		}
+		result, err = stdFinder.FindFunctionsParallel(readAllLines(inp), inp, opts.workers)
+		if err != nil {
+			cli.FatalError("%v", err)
+		}
 	} else {
 		// Standard mode: read entire file
 		result, err = finder.FindFunctions(inp)
 		if err != nil {
-			internal.FatalError("%v", err)
+			cli.FatalError("%v", err)
+		}
+	}
+
+	reportAmbiguities(inp, result.Ambiguities, opts.strict)
+
+	// --visibility-summary: счётчики public/private вместо списка
+	if opts.visibility {
+		checker := newExportChecker(langConfig, inp)
+		fmt.Println(internal.FormatVisibilitySummary(internal.CountVisibility(result.Functions, result.Classes, checker), jsonOut))
+		return
+	}
+
+	// --get: функции по пути Class/method (все перегрузки)
+	if opts.selector != nil {
+		result.Functions = internal.FilterBySelector(result.Functions, *opts.selector)
+	}
+
+	// --category: только init/test/benchmark/example/fuzz функции
+	if opts.category != "" {
+		result.Functions = internal.FilterByCategory(result.Functions, opts.category)
+	}
+	if opts.generators {
+		result.Functions = internal.FilterGenerators(result.Functions)
+	}
+	if opts.deprecated {
+		internal.AttachDeprecated(result.Functions, readAllLines(inp), langConfig)
+		result.Functions = internal.FilterDeprecated(result.Functions)
+	}
+
+	// --exported-only: только публичные функции
+	if opts.exported {
+		result.Functions = internal.FilterExportedFunctions(result.Functions, newExportChecker(langConfig, inp))
+	}
+
+	// --with-docs: документирующие комментарии над функциями
+	if opts.withDocs {
+		internal.AttachDocs(result.Functions, readAllLines(inp), langConfig)
+	}
+
+	// --stats: строки кода/комментариев/пустые по телу функции
+	if opts.stats {
+		internal.AttachLineStats(result.Functions, langConfig)
+	}
+
+	// --max-params: только функции с длинным списком параметров
+	if opts.maxParams >= 0 {
+		internal.AttachParamCounts(result.Functions, readAllLines(inp), langConfig)
+		result.Functions = internal.FilterByMaxParams(result.Functions, opts.maxParams)
+	}
+
+	// --returns: число возвращаемых значений (Python — по return в теле)
+	if opts.returns {
+		internal.AttachReturnCounts(result.Functions, readAllLines(inp), langConfig)
+	}
+
+	// --context-json: путь вложенности через точку (Python — по AnalyzePythonScopes)
+	if opts.scopePaths {
+		if langConfig.LangKey == "py" {
+			scopes, err := internal.AnalyzePythonScopes(inp)
+			if err != nil {
+				cli.FatalError("analyzing Python scopes: %v", err)
+			}
+			internal.AttachPythonScopePaths(result.Functions, scopes)
+		} else {
+			internal.AttachScopePaths(result.Functions, readAllLines(inp), langConfig)
+		}
+	}
+
+	// --assertions: только тесты, с числом утверждений в теле
+	if opts.assertions {
+		lines := readAllLines(inp)
+		result.Functions = append(internal.FilterTestFunctions(result.Functions, langConfig), internal.FindTestBlocks(lines, langConfig)...)
+		internal.SortFunctions(result.Functions, internal.SortLine)
+		internal.AttachAssertionCounts(result.Functions, lines, langConfig)
+	}
+
+	// --literals: строковые и числовые литералы в теле функции
+	if opts.literals {
+		internal.AttachLiteralCounts(result.Functions, readAllLines(inp), langConfig)
+	}
+
+	// --byte-offsets: смещения в байтах от начала файла
+	if opts.byteOffs {
+		data, err := os.ReadFile(inp)
+		if err != nil {
+			cli.FatalError("reading file: %v", err)
 		}
+		internal.AttachByteOffsets(result.Functions, data)
+	}
+
+	// --first-line: исходная первая строка каждой функции
+	if opts.firstLine {
+		internal.AttachFirstLines(result.Functions, readAllLines(inp))
+	}
+
+	// --flatten: замыкания в телах и глубина вложенности каждой функции
+	if opts.flatten {
+		result.Functions = internal.FlattenFunctions(result.Functions, readAllLines(inp), langConfig)
+	}
+
+	// --warn-length: функции длиннее N строк (предупреждения после вывода)
+	long := internal.LongFunctions(inp, result.Functions, opts.warnLen)
+
+	// --sort / --top: порядок вывода и N первых функций
+	if opts.sortOrder != "" {
+		internal.SortFunctions(result.Functions, opts.sortOrder)
+	}
+	result.Functions = internal.TopFunctions(result.Functions, opts.top)
+
+	// --extract-with-imports: блок импортов файла перед телом каждой функции
+	if opts.imports {
+		internal.AttachImports(result.Functions, internal.ImportBlock(readAllLines(inp), langConfig))
 	}
 
 	// Если ничего не найдено
 	if len(result.Functions) == 0 {
-		if mapMode || treeMode || treeFull {
-			internal.FatalErrorWithCode(2, "No functions found in file")
+		if opts.selector != nil {
+			cli.FatalErrorWithCode(2, "No function matches --get selector")
+		} else if opts.assertions {
+			cli.FatalErrorWithCode(2, "No test functions found in file")
+		} else if mapMode || treeMode || treeFull {
+			cli.FatalErrorWithCode(2, "No functions found in file")
 		} else {
-			internal.FatalErrorWithCode(2, "Specified functions not found")
+			cli.FatalErrorWithCode(2, "Specified functions not found")
 		}
 	}
 
 	// Форматируем и выводим результат
 	var output string
-	if extract {
+	if opts.dotMode {
+		output = internal.FormatDot(result)
+	} else if opts.annotate != "" {
+		output = internal.FormatAnnotations([]internal.DirResult{{Path: inp, Functions: result.Functions}}, opts.annotate)
+	} else if opts.formatTmpl != nil {
+		output, err = internal.FormatWithTemplate(opts.formatTmpl, result)
+		if err != nil {
+			cli.FatalError("%v", err)
+		}
+	} else if opts.stats && !jsonOut {
+		output = internal.FormatLineStats(result)
+	} else if opts.assertions && !jsonOut && !extract {
+		output = internal.FormatAssertions(result)
+	} else if opts.firstLine && !jsonOut {
+		output = internal.FormatFirstLines(result)
+	} else if opts.flatten && jsonOut {
+		output, err = internal.FormatFlatJSON(result)
+		if err != nil {
+			cli.FatalError("formatting output: %v", err)
+		}
+	} else if opts.flatten {
+		output = internal.FormatFlat(result)
+	} else if opts.selector != nil && jsonOut {
+		output, err = internal.FormatSelectedJSON(result)
+		if err != nil {
+			cli.FatalError("formatting output: %v", err)
+		}
+	} else if extract {
 		output = internal.FormatExtract(result)
+	} else if jsonOut && opts.sortOrder != "" {
+		// --sort --json: массив в порядке сортировки
+		output, err = internal.FormatJSONOrdered(result)
+		if err != nil {
+			cli.FatalError("formatting output: %v", err)
+		}
 	} else if jsonOut {
 		output, err = internal.FormatJSON(result)
 		if err != nil {
-			internal.FatalError("formatting output: %v", err)
+			cli.FatalError("formatting output: %v", err)
 		}
+	} else if opts.byClass {
+		output = internal.FormatGroupedByClass(result)
 	} else if treeMode {
 		output = internal.FormatTreeCompact(result)
 	} else if treeFull {
@@ -392,14 +1722,56 @@ func processFunctions(langConfig *internal.LanguageConfig, funcStr, mode string,
 		output = internal.FormatGrepStyle(result)
 	}
 
+	// --envelope: JSON с метаданными о парсере
+	if opts.envelope {
+		output, err = internal.WrapEnvelope(output, result, langConfig, time.Now())
+		if err != nil {
+			cli.FatalError("formatting output: %v", err)
+		}
+	}
+
 	fmt.Println(output)
+	reportLongFunctions(long, opts.warnLen, opts.strict)
+	if opts.assertions {
+		reportNoAssertionTests(inp, internal.NoAssertionTests(result.Functions), opts.strict)
+	}
+}
+
+// newExportChecker читает файл целиком для определения видимости (--exported-only)
+func newExportChecker(langConfig *internal.LanguageConfig, inp string) *internal.ExportChecker {
+	return internal.NewExportChecker(langConfig, readAllLines(inp))
+}
+
+// readAllLines читает файл целиком (для пост-обработки результатов)
+func readAllLines(inp string) []string {
+	lines, _, err := internal.ReadFileLines(inp, internal.LineRange{Start: 1, End: -1})
+	if err != nil {
+		cli.FatalError("reading file: %v", err)
+	}
+	return lines
+}
+
+// applyFieldsDepth включает рекурсию во вложенные структуры (--fields-depth);
+// она есть только у finder'а скобочных языков
+func applyFieldsDepth(structFinder internal.StructFinderInterface, langConfig *internal.LanguageConfig, depth int) {
+	if depth == 0 {
+		return
+	}
+	braceFinder, ok := structFinder.(*internal.StructFinder)
+	if !ok || langConfig.IndentBased || langConfig.BlockEndKeyword != "" {
+		cli.FatalError("--fields-depth is not supported for %s: it needs a brace language with nested struct types", langConfig.Name)
+	}
+	braceFinder.SetFieldsDepth(depth)
 }
 
 // processStructs обрабатывает режим поиска структур/классов (--struct)
-func processStructs(langConfig *internal.LanguageConfig, typeStr, mode string, extractMode bool, inp, linesRange string, mapMode, treeMode, treeFull, jsonOut, extract bool) {
+func processStructs(langConfig *internal.LanguageConfig, mode string, extractMode bool, opts fileOptions) {
+	typeStr, inp, linesRange := opts.typeStr, opts.inp, opts.linesRange
+	mapMode, treeMode, treeFull, extract := opts.mapMode, opts.treeMode, opts.treeFull, opts.extract
+
 	// Проверяем поддержку struct patterns
 	if !langConfig.HasStructSupport() {
-		internal.FatalError("Language %s does not have struct/type pattern support", langConfig.Name)
+		cli.FatalError("Language %s does not have struct/type pattern support", langConfig.Name)
 	}
 
 	// Создаем struct finder через фабрику
@@ -408,43 +1780,83 @@ func processStructs(langConfig *internal.LanguageConfig, typeStr, mode string, e
 	findAll := mapMode || treeMode || treeFull || (typeStr == "" && extract)
 	factory := internal.NewStructFinderFactory()
 	structFinder := factory.CreateStructFinder(langConfig, typeStr, findAll, extractMode)
+	applyFieldsDepth(structFinder, langConfig, opts.fieldDepth)
 
 	var result *internal.StructFindResult
 	var err error
 
 	// Для struct mode пока не поддерживаем --lines
 	if linesRange != "" {
-		internal.FatalError("--lines is not yet supported with --struct mode")
+		cli.FatalError("--lines is not yet supported with --struct mode")
 	}
 
 	// Находим типы в файле
 	result, err = structFinder.FindStructures(inp)
 	if err != nil {
-		internal.FatalError("%v", err)
+		cli.FatalError("%v", err)
+	}
+	if opts.exported {
+		result.Types = internal.FilterExportedTypes(result.Types, newExportChecker(langConfig, inp))
+	}
+
+	// --with-methods: методы типа - функции, чей класс совпадает с его именем
+	if opts.methods {
+		funcResult, err := newFunctionFinder(langConfig, "", "map", false, opts.rawMode, opts).FindFunctions(inp)
+		if err != nil {
+			cli.FatalError("finding functions: %v", err)
+		}
+		internal.AttachMethods(result.Types, funcResult.Functions)
 	}
+	printStructs(result, opts)
+}
+
+// printStructs выводит найденные типы в формате, выбранном флагами
+func printStructs(result *internal.StructFindResult, opts fileOptions) {
+	inp := opts.inp
+	mapMode, treeMode, treeFull, jsonOut, extract := opts.mapMode, opts.treeMode, opts.treeFull, opts.jsonOut, opts.extract
+	var err error
 
 	// Если ничего не найдено
 	if len(result.Types) == 0 {
 		if mapMode || treeMode || treeFull {
-			internal.FatalErrorWithCode(2, "No types found in file")
+			cli.FatalErrorWithCode(2, "No types found in file")
 		} else {
-			internal.FatalErrorWithCode(2, "Specified types not found")
+			cli.FatalErrorWithCode(2, "Specified types not found")
 		}
 	}
 
 	// Форматируем и выводим результат
 	var output string
-	if extract {
+	if opts.dotMode {
+		output = internal.FormatStructDot(result)
+	} else if opts.annotate != "" {
+		types := internal.MergeStructClasses(&internal.FindResult{}, result)
+		output = internal.FormatAnnotations([]internal.DirResult{{Path: inp, Classes: types.Classes}}, opts.annotate)
+	} else if opts.fieldTypes && jsonOut {
+		output, err = internal.FormatStructFieldTypesJSON(result)
+		if err != nil {
+			cli.FatalError("formatting output: %v", err)
+		}
+	} else if opts.fieldTypes {
+		output = internal.FormatStructFieldTypes(result)
+	} else if opts.typesOnly && jsonOut {
+		output, err = internal.FormatStructTypesOnlyJSON(result)
+		if err != nil {
+			cli.FatalError("formatting output: %v", err)
+		}
+	} else if opts.typesOnly {
+		output = internal.FormatStructTypesOnly(result)
+	} else if extract {
 		// Для extract режима нужны все строки файла
 		allLines, _, err := internal.ReadFileLines(inp, internal.LineRange{Start: 1, End: -1})
 		if err != nil {
-			internal.FatalError("reading file: %v", err)
+			cli.FatalError("reading file: %v", err)
 		}
 		output = internal.FormatStructExtract(result, allLines)
 	} else if jsonOut {
 		output, err = internal.FormatStructJSON(result)
 		if err != nil {
-			internal.FatalError("formatting output: %v", err)
+			cli.FatalError("formatting output: %v", err)
 		}
 	} else if treeMode || treeFull {
 		output = internal.FormatStructTree(result)
@@ -455,18 +1867,62 @@ func processStructs(langConfig *internal.LanguageConfig, typeStr, mode string, e
 	fmt.Println(output)
 }
 
+// processDataKeys выводит иерархию ключей YAML/JSON/TOML файла как типы:
+// имя - путь ключа (server.tls.cert), родитель - путь объемлющего ключа
+func processDataKeys(format string, opts fileOptions) {
+	if opts.funcStr != "" || opts.allMode {
+		cli.FatalError("--func and --all cannot be used with %s files (keys are mapped as --struct types)", format)
+	}
+	if opts.typeStr == "" && !opts.mapMode && !opts.treeMode && !opts.treeFull && !opts.extract {
+		cli.FatalError("either --type, --map, --tree, or --extract must be specified for %s files", format)
+	}
+	if opts.linesRange != "" {
+		cli.FatalError("--lines is not yet supported with %s files", format)
+	}
+	if opts.fieldTypes || opts.exported || opts.fieldDepth > 0 {
+		cli.FatalError("--field-types, --exported and --fields-depth apply to source types, not %s keys", format)
+	}
+
+	findAll := opts.mapMode || opts.treeMode || opts.treeFull || (opts.typeStr == "" && opts.extract)
+	result, err := internal.NewDataStructFinder(format, opts.typeStr, findAll).FindStructures(opts.inp)
+	if err != nil {
+		cli.FatalError("%v", err)
+	}
+	printStructs(result, opts)
+}
+
 // processAll обрабатывает комбинированный режим (--all): функции + структуры
-func processAll(langConfig *internal.LanguageConfig, mode string, extractMode, rawMode bool, inp, linesRange string, mapMode, treeMode, treeFull, jsonOut, extract bool) {
+func processAll(langConfig *internal.LanguageConfig, mode string, extractMode bool, opts fileOptions) {
+	rawMode, inp, linesRange := opts.rawMode, opts.inp, opts.linesRange
+	treeMode, treeFull, jsonOut, extract := opts.treeMode, opts.treeFull, opts.jsonOut, opts.extract
+
 	// Для --all режима пока не поддерживаем --lines
 	if linesRange != "" {
-		internal.FatalError("--lines is not yet supported with --all mode")
+		cli.FatalError("--lines is not yet supported with --all mode")
 	}
 
 	// Создаем function finder (всегда в режиме "map")
 	funcFinder := internal.CreateFinder(langConfig, "", "map", extractMode, rawMode)
+	if skipper, ok := funcFinder.(internal.AnonymousSkipper); ok {
+		skipper.SetExcludeAnonymous(opts.anonymous)
+	}
+	if macroFinder, ok := funcFinder.(internal.MacroFinder); ok {
+		macroFinder.SetIncludeMacros(opts.macros)
+	}
 	funcResult, err := funcFinder.FindFunctions(inp)
 	if err != nil {
-		internal.FatalError("finding functions: %v", err)
+		cli.FatalError("finding functions: %v", err)
+	}
+	reportAmbiguities(inp, funcResult.Ambiguities, opts.strict)
+	if opts.category != "" {
+		funcResult.Functions = internal.FilterByCategory(funcResult.Functions, opts.category)
+	}
+	if opts.generators {
+		funcResult.Functions = internal.FilterGenerators(funcResult.Functions)
+	}
+	if opts.deprecated {
+		internal.AttachDeprecated(funcResult.Functions, readAllLines(inp), langConfig)
+		funcResult.Functions = internal.FilterDeprecated(funcResult.Functions)
 	}
 
 	// Создаем struct finder (если язык поддерживает)
@@ -474,9 +1930,23 @@ func processAll(langConfig *internal.LanguageConfig, mode string, extractMode, r
 	if langConfig.HasStructSupport() {
 		factory := internal.NewStructFinderFactory()
 		structFinder := factory.CreateStructFinder(langConfig, "", true, extractMode)
+		applyFieldsDepth(structFinder, langConfig, opts.fieldDepth)
 		structResult, err = structFinder.FindStructures(inp)
 		if err != nil {
-			internal.FatalError("finding types: %v", err)
+			cli.FatalError("finding types: %v", err)
+		}
+	}
+
+	if opts.withDocs {
+		internal.AttachDocs(funcResult.Functions, readAllLines(inp), langConfig)
+	}
+
+	if opts.exported {
+		checker := newExportChecker(langConfig, inp)
+		funcResult.Functions = internal.FilterExportedFunctions(funcResult.Functions, checker)
+		funcResult.Classes = internal.FilterExportedClasses(funcResult.Classes, checker)
+		if structResult != nil {
+			structResult.Types = internal.FilterExportedTypes(structResult.Types, checker)
 		}
 	}
 
@@ -488,11 +1958,18 @@ func processAll(langConfig *internal.LanguageConfig, mode string, extractMode, r
 	}
 
 	if funcCount == 0 && typeCount == 0 {
-		internal.FatalErrorWithCode(2, "No functions or types found in file")
+		cli.FatalErrorWithCode(2, "No functions or types found in file")
 	}
 
 	// Форматируем и выводим результат
-	if jsonOut {
+	if opts.dotMode {
+		fmt.Println(internal.FormatDot(internal.MergeStructClasses(funcResult, structResult)))
+	} else if opts.annotate != "" {
+		merged := internal.MergeStructClasses(funcResult, structResult)
+		fmt.Println(internal.FormatAnnotations([]internal.DirResult{{Path: inp, Functions: merged.Functions, Classes: merged.Classes}}, opts.annotate))
+	} else if opts.unified {
+		fmt.Println(internal.FormatUnifiedTree(funcResult, structResult, treeFull))
+	} else if jsonOut {
 		outputCombinedJSON(funcResult, structResult)
 	} else if extract {
 		if funcCount > 0 {
@@ -506,7 +1983,7 @@ func processAll(langConfig *internal.LanguageConfig, mode string, extractMode, r
 			fmt.Println("=== TYPES ===")
 			allLines, _, err := internal.ReadFileLines(inp, internal.LineRange{Start: 1, End: -1})
 			if err != nil {
-				internal.FatalError("reading file: %v", err)
+				cli.FatalError("reading file: %v", err)
 			}
 			fmt.Println(internal.FormatStructExtract(structResult, allLines))
 		}
@@ -581,6 +2058,112 @@ func outputCombinedJSON(funcResult *internal.FindResult, structResult *internal.
 	fmt.Println("}")
 }
 
+// stringList is a repeatable string flag (--ignore-file a --ignore-file b)
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// printDirectives выводит директивы Go-файла (--directives); остальные
+// режимы вывода с ним не сочетаются
+func printDirectives(inp, source string, opts fileOptions) {
+	if source != "go" {
+		cli.FatalError("--directives is supported for Go sources only (got --source %s)", source)
+	}
+	if opts.funcStr != "" || opts.typeStr != "" || opts.structMode || opts.allMode || opts.treeMode || opts.treeFull || opts.extract || opts.linesRange != "" || opts.envelope {
+		cli.FatalError("--directives replaces the listing and cannot be combined with --func, --type, --struct, --all, --tree, --tree-full, --extract, --lines or --envelope")
+	}
+	lines, _, err := internal.ReadFileLines(inp, internal.LineRange{Start: 1, End: -1})
+	if err != nil {
+		cli.FatalError("reading file: %v", err)
+	}
+	if output := internal.FormatDirectives(internal.FindDirectives(lines), opts.jsonOut); output != "" {
+		fmt.Println(output)
+	}
+}
+
+// printChangedFunctions сравнивает функции --inp со старой версией файла
+// (--changed-functions); обе версии разбираются с телами функций
+func printChangedFunctions(langConfig *internal.LanguageConfig, mode string, opts fileOptions) {
+	if opts.structMode || opts.allMode || opts.treeMode || opts.treeFull || opts.extract || opts.linesRange != "" || opts.envelope || opts.formatTmpl != nil || opts.dotMode || opts.annotate != "" {
+		cli.FatalError("--changed-functions replaces the listing and cannot be combined with --struct, --all, --tree, --tree-full, --extract, --lines, --envelope, --format, --dot or --annotate")
+	}
+	finder := newFunctionFinder(langConfig, opts.funcStr, mode, true, opts.rawMode, opts)
+	oldResult, err := finder.FindFunctions(opts.changedVs)
+	if err != nil {
+		cli.FatalError("finding functions: %v", err)
+	}
+	newResult, err := finder.FindFunctions(opts.inp)
+	if err != nil {
+		cli.FatalError("finding functions: %v", err)
+	}
+	changes := internal.DiffFunctions(oldResult.Functions, newResult.Functions, langConfig)
+	output, err := internal.FormatFunctionChanges(changes, opts.jsonOut)
+	if err != nil {
+		cli.FatalError("formatting output: %v", err)
+	}
+	fmt.Println(output)
+}
+
+// runValidateConfig печатает отчёт --validate-config и завершает процесс
+func runValidateConfig() {
+	report, err := internal.ValidateConfig()
+	if err != nil {
+		cli.FatalError("%v", err)
+	}
+	fmt.Println(internal.FormatConfigReport(report))
+	if len(report.Issues) > 0 {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// hoistPositionalArgs moves positional arguments behind all flags so that
+// flag.Parse, which stops at the first non-flag argument, sees flags given
+// after a file name. Whether a flag consumes the next argument is looked up
+// in fs; everything after "--" stays positional.
+func hoistPositionalArgs(args []string, fs *flag.FlagSet) []string {
+	flags := make([]string, 0, len(args))
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+		flags = append(flags, arg)
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+		if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+			continue
+		}
+		if i+1 < len(args) {
+			flags = append(flags, args[i+1])
+			i++
+		}
+	}
+	if len(positional) == 0 {
+		return flags
+	}
+	return append(append(flags, "--"), positional...)
+}
+
 // preprocessStructArg rewrites os.Args before flag.Parse so that
 //   --struct "TypeA,TypeB" --extract
 // is treated the same as:
@@ -0,0 +1,496 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain runs main instead of the tests when FUNCFINDER_RUN_MAIN is set,
+// so tests can invoke the CLI by re-executing the test binary
+func TestMain(m *testing.M) {
+	if os.Getenv("FUNCFINDER_RUN_MAIN") == "1" {
+		os.Args = append([]string{"funcfinder"}, strings.Fields(os.Getenv("FUNCFINDER_ARGS"))...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runFuncfinder runs the CLI in dir and returns its stdout and exit code
+func runFuncfinder(t *testing.T, dir string, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0])
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "FUNCFINDER_RUN_MAIN=1", "FUNCFINDER_ARGS="+strings.Join(args, " "))
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return string(out), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatalf("running funcfinder: %v", err)
+	}
+	return string(out), 0
+}
+
+func writePyFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	code := "def load(path):\n    return open(path)\n\n\ndef save(path, data):\n    pass\n"
+	if err := os.WriteFile(filepath.Join(dir, "tool.py"), []byte(code), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return dir
+}
+
+func TestPositionalFile_DetectsLanguage(t *testing.T) {
+	dir := writePyFixture(t)
+
+	for _, args := range [][]string{
+		{"tool.py", "--map"},
+		{"--map", "tool.py"},
+	} {
+		out, code := runFuncfinder(t, dir, args...)
+		if code != 0 {
+			t.Fatalf("funcfinder %v exit code = %d, output %q", args, code, out)
+		}
+		if !strings.Contains(out, "load: 1-") || !strings.Contains(out, "save: 5-") {
+			t.Errorf("funcfinder %v = %q, want load and save", args, out)
+		}
+	}
+}
+
+func TestPositionalFile_SourceOverride(t *testing.T) {
+	dir := writePyFixture(t)
+
+	// Go patterns find no def functions, so the override is what is used
+	out, code := runFuncfinder(t, dir, "tool.py", "--source", "go", "--map")
+	if strings.Contains(out, "load") {
+		t.Errorf("--source go output = %q (exit %d), want no Python functions", out, code)
+	}
+}
+
+func TestPositionalFile_ConflictsWithInp(t *testing.T) {
+	dir := writePyFixture(t)
+	if _, code := runFuncfinder(t, dir, "--inp", "tool.py", "tool.py", "--map"); code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
+
+func TestHoistPositionalArgs(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("map", false, "")
+	fs.String("source", "", "")
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"a.py", "--map"}, "--map -- a.py"},
+		{[]string{"--source", "py", "a.py", "--map"}, "--source py --map -- a.py"},
+		{[]string{"a.py", "--source=py", "b.py"}, "--source=py -- a.py b.py"},
+		{[]string{"--map", "--", "-odd.py"}, "--map -- -odd.py"},
+		{[]string{"--map"}, "--map"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(hoistPositionalArgs(tt.args, fs), " "); got != tt.want {
+			t.Errorf("hoistPositionalArgs(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestGet_SelectsClassMethod(t *testing.T) {
+	dir := t.TempDir()
+	src := "package x\n\ntype Box struct{}\n\nfunc (b *Box) Open() {\n}\n\nfunc Open() {\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "box.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runFuncfinder(t, dir, "box.go", "--get", "Box/Open", "--extract")
+	if code != 0 {
+		t.Fatalf("exit code = %d, output %q", code, out)
+	}
+	if want := "// Open: 5-6\nfunc (b *Box) Open() {\n}\n"; out != want {
+		t.Errorf("--get Box/Open --extract = %q, want %q", out, want)
+	}
+
+	if _, code := runFuncfinder(t, dir, "box.go", "--get", "Lid/Open"); code != 2 {
+		t.Errorf("unmatched --get exit code = %d, want 2", code)
+	}
+}
+
+func TestTop_JSONKeepsLargest(t *testing.T) {
+	dir := t.TempDir()
+	src := "def a():\n    pass\n\n\ndef b():\n    x = 1\n    y = 2\n    return x + y\n\n\ndef c():\n    return 1\n"
+	if err := os.WriteFile(filepath.Join(dir, "m.py"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runFuncfinder(t, dir, "m.py", "--sort", "size", "--top", "1", "--json")
+	if code != 0 {
+		t.Fatalf("exit code = %d, output %q", code, out)
+	}
+	if !strings.Contains(out, `"b"`) || strings.Contains(out, `"a"`) || strings.Contains(out, `"c"`) {
+		t.Errorf("--sort size --top 1 --json = %s, want only b", out)
+	}
+}
+
+func TestFilesFrom_ScansListedFiles(t *testing.T) {
+	dir := writePyFixture(t)
+	if err := os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n\nfunc Run() {\n}\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "skipped.go"), []byte("package app\n\nfunc Skipped() {\n}\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "list.txt"), []byte("tool.py\r\n\napp.go\nREADME.md\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runFuncfinder(t, dir, "--files-from", "list.txt", "--map")
+	if code != 0 {
+		t.Fatalf("exit code = %d, output %q", code, out)
+	}
+	for _, want := range []string{"tool.py", "load", "save", "app.go", "Run"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("--files-from output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Skipped") {
+		t.Errorf("--files-from scanned an unlisted file:\n%s", out)
+	}
+
+	if _, code := runFuncfinder(t, dir, "--files-from", "list.txt", "--dir", "."); code != 1 {
+		t.Errorf("--files-from with --dir exit code = %d, want 1", code)
+	}
+}
+
+func TestGroupByClass_SectionsPerClass(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\nfunc New() *Server { return nil }\n\ntype Server struct{}\n\nfunc (s *Server) Run() {}\n\nfunc (s *Server) Stop() {}\n\ntype Client struct{}\n\nfunc (c Client) Fetch() {}\n\nfunc helper() {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runFuncfinder(t, dir, "p.go", "--group-by-class")
+	if code != 0 {
+		t.Fatalf("exit code = %d, output %q", code, out)
+	}
+	want := "Server:\n  Run: 7-7\n  Stop: 9-9\nClient:\n  Fetch: 13-13\n(top-level):\n  New: 3-3\n  helper: 15-15\n"
+	if out != want {
+		t.Errorf("--group-by-class =\n%s\nwant\n%s", out, want)
+	}
+
+	if _, code := runFuncfinder(t, dir, "p.go", "--group-by-class", "--json"); code != 1 {
+		t.Errorf("--group-by-class --json exit code = %d, want 1", code)
+	}
+}
+
+func TestDirectives_ReportsBuildTagAndGenerate(t *testing.T) {
+	dir := t.TempDir()
+	src := "//go:build linux\n\npackage p\n\n//go:generate go run gen.go\n\nfunc F() {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runFuncfinder(t, dir, "p.go", "--directives")
+	if code != 0 {
+		t.Fatalf("exit code = %d, output %q", code, out)
+	}
+	if want := "1: go:build linux\n5: go:generate go run gen.go\n"; out != want {
+		t.Errorf("--directives = %q, want %q", out, want)
+	}
+
+	if _, code := runFuncfinder(t, writePyFixture(t), "tool.py", "--directives"); code != 1 {
+		t.Errorf("--directives on Python exit code = %d, want 1", code)
+	}
+}
+
+func TestMaxFunctions_StrictFailsOnOversizedFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "god.go"), []byte("package p\n\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if out, code := runFuncfinder(t, dir, "--dir", ".", "--max-functions", "2"); code != 0 || !strings.Contains(out, "god.go:3: A") {
+		t.Errorf("--max-functions without --strict: exit code = %d, output %q; want the listing and 0", code, out)
+	}
+	if _, code := runFuncfinder(t, dir, "--dir", ".", "--max-functions", "2", "--strict"); code != 3 {
+		t.Errorf("--max-functions --strict exit code = %d, want 3", code)
+	}
+	if _, code := runFuncfinder(t, dir, "--dir", ".", "--max-functions", "3", "--strict"); code != 0 {
+		t.Errorf("file at the limit: exit code = %d, want 0", code)
+	}
+	if _, code := runFuncfinder(t, dir, "god.go", "--map", "--max-functions", "2"); code != 1 {
+		t.Errorf("--max-functions with a single file: exit code = %d, want 1", code)
+	}
+}
+
+func TestFieldsDepth_DottedNestedFields(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\ntype Outer struct {\n\tA int\n\tInner struct {\n\t\tX int\n\t}\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runFuncfinder(t, dir, "p.go", "--struct", "--field-types-only", "--fields-depth", "2")
+	if code != 0 {
+		t.Fatalf("exit code = %d, output %q", code, out)
+	}
+	if want := "Outer.A: int\nOuter.Inner: struct\nOuter.Inner.X: int\n"; out != want {
+		t.Errorf("--fields-depth 2 = %q, want %q", out, want)
+	}
+
+	if _, code := runFuncfinder(t, dir, "p.go", "--map", "--fields-depth", "2"); code != 1 {
+		t.Errorf("--fields-depth without --struct: exit code = %d, want 1", code)
+	}
+}
+
+func TestDeprecatedOnly_GoDocAndDirMode(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\n// Old is kept for compatibility.\n//\n// Deprecated: use New.\nfunc Old() {}\n\n// New replaces Old.\nfunc New() {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runFuncfinder(t, dir, "p.go", "--deprecated-only", "--json")
+	if code != 0 {
+		t.Fatalf("exit code = %d, output %q", code, out)
+	}
+	if !strings.Contains(out, `"Old"`) || !strings.Contains(out, `"deprecated": true`) || strings.Contains(out, `"New"`) {
+		t.Errorf("--deprecated-only --json = %s, want Old only, marked deprecated", out)
+	}
+
+	out, code = runFuncfinder(t, dir, "--dir", ".", "--deprecated-only")
+	if code != 0 || !strings.Contains(out, "Old") || strings.Contains(out, "New") {
+		t.Errorf("--dir --deprecated-only: exit code = %d, output %q", code, out)
+	}
+}
+
+func TestErrorFormatJSON_WritesOneObjectToStderr(t *testing.T) {
+	dir := writePyFixture(t)
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"missing file", []string{"--inp", "missing.py", "--map"}, "failed to open file"},
+		{"unsupported language", []string{"--inp", "tool.py", "--source", "cobol", "--map"}, "unsupported language: cobol"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0])
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "FUNCFINDER_RUN_MAIN=1", "FUNCFINDER_ARGS="+strings.Join(append([]string{"--error-format", "json"}, tt.args...), " "))
+			_, err := cmd.Output()
+			exitErr, ok := err.(*exec.ExitError)
+			if !ok {
+				t.Fatalf("funcfinder error = %v, want exit error", err)
+			}
+			var got struct {
+				Error string `json:"error"`
+				Code  int    `json:"code"`
+			}
+			if err := json.Unmarshal(exitErr.Stderr, &got); err != nil {
+				t.Fatalf("stderr %q is not one JSON object: %v", exitErr.Stderr, err)
+			}
+			if !strings.HasPrefix(got.Error, tt.wantErr) || got.Code != 1 || exitErr.ExitCode() != 1 {
+				t.Errorf("stderr = %+v, exit %d; want error %q..., code 1", got, exitErr.ExitCode(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChangedFunctions_ComparesWithOldVersion(t *testing.T) {
+	dir := writePyFixture(t)
+	changed := "def load(path):\n    return open(path, 'rb')\n\n\ndef save(path, data):\n    pass\n"
+	if err := os.WriteFile(filepath.Join(dir, "new.py"), []byte(changed), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runFuncfinder(t, dir, "--inp", "new.py", "--changed-functions", "tool.py")
+	if code != 0 {
+		t.Fatalf("exit code = %d, output:\n%s", code, out)
+	}
+	if want := "Modified (1):\n  load: 1-4\nUnchanged: 1\n"; out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestSplitOutput_WritesOneJSONPerFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":        "package main\n\nfunc main() {}\n",
+		"pkg/util.go":    "package pkg\n\nfunc A() {}\n\nfunc B() {}\n",
+		"pkg/sub/doc.go": "package sub\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	outDir := t.TempDir()
+
+	out, code := runFuncfinder(t, dir, "--dir", ".", "--split-output", outDir)
+	if code != 0 || !strings.Contains(out, "3 files") {
+		t.Fatalf("exit code = %d, output %q", code, out)
+	}
+	want := map[string]int{"main.go": 1, "pkg/util.go": 2, "pkg/sub/doc.go": 0}
+	for name, funcs := range want {
+		data, err := os.ReadFile(filepath.Join(outDir, filepath.FromSlash(name)+".json"))
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		var file struct {
+			Path      string            `json:"path"`
+			Functions []json.RawMessage `json:"functions"`
+		}
+		if err := json.Unmarshal(data, &file); err != nil {
+			t.Fatalf("%s.json is not valid JSON: %v", name, err)
+		}
+		if len(file.Functions) != funcs {
+			t.Errorf("%s.json has %d functions, want %d", name, len(file.Functions), funcs)
+		}
+	}
+
+	if _, code := runFuncfinder(t, dir, "--dir", ".", "--split-output", outDir, "--split", "--json"); code != 1 {
+		t.Errorf("--split-output with --split: exit code = %d, want 1", code)
+	}
+	if _, code := runFuncfinder(t, dir, "main.go", "--map", "--split-output", outDir); code != 1 {
+		t.Errorf("--split-output with a single file: exit code = %d, want 1", code)
+	}
+}
+
+func TestIndex_ListsEverySymbolOfTwoFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":     "package main\n\ntype Config struct {\n\tPort int\n}\n\nfunc main() {}\n",
+		"pkg/util.go": "package pkg\n\nfunc A() {}\n\nfunc B() {}\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+
+	out, code := runFuncfinder(t, dir, "--dir", ".", "--all", "--index", indexPath)
+	if code != 0 || !strings.Contains(out, "4 symbols in 2 files") {
+		t.Fatalf("exit code = %d, output %q", code, out)
+	}
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var index struct {
+		Symbols []struct {
+			Name string `json:"name"`
+			Kind string `json:"kind"`
+			Path string `json:"path"`
+		} `json:"symbols"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("index is not valid JSON: %v", err)
+	}
+	var got []string
+	for _, s := range index.Symbols {
+		got = append(got, s.Path+":"+s.Name+":"+s.Kind)
+	}
+	want := "main.go:Config:struct main.go:main:function pkg/util.go:A:function pkg/util.go:B:function"
+	if strings.Join(got, " ") != want {
+		t.Errorf("symbols = %v, want %s", got, want)
+	}
+
+	if _, code := runFuncfinder(t, dir, "main.go", "--map", "--index", indexPath); code != 1 {
+		t.Errorf("--index with a single file: exit code = %d, want 1", code)
+	}
+}
+
+func TestFlatten_ListsClosuresWithDepth(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\nfunc Outer() {\n\tf := func() {\n\t}\n\tg := func() {\n\t}\n\tf()\n\tg()\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runFuncfinder(t, dir, "p.go", "--flatten")
+	want := "Outer: 3-10 depth 0\nOuter.func1: 4-5 depth 1\nOuter.func2: 6-7 depth 1\n"
+	if code != 0 || out != want {
+		t.Errorf("exit code = %d, output:\n%s\nwant:\n%s", code, out, want)
+	}
+	if _, code := runFuncfinder(t, dir, "p.go", "--flatten", "--tree"); code != 1 {
+		t.Errorf("--flatten with --tree: exit code = %d, want 1", code)
+	}
+}
+
+func TestAssertions_CountsCallsAndFlagsEmptyTests(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\nimport \"testing\"\n\nfunc TestTwo(t *testing.T) {\n\tt.Errorf(\"a\")\n\tt.Errorf(\"b\")\n}\n\nfunc TestNone(t *testing.T) {\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p_test.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runFuncfinder(t, dir, "p_test.go", "--assertions", "--json")
+	if code != 0 || !strings.Contains(out, `"assertions": 2`) || !strings.Contains(out, `"assertions": 0`) {
+		t.Errorf("exit code = %d, output:\n%s\nwant counts 2 and 0", code, out)
+	}
+	if _, code := runFuncfinder(t, dir, "p_test.go", "--assertions", "--strict"); code != 3 {
+		t.Errorf("--strict with a test without assertions: exit code = %d, want 3", code)
+	}
+	if out, code := runFuncfinder(t, dir, "p_test.go", "--assertions", "--extract"); code != 0 || !strings.Contains(out, "// TestTwo: 5-8") {
+		t.Errorf("--assertions --extract: exit code = %d, output:\n%s\nwant the test bodies", code, out)
+	}
+}
+
+func TestSort_OnlyReordersWhenGiven(t *testing.T) {
+	dir := t.TempDir()
+	src := "function outer() {\n  function inner() {\n    return 1;\n  }\n  return inner;\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "n.js"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if out, code := runFuncfinder(t, dir, "n.js", "--map"); code != 0 || out != "inner: 2-4; outer: 1-6;\n" {
+		t.Errorf("without --sort: exit code = %d, output %q, want the finder's order", code, out)
+	}
+	if out, code := runFuncfinder(t, dir, "n.js", "--sort", "line"); code != 0 || out != "outer: 1-6; inner: 2-4;\n" {
+		t.Errorf("--sort line: exit code = %d, output %q", code, out)
+	}
+	out, code := runFuncfinder(t, dir, "n.js", "--sort", "name", "--json")
+	if code != 0 || strings.Index(out, `"name": "inner"`) > strings.Index(out, `"name": "outer"`) || !strings.Contains(out, `"functions": [`) {
+		t.Errorf("--sort name --json: exit code = %d, output:\n%s\nwant a functions array, inner first", code, out)
+	}
+}
+
+func TestWarnLength_WarnsAndFailsWithStrict(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\nfunc Short() {\n\t_ = 1\n}\n\nfunc Long() {\n\t_ = 1\n\t_ = 2\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// Short spans 3 lines, Long 4
+	if _, code := runFuncfinder(t, dir, "p.go", "--map", "--warn-length", "4", "--strict"); code != 0 {
+		t.Errorf("functions within --warn-length 4: exit code = %d, want 0", code)
+	}
+	if out, code := runFuncfinder(t, dir, "p.go", "--map", "--warn-length", "3"); code != 0 || !strings.Contains(out, "Long: 7-10") {
+		t.Errorf("--warn-length without --strict: exit code = %d, output %q; want the listing and 0", code, out)
+	}
+	if _, code := runFuncfinder(t, dir, "p.go", "--map", "--warn-length", "3", "--strict"); code != 3 {
+		t.Errorf("--warn-length 3 --strict exit code = %d, want 3", code)
+	}
+	if _, code := runFuncfinder(t, dir, "--dir", ".", "--warn-length", "3", "--strict"); code != 3 {
+		t.Errorf("--dir --warn-length 3 --strict exit code = %d, want 3", code)
+	}
+}
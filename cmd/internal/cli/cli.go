@@ -0,0 +1,51 @@
+// Package cli holds the exiting helpers shared by the cmd/ tools. The
+// internal package returns errors (see internal.ConfigError and
+// internal.ParseError); only main functions decide to exit.
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ruslano69/funcfinder/internal"
+)
+
+// ErrorFormatUsage is the help text of every tool's --error-format flag
+const ErrorFormatUsage = "how fatal errors are written to stderr: text (\"Error: ...\") or json ({\"error\":\"...\",\"code\":N} on one line); the exit code is the same"
+
+// SetErrorFormat applies an --error-format value from a hand-written
+// argument loop and exits on an invalid one
+func SetErrorFormat(s string) {
+	if err := internal.SetErrorFormat(s); err != nil {
+		FatalError("%v", err)
+	}
+}
+
+// FatalError prints an error message to stderr and exits with code 1
+func FatalError(format string, args ...interface{}) {
+	FatalErrorWithCode(1, format, args...)
+}
+
+// FatalErrorWithCode prints an error and exits with specific code
+func FatalErrorWithCode(code int, format string, args ...interface{}) {
+	internal.WriteError(os.Stderr, code, fmt.Sprintf(format, args...))
+	os.Exit(code)
+}
+
+// FatalErrorMsg prints error message and exits
+func FatalErrorMsg(msg string) {
+	internal.WriteError(os.Stderr, 1, msg)
+	os.Exit(1)
+}
+
+// PrintUsage prints usage information and exits
+func PrintUsage(usageFunc func()) {
+	usageFunc()
+	os.Exit(1)
+}
+
+// PrintVersion prints version and exits successfully
+func PrintVersion(toolName string) {
+	fmt.Printf("%s version %s\n", toolName, internal.Version)
+	os.Exit(0)
+}
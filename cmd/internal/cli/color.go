@@ -0,0 +1,37 @@
+package cli
+
+import "fmt"
+
+// ColorMode is the value of a --color flag
+type ColorMode string
+
+// Color modes: auto follows the terminal (TTY, TERM, NO_COLOR), always
+// emits ANSI escapes even into a pipe ("| less -R"), never emits none
+const (
+	ColorAuto   ColorMode = "auto"
+	ColorAlways ColorMode = "always"
+	ColorNever  ColorMode = "never"
+)
+
+// ParseColorMode validates a --color value; an empty value means ColorAuto
+func ParseColorMode(s string) (ColorMode, error) {
+	switch ColorMode(s) {
+	case "", ColorAuto:
+		return ColorAuto, nil
+	case ColorAlways, ColorNever:
+		return ColorMode(s), nil
+	}
+	return "", fmt.Errorf("invalid --color %q (expected always, auto or never)", s)
+}
+
+// Enabled reports whether to color the output; detect is the tool's
+// terminal check, consulted in auto mode only
+func (m ColorMode) Enabled(detect func() bool) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	return detect()
+}
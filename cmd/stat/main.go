@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/ruslano69/funcfinder/cmd/internal/cli"
 	"github.com/ruslano69/funcfinder/internal"
 )
 
@@ -27,7 +28,10 @@ type StatResult struct {
 	Imports      []string    `json:"imports"`
 	Decorators   []string    `json:"decorators"`
 	UniqueCalls  int         `json:"unique_calls"`
+	TotalCalls   int         `json:"total_calls"`
 	TopCalls     []CallEntry `json:"top_calls"`
+	// ComplexFunctions lists functions over -warn-complex
+	ComplexFunctions []internal.FunctionComplexity `json:"complex_functions,omitempty"`
 }
 
 // DirStatResult is the JSON output structure for directory mode.
@@ -40,6 +44,7 @@ type DirStatResult struct {
 	CommentLines int         `json:"comment_lines"`
 	BlankLines   int         `json:"blank_lines"`
 	UniqueCalls  int         `json:"unique_calls"`
+	TotalCalls   int         `json:"total_calls"`
 	TopCalls     []CallEntry `json:"top_calls"`
 	Files        []StatResult `json:"files"`
 }
@@ -47,6 +52,8 @@ type DirStatResult struct {
 type CallEntry struct {
 	Name  string `json:"name"`
 	Count int    `json:"count"`
+	// Percent is Count as a share of all calls of the file (or directory)
+	Percent float64 `json:"percent"`
 }
 
 // FileMetrics holds statistics about a source file
@@ -60,30 +67,13 @@ type FileMetrics struct {
 	FileSize     int64
 }
 
-// cleanLine removes comments and strings using enhanced_sanitizer
-func cleanLine(line string, sanitizer *internal.Sanitizer, state *internal.ParserState) (string, bool) {
-	// Skip shebang lines
-	if strings.HasPrefix(line, "#!") {
-		return "", true
-	}
-
-	// Use enhanced_sanitizer for proper string/comment removal
-	cleaned, newState := sanitizer.CleanLine(line, *state)
-	*state = newState
-
-	// Check if line is entirely comment/string (all spaces after cleaning)
-	if strings.TrimSpace(cleaned) == "" {
-		return "", true
-	}
-
-	return cleaned, false
-}
-
-// analyzeFile analyzes a source file and returns function calls and metrics
-func analyzeFile(filename string, config *internal.LanguageConfig) (map[string]int, *FileMetrics) {
+// analyzeFile analyzes a source file and returns function calls and metrics.
+// With normalizeImports the imports are canonical (aliases resolved) and
+// sorted instead of raw import_pattern matches in first-seen order.
+func analyzeFile(filename string, config *internal.LanguageConfig, normalizeImports bool) (map[string]int, *FileMetrics) {
 	file, err := os.Open(filename)
 	if err != nil {
-		internal.FatalError("opening file: %v", err)
+		cli.FatalError("opening file: %v", err)
 	}
 	defer file.Close()
 
@@ -97,7 +87,7 @@ func analyzeFile(filename string, config *internal.LanguageConfig) (map[string]i
 
 	callRegex := config.CallRegex()
 	if callRegex == nil {
-		internal.FatalError("no call pattern defined for language")
+		cli.FatalError("no call pattern defined for language")
 	}
 
 	callCounts := make(map[string]int)
@@ -114,26 +104,31 @@ func analyzeFile(filename string, config *internal.LanguageConfig) (map[string]i
 
 	importSet := make(map[string]bool)
 	decoratorSet := make(map[string]bool)
+	var importCollector *internal.ImportCollector
+	if normalizeImports {
+		importCollector = internal.NewImportCollector(config)
+	}
 
-	// Create sanitizer for proper string/comment removal
-	sanitizer := internal.NewSanitizer(config, false)
-	state := internal.StateNormal
+	// Shared code/comment/blank classification (same numbers as complexity)
+	classifier := internal.NewLineClassifier(config)
 
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
 		metrics.TotalLines++
 
-		trimmed := strings.TrimSpace(line)
+		cleanedLine, kind := classifier.Classify(line)
 
 		// Count blank lines
-		if trimmed == "" {
+		if kind == internal.LineBlank {
 			metrics.BlankLines++
 			continue
 		}
 
 		// Check for imports (before cleaning)
-		if importRegex != nil {
+		if importCollector != nil {
+			importCollector.Add(line, cleanedLine)
+		} else if importRegex != nil {
 			match := importRegex.FindStringSubmatch(line)
 			if len(match) >= 2 {
 				for i := 1; i < len(match); i++ {
@@ -156,18 +151,14 @@ func analyzeFile(filename string, config *internal.LanguageConfig) (map[string]i
 			}
 		}
 
-		cleanedLine, isComment := cleanLine(line, sanitizer, &state)
-
 		// Count comment lines
-		if isComment {
+		if kind == internal.LineComment {
 			metrics.CommentLines++
 			continue
 		}
 
 		// Count code lines (non-blank, non-comment)
-		if cleanedLine != "" {
-			metrics.CodeLines++
-		}
+		metrics.CodeLines++
 
 		// Count function calls
 		if cleanedLine == "" {
@@ -193,9 +184,35 @@ func analyzeFile(filename string, config *internal.LanguageConfig) (map[string]i
 		}
 	}
 
+	if importCollector != nil {
+		metrics.Imports = importCollector.Imports()
+	}
 	return callCounts, metrics
 }
 
+// complexFunctions returns the functions of filename whose cyclomatic
+// complexity exceeds threshold, using the same bounds as funcfinder
+func complexFunctions(filename string, config *internal.LanguageConfig, threshold int) []internal.FunctionComplexity {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		cli.FatalError("reading file: %v", err)
+	}
+	finder := internal.CreateFinder(config, "", "map", false, false)
+	result, err := finder.FindFunctionsInReader(strings.NewReader(string(data)), filename)
+	if err != nil {
+		cli.FatalError("%v", err)
+	}
+	cleaned := internal.NewSanitizer(config, false).CleanLines(strings.Split(string(data), "\n"))
+	return internal.ComplexFunctions(result.Functions, cleaned, threshold)
+}
+
+// warnComplexFunctions prints one warning per function over threshold
+func warnComplexFunctions(filename string, complex []internal.FunctionComplexity, threshold int) {
+	for _, fn := range complex {
+		internal.WarnError("%s:%d: %s() has cyclomatic complexity %d (over %d)", filename, fn.Start, fn.Name, fn.Complexity, threshold)
+	}
+}
+
 // sortedCalls converts a callCounts map to a sorted slice of pairs.
 func sortedCalls(callCounts map[string]int) []struct{ name string; count int } {
 	type pair struct {
@@ -215,19 +232,51 @@ func sortedCalls(callCounts map[string]int) []struct{ name string; count int } {
 	return result
 }
 
+// totalCalls sums the counts of all calls.
+func totalCalls(calls []struct{ name string; count int }) int {
+	total := 0
+	for _, c := range calls {
+		total += c.count
+	}
+	return total
+}
+
+// callPercent is count as a percentage of total (0 when there are no calls).
+func callPercent(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) * 100 / float64(total)
+}
+
 // toCallEntries converts a sorted calls slice to JSON-ready CallEntry slice, capped at topN.
+// Percentages are of all calls, not only the topN shown.
 func toCallEntries(calls []struct{ name string; count int }, topN int) []CallEntry {
+	total := totalCalls(calls)
 	entries := calls
 	if topN > 0 && topN < len(entries) {
 		entries = entries[:topN]
 	}
 	out := make([]CallEntry, len(entries))
 	for i, c := range entries {
-		out[i] = CallEntry{Name: c.name, Count: c.count}
+		out[i] = CallEntry{Name: c.name, Count: c.count, Percent: callPercent(c.count, total)}
 	}
 	return out
 }
 
+// printCallTable prints the top N calls with their share of all calls,
+// each line prefixed by indent.
+func printCallTable(calls []struct{ name string; count int }, topN int, indent string) {
+	total := totalCalls(calls)
+	printCount := len(calls)
+	if topN > 0 && topN < printCount {
+		printCount = topN
+	}
+	for i := 0; i < printCount; i++ {
+		fmt.Printf("%s%-*s %d (%.1f%%)\n", indent, 25-len(indent), calls[i].name, calls[i].count, callPercent(calls[i].count, total))
+	}
+}
+
 // printFileStats prints text output for a single analyzed file.
 func printFileStats(filename string, langName string, calls []struct{ name string; count int }, metrics *FileMetrics, topN int) {
 	fmt.Printf("Language: %s\n", langName)
@@ -255,16 +304,27 @@ func printFileStats(filename string, langName string, calls []struct{ name strin
 	}
 
 	fmt.Println(strings.Repeat("-", 35))
-	fmt.Printf("Function calls: %d unique\n", len(calls))
+	fmt.Printf("Total calls: %d across %d unique names\n", totalCalls(calls), len(calls))
 	fmt.Println(strings.Repeat("-", 35))
 
-	printCount := len(calls)
-	if topN > 0 && topN < printCount {
-		printCount = topN
-	}
-	for i := 0; i < printCount; i++ {
-		fmt.Printf("%-25s %d\n", calls[i].name, calls[i].count)
+	printCallTable(calls, topN, "")
+}
+
+// applyExtOverrides makes every -ext ".h=cpp" extension resolve to its
+// language before detection, so ambiguous headers are analyzed as asked
+func applyExtOverrides(config internal.Config, specs []string) error {
+	for _, spec := range specs {
+		mapping, err := internal.ParseExtMap(spec)
+		if err != nil {
+			return err
+		}
+		for ext, lang := range mapping {
+			if err := config.MapExtension(ext, lang); err != nil {
+				return fmt.Errorf("-ext %s: %v", spec, err)
+			}
+		}
 	}
+	return nil
 }
 
 func main() {
@@ -274,6 +334,9 @@ func main() {
 	langFlag := ""
 	topN := 0
 	jsonOut := false
+	warnComplex := 0
+	normalizeImports := false
+	var extOverrides []string
 
 	for i := 1; i < len(os.Args); i++ {
 		arg := os.Args[i]
@@ -283,8 +346,12 @@ func main() {
 			fmt.Println("  --version      Show version and exit")
 			fmt.Println("  --dir <path>   Analyze all source files in directory recursively")
 			fmt.Println("  -l <lang>      Force language (py, go, rs, js, ts, sw, c, cpp, java, d, cs)")
+			fmt.Println("  -ext <.ext=lang>     Force the language of an extension, e.g. -ext .h=cpp (repeatable)")
 			fmt.Println("  -n <num>       Show top N functions")
 			fmt.Println("  -j, --json     Output JSON")
+			fmt.Println("  -warn-complex <num>  Warn about functions with cyclomatic complexity over N")
+			fmt.Println("  -normalize-imports   Sort and deduplicate imports, aliases resolved to the package path")
+			fmt.Println("  --error-format <f>   Fatal errors as text (default) or json on stderr")
 			return
 		} else if arg == "--version" {
 			showVersion = true
@@ -294,9 +361,20 @@ func main() {
 		} else if arg == "-l" && i+1 < len(os.Args) {
 			langFlag = os.Args[i+1]
 			i++
+		} else if (arg == "-ext" || arg == "--ext") && i+1 < len(os.Args) {
+			extOverrides = append(extOverrides, os.Args[i+1])
+			i++
 		} else if arg == "-n" && i+1 < len(os.Args) {
 			fmt.Sscanf(os.Args[i+1], "%d", &topN)
 			i++
+		} else if arg == "-warn-complex" && i+1 < len(os.Args) {
+			fmt.Sscanf(os.Args[i+1], "%d", &warnComplex)
+			i++
+		} else if arg == "-normalize-imports" || arg == "--normalize-imports" {
+			normalizeImports = true
+		} else if arg == "--error-format" && i+1 < len(os.Args) {
+			cli.SetErrorFormat(os.Args[i+1])
+			i++
 		} else if arg == "-j" || arg == "--json" {
 			jsonOut = true
 		} else if !strings.HasPrefix(arg, "-") {
@@ -305,17 +383,20 @@ func main() {
 	}
 
 	if showVersion {
-		internal.PrintVersion("stat")
+		cli.PrintVersion("stat")
 	}
 
 	if dirMode == "" && filename == "" {
-		internal.FatalError("source file or --dir is required\nUsage: stat [OPTIONS] <source_file>\n       stat [OPTIONS] --dir <directory>")
+		cli.FatalError("source file or --dir is required\nUsage: stat [OPTIONS] <source_file>\n       stat [OPTIONS] --dir <directory>")
 	}
 
 	// Load shared configuration
 	config, err := internal.LoadConfig()
 	if err != nil {
-		internal.FatalError("loading config: %v", err)
+		cli.FatalError("loading config: %v", err)
+	}
+	if err := applyExtOverrides(config, extOverrides); err != nil {
+		cli.FatalError("%v", err)
 	}
 
 	// ── DIRECTORY MODE ────────────────────────────────────────────────────────
@@ -324,7 +405,7 @@ func main() {
 		if langFlag != "" {
 			langConfig, err = config.GetLanguageConfig(langFlag)
 			if err != nil {
-				internal.FatalError("%v\nSupported languages: %s", err, strings.Join(config.GetSupportedLanguages(), ", "))
+				cli.FatalError("%v\nSupported languages: %s", err, strings.Join(config.GetSupportedLanguages(), ", "))
 			}
 		} else {
 			// Auto-detect by finding files with supported extensions in the dir.
@@ -342,14 +423,15 @@ func main() {
 			}
 		}
 		if langConfig == nil {
-			internal.FatalError("no supported files found in directory\nSupported languages: %s", strings.Join(config.GetSupportedLanguages(), ", "))
+			cli.FatalError("no supported files found in directory\nSupported languages: %s", strings.Join(config.GetSupportedLanguages(), ", "))
 		}
 
 		// Single pass: collect per-file results and aggregate.
 		type perFile struct {
-			path  string
-			calls []struct{ name string; count int }
-			m     FileMetrics
+			path    string
+			calls   []struct{ name string; count int }
+			m       FileMetrics
+			complex []internal.FunctionComplexity
 		}
 		aggregateCounts := make(map[string]int)
 		var aggMetrics FileMetrics
@@ -357,10 +439,10 @@ func main() {
 
 		dirFiles, walkErr := internal.CollectSourceFiles(dirMode, langConfig, true)
 		if walkErr != nil {
-			internal.FatalError("walking directory: %v", walkErr)
+			cli.FatalError("walking directory: %v", walkErr)
 		}
 		for _, path := range dirFiles {
-			counts, m := analyzeFile(path, langConfig)
+			counts, m := analyzeFile(path, langConfig, normalizeImports)
 			for fn, cnt := range counts {
 				aggregateCounts[fn] += cnt
 			}
@@ -369,7 +451,11 @@ func main() {
 			aggMetrics.CommentLines += m.CommentLines
 			aggMetrics.BlankLines += m.BlankLines
 			aggMetrics.FileSize += m.FileSize
-			collected = append(collected, perFile{path: path, calls: sortedCalls(counts), m: *m})
+			pf := perFile{path: path, calls: sortedCalls(counts), m: *m}
+			if warnComplex > 0 {
+				pf.complex = complexFunctions(path, langConfig, warnComplex)
+			}
+			collected = append(collected, pf)
 		}
 
 		aggCalls := sortedCalls(aggregateCounts)
@@ -388,7 +474,10 @@ func main() {
 					Imports:      pf.m.Imports,
 					Decorators:   pf.m.Decorators,
 					UniqueCalls:  len(pf.calls),
+					TotalCalls:   totalCalls(pf.calls),
 					TopCalls:     toCallEntries(pf.calls, topN),
+
+					ComplexFunctions: pf.complex,
 				}
 			}
 			result := DirStatResult{
@@ -400,6 +489,7 @@ func main() {
 				CommentLines: aggMetrics.CommentLines,
 				BlankLines:   aggMetrics.BlankLines,
 				UniqueCalls:  len(aggregateCounts),
+				TotalCalls:   totalCalls(aggCalls),
 				TopCalls:     toCallEntries(aggCalls, topN),
 				Files:        fileResults,
 			}
@@ -412,26 +502,18 @@ func main() {
 		fmt.Printf("Language: %s  Dir: %s\n", langConfig.Name, dirMode)
 		fmt.Println(strings.Repeat("=", 45))
 		for _, pf := range collected {
-			printCount := len(pf.calls)
-			if topN > 0 && topN < printCount {
-				printCount = topN
-			}
 			fmt.Printf("\n%s (%.1f KB, %d lines, %d unique calls)\n",
 				pf.path, float64(pf.m.FileSize)/1024, pf.m.TotalLines, len(pf.calls))
-			for i := 0; i < printCount; i++ {
-				fmt.Printf("  %-23s %d\n", pf.calls[i].name, pf.calls[i].count)
-			}
+			printCallTable(pf.calls, topN, "  ")
 		}
 		fmt.Printf("\n%s\n", strings.Repeat("=", 45))
 		fmt.Printf("TOTAL  files: %d  lines: %d  unique calls: %d\n",
 			len(collected), aggMetrics.TotalLines, len(aggregateCounts))
+		fmt.Printf("Total calls: %d across %d unique names\n", totalCalls(aggCalls), len(aggCalls))
 		fmt.Println(strings.Repeat("-", 45))
-		printCount := len(aggCalls)
-		if topN > 0 && topN < printCount {
-			printCount = topN
-		}
-		for i := 0; i < printCount; i++ {
-			fmt.Printf("%-25s %d\n", aggCalls[i].name, aggCalls[i].count)
+		printCallTable(aggCalls, topN, "")
+		for _, pf := range collected {
+			warnComplexFunctions(pf.path, pf.complex, warnComplex)
 		}
 		return
 	}
@@ -441,17 +523,21 @@ func main() {
 	if langFlag != "" {
 		langConfig, err = config.GetLanguageConfig(langFlag)
 		if err != nil {
-			internal.FatalError("%v\nSupported languages: %s", err, strings.Join(config.GetSupportedLanguages(), ", "))
+			cli.FatalError("%v\nSupported languages: %s", err, strings.Join(config.GetSupportedLanguages(), ", "))
 		}
 	} else {
 		langConfig = config.GetLanguageByExtension(filename)
 		if langConfig == nil {
-			internal.FatalError("cannot detect language from file extension\nSupported languages: %s", strings.Join(config.GetSupportedLanguages(), ", "))
+			cli.FatalError("cannot detect language from file extension\nSupported languages: %s", strings.Join(config.GetSupportedLanguages(), ", "))
 		}
 	}
 
-	callCounts, metrics := analyzeFile(filename, langConfig)
+	callCounts, metrics := analyzeFile(filename, langConfig, normalizeImports)
 	calls := sortedCalls(callCounts)
+	var complex []internal.FunctionComplexity
+	if warnComplex > 0 {
+		complex = complexFunctions(filename, langConfig, warnComplex)
+	}
 
 	if jsonOut {
 		result := StatResult{
@@ -465,7 +551,10 @@ func main() {
 			Imports:      metrics.Imports,
 			Decorators:   metrics.Decorators,
 			UniqueCalls:  len(callCounts),
+			TotalCalls:   totalCalls(calls),
 			TopCalls:     toCallEntries(calls, topN),
+
+			ComplexFunctions: complex,
 		}
 		jsonBytes, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(jsonBytes))
@@ -473,4 +562,5 @@ func main() {
 	}
 
 	printFileStats(filename, langConfig.Name, calls, metrics, topN)
+	warnComplexFunctions(filename, complex, warnComplex)
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ruslano69/funcfinder/internal"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv("STAT_RUN_MAIN") == "1" {
+		os.Args = append([]string{"stat"}, strings.Fields(os.Getenv("STAT_ARGS"))...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runStat runs the CLI in dir and returns its stdout and exit code
+func runStat(t *testing.T, dir string, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0])
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "STAT_RUN_MAIN=1", "STAT_ARGS="+strings.Join(args, " "))
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return string(out), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatalf("running stat: %v", err)
+	}
+	return string(out), 0
+}
+
+func TestExtOverride_ForcesHeaderLanguage(t *testing.T) {
+	dir := t.TempDir()
+	src := "class Widget {\npublic:\n    void draw() { render(); }\n};\n"
+	if err := os.WriteFile(filepath.Join(dir, "widget.h"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	for _, tt := range []struct{ lang, want string }{{"cpp", "C++"}, {"c", "C"}} {
+		out, code := runStat(t, dir, "-ext", ".h="+tt.lang, "-j", "widget.h")
+		if code != 0 {
+			t.Fatalf("-ext .h=%s: exit code = %d, output:\n%s", tt.lang, code, out)
+		}
+		var result StatResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		if result.Language != tt.want {
+			t.Errorf("-ext .h=%s: language = %q, want %q", tt.lang, result.Language, tt.want)
+		}
+	}
+
+	out, code := runStat(t, dir, "-ext", ".h=cpp", "-j", "--dir", ".")
+	if code != 0 {
+		t.Fatalf("--dir: exit code = %d, output:\n%s", code, out)
+	}
+	var result DirStatResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Language != "C++" || result.TotalFiles != 1 {
+		t.Errorf("--dir: language = %q, files = %d, want C++ and 1", result.Language, result.TotalFiles)
+	}
+}
+
+func TestApplyExtOverrides_RejectsBadSpecs(t *testing.T) {
+	for _, spec := range []string{".h", ".h=cobol"} {
+		config, err := internal.LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if err := applyExtOverrides(config, []string{spec}); err == nil {
+			t.Errorf("applyExtOverrides(%q): expected error", spec)
+		}
+	}
+}
+
+func TestCallTotals_SumAndPercentages(t *testing.T) {
+	calls := sortedCalls(map[string]int{"print": 6, "len": 3, "open": 1})
+	if got := totalCalls(calls); got != 10 {
+		t.Errorf("totalCalls() = %d, want 10", got)
+	}
+
+	entries := toCallEntries(calls, 2)
+	want := []CallEntry{{"print", 6, 60}, {"len", 3, 30}}
+	if len(entries) != len(want) {
+		t.Fatalf("toCallEntries() = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entries[%d] = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+	if got := callPercent(0, 0); got != 0 {
+		t.Errorf("callPercent(0, 0) = %v, want 0", got)
+	}
+}
+
+func TestTotalCalls_TextAndJSON(t *testing.T) {
+	dir := t.TempDir()
+	src := "print(len(x))\nprint(x)\nprint(y)\n"
+	if err := os.WriteFile(filepath.Join(dir, "app.py"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, code := runStat(t, dir, "-j", "app.py")
+	if code != 0 {
+		t.Fatalf("exit code = %d, output:\n%s", code, out)
+	}
+	var result StatResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	sum := 0
+	for _, c := range result.TopCalls {
+		sum += c.Count
+	}
+	if result.TotalCalls != sum || result.UniqueCalls != len(result.TopCalls) {
+		t.Errorf("total_calls = %d, unique = %d, want the sum %d of %d calls", result.TotalCalls, result.UniqueCalls, sum, len(result.TopCalls))
+	}
+
+	out, _ = runStat(t, dir, "app.py")
+	line := fmt.Sprintf("Total calls: %d across %d unique names", result.TotalCalls, result.UniqueCalls)
+	if !strings.Contains(out, line) {
+		t.Errorf("text output missing %q:\n%s", line, out)
+	}
+	if !strings.Contains(out, "(75.0%)") {
+		t.Errorf("text output missing the print percentage:\n%s", out)
+	}
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func findAbstractSample(t *testing.T, lang, code string) map[string]FunctionBounds {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	result, err := NewFinder(config[lang], nil, true, false, false).FindFunctionsInLines(strings.Split(code, "\n"), 1, "sample")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	found := make(map[string]FunctionBounds)
+	for _, fn := range result.Functions {
+		found[fn.Name] = fn
+	}
+	return found
+}
+
+// checkAbstract expects name as a zero-body function on line with IsAbstract
+func checkAbstract(t *testing.T, found map[string]FunctionBounds, name, class string, line int) {
+	t.Helper()
+	fn, ok := found[name]
+	if !ok {
+		t.Fatalf("%s not found in %v", name, found)
+	}
+	if !fn.IsAbstract || fn.Start != line || fn.End != line || fn.ClassName != class {
+		t.Errorf("%s = %+v, want abstract %s.%s at %d-%d", name, fn, class, name, line, line)
+	}
+}
+
+func TestAbstract_JavaInterfaceMethod(t *testing.T) {
+	code := `public interface Shape {
+    double area();
+    <T> List<T> convert(Map<String, T> m) throws IOException;
+}
+
+class Square implements Shape {
+    private final Runnable r = () -> {
+        return compute(side);
+    };
+
+    public double perimeter() {
+        return 4 * side;
+    }
+}`
+	found := findAbstractSample(t, "java", code)
+	checkAbstract(t, found, "area", "Shape", 2)
+	checkAbstract(t, found, "convert", "Shape", 3)
+	if fn := found["perimeter"]; fn.IsAbstract || fn.Start != 11 || fn.End != 13 {
+		t.Errorf("perimeter = %+v, want implemented 11-13", fn)
+	}
+	if _, ok := found["compute"]; ok {
+		t.Error("return statement in a field lambda reported as abstract method")
+	}
+}
+
+func TestAbstract_CSharpAbstractMethod(t *testing.T) {
+	code := `public abstract class Animal
+{
+    public delegate void Handler(object sender);
+    public abstract string Speak(int times);
+
+    public void Run()
+    {
+        Speak(1);
+    }
+}`
+	found := findAbstractSample(t, "cs", code)
+	checkAbstract(t, found, "Speak", "Animal", 4)
+	if fn := found["Run"]; fn.IsAbstract || fn.Start != 6 || fn.End != 9 {
+		t.Errorf("Run = %+v, want implemented 6-9", fn)
+	}
+	if _, ok := found["Handler"]; ok {
+		t.Error("delegate declaration reported as abstract method")
+	}
+}
+
+func TestAbstract_CppPureVirtual(t *testing.T) {
+	code := `class Shape {
+public:
+    virtual ~Shape() = default;
+    virtual double area() const = 0;
+    void f() = 0;
+    int sides() const { return 0; }
+};`
+	found := findAbstractSample(t, "cpp", code)
+	checkAbstract(t, found, "area", "Shape", 4)
+	checkAbstract(t, found, "f", "Shape", 5)
+	if fn := found["sides"]; fn.IsAbstract || fn.Start != 6 {
+		t.Errorf("sides = %+v, want implemented at 6", fn)
+	}
+	if _, ok := found["~Shape"]; ok {
+		t.Error("defaulted destructor reported as abstract method")
+	}
+}
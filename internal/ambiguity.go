@@ -0,0 +1,73 @@
+// ambiguity.go - Parse ambiguities detected by the brace-based finder (--strict)
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Ambiguity kinds reported in ParseAmbiguity.Kind
+const (
+	// AmbiguityNoBrace: a FuncPattern matched but no opening brace followed
+	// within MaxSignatureLines lines
+	AmbiguityNoBrace = "no-brace"
+	// AmbiguityUnclosedFunction: a function body was still open at end of input
+	AmbiguityUnclosedFunction = "unclosed-function"
+	// AmbiguityUnclosedClass: a class body was still open at end of input
+	AmbiguityUnclosedClass = "unclosed-class"
+	// AmbiguityOverlap: two function ranges overlap without one containing the other
+	AmbiguityOverlap = "overlap"
+)
+
+// MaxSignatureLines is how many lines a multiline signature may span before
+// the missing opening brace is reported as an ambiguity
+const MaxSignatureLines = 10
+
+// ParseAmbiguity describes a place where regex-based parsing was uncertain
+type ParseAmbiguity struct {
+	Kind    string
+	Name    string // Function or class name
+	Line    int    // 1-based line where the ambiguous construct starts
+	Message string
+}
+
+func (a ParseAmbiguity) String() string {
+	return fmt.Sprintf("line %d: %s", a.Line, a.Message)
+}
+
+// findOverlaps reports function ranges that partially overlap. Nested
+// functions are fully contained in their parent and are not reported.
+func findOverlaps(functions []FunctionBounds) []ParseAmbiguity {
+	sorted := make([]FunctionBounds, len(functions))
+	copy(sorted, functions)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var ambiguities []ParseAmbiguity
+	for i, a := range sorted {
+		for _, b := range sorted[i+1:] {
+			if b.Start > a.End {
+				break
+			}
+			if b.End > a.End {
+				ambiguities = append(ambiguities, ParseAmbiguity{
+					Kind:    AmbiguityOverlap,
+					Name:    b.Name,
+					Line:    b.Start,
+					Message: fmt.Sprintf("function %s (%d-%d) overlaps %s (%d-%d)", b.Name, b.Start, b.End, a.Name, a.Start, a.End),
+				})
+			}
+		}
+	}
+	return ambiguities
+}
+
+// FormatAmbiguityReport formats the ambiguities found in one file
+func FormatAmbiguityReport(filename string, ambiguities []ParseAmbiguity) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "parse ambiguities in %s:", filename)
+	for _, a := range ambiguities {
+		fmt.Fprintf(&sb, "\n  %s", a)
+	}
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+// ambiguityKinds runs the finder over content and returns the reported kinds
+func ambiguityKinds(t *testing.T, config *LanguageConfig, content string) []string {
+	t.Helper()
+	finder := NewFinder(config, nil, true, false, false)
+	result, err := finder.FindFunctionsInLines(strings.Split(content, "\n"), 1, "test")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	var kinds []string
+	for _, a := range result.Ambiguities {
+		kinds = append(kinds, a.Kind)
+	}
+	return kinds
+}
+
+func TestAmbiguityNoBrace(t *testing.T) {
+	body := strings.Repeat("int counter_value = 0;\n", MaxSignatureLines+2)
+
+	// Simple path (C): a prototype-looking match never followed by a body
+	kinds := ambiguityKinds(t, getCConfig(t), "int helper(int x)\n"+body)
+	if len(kinds) != 1 || kinds[0] != AmbiguityNoBrace {
+		t.Errorf("C: expected one %s ambiguity, got %v", AmbiguityNoBrace, kinds)
+	}
+
+	// Nested path (Go): body-less declaration, e.g. an assembly stub
+	kinds = ambiguityKinds(t, getGoConfig(t), "package p\n\nfunc stub(x int) int\n"+strings.Repeat("// padding\n", MaxSignatureLines+2))
+	if len(kinds) != 1 || kinds[0] != AmbiguityNoBrace {
+		t.Errorf("Go: expected one %s ambiguity, got %v", AmbiguityNoBrace, kinds)
+	}
+
+	// A multiline signature within the window is not ambiguous
+	kinds = ambiguityKinds(t, getCConfig(t), "int helper(\n    int x,\n    int y)\n{\n    return x;\n}\n")
+	if len(kinds) != 0 {
+		t.Errorf("multiline signature: expected no ambiguities, got %v", kinds)
+	}
+}
+
+func TestAmbiguityUnclosedFunction(t *testing.T) {
+	kinds := ambiguityKinds(t, getGoConfig(t), "package p\n\nfunc broken() {\n\tif true {\n\t}\n")
+	if len(kinds) != 1 || kinds[0] != AmbiguityUnclosedFunction {
+		t.Errorf("Go: expected one %s ambiguity, got %v", AmbiguityUnclosedFunction, kinds)
+	}
+
+	kinds = ambiguityKinds(t, getCConfig(t), "int broken(void)\n{\n    return 1;\n")
+	if len(kinds) != 1 || kinds[0] != AmbiguityUnclosedFunction {
+		t.Errorf("C: expected one %s ambiguity, got %v", AmbiguityUnclosedFunction, kinds)
+	}
+}
+
+func TestAmbiguityUnclosedClass(t *testing.T) {
+	content := "package p\n\ntype Open struct {\n\tA int\n\ntype Empty struct{}\n"
+	kinds := ambiguityKinds(t, getGoConfig(t), content)
+	if len(kinds) != 1 || kinds[0] != AmbiguityUnclosedClass {
+		t.Errorf("expected one %s ambiguity, got %v", AmbiguityUnclosedClass, kinds)
+	}
+
+	// An empty body on the declaration line closes the class immediately
+	kinds = ambiguityKinds(t, getGoConfig(t), "package p\n\ntype Empty struct{}\n\nfunc f() {\n}\n")
+	if len(kinds) != 0 {
+		t.Errorf("empty struct: expected no ambiguities, got %v", kinds)
+	}
+}
+
+func TestAmbiguityOverlap(t *testing.T) {
+	functions := []FunctionBounds{
+		{Name: "outer", Start: 1, End: 20},
+		{Name: "inner", Start: 5, End: 10}, // nested, not an overlap
+		{Name: "crossing", Start: 15, End: 30},
+		{Name: "after", Start: 31, End: 40},
+	}
+	got := findOverlaps(functions)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 overlap, got %d: %v", len(got), got)
+	}
+	if got[0].Kind != AmbiguityOverlap || got[0].Name != "crossing" || got[0].Line != 15 {
+		t.Errorf("unexpected overlap: %+v", got[0])
+	}
+}
+
+func TestAmbiguitiesSkippedForBlockEndLanguages(t *testing.T) {
+	kinds := ambiguityKinds(t, getRubyConfig(t), "class Greeter\n  def greet\n    puts 'hi'\n  end\nend\n")
+	if len(kinds) != 0 {
+		t.Errorf("Ruby: expected no ambiguities, got %v", kinds)
+	}
+}
+
+func TestFormatAmbiguityReport(t *testing.T) {
+	report := FormatAmbiguityReport("main.go", []ParseAmbiguity{
+		{Kind: AmbiguityUnclosedFunction, Name: "f", Line: 3, Message: "function f: body is not closed before end of input"},
+	})
+	want := "parse ambiguities in main.go:\n  line 3: function f: body is not closed before end of input"
+	if report != want {
+		t.Errorf("FormatAmbiguityReport() =\n%s\nwant\n%s", report, want)
+	}
+}
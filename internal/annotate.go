@@ -0,0 +1,69 @@
+// annotate.go - CI annotation output (--annotate)
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AnnotateStyle selects the line format of FormatAnnotations
+type AnnotateStyle string
+
+const (
+	// AnnotateGrep writes "path:line: name", the --dir --map format
+	AnnotateGrep AnnotateStyle = "grep"
+	// AnnotateGitHub writes GitHub Actions workflow commands
+	// ("::notice file=path,line=N,endLine=M,title=function::name") that show
+	// up inline on pull requests
+	AnnotateGitHub AnnotateStyle = "github"
+)
+
+// ParseAnnotateStyle validates an --annotate value
+func ParseAnnotateStyle(s string) (AnnotateStyle, error) {
+	switch style := AnnotateStyle(s); style {
+	case AnnotateGrep, AnnotateGitHub:
+		return style, nil
+	}
+	return "", fmt.Errorf("invalid --annotate style %q (expected grep or github)", s)
+}
+
+// FormatAnnotations writes one annotation per function and class/type of
+// every result. Methods are named Class.method.
+func FormatAnnotations(results []DirResult, style AnnotateStyle) string {
+	var sb strings.Builder
+	for _, r := range results {
+		for _, fn := range r.Functions {
+			name := fn.Name
+			if fn.ClassName != "" {
+				name = fn.ClassName + "." + fn.Name
+			}
+			writeAnnotation(&sb, style, r.Path, fn.Start, fn.End, "function", name)
+		}
+		for _, cl := range r.Classes {
+			writeAnnotation(&sb, style, r.Path, cl.Start, cl.End, "type", cl.Name)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func writeAnnotation(sb *strings.Builder, style AnnotateStyle, path string, start, end int, kind, name string) {
+	if style == AnnotateGitHub {
+		sb.WriteString("::notice file=" + escapeGitHubProperty(path) +
+			",line=" + strconv.Itoa(start) +
+			",endLine=" + strconv.Itoa(end) +
+			",title=" + kind + "::" + escapeGitHubData(name) + "\n")
+		return
+	}
+	sb.WriteString(path + ":" + strconv.Itoa(start) + ": " + name + "\n")
+}
+
+// escapeGitHubData escapes a workflow command message
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a workflow command property value
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func annotateResults() []DirResult {
+	return []DirResult{{
+		Path: "src/app, v2/main.go",
+		Functions: []FunctionBounds{
+			{Name: "Run", Start: 3, End: 9},
+			{Name: "Close", Start: 12, End: 14, ClassName: "Server"},
+		},
+		Classes: []ClassBounds{{Name: "Server", Start: 11, End: 20}},
+	}}
+}
+
+func TestFormatAnnotations_GitHub(t *testing.T) {
+	got := FormatAnnotations(annotateResults(), AnnotateGitHub)
+	want := strings.Join([]string{
+		"::notice file=src/app%2C v2/main.go,line=3,endLine=9,title=function::Run",
+		"::notice file=src/app%2C v2/main.go,line=12,endLine=14,title=function::Server.Close",
+		"::notice file=src/app%2C v2/main.go,line=11,endLine=20,title=type::Server",
+	}, "\n")
+	if got != want {
+		t.Errorf("FormatAnnotations(github) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatAnnotations_Grep(t *testing.T) {
+	got := FormatAnnotations(annotateResults(), AnnotateGrep)
+	if !strings.HasPrefix(got, "src/app, v2/main.go:3: Run\n") || !strings.Contains(got, ":12: Server.Close\n") {
+		t.Errorf("unexpected grep annotations:\n%s", got)
+	}
+}
+
+func TestParseAnnotateStyle(t *testing.T) {
+	if style, err := ParseAnnotateStyle("github"); err != nil || style != AnnotateGitHub {
+		t.Errorf("ParseAnnotateStyle(github) = %q, %v", style, err)
+	}
+	if _, err := ParseAnnotateStyle("gitlab"); err == nil {
+		t.Error("expected error for unknown style")
+	}
+}
@@ -0,0 +1,31 @@
+// anonymous.go - Unnamed functions and IIFEs (--exclude-anonymous)
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SetExcludeAnonymous drops func_pattern matches that don't name a
+// function (--exclude-anonymous): an empty name, or a binding to the result
+// of an immediately invoked function. Named declarations and named arrow
+// functions ("const handler = () => {}") are kept.
+func (f *Finder) SetExcludeAnonymous(exclude bool) {
+	f.excludeAnonymous = exclude
+}
+
+// anonymousMatch reports whether the first funcRegex match in text, which
+// named name, is anonymous. A match ending in "= (" is an IIFE wrapper when
+// the parenthesis holds a function ("const api = (function () {",
+// "const cfg = (() => {", "(async () =>") rather than a parameter list.
+func anonymousMatch(funcRegex *regexp.Regexp, text, name string) bool {
+	if name == "" {
+		return true
+	}
+	loc := funcRegex.FindStringIndex(text)
+	if loc == nil || !strings.HasSuffix(strings.TrimRight(text[:loc[1]], " \t"), "(") {
+		return false
+	}
+	inner := strings.TrimSpace(text[loc[1]:])
+	return strings.HasPrefix(inner, "(") || strings.HasPrefix(inner, "function") || strings.HasPrefix(inner, "async ") || strings.HasPrefix(inner, "async(")
+}
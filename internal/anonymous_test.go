@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func jsFunctionNames(t *testing.T, src string, excludeAnonymous bool) string {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	finder := NewFinder(config["js"], nil, true, false, false)
+	finder.SetExcludeAnonymous(excludeAnonymous)
+	result, err := finder.FindFunctionsInLines(strings.Split(src, "\n"), 1, "app.js")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	var names []string
+	for _, fn := range result.Functions {
+		names = append(names, fn.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestExcludeAnonymous_KeepsNamedArrowDropsCallbacksAndIIFEs(t *testing.T) {
+	src := `const handler = () => {
+  return 1;
+};
+
+button.addEventListener("click", () => {
+  handler();
+});
+
+const api = (function () {
+  return {};
+})();
+
+const boot = (async () => {
+  await start();
+})();
+
+function named(x) {
+  return x;
+}
+
+const onSave = async (e) => {
+  save(e);
+};
+`
+	if got := jsFunctionNames(t, src, false); got != "handler,api,boot,named,onSave" {
+		t.Fatalf("without --exclude-anonymous: functions = %q", got)
+	}
+	if got, want := jsFunctionNames(t, src, true), "handler,named,onSave"; got != want {
+		t.Errorf("--exclude-anonymous: functions = %q, want %q", got, want)
+	}
+}
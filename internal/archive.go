@@ -0,0 +1,154 @@
+// archive.go - Scanning sources inside tar/tar.gz/zip archives without extracting
+package internal
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ArchiveEntrySeparator separates the archive path from the entry path in
+// DirResult.Path, e.g. "code.tar.gz!src/main.go"
+const ArchiveEntrySeparator = "!"
+
+// IsArchive reports whether filename has a supported archive extension
+func IsArchive(filename string) bool {
+	return archiveKind(filename) != ""
+}
+
+// archiveKind returns "tar", "tgz" or "zip" for supported archives, "" otherwise
+func archiveKind(filename string) string {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tgz"
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar"
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	}
+	return ""
+}
+
+// ProcessArchive scans every supported source file inside a .tar, .tar.gz/.tgz
+// or .zip archive. Entries are read into memory one at a time; nothing is
+// extracted to disk. Language is detected per entry by extension, and hidden
+// entries (any path component starting with '.') are skipped like in
+// ProcessDirectory.
+func (dp *DirProcessor) ProcessArchive(archivePath string) ([]DirResult, error) {
+	switch archiveKind(archivePath) {
+	case "zip":
+		zr, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer zr.Close()
+		return dp.processZip(&zr.Reader, archivePath)
+
+	case "tgz", "tar":
+		file, err := os.Open(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer file.Close()
+
+		var r io.Reader = file
+		if archiveKind(archivePath) == "tgz" {
+			gz, err := gzip.NewReader(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open archive: %w", err)
+			}
+			defer gz.Close()
+			r = gz
+		}
+		return dp.processTar(r, archivePath)
+	}
+
+	return nil, fmt.Errorf("unsupported archive format: %s", archivePath)
+}
+
+// processTar scans the entries of an (uncompressed) tar stream
+func (dp *DirProcessor) processTar(r io.Reader, archivePath string) ([]DirResult, error) {
+	var results []DirResult
+	cache := dp.newWorkerCache()
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name, langConfig := dp.archiveEntryLanguage(hdr.Name)
+		if langConfig == nil {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		results = append(results, dp.processSource(archivePath+ArchiveEntrySeparator+name, langConfig, data, cache))
+	}
+	return results, nil
+}
+
+// processZip scans the entries of a zip archive in name order
+func (dp *DirProcessor) processZip(zr *zip.Reader, archivePath string) ([]DirResult, error) {
+	files := make([]*zip.File, len(zr.File))
+	copy(files, zr.File)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	var results []DirResult
+	cache := dp.newWorkerCache()
+	for _, f := range files {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		name, langConfig := dp.archiveEntryLanguage(f.Name)
+		if langConfig == nil {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		results = append(results, dp.processSource(archivePath+ArchiveEntrySeparator+name, langConfig, data, cache))
+	}
+	return results, nil
+}
+
+// archiveEntryLanguage normalizes an entry name and returns it with the
+// language detected from its extension (nil if unsupported, hidden or
+// dropped by SetTestFiles)
+func (dp *DirProcessor) archiveEntryLanguage(entry string) (string, *LanguageConfig) {
+	name := strings.TrimPrefix(path.Clean(strings.ReplaceAll(entry, "\\", "/")), "/")
+	for _, part := range strings.Split(name, "/") {
+		if part != "." && strings.HasPrefix(part, ".") {
+			return name, nil
+		}
+	}
+	langConfig := dp.config.GetLanguageByExtension(name)
+	if langConfig != nil && !dp.testFiles.Keeps(langConfig, name) {
+		return name, nil
+	}
+	return name, langConfig
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func buildTar(t *testing.T, files map[string]string, order []string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range order {
+		body := files[name]
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(body)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("WriteHeader() error = %v", err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+var archiveFixture = map[string]string{
+	"./src/main.go":     "package main\n\nfunc main() {\n}\n\nfunc helper() int {\n\treturn 1\n}\n",
+	"lib/util.py":       "def util():\n    return 1\n",
+	"README.md":         "# not source\n",
+	".git/hooks/pre.go": "package hooks\n\nfunc hidden() {}\n",
+}
+var archiveOrder = []string{"./src/main.go", "lib/util.py", "README.md", ".git/hooks/pre.go"}
+
+func checkArchiveResults(t *testing.T, results []DirResult, archivePath string) {
+	t.Helper()
+	got := map[string][]string{}
+	for _, r := range results {
+		if r.Error != nil {
+			t.Fatalf("%s: unexpected error %v", r.Path, r.Error)
+		}
+		for _, fn := range r.Functions {
+			got[r.Path] = append(got[r.Path], fn.Name)
+		}
+	}
+
+	goPath := archivePath + "!src/main.go"
+	pyPath := archivePath + "!lib/util.py"
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2 (hidden and non-source entries skipped): %+v", len(results), results)
+	}
+	if names := got[goPath]; len(names) != 2 || names[0] != "main" || names[1] != "helper" {
+		t.Errorf("%s functions = %v, want [main helper]", goPath, names)
+	}
+	if names := got[pyPath]; len(names) != 1 || names[0] != "util" {
+		t.Errorf("%s functions = %v, want [util]", pyPath, names)
+	}
+}
+
+func TestProcessTar_InMemory(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	dp := NewDirProcessor(config, 1, true, false, "functions")
+
+	data := buildTar(t, archiveFixture, archiveOrder)
+	results, err := dp.processTar(bytes.NewReader(data), "code.tar")
+	if err != nil {
+		t.Fatalf("processTar() error = %v", err)
+	}
+	checkArchiveResults(t, results, "code.tar")
+
+	for _, r := range results {
+		if r.Path == "code.tar!src/main.go" && r.Functions[1].Start != 6 {
+			t.Errorf("helper start = %d, want 6", r.Functions[1].Start)
+		}
+	}
+}
+
+func TestProcessArchive_TarGzAndZip(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	dp := NewDirProcessor(config, 1, true, false, "functions")
+	tmpDir := t.TempDir()
+
+	// .tar.gz
+	var gzBuf bytes.Buffer
+	gz := gzip.NewWriter(&gzBuf)
+	gz.Write(buildTar(t, archiveFixture, archiveOrder))
+	gz.Close()
+	tgzPath := filepath.Join(tmpDir, "code.tar.gz")
+	mustWrite(t, tgzPath, gzBuf.String())
+
+	results, err := dp.ProcessArchive(tgzPath)
+	if err != nil {
+		t.Fatalf("ProcessArchive(tar.gz) error = %v", err)
+	}
+	checkArchiveResults(t, results, tgzPath)
+
+	// .zip
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	for _, name := range archiveOrder {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip Create() error = %v", err)
+		}
+		w.Write([]byte(archiveFixture[name]))
+	}
+	zw.Close()
+	zipPath := filepath.Join(tmpDir, "code.zip")
+	if err := os.WriteFile(zipPath, zipBuf.Bytes(), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	results, err = dp.ProcessArchive(zipPath)
+	if err != nil {
+		t.Fatalf("ProcessArchive(zip) error = %v", err)
+	}
+	checkArchiveResults(t, results, zipPath)
+}
+
+func TestIsArchive(t *testing.T) {
+	for name, want := range map[string]bool{
+		"a.tar.gz": true, "a.TGZ": true, "a.tar": true, "a.zip": true,
+		"a.go": false, "a.gz": false,
+	} {
+		if got := IsArchive(name); got != want {
+			t.Errorf("IsArchive(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
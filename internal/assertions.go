@@ -0,0 +1,152 @@
+// assertions.go - Assertion calls per test function (--assertions)
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IsTestFunction reports whether fn is a test: a Go TestXxx or FuzzXxx
+// function or a FindTestBlocks block (Category), in other languages also a
+// name starting with "test" (pytest test_x, unittest testX)
+func IsTestFunction(fn FunctionBounds, config *LanguageConfig) bool {
+	if fn.Category == CategoryTest || fn.Category == CategoryFuzz {
+		return true
+	}
+	return config.LangKey != "go" && strings.HasPrefix(strings.ToLower(fn.Name), "test")
+}
+
+// FindTestBlocks returns the tests of lines (the whole file) written as
+// test_block_pattern calls, Jest/Mocha test("adds", () => {...}) and
+// it(...): the finder doesn't see their callbacks as functions. Each block
+// is named after the test's description, has Category test and spans the
+// lines up to the call's closing parenthesis. Calls in comments and strings
+// are skipped. Without a test_block_pattern there are none.
+func FindTestBlocks(lines []string, config *LanguageConfig) []FunctionBounds {
+	testBlockRe := config.TestBlockRegex()
+	if testBlockRe == nil {
+		return nil
+	}
+	cleaned := NewSanitizer(config, false).CleanLines(lines)
+	var blocks []FunctionBounds
+	for i, line := range lines {
+		loc := testBlockRe.FindStringSubmatchIndex(line)
+		if loc == nil {
+			continue
+		}
+		// The sanitizer blanks calls in comments and strings
+		at := loc[0] + len(line[loc[0]:]) - len(strings.TrimLeft(line[loc[0]:], " \t"))
+		if at >= len(cleaned[i]) || cleaned[i][at] != line[at] {
+			continue
+		}
+		name := ""
+		for g := 2; g+1 < len(loc); g += 2 {
+			if loc[g] >= 0 && name == "" {
+				name = line[loc[g]:loc[g+1]]
+			}
+		}
+		end := callEnd(cleaned, i, at)
+		blocks = append(blocks, FunctionBounds{
+			Name:     name,
+			Start:    i + 1,
+			End:      end + 1,
+			Lines:    append([]string(nil), lines[i:end+1]...),
+			Category: CategoryTest,
+		})
+	}
+	return blocks
+}
+
+// callEnd returns the index of the line where the call starting at byte at
+// of cleaned[start] closes its parentheses (the last line if it doesn't)
+func callEnd(cleaned []string, start, at int) int {
+	depth := 0
+	for i := start; i < len(cleaned); i++ {
+		from := 0
+		if i == start {
+			from = at
+		}
+		for _, c := range cleaned[i][from:] {
+			switch c {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					return i
+				}
+			}
+		}
+	}
+	return len(cleaned) - 1
+}
+
+// FilterTestFunctions keeps only the functions IsTestFunction accepts
+func FilterTestFunctions(functions []FunctionBounds, config *LanguageConfig) []FunctionBounds {
+	filtered := make([]FunctionBounds, 0, len(functions))
+	for _, fn := range functions {
+		if IsTestFunction(fn, config) {
+			filtered = append(filtered, fn)
+		}
+	}
+	return filtered
+}
+
+// AttachAssertionCounts sets Assertions for every function to the number
+// of assertion_pattern matches in lines Start..End of lines (the whole
+// file), sanitized so calls in strings and comments don't count; nested
+// functions also count toward their parent. Without an assertion_pattern
+// nothing is set.
+func AttachAssertionCounts(functions []FunctionBounds, lines []string, config *LanguageConfig) {
+	assertionRe := config.AssertionRegex()
+	if assertionRe == nil {
+		return
+	}
+	sanitizer := NewSanitizer(config, false)
+	for i := range functions {
+		fn := &functions[i]
+		if fn.Start < 1 || fn.Start > len(lines) {
+			continue
+		}
+		end := min(max(fn.End, fn.Start), len(lines))
+		count := 0
+		for _, line := range sanitizer.CleanLines(lines[fn.Start-1 : end]) {
+			count += len(assertionRe.FindAllStringIndex(line, -1))
+		}
+		fn.Assertions = &count
+	}
+}
+
+// NoAssertionTests returns the functions counted by AttachAssertionCounts
+// that have no assertion at all: tests that can't fail
+func NoAssertionTests(functions []FunctionBounds) []FunctionBounds {
+	var empty []FunctionBounds
+	for _, fn := range functions {
+		if fn.Assertions != nil && *fn.Assertions == 0 {
+			empty = append(empty, fn)
+		}
+	}
+	return empty
+}
+
+// FormatAssertions prints the assertion count of each test function
+// Example:
+//
+//	NAME        LINES  ASSERTIONS
+//	TestParse   3-12            2
+func FormatAssertions(result *FindResult) string {
+	nameWidth := len("NAME")
+	for _, fn := range result.Functions {
+		nameWidth = max(nameWidth, len(fn.Name))
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-*s  %-9s  %10s\n", nameWidth, "NAME", "LINES", "ASSERTIONS")
+	for _, fn := range result.Functions {
+		count := 0
+		if fn.Assertions != nil {
+			count = *fn.Assertions
+		}
+		fmt.Fprintf(&sb, "%-*s  %-9s  %10d\n", nameWidth, fn.Name, fmt.Sprintf("%d-%d", fn.Start, fn.End), count)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
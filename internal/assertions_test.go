@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// assertionCounts finds the test functions of src and counts their assertions
+func assertionCounts(t *testing.T, config *LanguageConfig, src string) []FunctionBounds {
+	t.Helper()
+	lines := strings.Split(src, "\n")
+	result, err := NewFinder(config, nil, true, false, false).FindFunctionsInLines(lines, 1, "test")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	tests := FilterTestFunctions(result.Functions, config)
+	AttachAssertionCounts(tests, lines, config)
+	return tests
+}
+
+func TestAttachAssertionCounts_GoTests(t *testing.T) {
+	src := `package p
+
+import "testing"
+
+func TestTwo(t *testing.T) {
+	if got := 1; got != 2 {
+		t.Errorf("got %d", got)
+	}
+	// t.Errorf("commented out")
+	_ = "t.Fatal(x)"
+	t.Errorf("again")
+}
+
+func TestNone(t *testing.T) {
+	_ = 1
+}
+
+func helper(t *testing.T) {
+	t.Fatal("not a test")
+}
+`
+	tests := assertionCounts(t, getGoConfig(t), src)
+	if len(tests) != 2 {
+		t.Fatalf("got %d test functions, want 2 (helper is not a test)", len(tests))
+	}
+	for i, want := range map[int]struct {
+		name  string
+		count int
+	}{0: {"TestTwo", 2}, 1: {"TestNone", 0}} {
+		fn := tests[i]
+		if fn.Name != want.name || fn.Assertions == nil || *fn.Assertions != want.count {
+			t.Errorf("tests[%d] = %s with %v assertions, want %s with %d", i, fn.Name, fn.Assertions, want.name, want.count)
+		}
+	}
+
+	empty := NoAssertionTests(tests)
+	if len(empty) != 1 || empty[0].Name != "TestNone" {
+		t.Errorf("NoAssertionTests() = %v, want only TestNone", empty)
+	}
+}
+
+func TestAttachAssertionCounts_PythonTests(t *testing.T) {
+	src := `class TestParse(unittest.TestCase):
+    def test_ok(self):
+        self.assertEqual(parse("1"), 1)
+        self.assertTrue(True)
+
+def test_plain():
+    assert parse("2") == 2
+
+def test_noop():
+    print("assert nothing")
+`
+	config := getPyConfig(t)
+	tests := FilterTestFunctions(findPython(t, config, src, false), config)
+	AttachAssertionCounts(tests, strings.Split(src, "\n"), config)
+	counts := map[string]int{}
+	for _, fn := range tests {
+		if fn.Assertions == nil {
+			t.Fatalf("%s: Assertions not set", fn.Name)
+		}
+		counts[fn.Name] = *fn.Assertions
+	}
+	want := map[string]int{"test_ok": 2, "test_plain": 1, "test_noop": 0}
+	for name, n := range want {
+		if got, ok := counts[name]; !ok || got != n {
+			t.Errorf("%s: %d assertions (found %v), want %d", name, got, ok, n)
+		}
+	}
+}
+
+func TestFindTestBlocks_JestCallbacks(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	js := config["js"]
+	src := `describe('math', () => {
+  test('adds', () => {
+    expect(1 + 1).toBe(2);
+    expect(2).toBeTruthy();
+  });
+  // test('commented', () => {});
+  it("does nothing", function () {
+    const s = "test('in string', () => {})";
+  });
+  test.only(` + "`one line`" + `, () => expect(3).toBe(3));
+});
+`
+	lines := strings.Split(src, "\n")
+	blocks := FindTestBlocks(lines, js)
+	AttachAssertionCounts(blocks, lines, js)
+
+	var got []string
+	for _, b := range blocks {
+		got = append(got, fmt.Sprintf("%s %d-%d %d", b.Name, b.Start, b.End, *b.Assertions))
+	}
+	want := []string{"adds 2-5 2", "does nothing 7-9 0", "one line 10-10 1"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("blocks = %v, want %v", got, want)
+	}
+	for _, b := range blocks {
+		if !IsTestFunction(b, js) {
+			t.Errorf("IsTestFunction(%s) = false, want true", b.Name)
+		}
+	}
+}
+
+func TestSetAssertionPattern(t *testing.T) {
+	config := *getGoConfig(t)
+	if err := config.SetAssertionPattern(`\bcheck\(`); err != nil {
+		t.Fatalf("SetAssertionPattern() error = %v", err)
+	}
+	src := "package p\n\nfunc TestX(t *testing.T) {\n\tcheck(t)\n\tt.Errorf(\"x\")\n}\n"
+	tests := assertionCounts(t, &config, src)
+	if len(tests) != 1 || *tests[0].Assertions != 1 {
+		t.Errorf("custom pattern: got %v, want TestX with 1 assertion", tests)
+	}
+	if err := config.SetAssertionPattern(`(`); err == nil {
+		t.Error("SetAssertionPattern(\"(\") = nil, want error")
+	}
+}
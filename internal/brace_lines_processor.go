@@ -0,0 +1,53 @@
+package internal
+
+// ValidateBraceLineRange is the brace-language counterpart of
+// ValidateAndFixLineRange: using function bounds already found in the whole
+// file, it expands the requested range so that no function is cut in half.
+// A start inside a function moves up to the function's first line, an end
+// inside a function moves down to its last line; this repeats until stable,
+// so nested functions snap to their outermost cut parent.
+// requestedEnd == -1 means EOF and never needs adjusting.
+func ValidateBraceLineRange(functions []FunctionBounds, requestedStart, requestedEnd int) (int, int, []LineAdjustment) {
+	adjustments := []LineAdjustment{}
+	fixedStart, fixedEnd := requestedStart, requestedEnd
+
+	for changed := true; changed; {
+		changed = false
+		for _, fn := range functions {
+			if fn.Start < fixedStart && fixedStart <= fn.End {
+				fixedStart = fn.Start
+				changed = true
+				adjustments = append(adjustments, LineAdjustment{
+					OriginalStart: requestedStart,
+					OriginalEnd:   requestedEnd,
+					FixedStart:    fixedStart,
+					FixedEnd:      fixedEnd,
+					Reason:        "Start line is inside function body (" + formatLineRange(fn.Start, fn.End) + "), expanded to function start",
+					ScopeName:     fn.Name,
+					ScopeKind:     "function",
+				})
+			}
+			if fixedEnd != -1 && fn.Start <= fixedEnd && fixedEnd < fn.End {
+				fixedEnd = fn.End
+				changed = true
+				adjustments = append(adjustments, LineAdjustment{
+					OriginalStart: requestedStart,
+					OriginalEnd:   requestedEnd,
+					FixedStart:    fixedStart,
+					FixedEnd:      fixedEnd,
+					Reason:        "End line is inside function body (" + formatLineRange(fn.Start, fn.End) + "), expanded to function end",
+					ScopeName:     fn.Name,
+					ScopeKind:     "function",
+				})
+			}
+		}
+	}
+
+	return fixedStart, fixedEnd, adjustments
+}
+
+// FormatBraceLineAdjustmentReport creates the --snap-lines report in the same
+// layout as FormatLineAdjustmentReport
+func FormatBraceLineAdjustmentReport(adjustments []LineAdjustment, originalStart, originalEnd, fixedStart, fixedEnd int) string {
+	return formatAdjustmentReport("|                  LINES RANGE ADJUSTMENT REPORT                   |", adjustments, originalStart, originalEnd, fixedStart, fixedEnd)
+}
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+const braceRangeSample = `package demo
+
+func First() {
+	a := 1
+	_ = a
+}
+
+func Second() {
+	fn := func() {
+		println("inner")
+	}
+	fn()
+}
+
+func Third() {}
+`
+
+func braceRangeFunctions(t *testing.T) []FunctionBounds {
+	t.Helper()
+	finder := NewFinder(getGoConfig(t), nil, true, false, false)
+	result, err := finder.FindFunctionsInLines(strings.Split(braceRangeSample, "\n"), 1, "demo.go")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	return result.Functions
+}
+
+func TestValidateBraceLineRange(t *testing.T) {
+	functions := braceRangeFunctions(t)
+
+	tests := []struct {
+		name            string
+		start, end      int
+		wantStart       int
+		wantEnd         int
+		wantAdjustments int
+	}{
+		{"start inside function", 4, 7, 3, 7, 1},
+		{"end inside function", 7, 9, 7, 13, 1},
+		{"both ends inside different functions", 5, 11, 3, 13, 2},
+		{"end inside closure snaps to enclosing function", 1, 10, 1, 13, 1},
+		{"already aligned", 3, 6, 3, 6, 0},
+		{"open end", 10, -1, 8, -1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, adjustments := ValidateBraceLineRange(functions, tt.start, tt.end)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("got %d:%d, want %d:%d", start, end, tt.wantStart, tt.wantEnd)
+			}
+			if len(adjustments) != tt.wantAdjustments {
+				t.Errorf("got %d adjustments, want %d: %+v", len(adjustments), tt.wantAdjustments, adjustments)
+			}
+		})
+	}
+}
+
+func TestFormatBraceLineAdjustmentReport(t *testing.T) {
+	start, end, adjustments := ValidateBraceLineRange(braceRangeFunctions(t), 4, 7)
+	report := FormatBraceLineAdjustmentReport(adjustments, 4, 7, start, end)
+
+	for _, want := range []string{"LINES RANGE ADJUSTMENT REPORT", "Requested range: 4:7", "Adjusted range:  3:7", "function 'First'"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+
+	if FormatBraceLineAdjustmentReport(nil, 3, 6, 3, 6) != "" {
+		t.Error("expected empty report without adjustments")
+	}
+}
@@ -0,0 +1,30 @@
+// byte_offsets.go - Absolute byte offsets of functions (--byte-offsets)
+package internal
+
+import "bytes"
+
+// AttachByteOffsets sets ByteStart and ByteEnd of every function from data,
+// the whole file as read from disk. Offsets count bytes, not characters,
+// so multibyte UTF-8 text is accounted for: ByteStart is the first byte of
+// line Start, ByteEnd the byte after the last one of line End (without its
+// line break, "\n" or "\r\n"), and data[ByteStart:ByteEnd] is the
+// function's source. Each line adds len(line)+1 bytes, where the line still
+// holds the "\r" of a CRLF file, so offsets don't drift on Windows sources.
+func AttachByteOffsets(functions []FunctionBounds, data []byte) {
+	// lineStarts[i] is the offset of line i+1; the extra entry closes the last line
+	lines := bytes.Split(data, []byte("\n"))
+	lineStarts := make([]int, len(lines)+1)
+	for i, line := range lines {
+		lineStarts[i+1] = lineStarts[i] + len(line) + 1
+	}
+
+	for i := range functions {
+		fn := &functions[i]
+		if fn.Start < 1 || fn.Start > len(lines) {
+			continue
+		}
+		end := min(max(fn.End, fn.Start), len(lines))
+		fn.ByteStart = lineStarts[fn.Start-1]
+		fn.ByteEnd = lineStarts[end-1] + len(bytes.TrimSuffix(lines[end-1], []byte("\r")))
+	}
+}
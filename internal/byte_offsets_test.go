@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttachByteOffsets_MultibyteSource(t *testing.T) {
+	src := "package p\n\n// Привет, мир 🌍\nfunc Greet() string {\n\treturn \"日本語\"\n}\n\nfunc Añadir(a, b int) int { return a + b }\n"
+	result, err := NewFinder(getGoConfig(t), nil, true, false, false).FindFunctionsInLines(strings.Split(src, "\n"), 1, "p.go")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	AttachByteOffsets(result.Functions, []byte(src))
+
+	want := map[string]string{
+		"Greet":  "func Greet() string {\n\treturn \"日本語\"\n}",
+		"Añadir": "func Añadir(a, b int) int { return a + b }",
+	}
+	if len(result.Functions) != len(want) {
+		t.Fatalf("found %d functions, want %d", len(result.Functions), len(want))
+	}
+	for _, fn := range result.Functions {
+		if got := src[fn.ByteStart:fn.ByteEnd]; got != want[fn.Name] {
+			t.Errorf("%s: src[%d:%d] = %q, want %q", fn.Name, fn.ByteStart, fn.ByteEnd, got, want[fn.Name])
+		}
+	}
+}
+
+func TestAttachByteOffsets_CRLF(t *testing.T) {
+	src := "# ü\r\ndef ok():\r\n    return 1\r\n"
+	functions := []FunctionBounds{{Name: "ok", Start: 2, End: 3}}
+	AttachByteOffsets(functions, []byte(src))
+
+	if got, want := src[functions[0].ByteStart:functions[0].ByteEnd], "def ok():\r\n    return 1"; got != want {
+		t.Errorf("src[%d:%d] = %q, want %q", functions[0].ByteStart, functions[0].ByteEnd, got, want)
+	}
+}
+
+func TestAttachByteOffsets_OutOfRangeLeftUnset(t *testing.T) {
+	functions := []FunctionBounds{{Name: "ghost", Start: 10, End: 12}}
+	AttachByteOffsets(functions, []byte("one line\n"))
+	if functions[0].ByteStart != 0 || functions[0].ByteEnd != 0 {
+		t.Errorf("offsets = %d:%d, want unset", functions[0].ByteStart, functions[0].ByteEnd)
+	}
+}
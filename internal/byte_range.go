@@ -0,0 +1,69 @@
+// byte_range.go - Re-scan of the definitions around a byte window (editor integration)
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FindFunctionsInByteRange returns the functions overlapping the byte
+// window [from, to) of data, the whole file; from == to is a cursor
+// position. Only the top-level blocks the window touches are parsed: blocks
+// are cut where the brace depth outside comments and strings returns to
+// zero, so a window inside a function yields the whole function (and, in a
+// class, the methods the window overlaps) and a window between functions
+// yields none; nested functions inside the blocks are found as usual.
+// Functions carry ByteStart/ByteEnd as with --byte-offsets. Languages whose
+// blocks don't end with a brace (Python, Ruby, Haskell) are not supported.
+func (f *Finder) FindFunctionsInByteRange(data []byte, from, to int) ([]FunctionBounds, error) {
+	if f.config.IndentBased || f.config.BlockEndKeyword != "" || f.config.LangKey == "hs" {
+		return nil, fmt.Errorf("byte range scan is not supported for %s: it needs a brace language", f.config.Name)
+	}
+	if from < 0 || to < from || to > len(data) {
+		return nil, fmt.Errorf("invalid byte range %d-%d for %d bytes", from, to, len(data))
+	}
+
+	raw := bytes.Split(data, []byte("\n"))
+	lines := make([]string, len(raw))
+	lineStarts := make([]int, len(raw))
+	for i, line := range raw {
+		lines[i] = strings.TrimSuffix(string(line), "\r")
+		if i > 0 {
+			lineStarts[i] = lineStarts[i-1] + len(raw[i-1]) + 1
+		}
+	}
+	// line numbers (1-based) of the first and last byte of the window
+	lineOf := func(offset int) int {
+		return sort.Search(len(lineStarts), func(i int) bool { return lineStarts[i] > offset })
+	}
+	firstLine, lastLine := lineOf(from), lineOf(max(to-1, from))
+
+	// contiguous top-level blocks touching the window, as [start, end) indexes
+	start, end := -1, -1
+	for _, r := range cutTopLevelRegions(lines, f.sanitizer, 1) {
+		if r[1] >= firstLine && r[0] < lastLine {
+			if start < 0 {
+				start = r[0]
+			}
+			end = r[1]
+		}
+	}
+	if start < 0 {
+		return []FunctionBounds{}, nil
+	}
+
+	result, err := f.FindFunctionsInLines(lines[start:end], start+1, "")
+	if err != nil {
+		return nil, err
+	}
+	functions := []FunctionBounds{}
+	for _, fn := range result.Functions {
+		if fn.Start <= lastLine && fn.End >= firstLine {
+			functions = append(functions, fn)
+		}
+	}
+	AttachByteOffsets(functions, data)
+	return functions, nil
+}
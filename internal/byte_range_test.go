@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const byteRangeSample = `package p
+
+// A is first
+func A() {
+	s := "}"
+	_ = s
+}
+
+func B(x int) int {
+	if x > 0 {
+		return x
+	}
+	return 0
+}
+`
+
+func TestFindFunctionsInByteRange(t *testing.T) {
+	finder := NewFinder(getGoConfig(t), nil, true, false, false)
+	data := []byte(byteRangeSample)
+	at := func(s string) int { return strings.Index(byteRangeSample, s) }
+
+	tests := []struct {
+		name     string
+		from, to int
+		want     string
+	}{
+		{"cursor in A after a brace in a string", at(`_ = s`), at(`_ = s`), "A 4-7"},
+		{"range inside B's if", at("return x"), at("return 0"), "B 9-14"},
+		{"range across both", at("s :="), at("x > 0"), "A 4-7, B 9-14"},
+		{"blank line between", at("\n\nfunc B") + 1, at("\n\nfunc B") + 2, ""},
+		{"package clause", 0, len("package p"), ""},
+	}
+	for _, tt := range tests {
+		functions, err := finder.FindFunctionsInByteRange(data, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("%s: FindFunctionsInByteRange() error = %v", tt.name, err)
+		}
+		var got []string
+		for _, fn := range functions {
+			got = append(got, fmt.Sprintf("%s %d-%d", fn.Name, fn.Start, fn.End))
+		}
+		if strings.Join(got, ", ") != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, strings.Join(got, ", "), tt.want)
+		}
+	}
+
+	functions, _ := finder.FindFunctionsInByteRange(data, at("return x"), at("return x"))
+	if fn := functions[0]; string(data[fn.ByteStart:fn.ByteEnd]) != byteRangeSample[at("func B"):len(byteRangeSample)-1] {
+		t.Errorf("B byte offsets %d-%d don't cover its source", fn.ByteStart, fn.ByteEnd)
+	}
+	if _, err := finder.FindFunctionsInByteRange(data, 10, len(data)+1); err == nil {
+		t.Error("FindFunctionsInByteRange() accepted a range past the end of the data")
+	}
+}
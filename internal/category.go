@@ -0,0 +1,101 @@
+// category.go - Classification of Go functions by go test/runtime role
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Function categories reported in FunctionBounds.Category (Go only)
+const (
+	CategoryInit      = "init"
+	CategoryTest      = "test"
+	CategoryBenchmark = "benchmark"
+	CategoryExample   = "example"
+	CategoryFuzz      = "fuzz"
+)
+
+// ValidCategories lists the values accepted by --category
+var ValidCategories = []string{CategoryTest, CategoryBenchmark, CategoryInit, CategoryExample, CategoryFuzz}
+
+var (
+	// func TestXxx(t *testing.T) — the parameter type letter must match the prefix
+	goTestFuncRe = regexp.MustCompile(`^\s*func\s+(Test|Benchmark|Fuzz)([\p{L}\p{Nd}_]*)\s*\(\s*[\p{L}\p{Nd}_]+\s+\*(?:[\p{L}\p{Nd}_]+\.)?([TBF])\s*\)`)
+	// func init() / func ExampleXxx() — no receiver, no parameters
+	goNoArgFuncRe = regexp.MustCompile(`^\s*func\s+(init|Example[\p{L}\p{Nd}_]*)\s*\(\s*\)`)
+)
+
+// ClassifyGoFunction returns the category of a Go function from its name and
+// signature line, following the go test naming rules (TestXxx where Xxx does
+// not start with a lowercase letter, with a *testing.T/B/F parameter). Methods
+// and functions with other signatures get "".
+func ClassifyGoFunction(name, signature string) string {
+	if m := goNoArgFuncRe.FindStringSubmatch(signature); m != nil && m[1] == name {
+		if name == "init" {
+			return CategoryInit
+		}
+		if validTestSuffix(strings.TrimPrefix(name, "Example")) {
+			return CategoryExample
+		}
+		return ""
+	}
+
+	m := goTestFuncRe.FindStringSubmatch(signature)
+	if m == nil || m[1]+m[2] != name || !validTestSuffix(m[2]) {
+		return ""
+	}
+	switch {
+	case m[1] == "Test" && m[3] == "T":
+		return CategoryTest
+	case m[1] == "Benchmark" && m[3] == "B":
+		return CategoryBenchmark
+	case m[1] == "Fuzz" && m[3] == "F":
+		return CategoryFuzz
+	}
+	return ""
+}
+
+// validTestSuffix reports whether the part after Test/Benchmark/Example/Fuzz
+// is acceptable to go test: empty or not starting with a lowercase letter.
+// Example suffixes also allow "_suffix" forms.
+func validTestSuffix(suffix string) bool {
+	if suffix == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(suffix)
+	return !unicode.IsLower(r)
+}
+
+// tagGoCategories fills Category for functions found in lines
+func tagGoCategories(functions []FunctionBounds, lines []string, lineOffset int) {
+	for i := range functions {
+		idx := functions[i].Start - 1 - lineOffset
+		if idx >= 0 && idx < len(lines) {
+			functions[i].Category = ClassifyGoFunction(functions[i].Name, lines[idx])
+		}
+	}
+}
+
+// ParseCategory validates a --category value
+func ParseCategory(category string) (string, error) {
+	for _, c := range ValidCategories {
+		if category == c {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("invalid category %q (valid: %s)", category, strings.Join(ValidCategories, ", "))
+}
+
+// FilterByCategory keeps only functions of the given category
+func FilterByCategory(functions []FunctionBounds, category string) []FunctionBounds {
+	filtered := make([]FunctionBounds, 0, len(functions))
+	for _, fn := range functions {
+		if fn.Category == category {
+			filtered = append(filtered, fn)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,104 @@
+package internal
+
+import "testing"
+
+func TestClassifyGoFunction(t *testing.T) {
+	tests := []struct {
+		name, signature, want string
+	}{
+		{"init", "func init() {", CategoryInit},
+		{"TestParse", "func TestParse(t *testing.T) {", CategoryTest},
+		{"Test", "func Test(t *testing.T) {", CategoryTest},
+		{"Test_edge", "func Test_edge(t *testing.T) {", CategoryTest},
+		{"BenchmarkParse", "func BenchmarkParse(b *testing.B) {", CategoryBenchmark},
+		{"FuzzParse", "func FuzzParse(f *testing.F) {", CategoryFuzz},
+		{"ExampleParse", "func ExampleParse() {", CategoryExample},
+		{"Example_suffix", "func Example_suffix() {", CategoryExample},
+		// Not recognized by go test
+		{"Testify", "func Testify(t *testing.T) {", ""},
+		{"TestParse", "func TestParse(b *testing.B) {", ""},
+		{"TestHelper", "func TestHelper(t *testing.T, name string) {", ""},
+		{"init", "func init(x int) {", ""},
+		{"Examples", "func Examples() {", ""},
+		{"ExampleParse", "func ExampleParse(t *testing.T) {", ""},
+		{"TestParse", "func (s *Suite) TestParse(t *testing.T) {", ""},
+		{"helper", "func helper() {", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyGoFunction(tt.name, tt.signature); got != tt.want {
+			t.Errorf("ClassifyGoFunction(%q) = %q, want %q", tt.signature, got, tt.want)
+		}
+	}
+}
+
+func TestFinderGoCategories(t *testing.T) {
+	content := `package demo
+
+import "testing"
+
+func init() {
+}
+
+func helper() int {
+	return 1
+}
+
+func TestHelper(t *testing.T) {
+	helper()
+}
+
+func BenchmarkHelper(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		helper()
+	}
+}
+
+func ExampleHelper() {
+}
+
+func FuzzHelper(f *testing.F) {
+}
+`
+	tmpFile := createTempFile(t, content, "*_test.go")
+
+	finder := NewFinder(getGoConfig(t), nil, true, false, false)
+	result, err := finder.FindFunctions(tmpFile)
+	if err != nil {
+		t.Fatalf("FindFunctions failed: %v", err)
+	}
+
+	want := map[string]string{
+		"init":            CategoryInit,
+		"helper":          "",
+		"TestHelper":      CategoryTest,
+		"BenchmarkHelper": CategoryBenchmark,
+		"ExampleHelper":   CategoryExample,
+		"FuzzHelper":      CategoryFuzz,
+	}
+	if len(result.Functions) != len(want) {
+		t.Fatalf("Expected %d functions, got %d", len(want), len(result.Functions))
+	}
+	for _, fn := range result.Functions {
+		if fn.Category != want[fn.Name] {
+			t.Errorf("%s: category = %q, want %q", fn.Name, fn.Category, want[fn.Name])
+		}
+	}
+
+	tests := FilterByCategory(result.Functions, CategoryTest)
+	if len(tests) != 1 || tests[0].Name != "TestHelper" {
+		t.Errorf("FilterByCategory(test) = %+v, want only TestHelper", tests)
+	}
+	if got := FilterByCategory(result.Functions, CategoryBenchmark); len(got) != 1 || got[0].Name != "BenchmarkHelper" {
+		t.Errorf("FilterByCategory(benchmark) = %+v, want only BenchmarkHelper", got)
+	}
+}
+
+func TestParseCategory(t *testing.T) {
+	if _, err := ParseCategory("benchmark"); err != nil {
+		t.Errorf("ParseCategory(benchmark) unexpected error: %v", err)
+	}
+	if _, err := ParseCategory("unit"); err == nil {
+		t.Error("ParseCategory(unit) expected error")
+	}
+}
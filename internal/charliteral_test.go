@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -65,3 +66,51 @@ func TestCharLiteralSanitized(t *testing.T) {
 		}
 	}
 }
+
+// Rust lifetimes ('a, 'static) and labels share the char delimiter; with
+// lifetime_syntax they stay code, while a real char literal on the same line
+// is still blanked.
+func TestCharLiteral_RustLifetimes(t *testing.T) {
+	c, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	cfg := c["rust"]
+	s := NewSanitizer(cfg, false)
+
+	tests := []struct {
+		line, want string
+	}{
+		{`fn foo<'a>(x: &'a str) -> &'a str {`, `fn foo<'a>(x: &'a str) -> &'a str {`},
+		{`static NAME: &'static str = "x";`, `static NAME: &'static str =    ;`},
+		{`fn first<'a>(s: &'a str) -> char { if s == "" { '{' } else { 'x' } }`, `fn first<'a>(s: &'a str) -> char { if s ==    {     } else {     } }`},
+		{`'outer: loop { break 'outer; }`, `'outer: loop { break 'outer; }`},
+		{`let c = '\''; let r: &'b u8;`, `let c =     ; let r: &'b u8;`},
+	}
+	for _, tt := range tests {
+		got, state := s.CleanLine(tt.line, StateNormal)
+		if got != tt.want || state != StateNormal {
+			t.Errorf("CleanLine(%q) = %q (state %v), want %q", tt.line, got, state, tt.want)
+		}
+	}
+
+	code := "fn longest<'a>(x: &'a str, y: &'a str) -> &'a str {\n    if x.len() > y.len() { x } else { y }\n}\n\nfn chars() -> [char; 2] {\n    ['{', 'y']\n}\n\nfn after() {}\n"
+	res, err := NewFinder(cfg, nil, true, false, false).FindFunctionsInLines(strings.Split(code, "\n"), 1, "l.rs")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	var got []string
+	for _, fn := range res.Functions {
+		got = append(got, fmt.Sprintf("%s %d-%d", fn.Name, fn.Start, fn.End))
+	}
+	if want := "longest 1-3|chars 5-7|after 9-9"; strings.Join(got, "|") != want {
+		t.Errorf("functions = %v, want %s", got, want)
+	}
+
+	// Without lifetime_syntax the same quote opens a char literal
+	plain := *cfg
+	plain.LifetimeSyntax = false
+	if got, _ := NewSanitizer(&plain, false).CleanLine(`&'a str`, StateNormal); got == `&'a str` {
+		t.Errorf("lifetime kept as code without LifetimeSyntax: %q", got)
+	}
+}
@@ -0,0 +1,37 @@
+// closures.go - Counting anonymous function literals inside function bodies
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// countClosures sets ClosureCount for every function: the number of
+// closure_pattern matches in its body. cleaned are sanitized lines (strings
+// and comments blanked) of the same slice the functions were found in.
+// Everything before the first "{" of the function is its signature, so a
+// declaring arrow (const f = () => {) or a func-typed parameter is not counted.
+func countClosures(functions []FunctionBounds, cleaned []string, lineOffset int, closureRe *regexp.Regexp) {
+	for i := range functions {
+		fn := &functions[i]
+		count := 0
+		bodyStarted := false
+		for lineNum := fn.Start; lineNum <= fn.End; lineNum++ {
+			idx := lineNum - 1 - lineOffset
+			if idx < 0 || idx >= len(cleaned) {
+				continue
+			}
+			text := cleaned[idx]
+			if !bodyStarted {
+				brace := strings.Index(text, "{")
+				if brace < 0 {
+					continue
+				}
+				bodyStarted = true
+				text = text[brace+1:]
+			}
+			count += len(closureRe.FindAllStringIndex(text, -1))
+		}
+		fn.ClosureCount = count
+	}
+}
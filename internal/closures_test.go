@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func closureCounts(t *testing.T, config *LanguageConfig, code string) map[string]int {
+	t.Helper()
+	finder := NewFinder(config, nil, true, false, false)
+	result, err := finder.FindFunctionsInLines(strings.Split(code, "\n"), 1, "sample")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, fn := range result.Functions {
+		counts[fn.Name] = fn.ClosureCount
+	}
+	return counts
+}
+
+func TestClosureCount_Go(t *testing.T) {
+	code := `package demo
+
+func Run(handler func(int) error) {
+	defer func() {
+		recover()
+	}()
+	apply := func(x int) int { return x * 2 }
+	println("func() { not a closure }", apply(1))
+	// func() {} in a comment
+}
+
+func Plain() {}
+`
+	counts := closureCounts(t, getGoConfig(t), code)
+	if counts["Run"] != 2 {
+		t.Errorf("Run: ClosureCount = %d, want 2", counts["Run"])
+	}
+	if counts["Plain"] != 0 {
+		t.Errorf("Plain: ClosureCount = %d, want 0", counts["Plain"])
+	}
+}
+
+func TestClosureCount_JSArrowCallbacks(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	code := `const load = (items) => {
+  const ids = items.map((item) => item.id);
+  ids.forEach(id => console.log("=>", id));
+  setTimeout(function () { done(); }, 10);
+};
+
+function plain(a) {
+  return a;
+}
+`
+	counts := closureCounts(t, config["js"], code)
+	if counts["load"] != 3 {
+		t.Errorf("load: ClosureCount = %d, want 3", counts["load"])
+	}
+	if counts["plain"] != 0 {
+		t.Errorf("plain: ClosureCount = %d, want 0", counts["plain"])
+	}
+}
+
+func TestFormatJSON_ClosureCount(t *testing.T) {
+	result := &FindResult{Functions: []FunctionBounds{{Name: "Run", Start: 1, End: 5, ClosureCount: 2}, {Name: "Plain", Start: 6, End: 6}}}
+	out, err := FormatJSON(result)
+	if err != nil {
+		t.Fatalf("FormatJSON failed: %v", err)
+	}
+	if !strings.Contains(out, `"closure_count": 2`) || strings.Count(out, "closure_count") != 1 {
+		t.Errorf("expected closure_count only for Run, got:\n%s", out)
+	}
+}
@@ -0,0 +1,103 @@
+// component.go - <script> blocks of .vue / .svelte single-file components
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ComponentExtensions are the single-file component formats: markup,
+// <script> and <style> in one file
+var ComponentExtensions = []string{".vue", ".svelte"}
+
+var (
+	// scriptOpenTag starts a <script> tag at the beginning of a line; the
+	// attributes may continue on the following lines up to ">"
+	scriptOpenTag = regexp.MustCompile(`^\s*<script\b`)
+	// scriptCloseTag ends a <script> block
+	scriptCloseTag = regexp.MustCompile(`</script\s*>`)
+	// scriptTSLang is a lang="ts" attribute of <script>
+	scriptTSLang = regexp.MustCompile(`\blang\s*=\s*["']?(?:ts|tsx|typescript)\b`)
+)
+
+// ScriptBlock is the code of one <script> element: the lines between the
+// opening and the closing tag
+type ScriptBlock struct {
+	Lines     []string
+	StartLine int    // line of Lines[0] in the component file (1-based)
+	LangKey   string // "ts" for lang="ts", otherwise "js"
+}
+
+// IsComponentFile reports whether path is a .vue or .svelte component
+func IsComponentFile(path string) bool {
+	return containsString(ComponentExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// ExtractScriptBlocks returns the <script> blocks of a component in file
+// order (Vue "<script>" and "<script setup>", Svelte
+// "<script context="module">"). Code on the tag lines themselves is not
+// part of a block; a block without a closing tag runs to the end of file.
+func ExtractScriptBlocks(lines []string) []ScriptBlock {
+	var blocks []ScriptBlock
+	for i := 0; i < len(lines); i++ {
+		if !scriptOpenTag.MatchString(lines[i]) {
+			continue
+		}
+		// The opening tag ends at the first ">" (multi-line attributes)
+		tag := lines[i]
+		for !strings.Contains(tag[strings.Index(tag, "<script"):], ">") && i+1 < len(lines) {
+			i++
+			tag += " " + lines[i]
+		}
+		if scriptCloseTag.MatchString(tag) {
+			continue // one-line <script ...></script>
+		}
+
+		block := ScriptBlock{StartLine: i + 2, LangKey: "js"}
+		if scriptTSLang.MatchString(tag) {
+			block.LangKey = "ts"
+		}
+		for i++; i < len(lines) && !scriptCloseTag.MatchString(lines[i]); i++ {
+			block.Lines = append(block.Lines, lines[i])
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// findComponentFunctions runs FindFunctionsInLines over every <script>
+// block of a component and merges the results, so line numbers point into
+// the component file
+func (f *Finder) findComponentFunctions(lines []string, filename string) (*FindResult, error) {
+	merged := &FindResult{Filename: filename, Functions: []FunctionBounds{}}
+	if !f.noClasses {
+		merged.Classes = []ClassBounds{}
+	}
+	for _, block := range ExtractScriptBlocks(lines) {
+		result, err := f.FindFunctionsInLines(block.Lines, block.StartLine, filename)
+		if err != nil {
+			return nil, err
+		}
+		merged.Functions = append(merged.Functions, result.Functions...)
+		if !f.noClasses {
+			merged.Classes = append(merged.Classes, result.Classes...)
+		}
+		merged.Ambiguities = append(merged.Ambiguities, result.Ambiguities...)
+	}
+	return merged, nil
+}
+
+// detectComponentLanguage picks TypeScript for a component whose first
+// <script> block has lang="ts" and JavaScript otherwise
+func (c Config) detectComponentLanguage(path string) *LanguageConfig {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c["js"]
+	}
+	if blocks := ExtractScriptBlocks(strings.Split(string(data), "\n")); len(blocks) > 0 {
+		return c[blocks[0].LangKey]
+	}
+	return c["js"]
+}
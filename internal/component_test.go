@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const vueComponent = `<template>
+  <button @click="increment">{{ label(count) }}</button>
+</template>
+
+<script setup
+        lang="ts">
+import { ref } from 'vue'
+
+const count = ref(0)
+
+function increment(): void {
+  count.value++
+}
+
+function label(n: number): string {
+  return "clicked " + n
+}
+</script>
+
+<style scoped>
+button { color: red; }
+</style>
+`
+
+func TestExtractScriptBlocks_VueKeepsOffsets(t *testing.T) {
+	blocks := ExtractScriptBlocks(strings.Split(vueComponent, "\n"))
+	if len(blocks) != 1 {
+		t.Fatalf("blocks = %+v, want 1", blocks)
+	}
+	b := blocks[0]
+	if b.StartLine != 7 || b.LangKey != "ts" {
+		t.Errorf("block StartLine = %d, LangKey = %q; want 7, ts", b.StartLine, b.LangKey)
+	}
+	if b.Lines[0] != "import { ref } from 'vue'" || b.Lines[len(b.Lines)-1] != "}" {
+		t.Errorf("block lines = %q", b.Lines)
+	}
+}
+
+func TestFinder_VueComponentOriginalLines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Counter.vue")
+	if err := os.WriteFile(path, []byte(vueComponent), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	langConfig := config.DetectLanguage(path)
+	if langConfig == nil || langConfig.LangKey != "ts" {
+		t.Fatalf("DetectLanguage(%s) = %v, want ts", path, langConfig)
+	}
+
+	result, err := NewFinder(langConfig, nil, true, false, false).FindFunctions(path)
+	if err != nil {
+		t.Fatalf("FindFunctions() error = %v", err)
+	}
+	want := []FunctionBounds{{Name: "increment", Start: 11, End: 13}, {Name: "label", Start: 15, End: 17}}
+	if len(result.Functions) != len(want) {
+		t.Fatalf("functions = %+v, want %+v", result.Functions, want)
+	}
+	for i, w := range want {
+		got := result.Functions[i]
+		if got.Name != w.Name || got.Start != w.Start || got.End != w.End {
+			t.Errorf("function %d = %s %d-%d, want %s %d-%d", i, got.Name, got.Start, got.End, w.Name, w.Start, w.End)
+		}
+	}
+}
+
+func TestExtractScriptBlocks_SvelteModuleAndInstance(t *testing.T) {
+	src := "<script context=\"module\">\n  export function preload() {}\n</script>\n\n<script>\n  function increment() {}\n</script>\n<script src=\"x.js\"></script>\n"
+	blocks := ExtractScriptBlocks(strings.Split(src, "\n"))
+	if len(blocks) != 2 || blocks[0].StartLine != 2 || blocks[1].StartLine != 6 || blocks[1].LangKey != "js" {
+		t.Errorf("blocks = %+v, want module block at 2 and instance block at 6 (js)", blocks)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strings"
+	"unicode"
 )
 
 //go:embed languages.json
@@ -25,14 +27,49 @@ type LanguageConfig struct {
 	// Basic info
 	Name       string   `json:"name"`
 	Extensions []string `json:"extensions"`
+	// ShebangInterpreters names the interpreters of extensionless scripts
+	// ("#!/usr/bin/env python3"); see GetLanguageByShebang
+	ShebangInterpreters []string `json:"shebang_interpreters,omitempty"`
+	// TestFilePatterns are filepath.Match globs on the base name of test
+	// files ("*_test.go"); see --exclude-tests and --tests-only
+	TestFilePatterns []string `json:"test_file_patterns,omitempty"`
 
 	// Function/Class patterns (for funcfinder)
 	FuncPattern  string `json:"func_pattern"`
 	ClassPattern string `json:"class_pattern"`
+	// ExpressionBodyPattern matches a signature line whose body is a single
+	// expression or absent (Scala "def f(x: Int): Int = x + 1"); such
+	// functions end on the signature line instead of waiting for a brace.
+	ExpressionBodyPattern string `json:"expression_body_pattern,omitempty"`
+	// ClosurePattern matches an anonymous function literal inside a body
+	// (Go "func(...) {", JS "=>" and "function("); see closures.go.
+	ClosurePattern string `json:"closure_pattern,omitempty"`
+	// GeneratorPattern marks a function as a generator when it matches one of
+	// the function's own sanitized lines (Python yield, JS function*)
+	GeneratorPattern string `json:"generator_pattern,omitempty"`
+	// AssertionPattern matches one assertion call on a sanitized line of a
+	// test function (Go t.Errorf, Python self.assertEqual); see assertions.go.
+	AssertionPattern string `json:"assertion_pattern,omitempty"`
+	// TestBlockPattern matches a test written as a call with a callback
+	// (Jest/Mocha test("name", () => {...}), it(...)); the first non-empty
+	// group is the test's name. See FindTestBlocks.
+	TestBlockPattern string `json:"test_block_pattern,omitempty"`
+	// AbstractPattern matches a method declared without a body (Java/C#
+	// interface and abstract methods, C++ pure virtual "= 0;"); outside a
+	// function body such a line is a zero-body function with IsAbstract set.
+	AbstractPattern string `json:"abstract_pattern,omitempty"`
+	// MacroPattern matches the first line of a function-like preprocessor
+	// macro (C/C++ "#define MAX(a, b)"), the name in group 1; reported with
+	// --include-macros, see macros.go.
+	MacroPattern string `json:"macro_pattern,omitempty"`
 
 	// Struct/type patterns (for findstruct) - stored as map for flexible access
 	StructTypePatterns []StructTypePattern `json:"struct_type_patterns,omitempty"`
 	FieldPattern       string              `json:"field_pattern,omitempty"`
+	// ParamFieldsPattern matches a type header up to the "(" that opens its
+	// constructor parameters (Scala case classes); each "name: Type"
+	// parameter is reported as a field.
+	ParamFieldsPattern string `json:"param_fields_pattern,omitempty"`
 
 	// Call patterns (for stat.go)
 	CallPattern      string   `json:"call_pattern"`
@@ -43,6 +80,8 @@ type LanguageConfig struct {
 	ImportPattern    string   `json:"import_pattern"`
 	MultiLineBlock   string   `json:"multi_line_block"`
 	ExcludePatterns  []string `json:"exclude_patterns"`
+	// StdlibPrefixes lists standard library modules; see IsStdlib
+	StdlibPrefixes []string `json:"stdlib_prefixes,omitempty"`
 
 	// Comment/String handling
 	LineComment       string   `json:"line_comment"`
@@ -52,13 +91,30 @@ type LanguageConfig struct {
 	RawStringChars    []string `json:"raw_string_chars"`
 	EscapeChar        string   `json:"escape_char"`
 	CharDelimiters    []string `json:"char_delimiters,omitempty"`
+	LifetimeSyntax    bool     `json:"lifetime_syntax,omitempty"` // A quote before an unclosed identifier is a lifetime (Rust 'a), not a char literal
 	DocStringMarkers  []string `json:"doc_string_markers,omitempty"`
+	DocCommentLine    []string `json:"doc_comment_line,omitempty"`  // Doc comment line markers (Rust "///", "//!"); empty: every LineComment line is doc
+	DocCommentBlock   string   `json:"doc_comment_block,omitempty"` // Doc comment block opener (Java "/**"); empty: every block comment is doc
 	IndentBased       bool     `json:"indent_based"`
-	BlockEndKeyword   string   `json:"block_end_keyword,omitempty"` // For Ruby-like languages (end keyword)
+	BlockEndKeyword   string   `json:"block_end_keyword,omitempty"`    // For Ruby-like languages (end keyword)
+	Interpolation     string   `json:"string_interpolation,omitempty"` // Opens an expression inside strings, closed by '}' (Dart "${expr}")
+
+	// IndentBlockSuffix ends a header whose body is the indented block below
+	// it (Nim "proc f() ="); empty means ":" as in Python
+	IndentBlockSuffix string `json:"indent_block_suffix,omitempty"`
+
+	// Keyword blocks (Elixir): BlockOpenPattern matches a keyword that opens
+	// a block closed by BlockEndKeyword; when set, KeywordFinder is used
+	BlockOpenPattern string `json:"block_open_pattern,omitempty"`
+	// SigilPrefix starts a sigil literal such as Elixir ~s(...) or ~w[...]
+	SigilPrefix string `json:"sigil_prefix,omitempty"`
 
 	// Nested function support
 	SupportsNested bool `json:"supports_nested"`
 
+	// Visibility rule for --exported-only (see export.go)
+	ExportRule ExportRule `json:"export_rule,omitempty"`
+
 	// Language key for stdlib detection (e.g., "py", "go", "rs")
 	LangKey string `json:"lang_key"`
 
@@ -70,10 +126,20 @@ type LanguageConfig struct {
 	classRegex      *regexp.Regexp
 	structPatterns  map[string]*regexp.Regexp
 	fieldRegex      *regexp.Regexp
+	paramFieldsRe   *regexp.Regexp
+	exprBodyRegex   *regexp.Regexp
+	blockOpenRegex  *regexp.Regexp
+	closureRegex    *regexp.Regexp
+	generatorRegex  *regexp.Regexp
+	assertionRegex  *regexp.Regexp
+	testBlockRegex  *regexp.Regexp
+	abstractRegex   *regexp.Regexp
+	macroRegex      *regexp.Regexp
 	callRegex       *regexp.Regexp
 	importRegex     *regexp.Regexp
 	decoratorRe     *regexp.Regexp
 	blockCommentRe  *regexp.Regexp
+	extraKinds      []string // kinds added by AddStructPattern, in order
 }
 
 // Config is a map of language keys to their configurations
@@ -90,13 +156,13 @@ type LanguageConfigWithMap struct {
 func LoadConfig() (Config, error) {
 	data, err := languagesFS.ReadFile("languages.json")
 	if err != nil {
-		return nil, fmt.Errorf("failed to read languages.json: %w", err)
+		return nil, &ConfigError{Kind: ErrConfigLoad, Msg: "failed to read languages.json", Err: err}
 	}
 
 	// First, unmarshal into a map to handle struct_type_patterns as object
 	var rawConfig map[string]*LanguageConfigWithMap
 	if err := json.Unmarshal(data, &rawConfig); err != nil {
-		return nil, fmt.Errorf("failed to parse languages.json: %w", err)
+		return nil, &ConfigError{Kind: ErrConfigLoad, Msg: "failed to parse languages.json", Err: err}
 	}
 
 	// Convert to final Config
@@ -110,7 +176,7 @@ func LoadConfig() (Config, error) {
 		for typeKind, pattern := range langConf.StructTypePatternsMap {
 			re, err := regexp.Compile(expandIdentPlaceholder(pattern))
 			if err != nil {
-				return nil, fmt.Errorf("invalid struct pattern for %s (%s): %w", lang, typeKind, err)
+				return nil, newPatternError(lang, "struct_type_patterns", fmt.Sprintf("invalid struct pattern for %s (%s)", lang, typeKind), err)
 			}
 			conf.structPatterns[typeKind] = re
 		}
@@ -124,7 +190,7 @@ func LoadConfig() (Config, error) {
 		if conf.FuncPattern != "" {
 			re, err := regexp.Compile(expandIdentPlaceholder(conf.FuncPattern))
 			if err != nil {
-				return nil, fmt.Errorf("invalid func regex for %s: %w", lang, err)
+				return nil, newPatternError(lang, "func_pattern", "invalid func regex for "+lang, err)
 			}
 			conf.funcRegex = re
 		}
@@ -133,7 +199,7 @@ func LoadConfig() (Config, error) {
 		if conf.ClassPattern != "" {
 			classRe, err := regexp.Compile(expandIdentPlaceholder(conf.ClassPattern))
 			if err != nil {
-				return nil, fmt.Errorf("invalid class regex for %s: %w", lang, err)
+				return nil, newPatternError(lang, "class_pattern", "invalid class regex for "+lang, err)
 			}
 			conf.classRegex = classRe
 		}
@@ -142,16 +208,97 @@ func LoadConfig() (Config, error) {
 		if conf.FieldPattern != "" {
 			fieldRe, err := regexp.Compile(expandIdentPlaceholder(conf.FieldPattern))
 			if err != nil {
-				return nil, fmt.Errorf("invalid field pattern for %s: %w", lang, err)
+				return nil, newPatternError(lang, "field_pattern", "invalid field pattern for "+lang, err)
 			}
 			conf.fieldRegex = fieldRe
 		}
 
+		// Compile constructor parameter pattern if specified
+		if conf.ParamFieldsPattern != "" {
+			paramRe, err := regexp.Compile(expandIdentPlaceholder(conf.ParamFieldsPattern))
+			if err != nil {
+				return nil, newPatternError(lang, "param_fields_pattern", "invalid param fields pattern for "+lang, err)
+			}
+			conf.paramFieldsRe = paramRe
+		}
+
+		// Compile closure pattern if specified
+		if conf.ClosurePattern != "" {
+			closureRe, err := regexp.Compile(expandIdentPlaceholder(conf.ClosurePattern))
+			if err != nil {
+				return nil, newPatternError(lang, "closure_pattern", "invalid closure pattern for "+lang, err)
+			}
+			conf.closureRegex = closureRe
+		}
+
+		// Compile generator pattern if specified
+		if conf.GeneratorPattern != "" {
+			generatorRe, err := regexp.Compile(expandIdentPlaceholder(conf.GeneratorPattern))
+			if err != nil {
+				return nil, newPatternError(lang, "generator_pattern", "invalid generator pattern for "+lang, err)
+			}
+			conf.generatorRegex = generatorRe
+		}
+
+		// Compile assertion pattern if specified
+		if conf.AssertionPattern != "" {
+			assertionRe, err := regexp.Compile(expandIdentPlaceholder(conf.AssertionPattern))
+			if err != nil {
+				return nil, newPatternError(lang, "assertion_pattern", "invalid assertion pattern for "+lang, err)
+			}
+			conf.assertionRegex = assertionRe
+		}
+
+		// Compile test block pattern if specified
+		if conf.TestBlockPattern != "" {
+			testBlockRe, err := regexp.Compile(expandIdentPlaceholder(conf.TestBlockPattern))
+			if err != nil {
+				return nil, newPatternError(lang, "test_block_pattern", "invalid test block pattern for "+lang, err)
+			}
+			conf.testBlockRegex = testBlockRe
+		}
+
+		// Compile abstract method pattern if specified
+		if conf.AbstractPattern != "" {
+			abstractRe, err := regexp.Compile(expandIdentPlaceholder(conf.AbstractPattern))
+			if err != nil {
+				return nil, newPatternError(lang, "abstract_pattern", "invalid abstract pattern for "+lang, err)
+			}
+			conf.abstractRegex = abstractRe
+		}
+
+		// Compile macro pattern if specified
+		if conf.MacroPattern != "" {
+			macroRe, err := regexp.Compile(expandIdentPlaceholder(conf.MacroPattern))
+			if err != nil {
+				return nil, newPatternError(lang, "macro_pattern", "invalid macro pattern for "+lang, err)
+			}
+			conf.macroRegex = macroRe
+		}
+
+		// Compile expression body pattern if specified
+		if conf.ExpressionBodyPattern != "" {
+			exprRe, err := regexp.Compile(expandIdentPlaceholder(conf.ExpressionBodyPattern))
+			if err != nil {
+				return nil, newPatternError(lang, "expression_body_pattern", "invalid expression body pattern for "+lang, err)
+			}
+			conf.exprBodyRegex = exprRe
+		}
+
+		// Compile block open pattern if specified
+		if conf.BlockOpenPattern != "" {
+			openRe, err := regexp.Compile(expandIdentPlaceholder(conf.BlockOpenPattern))
+			if err != nil {
+				return nil, newPatternError(lang, "block_open_pattern", "invalid block open pattern for "+lang, err)
+			}
+			conf.blockOpenRegex = openRe
+		}
+
 		// Compile call regex if specified
 		if conf.CallPattern != "" {
 			callRe, err := regexp.Compile(expandIdentPlaceholder(conf.CallPattern))
 			if err != nil {
-				return nil, fmt.Errorf("invalid call regex for %s: %w", lang, err)
+				return nil, newPatternError(lang, "call_pattern", "invalid call regex for "+lang, err)
 			}
 			conf.callRegex = callRe
 		}
@@ -160,7 +307,7 @@ func LoadConfig() (Config, error) {
 		if conf.ImportPattern != "" {
 			importRe, err := regexp.Compile(conf.ImportPattern)
 			if err != nil {
-				return nil, fmt.Errorf("invalid import regex for %s: %w", lang, err)
+				return nil, newPatternError(lang, "import_pattern", "invalid import regex for "+lang, err)
 			}
 			conf.importRegex = importRe
 		}
@@ -169,7 +316,7 @@ func LoadConfig() (Config, error) {
 		if conf.DecoratorPattern != "" {
 			decoratorRe, err := regexp.Compile(conf.DecoratorPattern)
 			if err != nil {
-				return nil, fmt.Errorf("invalid decorator regex for %s: %w", lang, err)
+				return nil, newPatternError(lang, "decorator_pattern", "invalid decorator regex for "+lang, err)
 			}
 			conf.decoratorRe = decoratorRe
 		}
@@ -182,7 +329,7 @@ func LoadConfig() (Config, error) {
 			pattern := fmt.Sprintf(`%s[\s\S]*?%s`, start, end)
 			blockRe, err := regexp.Compile(pattern)
 			if err != nil {
-				return nil, fmt.Errorf("invalid block comment regex for %s: %w", lang, err)
+				return nil, newPatternError(lang, "block_comment_start", "invalid block comment regex for "+lang, err)
 			}
 			conf.blockCommentRe = blockRe
 		}
@@ -197,7 +344,7 @@ func LoadConfig() (Config, error) {
 func (c Config) GetLanguageConfig(lang string) (*LanguageConfig, error) {
 	conf, ok := c[lang]
 	if !ok {
-		return nil, fmt.Errorf("unsupported language: %s", lang)
+		return nil, &ConfigError{Kind: ErrUnsupportedLanguage, Lang: lang, Msg: "unsupported language: " + lang}
 	}
 	return conf, nil
 }
@@ -215,6 +362,53 @@ func (c Config) GetLanguageByExtension(filename string) *LanguageConfig {
 	return nil
 }
 
+// ParseExtMap parses an --ext-map value ".inc=php,.tmpl=go" into
+// extension -> language key; the leading dot is optional
+func ParseExtMap(spec string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		ext, lang, ok := strings.Cut(pair, "=")
+		ext, lang = strings.TrimSpace(ext), strings.TrimSpace(lang)
+		if !ok || ext == "" || ext == "." || lang == "" {
+			return nil, fmt.Errorf("invalid --ext-map entry %q (expected .ext=lang)", pair)
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		mapping[ext] = lang
+	}
+	return mapping, nil
+}
+
+// MapExtension makes files with extension ext resolve to language lang,
+// removing ext from every other language so the lookup is unambiguous
+func (c Config) MapExtension(ext, lang string) error {
+	target, err := c.GetLanguageConfig(lang)
+	if err != nil {
+		return err
+	}
+	for _, langConf := range c {
+		if langConf == target {
+			continue
+		}
+		kept := make([]string, 0, len(langConf.Extensions))
+		for _, e := range langConf.Extensions {
+			if e != ext {
+				kept = append(kept, e)
+			}
+		}
+		langConf.Extensions = kept
+	}
+	if !containsString(target.Extensions, ext) {
+		target.Extensions = append(target.Extensions, ext)
+	}
+	return nil
+}
+
 // GetSupportedLanguages returns a sorted list of all supported language keys
 func (c Config) GetSupportedLanguages() []string {
 	languages := make([]string, 0, len(c))
@@ -235,6 +429,58 @@ func (lc *LanguageConfig) ClassRegex() *regexp.Regexp {
 	return lc.classRegex
 }
 
+// GeneratorRegex returns the compiled generator pattern (nil if unset)
+func (lc *LanguageConfig) GeneratorRegex() *regexp.Regexp {
+	return lc.generatorRegex
+}
+
+// AssertionRegex returns the compiled assertion pattern (nil if unset)
+func (lc *LanguageConfig) AssertionRegex() *regexp.Regexp {
+	return lc.assertionRegex
+}
+
+// TestBlockRegex returns the compiled test block pattern (nil if unset)
+func (lc *LanguageConfig) TestBlockRegex() *regexp.Regexp {
+	return lc.testBlockRegex
+}
+
+// SetAssertionPattern replaces the language's assertion_pattern
+// (--assertion-pattern)
+func (lc *LanguageConfig) SetAssertionPattern(pattern string) error {
+	re, err := regexp.Compile(expandIdentPlaceholder(pattern))
+	if err != nil {
+		return &ConfigError{Kind: ErrInvalidArgs, Lang: lc.LangKey, Field: "assertion_pattern",
+			Msg: "invalid --assertion-pattern for " + lc.LangKey, Err: err}
+	}
+	lc.AssertionPattern, lc.assertionRegex = pattern, re
+	return nil
+}
+
+// AbstractRegex returns the compiled abstract method pattern (nil if unset)
+func (lc *LanguageConfig) AbstractRegex() *regexp.Regexp {
+	return lc.abstractRegex
+}
+
+// MacroRegex returns the compiled function-like macro pattern (nil if unset)
+func (lc *LanguageConfig) MacroRegex() *regexp.Regexp {
+	return lc.macroRegex
+}
+
+// ClosureRegex returns the compiled closure pattern (nil if unset)
+func (lc *LanguageConfig) ClosureRegex() *regexp.Regexp {
+	return lc.closureRegex
+}
+
+// ExpressionBodyRegex returns the compiled expression body pattern (nil if unset)
+func (lc *LanguageConfig) ExpressionBodyRegex() *regexp.Regexp {
+	return lc.exprBodyRegex
+}
+
+// BlockOpenRegex returns the compiled block open pattern (nil if unset)
+func (lc *LanguageConfig) BlockOpenRegex() *regexp.Regexp {
+	return lc.blockOpenRegex
+}
+
 // GetExtraPattern returns an extra pattern by key
 func (lc *LanguageConfig) GetExtraPattern(key string) string {
 	if lc.ExtraPatterns != nil {
@@ -247,6 +493,14 @@ func (lc *LanguageConfig) HasClasses() bool {
 	return lc.ClassPattern != ""
 }
 
+// BlockSuffix returns the IndentBlockSuffix of an indent-based language
+func (lc *LanguageConfig) BlockSuffix() string {
+	if lc.IndentBlockSuffix == "" {
+		return ":"
+	}
+	return lc.IndentBlockSuffix
+}
+
 // Struct pattern getters for findstruct
 
 // GetStructPatterns returns all compiled struct type patterns
@@ -254,6 +508,58 @@ func (lc *LanguageConfig) GetStructPatterns() map[string]*regexp.Regexp {
 	return lc.structPatterns
 }
 
+// StructPatternKinds returns the struct pattern kinds in the order finders
+// try them: kinds added with AddStructPattern first, so a custom kind wins
+// over a built-in one matching the same line, then the built-in kinds sorted
+func (lc *LanguageConfig) StructPatternKinds() []string {
+	kinds := append([]string(nil), lc.extraKinds...)
+	var builtin []string
+	for kind := range lc.structPatterns {
+		if !containsString(lc.extraKinds, kind) {
+			builtin = append(builtin, kind)
+		}
+	}
+	sort.Strings(builtin)
+	return append(kinds, builtin...)
+}
+
+// AddStructPattern registers a custom type kind (--extra-type), e.g. kind
+// "entity" with pattern `^@Entity\s+class\s+({IDENT}+)`. The last non-empty
+// group is the type name. Adding an existing kind replaces its pattern.
+func (lc *LanguageConfig) AddStructPattern(kind, pattern string) error {
+	re, err := regexp.Compile(expandIdentPlaceholder(pattern))
+	if err != nil {
+		return &ConfigError{Kind: ErrInvalidArgs, Lang: lc.LangKey, Field: "struct_type_patterns",
+			Msg: fmt.Sprintf("invalid extra type pattern for %s (%s)", kind, lc.LangKey), Err: err}
+	}
+	if lc.structPatterns == nil {
+		lc.structPatterns = make(map[string]*regexp.Regexp)
+	}
+	lc.structPatterns[kind] = re
+	if !containsString(lc.extraKinds, kind) {
+		lc.extraKinds = append(lc.extraKinds, kind)
+	}
+	return nil
+}
+
+// ParseExtraType splits an --extra-type value "kind:pattern"
+func ParseExtraType(spec string) (kind, pattern string, err error) {
+	kind, pattern, ok := strings.Cut(spec, ":")
+	if !ok || kind == "" || pattern == "" {
+		return "", "", fmt.Errorf("invalid --extra-type %q (expected kind:pattern)", spec)
+	}
+	return kind, pattern, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 // GetStructPattern returns a compiled pattern for a specific struct type
 func (lc *LanguageConfig) GetStructPattern(typeKind string) *regexp.Regexp {
 	if lc.structPatterns != nil {
@@ -267,6 +573,11 @@ func (lc *LanguageConfig) GetFieldPattern() *regexp.Regexp {
 	return lc.fieldRegex
 }
 
+// GetParamFieldsPattern returns the compiled constructor parameter pattern regex
+func (lc *LanguageConfig) GetParamFieldsPattern() *regexp.Regexp {
+	return lc.paramFieldsRe
+}
+
 // HasStructSupport returns true if the language has struct type patterns configured
 func (lc *LanguageConfig) HasStructSupport() bool {
 	return len(lc.structPatterns) > 0
@@ -286,6 +597,29 @@ func (lc *LanguageConfig) ImportRegex() *regexp.Regexp {
 	return lc.importRegex
 }
 
+// IsStdlib reports whether importPath belongs to the standard library.
+// A prefix ending in a separator ("java.", "std::", "dart:") matches by
+// prefix; a bare name ("os", "encoding") matches the module itself and its
+// submodules only, so "os" does not claim "osmosis".
+func (lc *LanguageConfig) IsStdlib(importPath string) bool {
+	for _, p := range lc.StdlibPrefixes {
+		if p == "" {
+			continue
+		}
+		last := rune(p[len(p)-1])
+		if !unicode.IsLetter(last) && !unicode.IsDigit(last) && last != '_' {
+			if strings.HasPrefix(importPath, p) {
+				return true
+			}
+			continue
+		}
+		if importPath == p || strings.HasPrefix(importPath, p+".") || strings.HasPrefix(importPath, p+"/") || strings.HasPrefix(importPath, p+"::") {
+			return true
+		}
+	}
+	return false
+}
+
 func (lc *LanguageConfig) BlockCommentRegex() *regexp.Regexp {
 	return lc.blockCommentRe
 }
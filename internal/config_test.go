@@ -572,3 +572,64 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+func TestIsStdlib(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		lang, importPath string
+		want             bool
+	}{
+		{"go", "fmt", true},
+		{"go", "net/http", true},
+		{"go", "github.com/spf13/cobra", false},
+		{"go", "fmtx", false},
+		{"py", "os", true},
+		{"py", "os.path", true},
+		{"py", "requests", false},
+		{"py", "osmosis", false},
+		{"java", "java.util.List", true},
+		{"rust", "std::collections::HashMap", true},
+		{"dart", "dart:async", true},
+		{"dart", "package:http/http.dart", false},
+	}
+	for _, tt := range tests {
+		if got := config[tt.lang].IsStdlib(tt.importPath); got != tt.want {
+			t.Errorf("%s IsStdlib(%q) = %v, want %v", tt.lang, tt.importPath, got, tt.want)
+		}
+	}
+}
+
+func TestParseExtMap(t *testing.T) {
+	got, err := ParseExtMap(".inc=php, tmpl=go")
+	if err != nil {
+		t.Fatalf("ParseExtMap() error = %v", err)
+	}
+	if len(got) != 2 || got[".inc"] != "php" || got[".tmpl"] != "go" {
+		t.Errorf("ParseExtMap() = %v", got)
+	}
+	for _, spec := range []string{".inc", "=php", ".=go", ".inc="} {
+		if _, err := ParseExtMap(spec); err == nil {
+			t.Errorf("ParseExtMap(%q): expected error", spec)
+		}
+	}
+}
+
+func TestMapExtension_OverridesLookup(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if err := config.MapExtension(".h", "cpp"); err != nil {
+		t.Fatalf("MapExtension() error = %v", err)
+	}
+	if lc := config.GetLanguageByExtension("widget.h"); lc != config["cpp"] {
+		t.Errorf(".h resolves to %v, want cpp", lc)
+	}
+	if err := config.MapExtension(".inc", "cobol"); err == nil {
+		t.Error("expected error for unknown language")
+	}
+}
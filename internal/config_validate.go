@@ -0,0 +1,274 @@
+// config_validate.go - Proactive languages.json check (--validate-config)
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// canonicalSnippets holds, per language and pattern field, a line of
+// idiomatic source the pattern must match. A regex that compiles but no
+// longer matches the most ordinary declaration of its language is almost
+// always a broken edit; new languages should add their snippets here.
+var canonicalSnippets = map[string]map[string]string{
+	"go": {
+		"func_pattern":      "func (s *Server) Run(ctx context.Context) error {",
+		"class_pattern":     "type Server struct {",
+		"import_pattern":    `import "fmt"`,
+		"assertion_pattern": `t.Errorf("got %d", n)`,
+	},
+	"c": {
+		"func_pattern":   "static int parse_args(int argc, char **argv) {",
+		"class_pattern":  "typedef struct {",
+		"import_pattern": "#include <stdio.h>",
+	},
+	"cpp": {
+		"func_pattern":   "void Parser::parse(const std::string& input) {",
+		"class_pattern":  "class Parser {",
+		"import_pattern": "#include <vector>",
+	},
+	"cs": {
+		"func_pattern":      "public void Run(string[] args)",
+		"class_pattern":     "public class Program",
+		"import_pattern":    "using System.Text;",
+		"decorator_pattern": "[Obsolete]",
+	},
+	"java": {
+		"func_pattern":      "public static void main(String[] args) {",
+		"class_pattern":     "public class Main {",
+		"import_pattern":    "import java.util.List;",
+		"decorator_pattern": "@Override",
+	},
+	"d": {
+		"func_pattern":   "int parse(string input)",
+		"class_pattern":  "class Parser",
+		"import_pattern": "import std.stdio;",
+	},
+	"js": {
+		"func_pattern":       "export async function load(url) {",
+		"class_pattern":      "export class Loader {",
+		"import_pattern":     "import { readFile } from 'fs';",
+		"test_block_pattern": "test('loads a file', async () => {",
+	},
+	"ts": {
+		"func_pattern":       "export function load<T>(url: string): Promise<T> {",
+		"class_pattern":      "export class Loader {",
+		"import_pattern":     "import { readFile } from 'fs';",
+		"field_pattern":      "    readonly name?: string;",
+		"test_block_pattern": "  it(\"returns the user\", () => {",
+	},
+	"py": {
+		"func_pattern":      "async def fetch(self, url):",
+		"class_pattern":     "class Client(Base):",
+		"import_pattern":    "from os import path",
+		"decorator_pattern": "@staticmethod",
+		"assertion_pattern": "self.assertEqual(a, b)",
+	},
+	"rust": {
+		"func_pattern":      "pub async fn fetch<T>(url: &str) -> Result<T> {",
+		"class_pattern":     "pub struct Client {",
+		"import_pattern":    "use std::collections::HashMap;",
+		"decorator_pattern": "#[inline]",
+	},
+	"swift": {
+		"func_pattern":   "public func fetch(url: URL) -> Data {",
+		"class_pattern":  "struct Client {",
+		"import_pattern": "import Foundation",
+	},
+	"kotlin": {
+		"func_pattern":      "suspend fun fetch(url: String): String {",
+		"class_pattern":     "data class User(val name: String)",
+		"import_pattern":    "import kotlin.math.max",
+		"decorator_pattern": "@JvmStatic",
+	},
+	"php": {
+		"func_pattern":   "public static function create($name) {",
+		"class_pattern":  "final class User {",
+		"import_pattern": `use App\Models\User;`,
+	},
+	"ruby": {
+		"func_pattern":   "def valid?(record)",
+		"class_pattern":  "class User < Base",
+		"import_pattern": "require 'json'",
+	},
+	"ex": {
+		"func_pattern":   "def fetch(url) do",
+		"class_pattern":  "defmodule MyApp.Client do",
+		"import_pattern": "alias MyApp.Repo",
+	},
+	"scala": {
+		"func_pattern":   "def fetch(url: String): String = {",
+		"class_pattern":  "case class User(name: String)",
+		"import_pattern": "import scala.collection.mutable",
+	},
+	"groovy": {
+		"func_pattern":      "def fetch(String url) {",
+		"class_pattern":     "class Client {",
+		"import_pattern":    "import groovy.json.JsonSlurper",
+		"decorator_pattern": "@CompileStatic",
+	},
+	"dart": {
+		"func_pattern":      "Future<String> fetch(String url) async {",
+		"class_pattern":     "class Client {",
+		"import_pattern":    "import 'package:http/http.dart';",
+		"decorator_pattern": "@override",
+	},
+	"hs": {
+		"func_pattern":   "fetch :: String -> IO String",
+		"import_pattern": "import qualified Data.Map as Map",
+	},
+	"nim": {
+		"func_pattern":   "proc fetch*(url: string): string =",
+		"field_pattern":  "  x*, y*: float",
+		"import_pattern": "import std/strutils",
+	},
+}
+
+// ConfigReport is the result of ValidateConfigData: the number of languages
+// checked and every problem found, sorted by language and field
+type ConfigReport struct {
+	Languages int
+	Issues    []*ConfigError
+}
+
+// ValidateConfig validates the embedded languages.json
+func ValidateConfig() (*ConfigReport, error) {
+	data, err := languagesFS.ReadFile("languages.json")
+	if err != nil {
+		return nil, &ConfigError{Kind: ErrConfigLoad, Msg: "failed to read languages.json", Err: err}
+	}
+	return ValidateConfigData(data)
+}
+
+// ValidateConfigData checks a languages.json document the way LoadConfig
+// reads it, but instead of stopping at the first bad pattern it reports
+// every language that is missing a required field, every pattern that does
+// not compile and every pattern that does not match its canonical snippet.
+// The error is only for a document that is not valid JSON.
+func ValidateConfigData(data []byte) (*ConfigReport, error) {
+	var rawConfig map[string]*LanguageConfigWithMap
+	if err := json.Unmarshal(data, &rawConfig); err != nil {
+		return nil, &ConfigError{Kind: ErrConfigLoad, Msg: "failed to parse languages.json", Err: err}
+	}
+
+	report := &ConfigReport{Languages: len(rawConfig)}
+	for lang, langConf := range rawConfig {
+		report.Issues = append(report.Issues, validateLanguage(lang, langConf)...)
+	}
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Lang != report.Issues[j].Lang {
+			return report.Issues[i].Lang < report.Issues[j].Lang
+		}
+		return report.Issues[i].Field < report.Issues[j].Field
+	})
+	return report, nil
+}
+
+// validateLanguage returns the problems of a single language entry
+func validateLanguage(lang string, langConf *LanguageConfigWithMap) []*ConfigError {
+	conf := &langConf.LanguageConfig
+	var issues []*ConfigError
+	missing := func(field string) {
+		issues = append(issues, &ConfigError{Kind: ErrConfigLoad, Lang: lang, Field: field,
+			Msg: fmt.Sprintf("%s: missing required field %s", lang, field)})
+	}
+
+	if conf.Name == "" {
+		missing("name")
+	}
+	if len(conf.Extensions) == 0 {
+		missing("extensions")
+	}
+	if conf.FuncPattern == "" {
+		missing("func_pattern")
+	}
+	if conf.LineComment == "" && conf.BlockCommentStart == "" {
+		missing("line_comment")
+	}
+	if len(conf.StringChars) == 0 {
+		missing("string_chars")
+	}
+	if (conf.BlockCommentStart == "") != (conf.BlockCommentEnd == "") {
+		issues = append(issues, &ConfigError{Kind: ErrConfigLoad, Lang: lang, Field: "block_comment_end",
+			Msg: lang + ": block_comment_start and block_comment_end must be set together"})
+	}
+
+	// Same fields and the same {IDENT} expansion as LoadConfig
+	patterns := []struct {
+		field, pattern string
+		expand         bool
+	}{
+		{"func_pattern", conf.FuncPattern, true},
+		{"class_pattern", conf.ClassPattern, true},
+		{"field_pattern", conf.FieldPattern, true},
+		{"param_fields_pattern", conf.ParamFieldsPattern, true},
+		{"closure_pattern", conf.ClosurePattern, true},
+		{"generator_pattern", conf.GeneratorPattern, true},
+		{"assertion_pattern", conf.AssertionPattern, true},
+		{"test_block_pattern", conf.TestBlockPattern, true},
+		{"abstract_pattern", conf.AbstractPattern, true},
+		{"macro_pattern", conf.MacroPattern, true},
+		{"expression_body_pattern", conf.ExpressionBodyPattern, true},
+		{"block_open_pattern", conf.BlockOpenPattern, true},
+		{"call_pattern", conf.CallPattern, true},
+		{"import_pattern", conf.ImportPattern, false},
+		{"decorator_pattern", conf.DecoratorPattern, false},
+	}
+	for _, p := range patterns {
+		if p.pattern == "" {
+			continue
+		}
+		expr := p.pattern
+		if p.expand {
+			expr = expandIdentPlaceholder(expr)
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			issues = append(issues, newPatternError(lang, p.field, fmt.Sprintf("%s: invalid %s", lang, p.field), err))
+			continue
+		}
+		if snippet, ok := canonicalSnippets[lang][p.field]; ok && !re.MatchString(snippet) {
+			issues = append(issues, &ConfigError{Kind: ErrConfigLoad, Lang: lang, Field: p.field,
+				Msg: fmt.Sprintf("%s: %s does not match canonical snippet %q", lang, p.field, snippet)})
+		}
+	}
+
+	typeKinds := make([]string, 0, len(langConf.StructTypePatternsMap))
+	for typeKind := range langConf.StructTypePatternsMap {
+		typeKinds = append(typeKinds, typeKind)
+	}
+	sort.Strings(typeKinds)
+	for _, typeKind := range typeKinds {
+		if _, err := regexp.Compile(expandIdentPlaceholder(langConf.StructTypePatternsMap[typeKind])); err != nil {
+			issues = append(issues, newPatternError(lang, "struct_type_patterns",
+				fmt.Sprintf("%s: invalid struct pattern (%s)", lang, typeKind), err))
+		}
+	}
+
+	if conf.BlockCommentStart != "" && conf.BlockCommentEnd != "" {
+		pattern := fmt.Sprintf(`%s[\s\S]*?%s`, regexp.QuoteMeta(conf.BlockCommentStart), regexp.QuoteMeta(conf.BlockCommentEnd))
+		if _, err := regexp.Compile(pattern); err != nil {
+			issues = append(issues, newPatternError(lang, "block_comment_start", lang+": invalid block comment regex", err))
+		}
+	}
+	return issues
+}
+
+// FormatConfigReport renders a ConfigReport as one line per issue followed
+// by a summary line
+func FormatConfigReport(report *ConfigReport) string {
+	if len(report.Issues) == 0 {
+		return fmt.Sprintf("languages.json OK: %d languages validated", report.Languages)
+	}
+	var sb strings.Builder
+	langs := make(map[string]bool)
+	for _, issue := range report.Issues {
+		sb.WriteString(issue.Error() + "\n")
+		langs[issue.Lang] = true
+	}
+	fmt.Fprintf(&sb, "languages.json: %d issue(s) in %d of %d languages", len(report.Issues), len(langs), report.Languages)
+	return sb.String()
+}
@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_BundledConfigPasses(t *testing.T) {
+	report, err := ValidateConfig()
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	for _, issue := range report.Issues {
+		t.Errorf("%s", issue)
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if report.Languages != len(config) {
+		t.Errorf("Languages = %d, want %d", report.Languages, len(config))
+	}
+	for lang := range canonicalSnippets {
+		if config[lang] == nil {
+			t.Errorf("canonical snippets for unknown language %q", lang)
+		}
+	}
+}
+
+func TestValidateConfigData_ReportsEveryIssue(t *testing.T) {
+	data := []byte(`{
+  "go": {
+    "name": "Go",
+    "extensions": [".go"],
+    "func_pattern": "^\\s*fn\\s+(\\w+)",
+    "class_pattern": "(unclosed",
+    "line_comment": "//",
+    "string_chars": ["\""]
+  },
+  "x": {
+    "name": "X",
+    "import_pattern": "[",
+    "block_comment_start": "/*"
+  }
+}`)
+	report, err := ValidateConfigData(data)
+	if err != nil {
+		t.Fatalf("ValidateConfigData() error = %v", err)
+	}
+
+	var got []string
+	for _, issue := range report.Issues {
+		got = append(got, issue.Lang+"."+issue.Field)
+	}
+	want := []string{
+		"go.class_pattern",    // does not compile
+		"go.func_pattern",     // compiles but misses the canonical snippet
+		"x.block_comment_end", // start without end
+		"x.extensions",        // required
+		"x.func_pattern",      // required
+		"x.import_pattern",    // does not compile
+		"x.string_chars",      // required
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+	if out := FormatConfigReport(report); !strings.HasSuffix(out, "7 issue(s) in 2 of 2 languages") {
+		t.Errorf("FormatConfigReport() = %q", out)
+	}
+}
+
+func TestValidateConfigData_InvalidJSON(t *testing.T) {
+	if _, err := ValidateConfigData([]byte("{")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func findCpp(t *testing.T, code string) map[string]FunctionBounds {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	finder := NewFinder(config["cpp"], nil, true, false, false)
+	result, err := finder.FindFunctionsInLines(strings.Split(code, "\n"), 1, "sample.cpp")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	found := make(map[string]FunctionBounds)
+	for _, fn := range result.Functions {
+		found[fn.Name] = fn
+	}
+	return found
+}
+
+func TestCpp_OutOfLineTemplateMethod(t *testing.T) {
+	code := `template <typename T>
+T Box<T>::get() const {
+    return value_;
+}
+
+template <typename K, typename V>
+std::map<K, std::vector<V>> Index<K, V>::group(const std::vector<V>& items)
+{
+    std::map<K, std::vector<V>> out;
+    return out;
+}
+
+Box<int>::Box(int v) : value_(v) {}
+`
+	found := findCpp(t, code)
+
+	want := []struct {
+		name, class string
+		start, end  int
+	}{
+		{"get", "Box", 2, 4},
+		{"group", "Index", 7, 11},
+		{"Box", "Box", 13, 13},
+	}
+	for _, w := range want {
+		fn, ok := found[w.name]
+		if !ok {
+			t.Errorf("%s not found; got %v", w.name, found)
+			continue
+		}
+		if fn.ClassName != w.class || fn.Start != w.start || fn.End != w.end {
+			t.Errorf("%s = class %q %d-%d, want class %q %d-%d", w.name, fn.ClassName, fn.Start, fn.End, w.class, w.start, w.end)
+		}
+	}
+}
+
+func TestCpp_FunctionInsideNamespace(t *testing.T) {
+	code := `namespace foo {
+
+int helper(int x) {
+    if (x > 0) {
+        return x;
+    }
+    else if (x < 0)
+    {
+        return -x;
+    }
+    return 0;
+}
+
+void outer::Widget::draw() {}
+
+}  // namespace foo
+`
+	found := findCpp(t, code)
+
+	helper, ok := found["helper"]
+	if !ok {
+		t.Fatalf("helper not found; got %v", found)
+	}
+	if helper.Start != 3 || helper.End != 12 || helper.ClassName != "" {
+		t.Errorf("helper = class %q %d-%d, want no class 3-12", helper.ClassName, helper.Start, helper.End)
+	}
+	if draw := found["draw"]; draw.ClassName != "Widget" {
+		t.Errorf("draw class = %q, want Widget", draw.ClassName)
+	}
+	if _, ok := found["if"]; ok {
+		t.Error("control-flow keyword reported as a function")
+	}
+}
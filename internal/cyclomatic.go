@@ -0,0 +1,44 @@
+// cyclomatic.go - Decision-point counts per function (stat -warn-complex)
+package internal
+
+import "regexp"
+
+// decisionPattern matches one decision point: a branching or looping
+// keyword of any supported language, or a short-circuit operator. else and
+// default add no path of their own, so "else if" counts once.
+var decisionPattern = regexp.MustCompile(`\b(?:if|elif|elsif|for|foreach|while|until|unless|case|when|catch|except|guard|and|or)\b|&&|\|\|`)
+
+// FunctionComplexity is a function's cyclomatic complexity
+type FunctionComplexity struct {
+	Name       string `json:"name"`
+	Start      int    `json:"start"`
+	End        int    `json:"end"`
+	Complexity int    `json:"complexity"`
+}
+
+// CyclomaticComplexity returns 1 plus the decision points in lines, which
+// must be sanitized so keywords in strings and comments don't count
+func CyclomaticComplexity(lines []string) int {
+	n := 1
+	for _, line := range lines {
+		n += len(decisionPattern.FindAllStringIndex(line, -1))
+	}
+	return n
+}
+
+// ComplexFunctions returns the functions whose cyclomatic complexity
+// exceeds threshold, in source order. cleaned are the sanitized lines of
+// the whole file; nested functions also count toward their parent.
+func ComplexFunctions(functions []FunctionBounds, cleaned []string, threshold int) []FunctionComplexity {
+	complex := []FunctionComplexity{}
+	for _, fn := range functions {
+		if fn.Start < 1 || fn.Start > len(cleaned) {
+			continue
+		}
+		end := min(fn.End, len(cleaned))
+		if c := CyclomaticComplexity(cleaned[fn.Start-1 : end]); c > threshold {
+			complex = append(complex, FunctionComplexity{Name: fn.Name, Start: fn.Start, End: fn.End, Complexity: c})
+		}
+	}
+	return complex
+}
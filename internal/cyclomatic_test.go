@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComplexFunctions_FlagsOnlyComplex(t *testing.T) {
+	src := `package p
+
+func simple(a int) int {
+	return a + 1 // if for while in a comment
+}
+
+func branchy(items []int, strict bool) int {
+	n := 0
+	for _, v := range items {
+		if v > 0 && strict {
+			n++
+		} else if v < 0 || !strict {
+			n--
+		}
+		switch v {
+		case 1:
+			n += 2
+		case 2:
+			n += 3
+		}
+	}
+	s := "if && case"
+	_ = s
+	return n
+}
+`
+	config := getGoConfig(t)
+	result, err := CreateFinder(config, "", "map", false, false).FindFunctionsInReader(strings.NewReader(src), "p.go")
+	if err != nil {
+		t.Fatalf("FindFunctionsInReader failed: %v", err)
+	}
+	cleaned := NewSanitizer(config, false).CleanLines(strings.Split(src, "\n"))
+
+	// branchy: 1 + for, if, &&, if, ||, case, case
+	got := ComplexFunctions(result.Functions, cleaned, 5)
+	if len(got) != 1 || got[0].Name != "branchy" || got[0].Complexity != 8 || got[0].Start != 7 {
+		t.Fatalf("ComplexFunctions(5) = %+v, want only branchy with complexity 8 at line 7", got)
+	}
+
+	if got := ComplexFunctions(result.Functions, cleaned, 0); len(got) != 2 || got[0].Complexity != 1 {
+		t.Errorf("ComplexFunctions(0) = %+v, want simple (1) and branchy", got)
+	}
+}
@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func getDartConfig(t *testing.T) *LanguageConfig {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	dart := config["dart"]
+	if dart == nil {
+		t.Fatal("dart config not found")
+	}
+	return dart
+}
+
+const dartSample = `import 'package:demo/demo.dart';
+
+class Point {
+  final double x;
+  final double y;
+
+  Point(this.x, this.y);
+
+  Point.origin() : x = 0, y = 0 {
+    print("origin ${'{'}");
+  }
+
+  double distance(Point other) {
+    return 0;
+  }
+}
+
+void main() {
+  final p = Point.origin();
+  print(p);
+}
+`
+
+func TestDartNamedConstructor(t *testing.T) {
+	finder := NewFinder(getDartConfig(t), nil, true, false, false)
+	result, err := finder.FindFunctionsInLines(strings.Split(dartSample, "\n"), 1, "point.dart")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+
+	byName := make(map[string]FunctionBounds)
+	for _, fn := range result.Functions {
+		byName[fn.Name] = fn
+	}
+
+	ctor, ok := byName["Point.origin"]
+	if !ok {
+		t.Fatalf("named constructor Point.origin not found, got %+v", result.Functions)
+	}
+	if ctor.Start != 9 || ctor.End != 11 {
+		t.Errorf("Point.origin: got %d-%d, want 9-11", ctor.Start, ctor.End)
+	}
+	if ctor.ClassName != "Point" {
+		t.Errorf("Point.origin: ClassName = %q, want Point", ctor.ClassName)
+	}
+	if byName["distance"].ClassName != "Point" {
+		t.Errorf("distance: ClassName = %q, want Point", byName["distance"].ClassName)
+	}
+	if len(result.Ambiguities) != 0 {
+		t.Errorf("unexpected ambiguities: %v", result.Ambiguities)
+	}
+}
+
+func TestDartTopLevelFunction(t *testing.T) {
+	finder := NewFinder(getDartConfig(t), []string{"main"}, false, false, false)
+	result, err := finder.FindFunctionsInLines(strings.Split(dartSample, "\n"), 1, "point.dart")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	if len(result.Functions) != 1 {
+		t.Fatalf("Expected 1 function, got %d", len(result.Functions))
+	}
+	fn := result.Functions[0]
+	if fn.Start != 18 || fn.End != 21 || fn.ClassName != "" {
+		t.Errorf("main: got %d-%d class %q, want 18-21 with no class", fn.Start, fn.End, fn.ClassName)
+	}
+}
+
+func TestDartClassPatterns(t *testing.T) {
+	re := getDartConfig(t).ClassRegex()
+	for _, line := range []string{"class A {", "abstract class Shape {", "mixin Logger on Shape {", "base mixin class M {"} {
+		if !re.MatchString(line) {
+			t.Errorf("class_pattern should match %q", line)
+		}
+	}
+}
+
+func TestDartSanitizerStrings(t *testing.T) {
+	s := NewSanitizer(getDartConfig(t), false)
+
+	tests := []struct {
+		line string
+		want int // brace delta after sanitizing
+	}{
+		// Quotes inside ${...} must not end the string
+		{`log("brace ${'{'} here") {`, 1},
+		{`final m = "${map["k"]} {";`, 0},
+		{`var s = 'it\'s $name {';`, 0},
+	}
+	for _, tt := range tests {
+		cleaned, state := s.CleanLine(tt.line, StateNormal)
+		if got := CountBraces(cleaned); got != tt.want || state != StateNormal {
+			t.Errorf("CleanLine(%q) = %q (state %v), brace delta %d, want %d", tt.line, cleaned, state, got, tt.want)
+		}
+	}
+
+	// Triple-quoted strings span lines; a lone " inside does not close them
+	lines := []string{`var doc = """`, `  He said "hi" {`, `""";`, `void f() {`}
+	state := StateNormal
+	var cleaned string
+	for i, line := range lines {
+		cleaned, state = s.CleanLine(line, state)
+		if i < 3 && CountBraces(cleaned) != 0 {
+			t.Errorf("line %d: brace inside triple-quoted string counted: %q", i+1, cleaned)
+		}
+	}
+	if state != StateNormal || CountBraces(cleaned) != 1 {
+		t.Errorf("after triple-quoted string: state %v, cleaned %q", state, cleaned)
+	}
+}
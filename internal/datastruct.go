@@ -0,0 +1,521 @@
+// datastruct.go - Key hierarchy of YAML, JSON and TOML files (--struct --source yaml)
+package internal
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Kinds of the TypeBounds reported for data files: a key holding a mapping
+// (YAML mapping, JSON object, TOML table), a list, an element of a list
+// that holds keys, or a key with a scalar value
+const (
+	DataKindSection = "section"
+	DataKindList    = "list"
+	DataKindItem    = "item"
+	DataKindKey     = "key"
+)
+
+// dataFormats maps the --source key of each data format to its extensions.
+// They are pseudo-languages: not in languages.json, so --dir scans and
+// --validate-config don't see them, and they only have a structure.
+var dataFormats = map[string][]string{
+	"yaml": {".yaml", ".yml"},
+	"json": {".json"},
+	"toml": {".toml"},
+}
+
+// dataSanitizers blank comments (and TOML strings) before keys are read;
+// JSON has neither comments nor unquoted keys and is scanned directly
+var dataSanitizers = map[string]*LanguageConfig{
+	"yaml": {Name: "YAML", LangKey: "yaml", LineComment: "#", StringChars: []string{`"`}, EscapeChar: `\`},
+	"toml": {Name: "TOML", LangKey: "toml", LineComment: "#", StringChars: []string{`"`, "'"}, DocStringMarkers: []string{`"""`, "'''"}, EscapeChar: `\`},
+}
+
+// IsDataFormat reports whether source names a data format (yaml, json, toml)
+func IsDataFormat(source string) bool {
+	_, ok := dataFormats[source]
+	return ok
+}
+
+// DetectDataFormat returns the data format of path by its extension, or ""
+func DetectDataFormat(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	for format, exts := range dataFormats {
+		for _, e := range exts {
+			if ext == e {
+				return format
+			}
+		}
+	}
+	return ""
+}
+
+// DataStructFinder maps the keys of a YAML, JSON or TOML file as types:
+// Name is the dotted path of the key ("server.tls.cert", "servers[0].host"),
+// ParentType the path of the enclosing key and Start/End the lines of the
+// key and its value
+type DataStructFinder struct {
+	format    string
+	typeNames map[string]bool
+	mapMode   bool
+}
+
+// NewDataStructFinder creates a finder for format (see IsDataFormat).
+// typeNamesStr selects keys by path or by their last segment.
+func NewDataStructFinder(format, typeNamesStr string, mapMode bool) *DataStructFinder {
+	nameMap := make(map[string]bool)
+	for _, name := range ParseFuncNames(typeNamesStr) {
+		nameMap[name] = true
+	}
+	return &DataStructFinder{format: format, typeNames: nameMap, mapMode: mapMode}
+}
+
+// FindStructures maps the keys of filename
+func (f *DataStructFinder) FindStructures(filename string) (*StructFindResult, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, newOpenError(filename, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, newReadError(filename, err)
+	}
+	return f.FindStructuresInLines(lines, 1, filename)
+}
+
+// FindStructuresInLines maps the keys of pre-read lines; startLine is the
+// number of lines[0] in the file
+func (f *DataStructFinder) FindStructuresInLines(lines []string, startLine int, filename string) (*StructFindResult, error) {
+	var nodes []TypeBounds
+	switch f.format {
+	case "yaml":
+		nodes = findYAMLKeys(lines, NewSanitizer(dataSanitizers["yaml"], false))
+	case "json":
+		nodes = findJSONKeys(lines)
+	case "toml":
+		nodes = findTOMLKeys(lines, NewSanitizer(dataSanitizers["toml"], false))
+	}
+
+	result := &StructFindResult{Filename: filename, Types: []TypeBounds{}}
+	for _, node := range nodes {
+		if !f.mapMode && !f.typeNames[node.Name] && !f.typeNames[lastKeySegment(node.Name)] {
+			continue
+		}
+		node.ParentType = parentKeyPath(node.Name)
+		node.Start += startLine - 1
+		node.End += startLine - 1
+		result.Types = append(result.Types, node)
+	}
+	return result, nil
+}
+
+// joinKeyPath appends key to the dotted path parent
+func joinKeyPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// itemPath is the path of element i of the list at parent
+func itemPath(parent string, i int) string {
+	return parent + "[" + strconv.Itoa(i) + "]"
+}
+
+// parentKeyPath strips the last ".key" or "[i]" of path
+func parentKeyPath(path string) string {
+	if i := strings.LastIndexAny(path, ".["); i > 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// lastKeySegment is the key name at the end of path
+func lastKeySegment(path string) string {
+	return path[strings.LastIndexAny(path, ".]")+1:]
+}
+
+// unquoteKey strips the quotes of a quoted key
+func unquoteKey(key string) string {
+	key = strings.TrimSpace(key)
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+// yamlFrame is an open key or list element while scanning YAML, at the
+// column of its key or "-"
+type yamlFrame struct {
+	indent int
+	path   string
+	node   int  // index in nodes, -1 while a list element has no keys
+	item   bool // a list element rather than a key
+	items  int  // list elements seen under this key
+	line   int  // line of the key or "-"
+}
+
+// findYAMLKeys tracks keys by indentation: a key owns the lines indented
+// deeper than itself, and a "- " element of a list may sit at its key's
+// column. Block scalars ("key: |") are skipped, "---" starts a new document.
+func findYAMLKeys(lines []string, sanitizer *Sanitizer) []TypeBounds {
+	var nodes []TypeBounds
+	var stack []yamlFrame
+	lastContent := 0
+	blockIndent := -1 // column of the key of an open block scalar
+
+	closeFrames := func(keep func(yamlFrame) bool) {
+		for len(stack) > 0 && !keep(stack[len(stack)-1]) {
+			if top := stack[len(stack)-1]; top.node >= 0 {
+				nodes[top.node].End = max(lastContent, nodes[top.node].Start)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	// addChild marks the innermost frame as the holder of a key or element
+	// and reports a list element the first time a key is found in it
+	addChild := func(kind string) {
+		if len(stack) == 0 {
+			return
+		}
+		top := &stack[len(stack)-1]
+		if top.node < 0 {
+			top.node = len(nodes)
+			nodes = append(nodes, TypeBounds{Name: top.path, Kind: DataKindItem, Start: top.line})
+		} else if top.item {
+			return
+		}
+		if nodes[top.node].Kind == DataKindKey {
+			nodes[top.node].Kind = kind
+		}
+	}
+
+	for i, line := range lines {
+		lineNum := i + 1
+		line = strings.TrimRight(line, "\r")
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		cleaned, _ := sanitizer.CleanLine(line, StateNormal)
+		if blockIndent >= 0 {
+			if strings.TrimSpace(line) == "" || indent > blockIndent {
+				if strings.TrimSpace(line) != "" {
+					lastContent = lineNum
+				}
+				continue
+			}
+			blockIndent = -1
+		}
+		content := strings.TrimSpace(cleaned)
+		if content == "" {
+			continue
+		}
+		if content == "---" || content == "..." {
+			closeFrames(func(yamlFrame) bool { return false })
+			continue
+		}
+
+		raw, clean := []rune(line), []rune(cleaned)
+		col := len([]rune(line[:indent]))
+		for col < len(clean) && clean[col] == '-' && (col+1 == len(clean) || clean[col+1] == ' ') {
+			// a list element: its key frame may be at the same column
+			closeFrames(func(fr yamlFrame) bool {
+				return fr.indent < col || (fr.indent == col && !fr.item)
+			})
+			addChild(DataKindList)
+			parent := ""
+			if len(stack) > 0 {
+				top := &stack[len(stack)-1]
+				parent = itemPath(top.path, top.items)
+				top.items++
+			}
+			stack = append(stack, yamlFrame{indent: col, path: parent, node: -1, item: true, line: lineNum})
+			col++
+			for col < len(clean) && clean[col] == ' ' {
+				col++
+			}
+		}
+
+		colon := yamlKeyColon(clean, col)
+		if colon < 0 {
+			lastContent = lineNum
+			continue
+		}
+		closeFrames(func(fr yamlFrame) bool { return fr.indent < col })
+		addChild(DataKindSection)
+		parent := ""
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1].path
+		}
+		key := unquoteKey(string(raw[col:colon]))
+		stack = append(stack, yamlFrame{indent: col, path: joinKeyPath(parent, key), node: len(nodes), line: lineNum})
+		nodes = append(nodes, TypeBounds{Name: joinKeyPath(parent, key), Kind: DataKindKey, Start: lineNum, End: lineNum})
+		if value := strings.TrimSpace(string(clean[colon+1:])); strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockIndent = col
+		}
+		lastContent = lineNum
+	}
+	closeFrames(func(yamlFrame) bool { return false })
+	return nodes
+}
+
+// yamlKeyColon returns the index of the ":" ending a mapping key that
+// starts at col (followed by a space or the end of the line), or -1. Flow
+// collections ("{a: 1}", "[x]") are values, not keys.
+func yamlKeyColon(clean []rune, col int) int {
+	if col >= len(clean) || clean[col] == '{' || clean[col] == '[' || clean[col] == '#' {
+		return -1
+	}
+	for p := col; p < len(clean); p++ {
+		if clean[p] == ':' && (p+1 == len(clean) || clean[p+1] == ' ') {
+			if p == col {
+				return -1
+			}
+			return p
+		}
+	}
+	return -1
+}
+
+// jsonFrame is an open object or array while scanning JSON
+type jsonFrame struct {
+	array bool
+	path  string
+	node  int // node of the key or element holding the container, -1 for the root
+	index int // element index within an array
+	value int // node of the key whose value is being read, -1 if none
+}
+
+// findJSONKeys scans JSON by brackets: a key ends with its value, on the
+// line of a scalar or of the bracket closing an object or array. Objects in
+// arrays are reported as elements ("servers[0]").
+func findJSONKeys(lines []string) []TypeBounds {
+	var nodes []TypeBounds
+	var stack []jsonFrame
+	var key strings.Builder
+	keyLine, lastToken := 0, 0
+	pendingKey := "" // a string in key position, waiting for its ':'
+	haveKey := false
+
+	// endValue closes the scalar value of the innermost object's key
+	endValue := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := &stack[len(stack)-1]
+		if top.value >= 0 && nodes[top.value].End == 0 {
+			nodes[top.value].End = lastToken
+		}
+		top.value = -1
+	}
+
+	for i, line := range lines {
+		lineNum := i + 1
+		runes := []rune(line)
+		for p := 0; p < len(runes); p++ {
+			r := runes[p]
+			switch {
+			case r == '"':
+				key.Reset()
+				for p++; p < len(runes) && runes[p] != '"'; p++ {
+					if runes[p] == '\\' && p+1 < len(runes) {
+						p++
+					}
+					key.WriteRune(runes[p])
+				}
+				lastToken = lineNum
+				if len(stack) > 0 && !stack[len(stack)-1].array && stack[len(stack)-1].value < 0 {
+					pendingKey, keyLine, haveKey = key.String(), lineNum, true
+				}
+			case r == ':' && haveKey:
+				top := &stack[len(stack)-1]
+				top.value = len(nodes)
+				nodes = append(nodes, TypeBounds{Name: joinKeyPath(top.path, pendingKey), Kind: DataKindKey, Start: keyLine})
+				haveKey = false
+			case r == '{' || r == '[':
+				frame := jsonFrame{array: r == '[', node: -1, value: -1}
+				if len(stack) > 0 {
+					top := &stack[len(stack)-1]
+					switch {
+					case top.array:
+						frame.path = itemPath(top.path, top.index)
+						if r == '{' {
+							frame.node = len(nodes)
+							nodes = append(nodes, TypeBounds{Name: frame.path, Kind: DataKindItem, Start: lineNum})
+						}
+					case top.value >= 0:
+						frame.path, frame.node = nodes[top.value].Name, top.value
+						nodes[top.value].Kind = DataKindSection
+						if r == '[' {
+							nodes[top.value].Kind = DataKindList
+						}
+					}
+				}
+				stack = append(stack, frame)
+				lastToken = lineNum
+			case r == '}' || r == ']':
+				lastToken = lineNum
+				endValue()
+				if len(stack) == 0 {
+					continue
+				}
+				if top := stack[len(stack)-1]; top.node >= 0 {
+					nodes[top.node].End = lineNum
+				}
+				stack = stack[:len(stack)-1]
+			case r == ',':
+				endValue()
+				if len(stack) > 0 && stack[len(stack)-1].array {
+					stack[len(stack)-1].index++
+				}
+			case r != ' ' && r != '\t' && r != '\r':
+				lastToken = lineNum
+			}
+		}
+	}
+	for i := range nodes {
+		if nodes[i].End == 0 {
+			nodes[i].End = max(lastToken, nodes[i].Start)
+		}
+	}
+	return nodes
+}
+
+// findTOMLKeys maps TOML tables ("[server]", "[[products]]" elements) and
+// their "key = value" lines; a value spans lines while its brackets or a
+// multi-line string are open. A table ends before the next header and also
+// contains the tables nested under its path.
+func findTOMLKeys(lines []string, sanitizer *Sanitizer) []TypeBounds {
+	var nodes []TypeBounds
+	table, tableNode := "", -1
+	arrayCounts := make(map[string]int)
+	lastContent := 0
+	openValue, depth := -1, 0 // key of a value spanning lines, bracket depth
+	state := StateNormal
+
+	for i, line := range lines {
+		lineNum := i + 1
+		line = strings.TrimRight(line, "\r")
+		startState := state
+		var cleaned string
+		cleaned, state = sanitizer.CleanLine(line, state)
+		if openValue >= 0 {
+			depth += strings.Count(cleaned, "[") + strings.Count(cleaned, "{") - strings.Count(cleaned, "]") - strings.Count(cleaned, "}")
+			lastContent = lineNum
+			if depth <= 0 && state == StateNormal {
+				nodes[openValue].End = lineNum
+				openValue = -1
+			}
+			continue
+		}
+		content := strings.TrimSpace(cleaned)
+		if content == "" || startState != StateNormal {
+			continue
+		}
+
+		if strings.HasPrefix(content, "[") {
+			raw := strings.TrimSpace(line)
+			array := strings.HasPrefix(raw, "[[")
+			closer := "]"
+			if array {
+				closer = "]]"
+			}
+			closeIdx := strings.Index(raw, closer)
+			if closeIdx < 0 {
+				// Malformed header without its closing bracket ("[a"): not a table
+				continue
+			}
+			if tableNode >= 0 {
+				nodes[tableNode].End = max(lastContent, nodes[tableNode].Start)
+			}
+			kind := DataKindSection
+			if array {
+				name := tomlKeyPath(raw[2:closeIdx])
+				table = itemPath(name, arrayCounts[name])
+				arrayCounts[name]++
+				kind = DataKindItem
+			} else {
+				table = tomlKeyPath(raw[1:closeIdx])
+			}
+			tableNode = len(nodes)
+			nodes = append(nodes, TypeBounds{Name: table, Kind: kind, Start: lineNum, End: lineNum})
+			lastContent = lineNum
+			continue
+		}
+
+		eq := strings.Index(cleaned, "=")
+		if eq < 0 {
+			continue
+		}
+		runes := []rune(line)
+		eqRune := len([]rune(cleaned[:eq]))
+		value := strings.TrimSpace(cleaned[eq+1:])
+		kind := DataKindKey
+		switch {
+		case strings.HasPrefix(value, "{"):
+			kind = DataKindSection
+		case strings.HasPrefix(value, "["):
+			kind = DataKindList
+		}
+		node := len(nodes)
+		nodes = append(nodes, TypeBounds{Name: joinKeyPath(table, tomlKeyPath(string(runes[:eqRune]))), Kind: kind, Start: lineNum, End: lineNum})
+		lastContent = lineNum
+		if depth = strings.Count(value, "[") + strings.Count(value, "{") - strings.Count(value, "]") - strings.Count(value, "}"); depth > 0 || state != StateNormal {
+			openValue = node
+		}
+	}
+	if tableNode >= 0 {
+		nodes[tableNode].End = max(lastContent, nodes[tableNode].Start)
+	}
+	if openValue >= 0 {
+		nodes[openValue].End = lastContent
+	}
+
+	// "[a]" contains "[a.b]" and "[[a.items]]" that follow it
+	for i := range nodes {
+		if nodes[i].Kind == DataKindKey {
+			continue
+		}
+		for _, n := range nodes[i+1:] {
+			if strings.HasPrefix(n.Name, nodes[i].Name+".") || strings.HasPrefix(n.Name, nodes[i].Name+"[") {
+				nodes[i].End = max(nodes[i].End, n.End)
+			}
+		}
+	}
+	return nodes
+}
+
+// tomlKeyPath normalizes a dotted TOML key ("a . \"b.c\"" is a, b.c):
+// spaces around dots go and quoted parts are unquoted
+func tomlKeyPath(key string) string {
+	var parts []string
+	var part strings.Builder
+	quote := rune(0)
+	for _, r := range strings.TrimSpace(key) {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				part.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '.':
+			parts = append(parts, strings.TrimSpace(part.String()))
+			part.Reset()
+		default:
+			part.WriteRune(r)
+		}
+	}
+	parts = append(parts, strings.TrimSpace(part.String()))
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// dataNodes maps src in format and renders each node as "name kind start-end parent"
+func dataNodes(t *testing.T, format, src string) []string {
+	t.Helper()
+	result, err := NewDataStructFinder(format, "", true).FindStructuresInLines(strings.Split(src, "\n"), 1, "config."+format)
+	if err != nil {
+		t.Fatalf("FindStructuresInLines() error = %v", err)
+	}
+	var nodes []string
+	for _, n := range result.Types {
+		nodes = append(nodes, fmt.Sprintf("%s %s %d-%d %s", n.Name, n.Kind, n.Start, n.End, n.ParentType))
+	}
+	return nodes
+}
+
+func checkDataNodes(t *testing.T, got, want []string) {
+	t.Helper()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("nodes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDataStructFinder_NestedYAML(t *testing.T) {
+	src := `# service config
+server:
+  host: example.com  # public: name
+  port: 8080
+  tls:
+    cert: /etc/cert.pem
+    "key": "/etc/key: pem"
+
+  motd: |
+    welcome
+    note: not a key
+servers:
+  - name: a
+    port: 1
+  - name: b
+tags:
+- web
+- api
+debug: false
+`
+	checkDataNodes(t, dataNodes(t, "yaml", src), []string{
+		"server section 2-11 ",
+		"server.host key 3-3 server",
+		"server.port key 4-4 server",
+		"server.tls section 5-7 server",
+		"server.tls.cert key 6-6 server.tls",
+		"server.tls.key key 7-7 server.tls",
+		"server.motd key 9-11 server",
+		"servers list 12-15 ",
+		"servers[0] item 13-14 servers",
+		"servers[0].name key 13-13 servers[0]",
+		"servers[0].port key 14-14 servers[0]",
+		"servers[1] item 15-15 servers",
+		"servers[1].name key 15-15 servers[1]",
+		"tags list 16-18 ",
+		"debug key 19-19 ",
+	})
+}
+
+func TestDataStructFinder_SelectsByPathOrKey(t *testing.T) {
+	lines := strings.Split("a:\n  name: x\nb:\n  name: y\n", "\n")
+	for _, tt := range []struct{ names, want string }{
+		{"b.name", "b.name"},
+		{"name", "a.name b.name"},
+	} {
+		result, err := NewDataStructFinder("yaml", tt.names, false).FindStructuresInLines(lines, 1, "c.yaml")
+		if err != nil {
+			t.Fatalf("FindStructuresInLines() error = %v", err)
+		}
+		var got []string
+		for _, n := range result.Types {
+			got = append(got, n.Name)
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("%s: got %v, want %s", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestDataStructFinder_JSON(t *testing.T) {
+	src := `{
+  "name": "app",
+  "deps": {
+    "a": 1,
+    "b": [1,
+      2]
+  },
+  "items": [
+    {"id": 1},
+    {"id": 2}
+  ]
+}`
+	checkDataNodes(t, dataNodes(t, "json", src), []string{
+		"name key 2-2 ",
+		"deps section 3-7 ",
+		"deps.a key 4-4 deps",
+		"deps.b list 5-6 deps",
+		"items list 8-11 ",
+		"items[0] item 9-9 items",
+		"items[0].id key 9-9 items[0]",
+		"items[1] item 10-10 items",
+		"items[1].id key 10-10 items[1]",
+	})
+}
+
+func TestDataStructFinder_TOML(t *testing.T) {
+	src := `title = "x = y"
+[server]
+host = "h"  # [not] a table
+ports = [
+  1,
+  2,
+]
+[server.tls]
+cert = """
+[abc]
+"""
+[[products]]
+name = "a"
+[[products]]
+name = "b"
+`
+	checkDataNodes(t, dataNodes(t, "toml", src), []string{
+		"title key 1-1 ",
+		"server section 2-11 ",
+		"server.host key 3-3 server",
+		"server.ports list 4-7 server",
+		"server.tls section 8-11 server",
+		"server.tls.cert key 9-11 server.tls",
+		"products[0] item 12-13 products",
+		"products[0].name key 13-13 products[0]",
+		"products[1] item 14-15 products",
+		"products[1].name key 15-15 products[1]",
+	})
+}
+
+func TestDataStructFinder_TOMLMalformedHeaders(t *testing.T) {
+	src := `[a
+x = 1
+[[b
+[ok]
+y = 2
+`
+	// Headers without a closing bracket are skipped, not a panic
+	checkDataNodes(t, dataNodes(t, "toml", src), []string{
+		"x key 2-2 ",
+		"ok section 4-5 ",
+		"ok.y key 5-5 ok",
+	})
+}
+
+func TestDetectDataFormat(t *testing.T) {
+	for path, want := range map[string]string{"a/ci.YML": "yaml", "package.json": "json", "Cargo.toml": "toml", "main.go": ""} {
+		if got := DetectDataFormat(path); got != want {
+			t.Errorf("DetectDataFormat(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
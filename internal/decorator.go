@@ -78,6 +78,32 @@ func (dw *DecoratorWindow) ExtractDecorators() ([]string, int) {
 	return decorators, firstDecoratorLine
 }
 
+// attachDecorators заполняет Decorators функций языков со скобками:
+// строки decorator_pattern (Rust #[inline], Java @Override, C# [Obsolete])
+// непосредственно над сигнатурой, пустые строки и строчные комментарии между
+// ними пропускаются. В отличие от Python, Start остаётся на сигнатуре.
+// lines - строки фрагмента, lineOffset - номер строки перед его началом.
+func attachDecorators(functions []FunctionBounds, lines []string, lineOffset int, config *LanguageConfig) {
+	decoratorRe := config.DecoratorRegex()
+	for i := range functions {
+		fn := &functions[i]
+		var decorators []string
+		for j := fn.Start - 2 - lineOffset; j >= 0 && j < len(lines); j-- {
+			line := strings.TrimSpace(lines[j])
+			if line == "" || (config.LineComment != "" && strings.HasPrefix(line, config.LineComment)) {
+				continue
+			}
+			if !decoratorRe.MatchString(line) {
+				break
+			}
+			decorators = append([]string{line}, decorators...)
+		}
+		if len(decorators) > 0 {
+			fn.Decorators = decorators
+		}
+	}
+}
+
 // Clear очищает окно
 func (dw *DecoratorWindow) Clear() {
 	dw.lines = dw.lines[:0]
@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -391,3 +393,51 @@ func TestDecoratorWindow_Integration(t *testing.T) {
 		t.Errorf("firstLine = %d, want 2", firstLine)
 	}
 }
+
+func TestFinder_RustAttributesAndQualifiers(t *testing.T) {
+	src := `/// Fetches the page.
+#[inline]
+#[tracing::instrument(skip(client))]
+pub(crate) async fn fetch(client: &Client) -> Result<String> {
+    Ok(String::new())
+}
+
+pub unsafe fn raw() {}
+
+pub(super) const unsafe fn pick<T: Into<String>>(x: T) -> Vec<u8> {
+    vec![]
+}
+
+pub extern "system" fn callback() {}
+
+mod tests {
+    #[test]
+
+    // the attribute may be separated by blank lines and comments
+    fn works() {}
+}
+`
+	result, err := NewFinder(getRustConfig(t), nil, true, false, false).FindFunctionsInLines(strings.Split(src, "\n"), 1, "lib.rs")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	want := map[string]string{
+		"fetch":    "4 #[inline]|#[tracing::instrument(skip(client))]",
+		"raw":      "8 ",
+		"pick":     "10 ",
+		"callback": "14 ",
+		"works":    "20 #[test]",
+	}
+	got := map[string]string{}
+	for _, fn := range result.Functions {
+		got[fn.Name] = fmt.Sprintf("%d %s", fn.Start, strings.Join(fn.Decorators, "|"))
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("%s = %q, want %q", name, got[name], w)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("found %v, want %d functions", got, len(want))
+	}
+}
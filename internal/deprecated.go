@@ -0,0 +1,112 @@
+// deprecated.go - Functions marked deprecated (--deprecated-only)
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// deprecatedAnnotation is a deprecation decorator line: Java/Kotlin
+	// "@Deprecated", Python "@deprecated" / "@typing_extensions.deprecated",
+	// C# "[Obsolete]" / "[System.Obsolete(...)]", Rust "#[deprecated(...)]"
+	deprecatedAnnotation = regexp.MustCompile(`^(?:@(?:[\w.]+\.)?[Dd]eprecated\b|\[\s*(?:System\.)?Obsolete(?:Attribute)?\b|#\[\s*deprecated\b)`)
+	// warnCall opens a Python warnings.warn(...) call
+	warnCall = regexp.MustCompile(`\bwarn\s*\(`)
+	// deprecationWarning is the warning category argument of warnCall
+	deprecationWarning = regexp.MustCompile(`\b(?:Pending)?DeprecationWarning\b`)
+)
+
+// AttachDeprecated sets IsDeprecated for every function that carries a
+// deprecation marker. lines are the whole file and Start/End are 1-based
+// line numbers into it. Markers are:
+//   - a doc comment paragraph starting with "Deprecated:" (the Go
+//     convention), a "@deprecated" tag (Javadoc, JSDoc, PHPDoc) or a
+//     ".. deprecated::" directive in a Python docstring, found the way
+//     AttachDocs finds documentation;
+//   - a decorator (decorator_pattern) such as @Deprecated or [Obsolete],
+//     above the signature or, in Python, at Start;
+//   - a warnings.warn(..., DeprecationWarning) call in the function's own
+//     body, outside functions nested in it.
+func AttachDeprecated(functions []FunctionBounds, lines []string, config *LanguageConfig) {
+	cleaned := NewSanitizer(config, false).CleanLines(lines)
+	for i := range functions {
+		fn := &functions[i]
+		fn.IsDeprecated = hasDeprecatedDecorator(lines, fn.Start, config) ||
+			hasDeprecatedDoc(lines, fn.Start, config) ||
+			warnsDeprecation(functions, i, cleaned)
+	}
+}
+
+// hasDeprecatedDecorator checks the decorator lines above start and, for
+// functions whose Start is their first decorator (Python), from start on
+func hasDeprecatedDecorator(lines []string, start int, config *LanguageConfig) bool {
+	decoratorRe := config.DecoratorRegex()
+	if decoratorRe == nil {
+		return false
+	}
+	for i := start - 2; i >= 0 && decoratorRe.MatchString(lines[i]); i-- {
+		if deprecatedAnnotation.MatchString(strings.TrimSpace(lines[i])) {
+			return true
+		}
+	}
+	for i := start - 1; i >= 0 && i < len(lines) && decoratorRe.MatchString(lines[i]); i++ {
+		if deprecatedAnnotation.MatchString(strings.TrimSpace(lines[i])) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDeprecatedDoc checks the function's documentation for a deprecation
+// paragraph or tag
+func hasDeprecatedDoc(lines []string, start int, config *LanguageConfig) bool {
+	var doc string
+	if config.IndentBased {
+		doc = docstring(lines, start, config)
+	} else {
+		doc = leadingCommentDoc(lines, start, config)
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		if strings.HasPrefix(line, "Deprecated:") || strings.HasPrefix(line, "@deprecated") || strings.HasPrefix(line, ".. deprecated::") {
+			return true
+		}
+	}
+	return false
+}
+
+// warnsDeprecation reports whether functions[idx] itself calls warn(...)
+// with a DeprecationWarning argument. cleaned are the sanitized file lines,
+// so calls in strings or comments don't count; a call may span lines.
+func warnsDeprecation(functions []FunctionBounds, idx int, cleaned []string) bool {
+	fn := functions[idx]
+	var own []string
+	for lineNum := fn.Start; lineNum <= fn.End && lineNum <= len(cleaned); lineNum++ {
+		if !inNestedFunction(functions, idx, lineNum) {
+			own = append(own, cleaned[lineNum-1])
+		}
+	}
+	body := strings.Join(own, " ")
+	for _, loc := range warnCall.FindAllStringIndex(body, -1) {
+		open := loc[1] - 1
+		args := body[open:]
+		if end := closingBracket(body, open); end >= 0 {
+			args = body[open : end+1]
+		}
+		if deprecationWarning.MatchString(args) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterDeprecated keeps only functions with IsDeprecated (--deprecated-only)
+func FilterDeprecated(functions []FunctionBounds) []FunctionBounds {
+	filtered := make([]FunctionBounds, 0, len(functions))
+	for _, fn := range functions {
+		if fn.IsDeprecated {
+			filtered = append(filtered, fn)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func deprecatedNames(t *testing.T, lang, src string) []string {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	langConfig := config[lang]
+	lines := strings.Split(src, "\n")
+	var functions []FunctionBounds
+	if lang == "py" {
+		functions = findPython(t, langConfig, src, false)
+	} else {
+		result, err := NewFinder(langConfig, nil, true, false, false).FindFunctionsInLines(lines, 1, "test")
+		if err != nil {
+			t.Fatalf("FindFunctionsInLines() error = %v", err)
+		}
+		functions = result.Functions
+	}
+	AttachDeprecated(functions, lines, langConfig)
+	var names []string
+	for _, fn := range FilterDeprecated(functions) {
+		names = append(names, fn.Name)
+	}
+	return names
+}
+
+func TestAttachDeprecated_GoDocParagraph(t *testing.T) {
+	src := `package p
+
+// Old does things.
+//
+// Deprecated: use New.
+func Old() {}
+
+// New mentions Deprecated: mid-sentence, which is not the convention.
+func New() {}
+
+// Deprecated: comment separated by a blank line is not the doc.
+
+func Plain() {}
+`
+	if got := strings.Join(deprecatedNames(t, "go", src), ","); got != "Old" {
+		t.Errorf("deprecated = %q, want %q", got, "Old")
+	}
+}
+
+func TestAttachDeprecated_JavaAnnotation(t *testing.T) {
+	src := `public class Api {
+    @Deprecated(since = "9")
+    @Override
+    public void legacy() {
+    }
+
+    /**
+     * @deprecated use {@link #legacy()}
+     */
+    public void tagged() {
+    }
+
+    @Override
+    public String toString() {
+        return "@Deprecated";
+    }
+}
+`
+	if got := strings.Join(deprecatedNames(t, "java", src), ","); got != "legacy,tagged" {
+		t.Errorf("deprecated = %q, want %q", got, "legacy,tagged")
+	}
+}
+
+func TestAttachDeprecated_PythonDecoratorAndWarning(t *testing.T) {
+	src := `@deprecated("use g")
+def f():
+    pass
+
+def g():
+    warnings.warn(
+        "g is old",
+        DeprecationWarning,
+    )
+
+def h():
+    warnings.warn("DeprecationWarning", UserWarning)
+
+def outer():
+    def inner():
+        warnings.warn("x", DeprecationWarning)
+    return inner
+`
+	if got := strings.Join(deprecatedNames(t, "py", src), ","); got != "f,g,inner" {
+		t.Errorf("deprecated = %q, want %q", got, "f,g,inner")
+	}
+}
+
+func TestAttachDeprecated_PythonOneLineDefBeforeDeprecatedDef(t *testing.T) {
+	src := `def a(): return 1
+
+def b():
+    """Deprecated: use a."""
+    return 2
+`
+	// a's body is on its header line: b's docstring is not a's
+	if got := strings.Join(deprecatedNames(t, "py", src), ","); got != "b" {
+		t.Errorf("deprecated = %q, want %q", got, "b")
+	}
+}
+
+func TestAttachDeprecated_CSharpObsolete(t *testing.T) {
+	src := "public class C\n{\n    [Obsolete(\"use B\")]\n    public void A()\n    {\n    }\n\n    public void B()\n    {\n    }\n}\n"
+	if got := strings.Join(deprecatedNames(t, "cs", src), ","); got != "A" {
+		t.Errorf("deprecated = %q, want %q", got, "A")
+	}
+}
+
+func TestAttachDeprecated_RustAttribute(t *testing.T) {
+	src := "#[deprecated(since = \"1.2\", note = \"use b\")]\n#[inline]\npub fn a() {}\n\n#[inline]\npub fn b() {}\n"
+	if got := strings.Join(deprecatedNames(t, "rust", src), ","); got != "a" {
+		t.Errorf("deprecated = %q, want %q", got, "a")
+	}
+}
@@ -0,0 +1,116 @@
+// directives.go - Go build constraints and //go: directives (--directives)
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Directive is one directive comment of a Go file
+type Directive struct {
+	Line int    `json:"line"`
+	Name string `json:"name"` // "go:build", "+build", "go:generate", "go:embed", ...
+	Args string `json:"args,omitempty"`
+}
+
+// FindDirectives scans the raw lines of a Go file: the sanitizer blanks
+// comments, so directives are read before it runs. Build constraints
+// ("//go:build", "// +build") only count above the package clause, where
+// the go tool reads them; "//go:" directives count anywhere. Like the go
+// tool, only comments starting in the first column are directives; lines
+// inside raw strings and block comments are skipped.
+func FindDirectives(lines []string) []Directive {
+	var found []Directive
+	header := true
+	var open byte // '`' or '*' while a raw string or block comment spans lines
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		startsOpen := open != 0
+		open = goLineState(line, open)
+		if startsOpen {
+			continue
+		}
+		if header && strings.HasPrefix(line, "package ") {
+			header = false
+			continue
+		}
+
+		var text string
+		switch {
+		case strings.HasPrefix(line, "//go:"):
+			text = line[len("//"):]
+		case header && strings.HasPrefix(line, "// +build"):
+			text = line[len("// "):]
+		case header && strings.HasPrefix(line, "//+build"):
+			text = line[len("//"):]
+		default:
+			continue
+		}
+		name, args, _ := strings.Cut(text, " ")
+		if name == "go:" || strings.HasPrefix(name, "+") && name != "+build" {
+			continue
+		}
+		found = append(found, Directive{Line: i + 1, Name: name, Args: strings.TrimSpace(args)})
+	}
+	return found
+}
+
+// FormatDirectives writes one "line: name args" row per directive, or the
+// list as JSON
+func FormatDirectives(directives []Directive, jsonOut bool) string {
+	if jsonOut {
+		if directives == nil {
+			directives = []Directive{}
+		}
+		b, err := json.MarshalIndent(directives, "", "  ")
+		if err != nil {
+			return "[]"
+		}
+		return string(b)
+	}
+	rows := make([]string, len(directives))
+	for i, d := range directives {
+		rows[i] = strings.TrimSpace(fmt.Sprintf("%d: %s %s", d.Line, d.Name, d.Args))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// goLineState scans one line of Go source and returns what is still open
+// at its end: '`' for a raw string, '*' for a block comment, 0 for nothing.
+// open is the state carried over from the previous line.
+func goLineState(line string, open byte) byte {
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch open {
+		case '`':
+			if c == '`' {
+				open = 0
+			}
+			continue
+		case '*':
+			if c == '*' && i+1 < len(line) && line[i+1] == '/' {
+				open = 0
+				i++
+			}
+			continue
+		}
+		switch {
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return 0
+		case c == '/' && i+1 < len(line) && line[i+1] == '*':
+			open = '*'
+			i++
+		case c == '`':
+			open = '`'
+		case c == '"' || c == '\'':
+			// interpreted strings and runes end on their line
+			for i++; i < len(line) && line[i] != c; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return open
+}
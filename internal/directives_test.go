@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindDirectives_BuildTagAndGenerate(t *testing.T) {
+	src := "//go:build linux && !cgo\n" +
+		"// +build linux,!cgo\n" +
+		"\n" +
+		"// Package p is built on Linux only.\n" +
+		"package p\n" +
+		"\n" +
+		"//go:generate stringer -type=Kind\n" +
+		"\n" +
+		"// +build ignored below the package clause\n" +
+		"//go:noinline\n" +
+		"func f() string {\n" +
+		"\treturn `\n" +
+		"//go:embed not-a-directive\n" +
+		"`\n" +
+		"}\n" +
+		"/*\n" +
+		"//go:generate commented out\n" +
+		"*/\n"
+
+	got := FindDirectives(strings.Split(src, "\n"))
+	want := []Directive{
+		{Line: 1, Name: "go:build", Args: "linux && !cgo"},
+		{Line: 2, Name: "+build", Args: "linux,!cgo"},
+		{Line: 7, Name: "go:generate", Args: "stringer -type=Kind"},
+		{Line: 10, Name: "go:noinline"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDirectives() = %+v, want %+v", got, want)
+	}
+
+	if text := FormatDirectives(got[:3], false); text != "1: go:build linux && !cgo\n2: +build linux,!cgo\n7: go:generate stringer -type=Kind" {
+		t.Errorf("FormatDirectives() = %q", text)
+	}
+	if text := FormatDirectives(nil, true); text != "[]" {
+		t.Errorf("FormatDirectives(nil, json) = %q, want []", text)
+	}
+}
+
+func TestFindDirectives_IndentedCommentIsNotDirective(t *testing.T) {
+	lines := []string{"package p", "", "func f() {", "\t//go:noinline", "\t// +build x", "}"}
+	if got := FindDirectives(lines); len(got) != 0 {
+		t.Errorf("FindDirectives() = %+v, want none", got)
+	}
+}
@@ -2,13 +2,17 @@
 package internal
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,9 +28,16 @@ type Job struct {
 // DirResult represents the outcome of processing a single file
 type DirResult struct {
 	Path      string
+	LangKey   string // Language key the file was parsed with
 	Functions []FunctionBounds
 	Classes   []ClassBounds
 	Error     error
+	// Ambiguities reported by the function finder (see --strict)
+	Ambiguities []ParseAmbiguity
+	// Public/private counts before --exported-only (--visibility-summary)
+	Visibility *VisibilitySummary
+	// Skipped because of a generated-file header (--ignore-generated)
+	Generated bool
 }
 
 // DirProcessor handles directory traversal and parallel file processing
@@ -35,12 +46,32 @@ type DirProcessor struct {
 	workers      int
 	recursive    bool
 	useGitignore bool
-	workMode     string // "functions", "structs", or "all"
+	workMode     string   // "functions", "structs", or "all"
+	exportedOnly bool     // keep only exported symbols (--exported-only)
+	ignoreFiles  []string // extra ignore files applied from the root (--ignore-file)
+	gitExcludes  bool     // apply .git/info/exclude and core.excludesFile (--gitignore-mode)
+	withDocs     bool     // attach doc comments to functions (--with-docs)
+	maxDepth     int      // directory levels below the root to scan, -1 = unlimited (--max-depth)
+	noClasses    bool     // skip class discovery in function finders (--no-classes)
+	maxParams    int      // keep functions with more parameters, -1 = off (--max-params)
+	deprecated   bool     // keep only functions marked deprecated (--deprecated-only)
+	anonymous    bool     // skip unnamed functions and IIFEs (--exclude-anonymous)
+	macros       bool     // report C/C++ function-like macros (--include-macros)
+	signatures   bool     // keep function source lines for {{.Signature}} (--format)
+	// drop test files or keep only them (--exclude-tests, --tests-only)
+	testFiles TestFileMode
+	// count public/private symbols of every file (--visibility-summary)
+	visibility bool
+	// absolute paths to scan; nil scans every supported file (--since)
+	onlyFiles map[string]bool
+	// header markers of generated files to skip, nil = off (--ignore-generated)
+	generatedMarkers []string
 }
 
 // TreeNode represents a node in the directory tree for tree output
 type DirTreeNode struct {
 	Path      string
+	LangKey   string // language label of a file node, empty for directories
 	Functions []FunctionBounds
 	Classes   []ClassBounds
 	Children  map[string]*DirTreeNode
@@ -57,9 +88,121 @@ func NewDirProcessor(config Config, workers int, recursive, useGitignore bool, w
 		recursive:    recursive,
 		useGitignore: useGitignore,
 		workMode:     workMode,
+		maxDepth:     -1,
+		maxParams:    -1,
 	}
 }
 
+// SetExportedOnly drops non-exported functions and types from every result
+func (dp *DirProcessor) SetExportedOnly(exportedOnly bool) {
+	dp.exportedOnly = exportedOnly
+}
+
+// SetVisibility sets DirResult.Visibility of every file
+func (dp *DirProcessor) SetVisibility(visibility bool) {
+	dp.visibility = visibility
+}
+
+// SetOnlyFiles restricts directory scans to the given absolute paths
+// (ChangedFiles); files outside the set are skipped, nil turns it off
+func (dp *DirProcessor) SetOnlyFiles(paths map[string]bool) {
+	dp.onlyFiles = paths
+}
+
+// SetGeneratedMarkers skips files with one of markers in their first lines
+// (IsGenerated); such files get an empty DirResult with Generated set
+func (dp *DirProcessor) SetGeneratedMarkers(markers []string) {
+	dp.generatedMarkers = markers
+}
+
+// SetMaxDepth limits recursion to depth directory levels below the root
+// (0 = root only, like non-recursive); a negative depth means unlimited
+func (dp *DirProcessor) SetMaxDepth(depth int) {
+	dp.maxDepth = depth
+}
+
+// SetWithDocs attaches leading doc comments / docstrings to functions
+func (dp *DirProcessor) SetWithDocs(withDocs bool) {
+	dp.withDocs = withDocs
+}
+
+// SetNoClasses skips class discovery: functions keep an empty ClassName
+// and results have no Classes
+func (dp *DirProcessor) SetNoClasses(noClasses bool) {
+	dp.noClasses = noClasses
+}
+
+// SetSignatures keeps each function's source lines in Lines, so --format
+// templates can render {{.Signature}} in directory and file-list modes
+func (dp *DirProcessor) SetSignatures(signatures bool) {
+	dp.signatures = signatures
+}
+
+// SetMaxParams keeps only functions with more than n parameters and sets
+// their ParamCount; a negative n turns the filter off
+func (dp *DirProcessor) SetMaxParams(n int) {
+	dp.maxParams = n
+}
+
+// SetDeprecatedOnly keeps only functions with a deprecation marker and
+// sets their IsDeprecated (AttachDeprecated)
+func (dp *DirProcessor) SetDeprecatedOnly(deprecatedOnly bool) {
+	dp.deprecated = deprecatedOnly
+}
+
+// SetExcludeAnonymous skips functions without a name and IIFEs in finders
+// that support it (AnonymousSkipper)
+func (dp *DirProcessor) SetExcludeAnonymous(exclude bool) {
+	dp.anonymous = exclude
+}
+
+// SetIncludeMacros reports function-like macros as functions of Kind
+// "macro" in finders that support it (MacroFinder)
+func (dp *DirProcessor) SetIncludeMacros(include bool) {
+	dp.macros = include
+}
+
+// SetTestFiles keeps or drops test files (test_file_patterns) when
+// collecting files from a directory or an archive
+func (dp *DirProcessor) SetTestFiles(mode TestFileMode) {
+	dp.testFiles = mode
+}
+
+// SetIgnoreFiles adds ignore files (.ignore, .dockerignore, ...) whose
+// patterns apply relative to the scanned root, even with gitignore disabled
+func (dp *DirProcessor) SetIgnoreFiles(files []string) {
+	dp.ignoreFiles = files
+}
+
+// SetGitExcludes also applies the excludes git keeps outside .gitignore
+// files: .git/info/exclude and core.excludesFile (see AddGitExcludes)
+func (dp *DirProcessor) SetGitExcludes(gitExcludes bool) {
+	dp.gitExcludes = gitExcludes
+}
+
+// newIgnoreMatcher builds the matcher for rootPath, or nil when neither
+// .gitignore, git excludes nor extra ignore files are in use
+func (dp *DirProcessor) newIgnoreMatcher(rootPath string) (*IgnoreMatcher, error) {
+	if !dp.useGitignore && !dp.gitExcludes && len(dp.ignoreFiles) == 0 {
+		return nil, nil
+	}
+	var m *IgnoreMatcher
+	if dp.useGitignore {
+		m = NewIgnoreMatcher(rootPath)
+	} else {
+		m = &IgnoreMatcher{root: rootPath}
+	}
+	if dp.gitExcludes {
+		m.AddGitExcludes()
+	}
+	for _, file := range dp.ignoreFiles {
+		if err := m.AddFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
 // ProcessDirectory processes all supported files in a directory
 func (dp *DirProcessor) ProcessDirectory(rootPath string) ([]DirResult, error) {
 	// Collect all files first
@@ -76,18 +219,64 @@ func (dp *DirProcessor) ProcessDirectory(rootPath string) ([]DirResult, error) {
 	return dp.processFilesParallel(files)
 }
 
+// ProcessFiles processes an explicit list of files (repeated --inp) and
+// returns their results in the order given. langKey forces one language for
+// every file; when empty the language is detected from the extension (or
+// the shebang line of an extensionless script).
+func (dp *DirProcessor) ProcessFiles(paths []string, langKey string) ([]DirResult, error) {
+	jobs := make([]Job, 0, len(paths))
+	for _, path := range paths {
+		key := langKey
+		if key == "" {
+			langConfig := dp.config.DetectLanguage(path)
+			if langConfig == nil {
+				return nil, fmt.Errorf("unsupported file extension: %s (use --source)", path)
+			}
+			key = langConfig.LangKey
+		}
+		jobs = append(jobs, Job{Path: path, Extension: filepath.Ext(path), LangKey: key})
+	}
+	if len(jobs) == 0 {
+		return []DirResult{}, nil
+	}
+
+	results, err := dp.processFilesParallel(jobs)
+	if err != nil {
+		return nil, err
+	}
+	order := make(map[string]int, len(paths))
+	for i, path := range paths {
+		if _, seen := order[path]; !seen {
+			order[path] = i
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return order[results[i].Path] < order[results[j].Path] })
+	return results, nil
+}
+
 // collectFiles walks the directory and collects all supported files
 func (dp *DirProcessor) collectFiles(rootPath string) ([]Job, error) {
 	var jobs []Job
 	var mu sync.Mutex
 
 	// Load gitignore patterns if enabled
-	var ignoreMatcher *IgnoreMatcher
-	if dp.useGitignore {
-		ignoreMatcher = NewIgnoreMatcher(rootPath)
+	ignoreMatcher, err := dp.newIgnoreMatcher(rootPath)
+	if err != nil {
+		return nil, err
 	}
 
-	err := filepath.Walk(rootPath, func(path string, info fs.FileInfo, err error) error {
+	// git reports resolved paths: compare against the resolved root
+	absRoot := rootPath
+	if dp.onlyFiles != nil {
+		if absRoot, err = filepath.Abs(rootPath); err != nil {
+			return nil, err
+		}
+		if resolved, err := filepath.EvalSymlinks(absRoot); err == nil {
+			absRoot = resolved
+		}
+	}
+
+	err = filepath.Walk(rootPath, func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			// Skip files/directories that can't be accessed
 			return nil
@@ -123,12 +312,23 @@ func (dp *DirProcessor) collectFiles(rootPath string) ([]Job, error) {
 			if !dp.recursive && path != rootPath {
 				return filepath.SkipDir
 			}
+			// --max-depth: "a" is level 1, "a/b" level 2, ...
+			if dp.maxDepth >= 0 && path != rootPath && strings.Count(filepath.ToSlash(relPath), "/")+1 > dp.maxDepth {
+				return filepath.SkipDir
+			}
+			// Nested .gitignore applies to this subtree only
+			if dp.useGitignore && path != rootPath {
+				ignoreMatcher.LoadNested(relPath)
+			}
 			return nil
 		}
 
-		// Check if file extension is supported
-		langConfig := dp.config.GetLanguageByExtension(path)
-		if langConfig == nil {
+		// Check if file extension (or shebang of a script) is supported
+		if dp.onlyFiles != nil && !dp.onlyFiles[filepath.Join(absRoot, relPath)] {
+			return nil
+		}
+		langConfig := dp.config.DetectLanguage(path)
+		if langConfig == nil || !dp.testFiles.Keeps(langConfig, path) {
 			return nil
 		}
 
@@ -189,14 +389,72 @@ func (dp *DirProcessor) processFilesParallel(jobs []Job) ([]DirResult, error) {
 
 // worker processes jobs from the channel
 func (dp *DirProcessor) worker(jobsChan <-chan Job, resultsChan chan<- DirResult) {
+	cache := dp.newWorkerCache()
 	for job := range jobsChan {
-		result := dp.processFile(job)
+		result := dp.processFile(job, cache)
 		resultsChan <- result
 	}
 }
 
+// finderCache holds the finders a single worker reuses across files, keyed
+// by LangKey. Finders only depend on the shared LanguageConfig, but they are
+// not safe for concurrent use (PythonFinder keeps a decorator window), so
+// every worker goroutine owns its own cache.
+type finderCache struct {
+	finders       map[string]LanguageFinder
+	structFinders map[string]StructFinderInterface
+	noClasses     bool
+	anonymous     bool
+	macros        bool
+}
+
+func newFinderCache() *finderCache {
+	return &finderCache{
+		finders:       make(map[string]LanguageFinder),
+		structFinders: make(map[string]StructFinderInterface),
+	}
+}
+
+// newWorkerCache returns a finder cache configured by the processor options
+func (dp *DirProcessor) newWorkerCache() *finderCache {
+	cache := newFinderCache()
+	cache.noClasses = dp.noClasses
+	cache.anonymous = dp.anonymous
+	cache.macros = dp.macros
+	return cache
+}
+
+// finder returns the cached map-mode function finder for langConfig
+func (c *finderCache) finder(langConfig *LanguageConfig) LanguageFinder {
+	finder, ok := c.finders[langConfig.LangKey]
+	if !ok {
+		finder = CreateFinder(langConfig, "", "map", false, false)
+		if skipper, ok := finder.(ClassSkipper); ok {
+			skipper.SetNoClasses(c.noClasses)
+		}
+		if skipper, ok := finder.(AnonymousSkipper); ok {
+			skipper.SetExcludeAnonymous(c.anonymous)
+		}
+		if macroFinder, ok := finder.(MacroFinder); ok {
+			macroFinder.SetIncludeMacros(c.macros)
+		}
+		c.finders[langConfig.LangKey] = finder
+	}
+	return finder
+}
+
+// structFinder returns the cached map-mode struct finder for langConfig
+func (c *finderCache) structFinder(langConfig *LanguageConfig) StructFinderInterface {
+	finder, ok := c.structFinders[langConfig.LangKey]
+	if !ok {
+		finder = NewStructFinderFactory().CreateStructFinder(langConfig, "", true, false)
+		c.structFinders[langConfig.LangKey] = finder
+	}
+	return finder
+}
+
 // processFile processes a single file
-func (dp *DirProcessor) processFile(job Job) DirResult {
+func (dp *DirProcessor) processFile(job Job, cache *finderCache) DirResult {
 	result := DirResult{
 		Path: job.Path,
 	}
@@ -207,17 +465,38 @@ func (dp *DirProcessor) processFile(job Job) DirResult {
 		return result
 	}
 
+	data, err := os.ReadFile(job.Path)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to open file: %w", err)
+		return result
+	}
+
+	return dp.processSource(job.Path, langConfig, data, cache)
+}
+
+// processSource runs the finders selected by workMode over in-memory source.
+// path is reported as DirResult.Path and may be virtual (archive entries).
+func (dp *DirProcessor) processSource(path string, langConfig *LanguageConfig, data []byte, cache *finderCache) DirResult {
+	result := DirResult{
+		Path:    path,
+		LangKey: langConfig.LangKey,
+	}
+	if dp.generatedMarkers != nil && IsGenerated(data, dp.generatedMarkers) {
+		result.Generated = true
+		return result
+	}
+
 	switch dp.workMode {
 	case "functions":
 		// Find only functions
-		finder := CreateFinder(langConfig, "", "map", false, false)
-		findResult, err := finder.FindFunctions(job.Path)
+		findResult, err := cache.finder(langConfig).FindFunctionsInReader(bytes.NewReader(data), path)
 		if err != nil {
 			result.Error = err
 			return result
 		}
 		result.Functions = findResult.Functions
 		result.Classes = findResult.Classes
+		result.Ambiguities = findResult.Ambiguities
 
 	case "structs":
 		// Find only structs/classes/types
@@ -225,9 +504,7 @@ func (dp *DirProcessor) processFile(job Job) DirResult {
 			// Skip languages without struct support
 			return result
 		}
-		factory := NewStructFinderFactory()
-		structFinder := factory.CreateStructFinder(langConfig, "", true, false)
-		structResult, err := structFinder.FindStructures(job.Path)
+		structResult, err := findStructuresInSource(cache.structFinder(langConfig), path, data)
 		if err != nil {
 			result.Error = err
 			return result
@@ -238,38 +515,41 @@ func (dp *DirProcessor) processFile(job Job) DirResult {
 				Name:  typ.Name,
 				Start: typ.Start,
 				End:   typ.End,
+				Kind:  typ.Kind,
 			})
 		}
 
 	case "all":
 		// Find both functions and structs
-		finder := CreateFinder(langConfig, "", "map", false, false)
-		findResult, err := finder.FindFunctions(job.Path)
+		findResult, err := cache.finder(langConfig).FindFunctionsInReader(bytes.NewReader(data), path)
 		if err != nil {
 			result.Error = err
 			return result
 		}
 		result.Functions = findResult.Functions
 		result.Classes = findResult.Classes
+		result.Ambiguities = findResult.Ambiguities
 
 		// Also find structs if language supports it
 		if langConfig.HasStructSupport() {
-			factory := NewStructFinderFactory()
-			structFinder := factory.CreateStructFinder(langConfig, "", true, false)
-			structResult, err := structFinder.FindStructures(job.Path)
+			structResult, err := findStructuresInSource(cache.structFinder(langConfig), path, data)
 			if err == nil {
-				// Dedup: only add types not already in Classes (from class_pattern)
-				seen := make(map[string]bool, len(result.Classes))
-				for _, c := range result.Classes {
-					seen[c.Name+":"+strconv.Itoa(c.Start)] = true
+				// Dedup: only add types not already in Classes (from class_pattern);
+				// those take the struct finder's kind
+				seen := make(map[string]int, len(result.Classes))
+				for i, c := range result.Classes {
+					seen[c.Name+":"+strconv.Itoa(c.Start)] = i
 				}
 				for _, typ := range structResult.Types {
 					key := typ.Name + ":" + strconv.Itoa(typ.Start)
-					if !seen[key] {
+					if i, ok := seen[key]; ok {
+						result.Classes[i].Kind = typ.Kind
+					} else {
 						result.Classes = append(result.Classes, ClassBounds{
 							Name:  typ.Name,
 							Start: typ.Start,
 							End:   typ.End,
+							Kind:  typ.Kind,
 						})
 					}
 				}
@@ -277,9 +557,74 @@ func (dp *DirProcessor) processFile(job Job) DirResult {
 		}
 	}
 
+	exportFilter := dp.exportedOnly && langConfig.ExportRule != ExportAll
+	paramFilter := dp.maxParams >= 0 && len(result.Functions) > 0
+	if exportFilter || paramFilter || dp.visibility || ((dp.withDocs || dp.deprecated || dp.signatures) && len(result.Functions) > 0) {
+		lines, err := scanSourceLines(bytes.NewReader(data))
+		if err != nil {
+			result.Error = fmt.Errorf("failed to read file: %w", err)
+			return result
+		}
+		if exportFilter || dp.visibility {
+			checker := NewExportChecker(langConfig, lines)
+			if dp.visibility {
+				v := CountVisibility(result.Functions, result.Classes, checker)
+				result.Visibility = &v
+			}
+			if exportFilter {
+				result.Functions = FilterExportedFunctions(result.Functions, checker)
+				result.Classes = FilterExportedClasses(result.Classes, checker)
+			}
+		}
+		if dp.withDocs {
+			AttachDocs(result.Functions, lines, langConfig)
+		}
+		if dp.signatures {
+			attachSourceLines(result.Functions, lines)
+		}
+		if paramFilter {
+			AttachParamCounts(result.Functions, lines, langConfig)
+			result.Functions = FilterByMaxParams(result.Functions, dp.maxParams)
+		}
+		if dp.deprecated {
+			AttachDeprecated(result.Functions, lines, langConfig)
+			result.Functions = FilterDeprecated(result.Functions)
+		}
+	}
+
 	return result
 }
 
+// attachSourceLines points each function's Lines at its span of lines
+func attachSourceLines(funcs []FunctionBounds, lines []string) {
+	for i := range funcs {
+		fn := &funcs[i]
+		if fn.Start >= 1 && fn.End >= fn.Start && fn.End <= len(lines) {
+			fn.Lines = lines[fn.Start-1 : fn.End]
+		}
+	}
+}
+
+// findStructuresInSource runs a struct finder over in-memory source,
+// splitting lines the same way FindStructures does for files on disk.
+func findStructuresInSource(structFinder StructFinderInterface, path string, data []byte) (*StructFindResult, error) {
+	lines, err := scanSourceLines(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return structFinder.FindStructuresInLines(lines, 1, path)
+}
+
+// scanSourceLines reads r line by line (without line terminators)
+func scanSourceLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
 // AggregateDirResults aggregates results from multiple files
 func AggregateDirResults(results []DirResult, jsonOut, treeMode, treeFull bool) string {
 	if jsonOut {
@@ -287,7 +632,7 @@ func AggregateDirResults(results []DirResult, jsonOut, treeMode, treeFull bool)
 	}
 
 	if treeMode || treeFull {
-		return formatDirResultsTree(results, treeFull)
+		return FormatDirResultsTree(results, treeFull, true)
 	}
 
 	return formatDirResultsGrep(results)
@@ -296,8 +641,15 @@ func AggregateDirResults(results []DirResult, jsonOut, treeMode, treeFull bool)
 // jsonSymbol is the on-disk shape for a mapped function or type: just its name
 // and starting line, not the internal Start/End/Lines/Decorators fields.
 type jsonSymbol struct {
-	Name string `json:"name"`
-	Line int    `json:"line"`
+	Name       string `json:"name"`
+	Line       int    `json:"line"`
+	Category   string `json:"category,omitempty"`
+	Doc        string `json:"doc,omitempty"`
+	Generator  bool   `json:"generator,omitempty"`
+	FirstLine  string `json:"first_line,omitempty"`
+	ParamCount int    `json:"param_count,omitempty"`
+	Abstract   bool   `json:"abstract,omitempty"`
+	Deprecated bool   `json:"deprecated,omitempty"`
 }
 
 type jsonFile struct {
@@ -307,35 +659,62 @@ type jsonFile struct {
 }
 
 type jsonDirResults struct {
-	Files          []jsonFile `json:"files"`
-	TotalFiles     int        `json:"total_files"`
-	TotalFunctions int        `json:"total_functions"`
-	TotalClasses   int        `json:"total_classes"`
+	Files          []jsonFile             `json:"files"`
+	TotalFiles     int                    `json:"total_files"`
+	TotalFunctions int                    `json:"total_functions"`
+	TotalClasses   int                    `json:"total_classes"`
+	LanguageStats  map[string]LangSummary `json:"language_stats,omitempty"`
+	// Files left out as generated (--ignore-generated)
+	SkippedGenerated int `json:"skipped_generated,omitempty"`
+}
+
+// toJSONFile converts one file's result to its --json shape
+func toJSONFile(r DirResult) jsonFile {
+	jf := jsonFile{
+		Path:      r.Path,
+		Functions: make([]jsonSymbol, 0, len(r.Functions)),
+		Classes:   make([]jsonSymbol, 0, len(r.Classes)),
+	}
+	for _, fn := range r.Functions {
+		jf.Functions = append(jf.Functions, jsonSymbol{Name: fn.Name, Line: fn.Start, Category: fn.Category, Doc: fn.Doc, Generator: fn.IsGenerator, FirstLine: fn.FirstLine, ParamCount: fn.ParamCount, Abstract: fn.IsAbstract, Deprecated: fn.IsDeprecated})
+	}
+	for _, c := range r.Classes {
+		jf.Classes = append(jf.Classes, jsonSymbol{Name: c.Name, Line: c.Start})
+	}
+	return jf
 }
 
 func formatDirResultsJSON(results []DirResult) string {
+	return marshalDirResultsJSON(buildJSONDirResults(results))
+}
+
+// FormatDirResultsJSONWithLanguageStats is the --json directory output with
+// a "language_stats" object keyed by language (--profile-languages)
+func FormatDirResultsJSONWithLanguageStats(results []DirResult) string {
+	out := buildJSONDirResults(results)
+	out.LanguageStats = LanguageStats(results)
+	return marshalDirResultsJSON(out)
+}
+
+func buildJSONDirResults(results []DirResult) jsonDirResults {
 	out := jsonDirResults{Files: []jsonFile{}}
 	for _, r := range results {
-		if len(r.Functions) == 0 && len(r.Classes) == 0 {
+		if r.Generated {
+			out.SkippedGenerated++
 			continue
 		}
-		jf := jsonFile{
-			Path:      r.Path,
-			Functions: make([]jsonSymbol, 0, len(r.Functions)),
-			Classes:   make([]jsonSymbol, 0, len(r.Classes)),
-		}
-		for _, fn := range r.Functions {
-			jf.Functions = append(jf.Functions, jsonSymbol{Name: fn.Name, Line: fn.Start})
-		}
-		for _, c := range r.Classes {
-			jf.Classes = append(jf.Classes, jsonSymbol{Name: c.Name, Line: c.Start})
+		if len(r.Functions) == 0 && len(r.Classes) == 0 {
+			continue
 		}
-		out.Files = append(out.Files, jf)
+		out.Files = append(out.Files, toJSONFile(r))
 		out.TotalFiles++
 		out.TotalFunctions += len(r.Functions)
 		out.TotalClasses += len(r.Classes)
 	}
+	return out
+}
 
+func marshalDirResultsJSON(out jsonDirResults) string {
 	b, err := json.MarshalIndent(out, "", "  ")
 	if err != nil {
 		return "{}\n"
@@ -343,7 +722,10 @@ func formatDirResultsJSON(results []DirResult) string {
 	return string(b) + "\n"
 }
 
-func formatDirResultsTree(results []DirResult, full bool) string {
+// FormatDirResultsTree renders results as a directory tree; with langLabels
+// every file is followed by its language ("main.go [go]"), which tells the
+// files of a mixed tree apart (--no-lang-label turns it off)
+func FormatDirResultsTree(results []DirResult, full, langLabels bool) string {
 	if len(results) == 0 {
 		return "No functions found"
 	}
@@ -382,6 +764,9 @@ func formatDirResultsTree(results []DirResult, full bool) string {
 			Functions: r.Functions,
 			Classes:   r.Classes,
 		}
+		if langLabels {
+			current.Children[filename].LangKey = r.LangKey
+		}
 	}
 
 	// Build tree output
@@ -398,7 +783,11 @@ func buildTreeOutput(node *DirTreeNode, prefix string, isLast bool) string {
 	}
 
 	if node.Path != "" {
-		output += prefix + connector + filepath.Base(node.Path) + "\n"
+		output += prefix + connector + filepath.Base(node.Path)
+		if node.LangKey != "" {
+			output += " [" + node.LangKey + "]"
+		}
+		output += "\n"
 		newPrefix := prefix
 		if isLast {
 			newPrefix += "    "
@@ -459,7 +848,10 @@ type IgnoreMatcher struct {
 
 type ignorePattern struct {
 	regex     *regexp.Regexp
-	directory bool // pattern ends with /
+	directory bool   // pattern ends with /
+	negate    bool   // pattern starts with ! (re-includes a previously ignored path)
+	base      string // directory of the defining .gitignore relative to root ("" = root)
+	prefix    string // root relative to the repository top level, for info/exclude patterns
 }
 
 func NewIgnoreMatcher(root string) *IgnoreMatcher {
@@ -476,11 +868,32 @@ func NewIgnoreMatcher(root string) *IgnoreMatcher {
 	}
 
 	// Parse patterns
-	m.parsePatterns(string(data))
+	m.parsePatterns(string(data), "")
 	return m
 }
 
-func (m *IgnoreMatcher) parsePatterns(content string) {
+// AddFile loads an extra ignore file (any name); its patterns are
+// relative to the matcher root, like the root .gitignore
+func (m *IgnoreMatcher) AddFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading ignore file: %w", err)
+	}
+	m.parsePatterns(string(data), "")
+	return nil
+}
+
+// LoadNested loads relDir/.gitignore (relDir relative to root) if present.
+// Its patterns only match paths inside relDir, relative to relDir.
+func (m *IgnoreMatcher) LoadNested(relDir string) {
+	data, err := os.ReadFile(filepath.Join(m.root, relDir, ".gitignore"))
+	if err != nil {
+		return
+	}
+	m.parsePatterns(string(data), filepath.ToSlash(relDir))
+}
+
+func (m *IgnoreMatcher) parsePatterns(content string, base string) {
 	lines := regexp.MustCompile(`\r?\n`).Split(content, -1)
 	for _, line := range lines {
 		line = regexp.MustCompile(`#.*`).ReplaceAllString(line, "")
@@ -513,29 +926,28 @@ func (m *IgnoreMatcher) parsePatterns(content string) {
 			continue
 		}
 
-		if !negate {
-			m.patterns = append(m.patterns, ignorePattern{
-				regex:     re,
-				directory: isDir,
-			})
-		}
+		m.patterns = append(m.patterns, ignorePattern{
+			regex:     re,
+			directory: isDir,
+			negate:    negate,
+			base:      base,
+		})
 	}
 }
 
 func (m *IgnoreMatcher) patternToRegex(pattern string) string {
-	// Escape regex special characters except * and ?
-	regex := regexp.MustCompile(`([.+?^${}()|[\]\\])`).ReplaceAllStringFunc(pattern, func(match string) string {
-		return "\\" + match
-	})
+	// Escape regex special characters; globs are restored below
+	regex := regexp.QuoteMeta(pattern)
 
-	// Handle ** glob pattern
+	// Handle ** glob pattern ("**/" also matches zero directories)
+	regex = strings.ReplaceAll(regex, `\*\*/`, "(?:.*/)?")
 	regex = strings.ReplaceAll(regex, `\*\*`, ".*")
 
 	// Handle single * glob pattern
 	regex = strings.ReplaceAll(regex, `\*`, "[^/]*")
 
 	// Handle ? glob pattern
-	regex = strings.ReplaceAll(regex, `\?`, ".")
+	regex = strings.ReplaceAll(regex, `\?`, "[^/]")
 
 	// Match full path or as subdirectory
 	if strings.HasPrefix(pattern, "/") {
@@ -545,16 +957,34 @@ func (m *IgnoreMatcher) patternToRegex(pattern string) string {
 	return "(^|/)" + regex + "($|/)"
 }
 
+// Matches applies patterns in order, like Git: the last matching pattern
+// decides, so a later "!pattern" re-includes a path ignored before it.
+// A negated directory pattern ("!vendor/keep/") also re-includes the files
+// below it. Files under a directory that stays ignored are never visited by
+// the walkers (SkipDir), which gives Git's "cannot re-include inside an
+// excluded directory" rule.
 func (m *IgnoreMatcher) Matches(path string, isDir bool) bool {
+	path = filepath.ToSlash(path)
+	ignored := false
 	for _, p := range m.patterns {
-		if p.directory && !isDir {
+		if p.directory && !isDir && !p.negate {
 			continue
 		}
-		if p.regex.MatchString(path) {
-			return true
+		rel := path
+		if p.base != "" {
+			if !strings.HasPrefix(path, p.base+"/") {
+				continue
+			}
+			rel = path[len(p.base)+1:]
+		}
+		if p.prefix != "" {
+			rel = p.prefix + "/" + rel
+		}
+		if p.regex.MatchString(rel) {
+			ignored = !p.negate
 		}
 	}
-	return false
+	return ignored
 }
 
 // CollectSourceFiles finds all source files matching langConfig in rootPath.
@@ -604,6 +1034,9 @@ func CollectSourceFiles(rootPath string, langConfig *LanguageConfig, recursive b
 			if !recursive && path != rootPath {
 				return filepath.SkipDir
 			}
+			if ignoreMatcher != nil && path != rootPath {
+				ignoreMatcher.LoadNested(relPath)
+			}
 			return nil
 		}
 
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+var benchGoSource = []byte(strings.Repeat("func handler(w int) int {\n\tif w > 0 {\n\t\treturn w\n\t}\n\treturn 0\n}\n\n", 20))
+
+// BenchmarkProcessSource_Cached reuses one worker's finders across files
+// (the DirProcessor path); compare allocs/op with _Uncached.
+func BenchmarkProcessSource_Cached(b *testing.B) {
+	config, err := LoadConfig()
+	if err != nil {
+		b.Fatal(err)
+	}
+	dp := NewDirProcessor(config, 1, false, false, "all")
+	langConfig := config["go"]
+	cache := newFinderCache()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dp.processSource("bench.go", langConfig, benchGoSource, cache)
+	}
+}
+
+// BenchmarkProcessSource_Uncached builds new finders for every file
+func BenchmarkProcessSource_Uncached(b *testing.B) {
+	config, err := LoadConfig()
+	if err != nil {
+		b.Fatal(err)
+	}
+	dp := NewDirProcessor(config, 1, false, false, "all")
+	langConfig := config["go"]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dp.processSource("bench.go", langConfig, benchGoSource, newFinderCache())
+	}
+}
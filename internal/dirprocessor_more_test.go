@@ -52,21 +52,139 @@ func TestIgnoreMatcher_NoGitignoreFile(t *testing.T) {
 	}
 }
 
-func TestIgnoreMatcher_NegationLinesAreDropped(t *testing.T) {
-	// parsePatterns simply discards "!"-prefixed lines instead of
-	// re-including a path ignored by an earlier pattern — so a negated
-	// path stays ignored as long as another pattern still covers it.
+func TestIgnoreMatcher_NegationReincludes(t *testing.T) {
 	tmpDir := t.TempDir()
-	gitignore := "*.log\n!important.log\n"
+	gitignore := "*.log\n!keep.log\n"
 	if err := os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte(gitignore), 0644); err != nil {
 		t.Fatalf("write .gitignore: %v", err)
 	}
 	m := NewIgnoreMatcher(tmpDir)
-	if !m.Matches("important.log", false) {
-		t.Error("negation line is dropped, not subtracted — *.log should still cover important.log")
+	if m.Matches("keep.log", false) {
+		t.Error("!keep.log should re-include keep.log after *.log")
+	}
+	if m.Matches("logs/keep.log", false) {
+		t.Error("!keep.log should re-include keep.log in subdirectories too")
 	}
 	if !m.Matches("other.log", false) {
-		t.Error("non-negated *.log pattern should still match")
+		t.Error("*.log should still ignore other.log")
+	}
+}
+
+func TestIgnoreMatcher_NegationOrderMatters(t *testing.T) {
+	tmpDir := t.TempDir()
+	// The later pattern wins: keep.log is ignored again
+	gitignore := "*.log\n!keep.log\n*.log\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte(gitignore), 0644); err != nil {
+		t.Fatalf("write .gitignore: %v", err)
+	}
+	if !NewIgnoreMatcher(tmpDir).Matches("keep.log", false) {
+		t.Error("a later *.log should override the earlier !keep.log")
+	}
+}
+
+func TestIgnoreMatcher_DirectoryReinclusion(t *testing.T) {
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, ".gitignore"), "vendor/*\n!vendor/keep/\n")
+	mustMkdir(t, filepath.Join(tmpDir, "vendor", "drop"))
+	mustMkdir(t, filepath.Join(tmpDir, "vendor", "keep"))
+	mustWrite(t, filepath.Join(tmpDir, "vendor", "drop", "a.go"), "package drop\n")
+	mustWrite(t, filepath.Join(tmpDir, "vendor", "keep", "b.go"), "package keep\n")
+	mustWrite(t, filepath.Join(tmpDir, "main.go"), "package main\n")
+
+	m := NewIgnoreMatcher(tmpDir)
+	if !m.Matches("vendor/drop", true) {
+		t.Error("vendor/drop should be ignored by vendor/*")
+	}
+	if m.Matches("vendor/keep", true) || m.Matches("vendor/keep/b.go", false) {
+		t.Error("!vendor/keep/ should re-include the directory and its files")
+	}
+
+	files, err := CollectSourceFiles(tmpDir, nil, true)
+	if err != nil {
+		t.Fatalf("CollectSourceFiles() error = %v", err)
+	}
+	got := map[string]bool{}
+	for _, f := range files {
+		rel, _ := filepath.Rel(tmpDir, f)
+		got[filepath.ToSlash(rel)] = true
+	}
+	if !got["vendor/keep/b.go"] || !got["main.go"] || got["vendor/drop/a.go"] {
+		t.Errorf("unexpected files collected: %v", got)
+	}
+}
+
+func TestIgnoreMatcher_NestedGitignoreAppliesToSubtreeOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "api.gen.go"), "package main\n")
+	mustWrite(t, filepath.Join(tmpDir, "main.go"), "package main\n")
+	mustMkdir(t, filepath.Join(tmpDir, "sub"))
+	mustWrite(t, filepath.Join(tmpDir, "sub", ".gitignore"), "*.gen.go\n/local.go\n")
+	mustWrite(t, filepath.Join(tmpDir, "sub", "api.gen.go"), "package sub\n")
+	mustWrite(t, filepath.Join(tmpDir, "sub", "local.go"), "package sub\n")
+	mustWrite(t, filepath.Join(tmpDir, "sub", "keep.go"), "package sub\n")
+	mustMkdir(t, filepath.Join(tmpDir, "sub", "deep"))
+	mustWrite(t, filepath.Join(tmpDir, "sub", "deep", "local.go"), "package deep\n")
+	mustWrite(t, filepath.Join(tmpDir, "sub", "deep", "x.gen.go"), "package deep\n")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	goConfig, err := config.GetLanguageConfig("go")
+	if err != nil {
+		t.Fatalf("GetLanguageConfig(go) error = %v", err)
+	}
+
+	files, err := CollectSourceFiles(tmpDir, goConfig, true)
+	if err != nil {
+		t.Fatalf("CollectSourceFiles() error = %v", err)
+	}
+	got := map[string]bool{}
+	for _, f := range files {
+		rel, _ := filepath.Rel(tmpDir, f)
+		got[filepath.ToSlash(rel)] = true
+	}
+
+	// "*.gen.go" in sub/.gitignore must not reach the root; "/local.go" is
+	// anchored to sub/ and leaves sub/deep/local.go alone
+	want := []string{"api.gen.go", "main.go", "sub/keep.go", "sub/deep/local.go"}
+	if len(got) != len(want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("expected %s to be collected, got %v", w, got)
+		}
+	}
+}
+
+func TestDirProcessor_IgnoreFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "a.go"), "package main\n\nfunc Foo() {}\n")
+	mustMkdir(t, filepath.Join(tmpDir, "vendor"))
+	mustWrite(t, filepath.Join(tmpDir, "vendor", "b.go"), "package vendor\n\nfunc Bar() {}\n")
+	ignoreFile := filepath.Join(t.TempDir(), ".dockerignore")
+	mustWrite(t, ignoreFile, "vendor/\n")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	// gitignore disabled: the extra ignore file still applies
+	dp := NewDirProcessor(config, 1, true, false, "functions")
+	dp.SetIgnoreFiles([]string{ignoreFile})
+
+	results, err := dp.ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+	if len(results) != 1 || filepath.Base(results[0].Path) != "a.go" {
+		t.Errorf("expected only a.go, got %+v", results)
+	}
+
+	dp.SetIgnoreFiles([]string{filepath.Join(tmpDir, "missing.ignore")})
+	if _, err := dp.ProcessDirectory(tmpDir); err == nil {
+		t.Error("expected an error for a missing ignore file")
 	}
 }
 
@@ -160,6 +278,51 @@ func TestProcessDirectory_EndToEnd(t *testing.T) {
 	}
 }
 
+func TestProcessDirectory_MaxDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "root.go"), "package main\n\nfunc Root() {}\n")
+	mustMkdir(t, filepath.Join(tmpDir, "l1", "l2", "l3"))
+	mustWrite(t, filepath.Join(tmpDir, "l1", "one.go"), "package l1\n\nfunc One() {}\n")
+	mustWrite(t, filepath.Join(tmpDir, "l1", "l2", "two.go"), "package l2\n\nfunc Two() {}\n")
+	mustWrite(t, filepath.Join(tmpDir, "l1", "l2", "l3", "three.go"), "package l3\n\nfunc Three() {}\n")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	cases := []struct {
+		depth int
+		want  []string
+	}{
+		{0, []string{"Root"}},
+		{1, []string{"Root", "One"}},
+		{-1, []string{"Root", "One", "Two", "Three"}},
+	}
+	for _, c := range cases {
+		dp := NewDirProcessor(config, 1, true, false, "functions")
+		dp.SetMaxDepth(c.depth)
+		results, err := dp.ProcessDirectory(tmpDir)
+		if err != nil {
+			t.Fatalf("ProcessDirectory() error = %v", err)
+		}
+		names := map[string]bool{}
+		for _, r := range results {
+			for _, fn := range r.Functions {
+				names[fn.Name] = true
+			}
+		}
+		if len(names) != len(c.want) {
+			t.Errorf("max-depth %d: got %v, want %v", c.depth, names, c.want)
+		}
+		for _, w := range c.want {
+			if !names[w] {
+				t.Errorf("max-depth %d: missing %s, got %v", c.depth, w, names)
+			}
+		}
+	}
+}
+
 func TestProcessDirectory_EmptyDir(t *testing.T) {
 	tmpDir := t.TempDir()
 	config, err := LoadConfig()
@@ -240,9 +403,9 @@ func TestAggregateDirResults_TreeFull(t *testing.T) {
 }
 
 func TestFormatDirResultsTree_Empty(t *testing.T) {
-	out := formatDirResultsTree(nil, false)
+	out := FormatDirResultsTree(nil, false, true)
 	if out != "No functions found" {
-		t.Errorf("formatDirResultsTree(nil) = %q, want %q", out, "No functions found")
+		t.Errorf("FormatDirResultsTree(nil) = %q, want %q", out, "No functions found")
 	}
 }
 
@@ -451,3 +614,26 @@ func mustMkdir(t *testing.T, path string) {
 		t.Fatalf("mkdir %s: %v", path, err)
 	}
 }
+
+func TestFormatDirResultsTree_LangLabels(t *testing.T) {
+	results := []DirResult{
+		{Path: filepath.Join("src", "main.go"), LangKey: "go", Functions: []FunctionBounds{{Name: "main", Start: 3}}},
+		{Path: filepath.Join("src", "tool.py"), LangKey: "py", Functions: []FunctionBounds{{Name: "run", Start: 1}}},
+		{Path: filepath.Join("web", "app.ts"), LangKey: "ts", Classes: []ClassBounds{{Name: "App", Start: 2}}},
+	}
+
+	labeled := FormatDirResultsTree(results, false, true)
+	for _, want := range []string{"main.go [go]\n", "tool.py [py]\n", "app.ts [ts]\n"} {
+		if !strings.Contains(labeled, want) {
+			t.Errorf("tree output missing %q:\n%s", want, labeled)
+		}
+	}
+	if strings.Contains(labeled, "src [") || strings.Contains(labeled, "web [") {
+		t.Errorf("directory nodes must not be labeled:\n%s", labeled)
+	}
+
+	plain := FormatDirResultsTree(results, false, false)
+	if strings.Contains(plain, "[go]") || !strings.Contains(plain, "main.go\n") {
+		t.Errorf("--no-lang-label output still labeled:\n%s", plain)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -41,7 +43,7 @@ public enum LogLevel {
 		LangKey:   "swift",
 	}
 
-	result := dp.processFile(job)
+	result := dp.processFile(job, newFinderCache())
 	if result.Error != nil {
 		t.Fatalf("processFile() error = %v", result.Error)
 	}
@@ -109,7 +111,7 @@ impl Handler for Config {
 		LangKey:   "rust",
 	}
 
-	result := dp.processFile(job)
+	result := dp.processFile(job, newFinderCache())
 	if result.Error != nil {
 		t.Fatalf("processFile() error = %v", result.Error)
 	}
@@ -130,3 +132,211 @@ impl Handler for Config {
 		seen[key] = true
 	}
 }
+
+func TestFinderCache_ReusesFindersPerLanguage(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	cache := newFinderCache()
+
+	goFinder := cache.finder(config["go"])
+	if cache.finder(config["go"]) != goFinder {
+		t.Error("expected the same Go finder on second lookup")
+	}
+	if cache.finder(config["py"]) == goFinder {
+		t.Error("expected a separate finder per language")
+	}
+	if cache.structFinder(config["go"]) != cache.structFinder(config["go"]) {
+		t.Error("expected the same Go struct finder on second lookup")
+	}
+}
+
+func TestFinderCache_PythonDecoratorsDoNotLeakAcrossFiles(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	dp := NewDirProcessor(config, 1, false, false, "functions")
+	cache := newFinderCache()
+
+	// First file ends with a dangling decorator line
+	first := dp.processSource("a.py", config["py"], []byte("def a():\n    pass\n\n@cached\n"), cache)
+	if first.Error != nil {
+		t.Fatalf("processSource(a.py) error = %v", first.Error)
+	}
+
+	second := dp.processSource("b.py", config["py"], []byte("def b():\n    pass\n"), cache)
+	if second.Error != nil {
+		t.Fatalf("processSource(b.py) error = %v", second.Error)
+	}
+	if len(second.Functions) != 1 {
+		t.Fatalf("Expected 1 function in b.py, got %d", len(second.Functions))
+	}
+	if fn := second.Functions[0]; len(fn.Decorators) != 0 || fn.Start != 1 {
+		t.Errorf("b: got decorators %v start %d, want none starting at 1", fn.Decorators, fn.Start)
+	}
+}
+
+func TestProcessFiles_MergesInInputOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	a := filepath.Join(tmpDir, "a.go")
+	b := filepath.Join(tmpDir, "b.go")
+	mustWrite(t, a, "package a\n\nfunc Alpha() {\n}\n")
+	mustWrite(t, b, "package b\n\nfunc Beta() {}\n\nfunc Gamma() {}\n")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	dp := NewDirProcessor(config, 2, false, false, "functions")
+
+	results, err := dp.ProcessFiles([]string{b, a}, "go")
+	if err != nil {
+		t.Fatalf("ProcessFiles() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Path != b || results[1].Path != a {
+		t.Fatalf("results = %+v, want b.go then a.go", results)
+	}
+	if len(results[0].Functions) != 2 || len(results[1].Functions) != 1 {
+		t.Errorf("functions per file = %d, %d; want 2, 1", len(results[0].Functions), len(results[1].Functions))
+	}
+
+	want := b + ":3: Beta\n" + b + ":5: Gamma\n" + a + ":3: Alpha\n"
+	if got := AggregateDirResults(results, false, false, false); got != want {
+		t.Errorf("combined output = %q, want %q", got, want)
+	}
+}
+
+func TestProcessFiles_DetectsLanguageByExtension(t *testing.T) {
+	tmpDir := t.TempDir()
+	goFile := filepath.Join(tmpDir, "main.go")
+	pyFile := filepath.Join(tmpDir, "tool.py")
+	mustWrite(t, goFile, "package main\n\nfunc main() {}\n")
+	mustWrite(t, pyFile, "def run():\n    pass\n")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	dp := NewDirProcessor(config, 1, false, false, "functions")
+
+	results, err := dp.ProcessFiles([]string{goFile, pyFile}, "")
+	if err != nil {
+		t.Fatalf("ProcessFiles() error = %v", err)
+	}
+	for i, name := range []string{"main", "run"} {
+		if len(results[i].Functions) != 1 || results[i].Functions[0].Name != name {
+			t.Errorf("%s: functions = %+v, want %s", results[i].Path, results[i].Functions, name)
+		}
+	}
+
+	if _, err := dp.ProcessFiles([]string{filepath.Join(tmpDir, "notes.txt")}, ""); err == nil {
+		t.Error("expected error for an unsupported extension without a language")
+	}
+}
+
+func TestProcessDirectory_NoClasses(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "Greeter.java"), "class Greeter {\n    void greet() {\n    }\n}\n")
+
+	dp := NewDirProcessor(config, 1, false, false, "functions")
+	dp.SetNoClasses(true)
+	results, err := dp.ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+	if len(results) != 1 || len(results[0].Functions) != 1 {
+		t.Fatalf("results = %+v, want one file with greet", results)
+	}
+	if results[0].Classes != nil || results[0].Functions[0].ClassName != "" {
+		t.Errorf("classes reported with --no-classes: %+v", results[0])
+	}
+}
+
+func TestProcessDirectory_ExtMap(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "lib.inc"), "<?php\nfunction helper($a) {\n    return $a;\n}\n")
+	mustWrite(t, filepath.Join(tmpDir, "page.tmpl"), "package x\n\nfunc Render() {\n}\n")
+
+	dp := NewDirProcessor(config, 1, false, false, "functions")
+	if results, err := dp.ProcessDirectory(tmpDir); err != nil || len(results) != 0 {
+		t.Fatalf("without --ext-map: results = %+v, err = %v, want none", results, err)
+	}
+
+	mapping, err := ParseExtMap(".inc=php,.tmpl=go")
+	if err != nil {
+		t.Fatalf("ParseExtMap() error = %v", err)
+	}
+	for ext, lang := range mapping {
+		if err := config.MapExtension(ext, lang); err != nil {
+			t.Fatalf("MapExtension(%s, %s) error = %v", ext, lang, err)
+		}
+	}
+	results, err := dp.ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+	got := make(map[string]string)
+	for _, r := range results {
+		if len(r.Functions) != 1 {
+			t.Errorf("%s: functions = %+v", r.Path, r.Functions)
+			continue
+		}
+		got[filepath.Base(r.Path)] = r.LangKey + ":" + r.Functions[0].Name
+	}
+	if got["lib.inc"] != "php:helper" || got["page.tmpl"] != "go:Render" {
+		t.Errorf("results = %v, want lib.inc as php and page.tmpl as go", got)
+	}
+}
+
+func TestProcessDirectory_TestFiles(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "app.go"), "package app\n\nfunc Run() {\n}\n")
+	mustWrite(t, filepath.Join(tmpDir, "app_test.go"), "package app\n\nfunc TestRun(t *testing.T) {\n}\n")
+	mustWrite(t, filepath.Join(tmpDir, "tool.py"), "def main():\n    pass\n")
+	mustWrite(t, filepath.Join(tmpDir, "test_tool.py"), "def test_main():\n    pass\n")
+	mustWrite(t, filepath.Join(tmpDir, "tool_test.py"), "def test_other():\n    pass\n")
+	mustWrite(t, filepath.Join(tmpDir, "testing.py"), "def helper():\n    pass\n")
+
+	scan := func(mode TestFileMode) []string {
+		dp := NewDirProcessor(config, 2, false, false, "functions")
+		dp.SetTestFiles(mode)
+		results, err := dp.ProcessDirectory(tmpDir)
+		if err != nil {
+			t.Fatalf("ProcessDirectory() error = %v", err)
+		}
+		var names []string
+		for _, r := range results {
+			names = append(names, filepath.Base(r.Path))
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	tests := []struct {
+		mode TestFileMode
+		want string
+	}{
+		{TestFilesAll, "app.go,app_test.go,test_tool.py,testing.py,tool.py,tool_test.py"},
+		{TestFilesExclude, "app.go,testing.py,tool.py"},
+		{TestFilesOnly, "app_test.go,test_tool.py,tool_test.py"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(scan(tt.mode), ","); got != tt.want {
+			t.Errorf("mode %d: files = %s, want %s", tt.mode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// dirstream.go - Streaming directory output (--json-stream)
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// Format selects how ProcessDirectoryStream writes each file's result
+type Format string
+
+const (
+	// FormatNDJSON writes one JSON object per file (the shape of a --json
+	// "files" entry) followed by a summary object with the totals
+	FormatNDJSON Format = "ndjson"
+	// FormatGrep writes "path:line: name" lines, like --dir --map
+	FormatGrep Format = "grep"
+)
+
+// jsonStreamSummary is the trailing NDJSON object of ProcessDirectoryStream
+type jsonStreamSummary struct {
+	TotalFiles     int `json:"total_files"`
+	TotalFunctions int `json:"total_functions"`
+	TotalClasses   int `json:"total_classes"`
+	// Files left out as generated (--ignore-generated)
+	SkippedGenerated int `json:"skipped_generated,omitempty"`
+}
+
+// ProcessDirectoryStream processes rootPath like ProcessDirectory but writes
+// every file's result to out as soon as a worker produces it instead of
+// collecting a []DirResult, so memory stays flat however large the tree is.
+// Files come out in completion order, not path order. Parse ambiguities are
+// printed as warnings; files that failed to parse are skipped, as in
+// AggregateDirResults.
+func (dp *DirProcessor) ProcessDirectoryStream(rootPath string, out io.Writer, format Format) error {
+	if format != FormatNDJSON && format != FormatGrep {
+		return fmt.Errorf("unsupported stream format: %q", format)
+	}
+
+	jobs, err := dp.collectFiles(rootPath)
+	if err != nil {
+		return err
+	}
+
+	jobsChan := make(chan Job, dp.workers*2)
+	resultsChan := make(chan DirResult, dp.workers*2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < dp.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dp.worker(jobsChan, resultsChan)
+		}()
+	}
+	go func() {
+		for _, job := range jobs {
+			jobsChan <- job
+		}
+		close(jobsChan)
+	}()
+	go func() {
+		wg.Wait()
+		close(resultsChan)
+	}()
+
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	var summary jsonStreamSummary
+	var writeErr error
+
+	// Keep draining after a write error so the workers can finish
+	for r := range resultsChan {
+		for _, a := range r.Ambiguities {
+			WarnError("%s:%d: %s", r.Path, a.Line, a.Message)
+		}
+		if r.Generated {
+			summary.SkippedGenerated++
+		}
+		if writeErr != nil || r.Error != nil || (len(r.Functions) == 0 && len(r.Classes) == 0) {
+			continue
+		}
+		summary.TotalFiles++
+		summary.TotalFunctions += len(r.Functions)
+		summary.TotalClasses += len(r.Classes)
+
+		if format == FormatNDJSON {
+			writeErr = enc.Encode(toJSONFile(r))
+		} else {
+			writeErr = writeGrepResult(w, r)
+		}
+		if writeErr == nil {
+			// Flush per file: consumers read the stream while it is produced
+			writeErr = w.Flush()
+		}
+	}
+	if writeErr != nil {
+		return fmt.Errorf("writing results: %w", writeErr)
+	}
+
+	if format == FormatNDJSON {
+		if err := enc.Encode(summary); err != nil {
+			return fmt.Errorf("writing results: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("writing results: %w", err)
+	}
+	return nil
+}
+
+// writeGrepResult writes one file's symbols in formatDirResultsGrep format
+func writeGrepResult(w *bufio.Writer, r DirResult) error {
+	for _, fn := range r.Functions {
+		if _, err := w.WriteString(r.Path + ":" + strconv.Itoa(fn.Start) + ": " + fn.Name + "\n"); err != nil {
+			return err
+		}
+	}
+	for _, cl := range r.Classes {
+		if _, err := w.WriteString(r.Path + ":" + strconv.Itoa(cl.Start) + ": " + cl.Name + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
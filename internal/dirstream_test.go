@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func streamDir(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "a.go"), "package main\n\nfunc Foo() {}\n\nfunc Baz() {}\n")
+	mustWrite(t, filepath.Join(tmpDir, "b.go"), "package main\n\nfunc Bar() {}\n")
+	mustWrite(t, filepath.Join(tmpDir, "empty.go"), "package main\n")
+	return tmpDir
+}
+
+func TestProcessDirectoryStream_NDJSON(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	dp := NewDirProcessor(config, 2, true, false, "functions")
+
+	var out bytes.Buffer
+	if err := dp.ProcessDirectoryStream(streamDir(t), &out, FormatNDJSON); err != nil {
+		t.Fatalf("ProcessDirectoryStream() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 2 files + summary:\n%s", len(lines), out.String())
+	}
+
+	var files []string
+	for _, line := range lines[:2] {
+		var jf jsonFile
+		if err := json.Unmarshal([]byte(line), &jf); err != nil {
+			t.Fatalf("invalid file object %q: %v", line, err)
+		}
+		files = append(files, fmt.Sprintf("%s:%d", filepath.Base(jf.Path), len(jf.Functions)))
+	}
+	sort.Strings(files)
+	if want := []string{"a.go:2", "b.go:1"}; strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+
+	var summary jsonStreamSummary
+	if err := json.Unmarshal([]byte(lines[2]), &summary); err != nil {
+		t.Fatalf("invalid summary %q: %v", lines[2], err)
+	}
+	if summary != (jsonStreamSummary{TotalFiles: 2, TotalFunctions: 3}) {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+func TestProcessDirectoryStream_Grep(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	dp := NewDirProcessor(config, 1, true, false, "functions")
+
+	var out bytes.Buffer
+	if err := dp.ProcessDirectoryStream(streamDir(t), &out, FormatGrep); err != nil {
+		t.Fatalf("ProcessDirectoryStream() error = %v", err)
+	}
+	if got := strings.Count(out.String(), "\n"); got != 3 {
+		t.Errorf("got %d lines, want 3:\n%s", got, out.String())
+	}
+	if !strings.Contains(out.String(), "b.go:3: Bar\n") {
+		t.Errorf("missing grep line for Bar:\n%s", out.String())
+	}
+}
+
+func TestProcessDirectoryStream_UnsupportedFormat(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	dp := NewDirProcessor(config, 1, true, false, "functions")
+	if err := dp.ProcessDirectoryStream(t.TempDir(), io.Discard, Format("xml")); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+// heapSampler records the peak live heap, sampled after a GC every few writes
+type heapSampler struct {
+	writes int
+	peak   uint64
+}
+
+func (s *heapSampler) Write(p []byte) (int, error) {
+	s.writes++
+	if s.writes%10 == 0 {
+		if live := liveHeap(); live > s.peak {
+			s.peak = live
+		}
+	}
+	return len(p), nil
+}
+
+func liveHeap() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+func TestProcessDirectoryStream_BoundedMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("writes a few hundred files")
+	}
+	tmpDir := t.TempDir()
+	var src strings.Builder
+	src.WriteString("package big\n\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&src, "func F%d() {\n\treturn\n}\n\n", i)
+	}
+	for i := 0; i < 400; i++ {
+		mustWrite(t, filepath.Join(tmpDir, fmt.Sprintf("f%03d.go", i)), src.String())
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	dp := NewDirProcessor(config, 2, true, false, "functions")
+
+	// What ProcessDirectory keeps alive for this tree
+	base := liveHeap()
+	results, err := dp.ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+	retained := liveHeap() - base
+	runtime.KeepAlive(results)
+
+	base = liveHeap()
+	sampler := &heapSampler{}
+	if err := dp.ProcessDirectoryStream(tmpDir, sampler, FormatNDJSON); err != nil {
+		t.Fatalf("ProcessDirectoryStream() error = %v", err)
+	}
+	var growth uint64
+	if sampler.peak > base {
+		growth = sampler.peak - base
+	}
+	t.Logf("retained %d bytes, streaming peak growth %d bytes", retained, growth)
+	if growth > retained/4 {
+		t.Errorf("streaming peak heap growth %d bytes, want well below the %d bytes ProcessDirectory retains", growth, retained)
+	}
+}
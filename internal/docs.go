@@ -0,0 +1,189 @@
+// docs.go - Documentation comments attached to functions (--with-docs)
+package internal
+
+import "strings"
+
+// AttachDocs fills FunctionBounds.Doc for every function. lines are the
+// whole file and Start/End are 1-based line numbers into it.
+//
+// Brace languages take the contiguous comment block right above the
+// function (Go "//" lines, Java/C# "/** ... */", Rust "///"); annotations or
+// attributes between the comment and the signature are skipped the same way
+// DecoratorWindow skips them, and a blank line ends the block. When the
+// language sets doc_comment_line or doc_comment_block, only comments with
+// those markers are documentation: ordinary "//" or "/* */" comments in the
+// block are left out.
+// Indent-based languages (Python) take the docstring: the first string
+// literal of the body.
+func AttachDocs(functions []FunctionBounds, lines []string, config *LanguageConfig) {
+	for i := range functions {
+		fn := &functions[i]
+		if config.IndentBased {
+			fn.Doc = docstring(lines, fn.Start, config)
+		} else {
+			fn.Doc = leadingCommentDoc(lines, fn.Start, config)
+		}
+	}
+}
+
+// leadingCommentDoc collects the comment block ending right above line start
+func leadingCommentDoc(lines []string, start int, config *LanguageConfig) string {
+	i := start - 2
+	decoratorRe := config.DecoratorRegex()
+	for decoratorRe != nil && i >= 0 && decoratorRe.MatchString(lines[i]) {
+		i--
+	}
+
+	var doc []string
+	for i >= 0 {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case trimmed == "":
+			return strings.Join(doc, "\n")
+
+		case config.LineComment != "" && strings.HasPrefix(trimmed, config.LineComment):
+			if marker, ok := docLineMarker(trimmed, config); ok {
+				doc = append([]string{stripLineComment(trimmed, marker)}, doc...)
+			}
+			i--
+
+		case config.BlockCommentEnd != "" && strings.HasSuffix(trimmed, config.BlockCommentEnd):
+			// Walk up to the line that opens the block
+			end := i
+			for i >= 0 && !strings.Contains(lines[i], config.BlockCommentStart) {
+				i--
+			}
+			// The block must stand alone, not trail code ("x++; /* ... */")
+			if i < 0 || !strings.HasPrefix(strings.TrimSpace(lines[i]), config.BlockCommentStart) {
+				return strings.Join(doc, "\n")
+			}
+			if config.DocCommentBlock == "" || strings.HasPrefix(strings.TrimSpace(lines[i]), config.DocCommentBlock) {
+				doc = append(blockCommentText(lines[i:end+1], config), doc...)
+			}
+			i--
+
+		default:
+			return strings.Join(doc, "\n")
+		}
+	}
+	return strings.Join(doc, "\n")
+}
+
+// docLineMarker returns the doc comment marker a comment line starts with.
+// Without doc_comment_line every line comment is a doc line.
+func docLineMarker(trimmed string, config *LanguageConfig) (string, bool) {
+	if len(config.DocCommentLine) == 0 {
+		return config.LineComment, true
+	}
+	for _, marker := range config.DocCommentLine {
+		if strings.HasPrefix(trimmed, marker) {
+			return marker, true
+		}
+	}
+	return "", false
+}
+
+// stripLineComment removes the comment marker and doc-comment sugar
+// ("///", "//!", "#:", "-- |")
+func stripLineComment(line, marker string) string {
+	text := strings.TrimPrefix(line, marker)
+	text = strings.TrimLeft(text, "/!:|")
+	return strings.TrimSpace(text)
+}
+
+// blockCommentText strips /** ... */ markers and leading "*" of each line
+func blockCommentText(block []string, config *LanguageConfig) []string {
+	var text []string
+	for i, line := range block {
+		line = strings.TrimSpace(line)
+		if i == 0 {
+			line = strings.TrimPrefix(line, config.BlockCommentStart)
+			line = strings.TrimLeft(line, "*!")
+		}
+		if i == len(block)-1 {
+			line = strings.TrimSuffix(line, config.BlockCommentEnd)
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if line == "" && (i == 0 || i == len(block)-1) {
+			continue
+		}
+		text = append(text, line)
+	}
+	return text
+}
+
+// docstring returns the first string literal of a Python-style body
+func docstring(lines []string, start int, config *LanguageConfig) string {
+	doc, _ := docstringAt(lines, start, config)
+	return doc
+}
+
+// docstringAt is docstring together with the 1-based line the docstring
+// starts on (0 without one). A body on the header line ("def f(): return
+// 1") has no docstring.
+func docstringAt(lines []string, start int, config *LanguageConfig) (string, int) {
+	i := docHeaderEnd(lines, start, config)
+	if i < 0 {
+		return "", 0
+	}
+	i++
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	if i >= len(lines) {
+		return "", 0
+	}
+	return docstringLiteral(lines, i, config), i + 1
+}
+
+// docHeaderEnd returns the 0-based index of the line ending the header
+// that starts at line start (decorators, then the signature as headerEnd
+// finds it), or -1 when that line doesn't end with ':': the body is on the
+// header line or there is no body
+func docHeaderEnd(lines []string, start int, config *LanguageConfig) int {
+	i := start - 1
+	for i >= 0 && i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "@") {
+		i++
+	}
+	if i < 0 || i >= len(lines) {
+		return -1
+	}
+	cleaned := NewSanitizer(config, false).CleanLines(lines[i:min(i+MaxSignatureLines, len(lines))])
+	end := headerEnd(cleaned, 0)
+	if !strings.HasSuffix(strings.TrimSpace(cleaned[end]), ":") {
+		return -1
+	}
+	return i + end
+}
+
+// docstringLiteral returns the string literal starting line i, or ""
+func docstringLiteral(lines []string, i int, config *LanguageConfig) string {
+
+	first := strings.TrimLeft(strings.TrimSpace(lines[i]), "rRuUbBfF")
+	markers := append(append([]string{}, config.DocStringMarkers...), config.StringChars...)
+	for _, marker := range markers {
+		if !strings.HasPrefix(first, marker) {
+			continue
+		}
+		rest := first[len(marker):]
+		if idx := strings.Index(rest, marker); idx >= 0 {
+			return strings.TrimSpace(rest[:idx])
+		}
+		if len(marker) == 1 {
+			return "" // unterminated one-line string
+		}
+
+		// Multi-line docstring up to the closing marker
+		doc := []string{strings.TrimSpace(rest)}
+		for j := i + 1; j < len(lines); j++ {
+			line := strings.TrimSpace(lines[j])
+			if idx := strings.Index(line, marker); idx >= 0 {
+				doc = append(doc, strings.TrimSpace(line[:idx]))
+				return strings.TrimSpace(strings.Join(doc, "\n"))
+			}
+			doc = append(doc, line)
+		}
+		return ""
+	}
+	return ""
+}
@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func docsByName(t *testing.T, config *LanguageConfig, code string) map[string]string {
+	t.Helper()
+	lines := strings.Split(code, "\n")
+	finder := CreateFinder(config, "", "map", false, false)
+	result, err := finder.FindFunctionsInReader(strings.NewReader(code), "sample")
+	if err != nil {
+		t.Fatalf("FindFunctionsInReader failed: %v", err)
+	}
+	AttachDocs(result.Functions, lines, config)
+	docs := make(map[string]string)
+	for _, fn := range result.Functions {
+		docs[fn.Name] = fn.Doc
+	}
+	return docs
+}
+
+func TestAttachDocs_Go(t *testing.T) {
+	code := `package demo
+
+// Detached comment: the blank line below ends it.
+
+// Parse reads the input.
+// It returns an error on EOF.
+func Parse() error { return nil }
+
+x := 1 /* trailing */
+func Undocumented() {}
+
+/* Block comments work too. */
+func Block() {}
+`
+	docs := docsByName(t, getGoConfig(t), code)
+	if want := "Parse reads the input.\nIt returns an error on EOF."; docs["Parse"] != want {
+		t.Errorf("Parse doc = %q, want %q", docs["Parse"], want)
+	}
+	if docs["Undocumented"] != "" {
+		t.Errorf("Undocumented doc = %q, want empty", docs["Undocumented"])
+	}
+	if docs["Block"] != "Block comments work too." {
+		t.Errorf("Block doc = %q", docs["Block"])
+	}
+}
+
+func TestAttachDocs_JavaJavadocAboveAnnotation(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	code := `public class Service {
+    /**
+     * Starts the service.
+     * @param port listen port
+     */
+    @Override
+    public void start(int port) {
+    }
+}
+`
+	docs := docsByName(t, config["java"], code)
+	if want := "Starts the service.\n@param port listen port"; docs["start"] != want {
+		t.Errorf("start doc = %q, want %q", docs["start"], want)
+	}
+}
+
+func TestAttachDocs_RustDocCommentsOnly(t *testing.T) {
+	code := `/// Parses the input.
+/// Returns None on EOF.
+pub fn parse() -> Option<u8> { None }
+
+// helper, not documentation
+fn helper() {}
+
+/// Loads the file.
+// TODO: cache
+fn load() {}
+
+//! Module docs use the inner marker.
+fn inner() {}
+`
+	docs := docsByName(t, getRustConfig(t), code)
+	want := map[string]string{
+		"parse":  "Parses the input.\nReturns None on EOF.",
+		"helper": "",
+		"load":   "Loads the file.",
+		"inner":  "Module docs use the inner marker.",
+	}
+	for name, w := range want {
+		if docs[name] != w {
+			t.Errorf("%s doc = %q, want %q", name, docs[name], w)
+		}
+	}
+}
+
+func TestAttachDocs_JavaIgnoresPlainBlockComment(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	code := `public class Service {
+    /* implementation note */
+    public void stop() {
+    }
+
+    // line comments stay documentation in Java
+    public void restart() {
+    }
+}
+`
+	docs := docsByName(t, config["java"], code)
+	if docs["stop"] != "" {
+		t.Errorf("stop doc = %q, want empty for a /* */ comment", docs["stop"])
+	}
+	if want := "line comments stay documentation in Java"; docs["restart"] != want {
+		t.Errorf("restart doc = %q, want %q", docs["restart"], want)
+	}
+}
+
+func TestAttachDocs_PythonDocstring(t *testing.T) {
+	code := `def one():
+    """Single line."""
+    return 1
+
+@cached
+def multi(a,
+          b):
+    """
+    Adds numbers.
+
+    Returns the sum.
+    """
+    return a + b
+
+def none():
+    # just a comment
+    return None
+`
+	docs := docsByName(t, getPyConfig(t), code)
+	if docs["one"] != "Single line." {
+		t.Errorf("one doc = %q", docs["one"])
+	}
+	if want := "Adds numbers.\n\nReturns the sum."; docs["multi"] != want {
+		t.Errorf("multi doc = %q, want %q", docs["multi"], want)
+	}
+	if docs["none"] != "" {
+		t.Errorf("none doc = %q, want empty", docs["none"])
+	}
+}
+
+func TestAttachDocs_PythonOneLineDefHasNoDocstring(t *testing.T) {
+	code := `def a(): return 1
+
+def b():
+    """B's doc."""
+    return 2
+
+def c() -> dict[str, int]:  # annotated
+    """C's doc."""
+    return {}
+`
+	docs := docsByName(t, getPyConfig(t), code)
+	if docs["a"] != "" {
+		t.Errorf("a doc = %q, want empty: the body is on the header line", docs["a"])
+	}
+	if docs["b"] != "B's doc." {
+		t.Errorf("b doc = %q, want %q", docs["b"], "B's doc.")
+	}
+	if docs["c"] != "C's doc." {
+		t.Errorf("c doc = %q, want %q", docs["c"], "C's doc.")
+	}
+}
+
+func TestFormatJSON_Doc(t *testing.T) {
+	out, err := FormatJSON(&FindResult{Functions: []FunctionBounds{{Name: "Parse", Start: 1, End: 2, Doc: "Parse reads."}}})
+	if err != nil {
+		t.Fatalf("FormatJSON failed: %v", err)
+	}
+	if !strings.Contains(out, `"doc": "Parse reads."`) {
+		t.Errorf("expected doc in JSON, got:\n%s", out)
+	}
+}
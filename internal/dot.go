@@ -0,0 +1,119 @@
+// dot.go - GraphViz DOT output for class/method and type hierarchies (--dot)
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
+
+// dotEscape escapes s for use inside a double-quoted DOT string
+func dotEscape(s string) string {
+	return dotEscaper.Replace(s)
+}
+
+// dotWriter accumulates DOT statements and hands out unique node IDs
+type dotWriter struct {
+	sb   strings.Builder
+	next int
+}
+
+func (w *dotWriter) node(label, shape, indent string) string {
+	id := "n" + strconv.Itoa(w.next)
+	w.next++
+	fmt.Fprintf(&w.sb, "%s%s [label=\"%s\", shape=%s];\n", indent, id, dotEscape(label), shape)
+	return id
+}
+
+func (w *dotWriter) edge(from, to, indent string) {
+	fmt.Fprintf(&w.sb, "%s%s -> %s;\n", indent, from, to)
+}
+
+// writeTree emits a node per tree node and a parent -> child edge for each
+// method (or nested function)
+func (w *dotWriter) writeTree(nodes []*TreeNode, parentID, indent string) {
+	for _, n := range nodes {
+		shape := "ellipse"
+		if n.Type == NodeTypeClass {
+			shape = "box"
+		}
+		id := w.node(fmt.Sprintf("%s (%d-%d)", n.Name, n.Start, n.End), shape, indent)
+		if parentID != "" {
+			w.edge(parentID, id, indent)
+		}
+		w.writeTree(n.Children, id, indent)
+	}
+}
+
+// FormatDot renders classes and their methods as a GraphViz digraph:
+// class nodes (box) with an edge to each method node (ellipse).
+// The output can be fed to `dot -Tpng`.
+func FormatDot(result *FindResult) string {
+	w := &dotWriter{}
+	w.sb.WriteString("digraph funcfinder {\n")
+	w.sb.WriteString("  rankdir=LR;\n")
+	w.writeTree(BuildTree(result), "", "  ")
+	w.sb.WriteString("}")
+	return w.sb.String()
+}
+
+// FormatStructDot renders types as a GraphViz digraph. Inner types get an
+// edge from their enclosing type (ParentType/ParentLine).
+func FormatStructDot(result *StructFindResult) string {
+	w := &dotWriter{}
+	w.sb.WriteString("digraph funcfinder {\n")
+	w.sb.WriteString("  rankdir=LR;\n")
+
+	ids := make(map[string]string, len(result.Types))
+	byName := make(map[string]string, len(result.Types))
+	for _, t := range result.Types {
+		id := w.node(fmt.Sprintf("%s [%s] (%d-%d)", t.Name, t.Kind, t.Start, t.End), "box", "  ")
+		ids[t.Name+":"+strconv.Itoa(t.Start)] = id
+		if _, ok := byName[t.Name]; !ok {
+			byName[t.Name] = id
+		}
+	}
+
+	for _, t := range result.Types {
+		if t.ParentType == "" {
+			continue
+		}
+		parentID, ok := ids[t.ParentType+":"+strconv.Itoa(t.ParentLine)]
+		if !ok {
+			parentID, ok = byName[t.ParentType]
+		}
+		if ok {
+			w.edge(parentID, ids[t.Name+":"+strconv.Itoa(t.Start)], "  ")
+		}
+	}
+
+	w.sb.WriteString("}")
+	return w.sb.String()
+}
+
+// FormatDirResultsDot renders a directory scan as one digraph with a
+// cluster per file
+func FormatDirResultsDot(results []DirResult) string {
+	sorted := make([]DirResult, 0, len(results))
+	for _, r := range results {
+		if len(r.Functions) > 0 || len(r.Classes) > 0 {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	w := &dotWriter{}
+	w.sb.WriteString("digraph funcfinder {\n")
+	w.sb.WriteString("  rankdir=LR;\n")
+	for i, r := range sorted {
+		fmt.Fprintf(&w.sb, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&w.sb, "    label=\"%s\";\n", dotEscape(r.Path))
+		w.writeTree(BuildTree(&FindResult{Functions: r.Functions, Classes: r.Classes, Filename: r.Path}), "", "    ")
+		w.sb.WriteString("  }\n")
+	}
+	w.sb.WriteString("}")
+	return w.sb.String()
+}
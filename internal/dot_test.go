@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatDot_ClassesAndMethods(t *testing.T) {
+	result := &FindResult{
+		Filename: "shapes.java",
+		Classes: []ClassBounds{
+			{Name: "Circle", Start: 1, End: 10},
+			{Name: "Square", Start: 12, End: 20},
+		},
+		Functions: []FunctionBounds{
+			{Name: "area", Start: 2, End: 4, ClassName: "Circle"},
+			{Name: "perimeter", Start: 5, End: 9, ClassName: "Circle"},
+			{Name: "area", Start: 13, End: 15, ClassName: "Square"},
+			{Name: "main", Start: 22, End: 30},
+		},
+	}
+
+	out := FormatDot(result)
+
+	if !strings.HasPrefix(out, "digraph funcfinder {") || !strings.HasSuffix(out, "}") {
+		t.Fatalf("not a digraph:\n%s", out)
+	}
+	if got := strings.Count(out, "shape=box"); got != 2 {
+		t.Errorf("got %d class nodes, want 2:\n%s", got, out)
+	}
+	if got := strings.Count(out, "shape=ellipse"); got != 4 {
+		t.Errorf("got %d function nodes, want 4:\n%s", got, out)
+	}
+	// One edge per method; the top-level main has none
+	if got := strings.Count(out, " -> "); got != 3 {
+		t.Errorf("got %d edges, want 3:\n%s", got, out)
+	}
+	if !strings.Contains(out, `label="Circle (1-10)"`) {
+		t.Errorf("missing Circle node label:\n%s", out)
+	}
+}
+
+func TestFormatDot_EscapesLabels(t *testing.T) {
+	result := &FindResult{
+		Functions: []FunctionBounds{{Name: `say"hi"\`, Start: 1, End: 2}},
+	}
+	out := FormatDot(result)
+	if !strings.Contains(out, `label="say\"hi\"\\ (1-2)"`) {
+		t.Errorf("label not escaped:\n%s", out)
+	}
+}
+
+func TestFormatStructDot_InnerTypeEdges(t *testing.T) {
+	result := &StructFindResult{
+		Types: []TypeBounds{
+			{Name: "Outer", Kind: "class", Start: 1, End: 20},
+			{Name: "Inner", Kind: "class", Start: 3, End: 8, ParentType: "Outer", ParentLine: 1},
+			{Name: "Other", Kind: "struct", Start: 22, End: 25},
+		},
+	}
+	out := FormatStructDot(result)
+	if got := strings.Count(out, "shape=box"); got != 3 {
+		t.Errorf("got %d type nodes, want 3:\n%s", got, out)
+	}
+	if !strings.Contains(out, "n0 -> n1;") || strings.Count(out, " -> ") != 1 {
+		t.Errorf("want single edge Outer -> Inner:\n%s", out)
+	}
+}
+
+func TestFormatDirResultsDot_ClusterPerFile(t *testing.T) {
+	results := []DirResult{
+		{Path: "b.go", Functions: []FunctionBounds{{Name: "B", Start: 1, End: 2}}},
+		{Path: "a.go", Functions: []FunctionBounds{{Name: "A", Start: 1, End: 2}}},
+		{Path: "empty.go"},
+	}
+	out := FormatDirResultsDot(results)
+	if got := strings.Count(out, "subgraph cluster_"); got != 2 {
+		t.Errorf("got %d clusters, want 2:\n%s", got, out)
+	}
+	if strings.Index(out, `label="a.go"`) > strings.Index(out, `label="b.go"`) {
+		t.Errorf("clusters not sorted by path:\n%s", out)
+	}
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func getElixirConfig(t *testing.T) *LanguageConfig {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	return config["ex"]
+}
+
+const elixirSample = `defmodule MyApp.Greeter do
+  @moduledoc """
+  Greets people. The words do and end are text here.
+  """
+
+  def hello(name) do
+    if name == "" do
+      "nobody"
+    else
+      "Hello, #{name} end"
+    end
+  end
+
+  defp shout(s), do: String.upcase(s)
+
+  def words(text) do
+    pattern = ~r/\bend\b/i
+    Enum.map(String.split(text), fn w -> {w, pattern, ~w(do end)} end)
+  end
+end
+`
+
+func TestKeywordFinder_ElixirModule(t *testing.T) {
+	finder := CreateFinder(getElixirConfig(t), "", "map", false, false)
+	result, err := finder.FindFunctionsInReader(strings.NewReader(elixirSample), "greeter.ex")
+	if err != nil {
+		t.Fatalf("FindFunctionsInReader failed: %v", err)
+	}
+
+	want := []struct {
+		name       string
+		start, end int
+	}{
+		{"hello", 6, 12},
+		{"shout", 14, 14},
+		{"words", 16, 19},
+	}
+	if len(result.Functions) != len(want) {
+		t.Fatalf("got %d functions, want %d: %+v", len(result.Functions), len(want), result.Functions)
+	}
+	for i, w := range want {
+		fn := result.Functions[i]
+		if fn.Name != w.name || fn.Start != w.start || fn.End != w.end {
+			t.Errorf("function %d = %s %d-%d, want %s %d-%d", i, fn.Name, fn.Start, fn.End, w.name, w.start, w.end)
+		}
+		if fn.ClassName != "MyApp.Greeter" {
+			t.Errorf("%s: ClassName = %q, want MyApp.Greeter", fn.Name, fn.ClassName)
+		}
+	}
+
+	if len(result.Classes) != 1 || result.Classes[0].Start != 1 || result.Classes[0].End != 20 {
+		t.Errorf("classes = %+v, want MyApp.Greeter 1-20", result.Classes)
+	}
+	if len(result.Ambiguities) != 0 {
+		t.Errorf("unexpected ambiguities: %+v", result.Ambiguities)
+	}
+}
+
+func TestKeywordFinder_ElixirOneLineDefs(t *testing.T) {
+	code := `def one(x), do: x + 1
+def head(a, b \\ nil)
+def two(x),
+  do: x * 2
+def three do
+  :ok
+end
+`
+	finder := NewKeywordFinder(getElixirConfig(t), []string{"two", "three"}, false, true)
+	result, err := finder.FindFunctionsInLines(strings.Split(code, "\n"), 1, "defs.ex")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	if len(result.Functions) != 2 {
+		t.Fatalf("got %+v, want two and three", result.Functions)
+	}
+	if fn := result.Functions[0]; fn.Name != "two" || fn.Start != 3 || fn.End != 4 || len(fn.Lines) != 2 {
+		t.Errorf("two = %d-%d (%d lines), want 3-4", fn.Start, fn.End, len(fn.Lines))
+	}
+	if fn := result.Functions[1]; fn.Name != "three" || fn.Start != 5 || fn.End != 7 {
+		t.Errorf("three = %d-%d, want 5-7", fn.Start, fn.End)
+	}
+}
+
+func TestSanitizer_ElixirSigilsAndHeredocs(t *testing.T) {
+	s := NewSanitizer(getElixirConfig(t), false)
+	lines := []string{
+		`x = ~s(do "end") <> ~S|fn| <> ~r/end/iu`,
+		`doc = ~S"""`,
+		`do end`,
+		`"""`,
+		`y = ~w[a b]a`,
+	}
+	keyword := regexp.MustCompile(`\b(?:do|end|fn)\b`)
+	cleaned := s.CleanLines(lines)
+	for i, line := range cleaned {
+		if keyword.MatchString(line) {
+			t.Errorf("line %d: keyword survived in %q", i+1, line)
+		}
+	}
+	if !strings.Contains(cleaned[0], "x =") || !strings.Contains(cleaned[4], "y =") {
+		t.Errorf("code outside sigils was lost: %q", cleaned)
+	}
+}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"strings"
+	"unicode"
 )
 
 type ParserState int
@@ -77,6 +78,14 @@ func (s ParserState) String() string {
 type Sanitizer struct {
 	config *LanguageConfig
 	useRaw bool
+
+	// countStrings turns on literal counting (see CountLiterals):
+	// stringCount is incremented for every string literal CleanLine opens
+	countStrings bool
+	stringCount  int
+	// keepStrings blanks comments only: string literals are copied to the
+	// result as written (see normalizedBody)
+	keepStrings bool
 }
 
 func NewSanitizer(config *LanguageConfig, useRaw bool) *Sanitizer {
@@ -211,8 +220,7 @@ func (s *Sanitizer) matchesDocStringStart(runes []rune, pos int) bool {
 }
 
 // State handlers
-func (s *Sanitizer) handleBlockComment(line string, runes []rune, result []rune, idx int) (int, ParserState) {
-	_ = line // retained for signature symmetry; scanning is rune-based
+func (s *Sanitizer) handleBlockComment(runes []rune, result []rune, idx int) (int, ParserState) {
 	endLen := runeLen(s.config.BlockCommentEnd)
 	if pos := indexRunesFrom(runes, idx, s.config.BlockCommentEnd); pos >= 0 {
 		// Found closing - blank from idx through the end delimiter (rune-based).
@@ -225,6 +233,12 @@ func (s *Sanitizer) handleBlockComment(line string, runes []rune, result []rune,
 }
 
 func (s *Sanitizer) handleString(runes []rune, result []rune, idx int) (int, ParserState) {
+	if s.config.Interpolation != "" && s.matchesAt(runes, idx, s.config.Interpolation) {
+		if end := s.interpolationEnd(runes, idx+runeLen(s.config.Interpolation)); end >= 0 {
+			// "${map['k']}" - quotes inside the expression must not close the string
+			return end + 1, StateString
+		}
+	}
 	if s.config.EscapeChar != "" && runes[idx] == firstRune(s.config.EscapeChar) && idx+1 < len(runes) {
 		replaceCharWithSpace(result, idx)
 		replaceCharWithSpace(result, idx+1)
@@ -236,6 +250,32 @@ func (s *Sanitizer) handleString(runes []rune, result []rune, idx int) (int, Par
 	return idx + 1, StateString
 }
 
+// interpolationEnd returns the index of the '}' closing an interpolated
+// expression that starts at pos, or -1 if it does not close on this line.
+// Braces are balanced and nested string literals are skipped whole.
+func (s *Sanitizer) interpolationEnd(runes []rune, pos int) int {
+	depth := 1
+	for p := pos; p < len(runes); p++ {
+		switch {
+		case runes[p] == '{':
+			depth++
+		case runes[p] == '}':
+			depth--
+			if depth == 0 {
+				return p
+			}
+		case s.matchesStringDelimiter(runes, p):
+			quote := runes[p]
+			for p++; p < len(runes) && runes[p] != quote; p++ {
+				if s.config.EscapeChar != "" && runes[p] == firstRune(s.config.EscapeChar) {
+					p++
+				}
+			}
+		}
+	}
+	return -1
+}
+
 func (s *Sanitizer) handleRawString(runes []rune, result []rune, idx int) (int, ParserState) {
 	if s.matchesRawStringDelimiter(runes, idx) {
 		return idx + 1, StateNormal
@@ -259,19 +299,22 @@ func (s *Sanitizer) handleCharLiteral(runes []rune, result []rune, idx int) (int
 	return idx + 1, StateCharLiteral
 }
 
-func (s *Sanitizer) handleMultiLineString(line string, runes []rune, result []rune, idx int) (int, ParserState) {
-	_ = line // retained for signature symmetry; scanning is rune-based
+func (s *Sanitizer) handleMultiLineString(runes []rune, result []rune, idx int) (int, ParserState) {
 	foundEnd := -1 // absolute rune index of the closing delimiter
 	foundDelim := ""
 	newState := StateMultiLineString
 
 	// Special case: C# verbatim strings end with " not @"
-	// Check if we started with @" by looking for " as closing
-	if pos := indexRunesFrom(runes, idx, `"`); pos >= 0 {
-		// Check if it's unescaped (not "")
-		if pos+1 >= len(runes) || runes[pos+1] != '"' {
-			foundEnd = pos
-			foundDelim = `"`
+	// Check if we started with @" by looking for " as closing.
+	// Only when @" is a marker: inside a triple-quoted string (Python,
+	// Dart) a lone " is ordinary content.
+	if s.hasVerbatimMarker() {
+		if pos := indexRunesFrom(runes, idx, `"`); pos >= 0 {
+			// Check if it's unescaped (not "")
+			if pos+1 >= len(runes) || runes[pos+1] != '"' {
+				foundEnd = pos
+				foundDelim = `"`
+			}
 		}
 	}
 
@@ -298,6 +341,16 @@ func (s *Sanitizer) handleMultiLineString(line string, runes []rune, result []ru
 	return len(runes), newState
 }
 
+// hasVerbatimMarker reports whether @" (C# verbatim string) is a doc string marker
+func (s *Sanitizer) hasVerbatimMarker() bool {
+	for _, marker := range s.config.DocStringMarkers {
+		if marker == `@"` {
+			return true
+		}
+	}
+	return false
+}
+
 // StateNormal helper functions - return (newIdx, newState, handled)
 func (s *Sanitizer) tryHandleCharDelimiter(runes []rune, result []rune, idx int) (int, ParserState, bool) {
 	if len(s.config.CharDelimiters) == 0 {
@@ -305,6 +358,9 @@ func (s *Sanitizer) tryHandleCharDelimiter(runes []rune, result []rune, idx int)
 	}
 	for _, char := range s.config.CharDelimiters {
 		if char != "" && s.matchesAt(runes, idx, char) {
+			if s.config.LifetimeSyntax && s.isLifetimeAt(runes, idx) {
+				return idx, StateNormal, false
+			}
 			replaceCharWithSpace(result, idx)
 			return idx + runeLen(char), StateCharLiteral, true
 		}
@@ -312,6 +368,20 @@ func (s *Sanitizer) tryHandleCharDelimiter(runes []rune, result []rune, idx int)
 	return idx, StateNormal, false
 }
 
+// isLifetimeAt reports whether the quote at idx starts a Rust lifetime or
+// loop label ('a, 'static, 'outer:) rather than a char literal: an
+// identifier follows it that is not closed by another quote ('a' is a char)
+func (s *Sanitizer) isLifetimeAt(runes []rune, idx int) bool {
+	p := idx + 1
+	if p >= len(runes) || !(unicode.IsLetter(runes[p]) || runes[p] == '_') {
+		return false
+	}
+	for p < len(runes) && (unicode.IsLetter(runes[p]) || unicode.IsDigit(runes[p]) || runes[p] == '_') {
+		p++
+	}
+	return p >= len(runes) || runes[p] != '\''
+}
+
 func (s *Sanitizer) tryHandleLineComment(runes []rune, idx int) (int, bool) {
 	if s.config.LineComment != "" && s.matchesAt(runes, idx, s.config.LineComment) {
 		return len(runes), true
@@ -319,6 +389,48 @@ func (s *Sanitizer) tryHandleLineComment(runes []rune, idx int) (int, bool) {
 	return idx, false
 }
 
+// sigilClosers maps the opening delimiter of a sigil to its closing one
+var sigilClosers = map[rune]rune{'(': ')', '[': ']', '{': '}', '<': '>', '/': '/', '|': '|', '"': '"', '\'': '\''}
+
+// tryHandleSigil skips (leaves blank) a sigil literal: SigilPrefix, the
+// sigil letters, a delimited body and trailing modifiers (~r/a+/i). A sigil
+// heredoc (~s""") hands its marker to the multi-line string handler; a
+// sigil not closed on the line is blanked to the end of the line.
+func (s *Sanitizer) tryHandleSigil(runes []rune, idx int) (int, bool) {
+	if s.config.SigilPrefix == "" || !s.matchesAt(runes, idx, s.config.SigilPrefix) {
+		return idx, false
+	}
+	p := idx + runeLen(s.config.SigilPrefix)
+	letters := p
+	for p < len(runes) && unicode.IsLetter(runes[p]) {
+		p++
+	}
+	if p == letters || p >= len(runes) {
+		return idx, false
+	}
+	if s.matchesDocStringStart(runes, p) {
+		return p, true
+	}
+	closer, ok := sigilClosers[runes[p]]
+	if !ok {
+		return idx, false
+	}
+	for q := p + 1; q < len(runes); q++ {
+		if s.config.EscapeChar != "" && runes[q] == firstRune(s.config.EscapeChar) {
+			q++
+			continue
+		}
+		if runes[q] == closer {
+			q++
+			for q < len(runes) && unicode.IsLetter(runes[q]) {
+				q++
+			}
+			return q, true
+		}
+	}
+	return len(runes), true
+}
+
 func (s *Sanitizer) tryHandleRegularStrings(runes []rune, idx int) (ParserState, bool) {
 	if !s.useRaw && s.matchesRawStringDelimiter(runes, idx) {
 		return StateRawString, true
@@ -328,7 +440,7 @@ func (s *Sanitizer) tryHandleRegularStrings(runes []rune, idx int) (ParserState,
 	return StateNormal, false
 }
 
-func (s *Sanitizer) tryHandleMultiLineString(line string, runes []rune, result []rune, idx int) (int, ParserState, bool) {
+func (s *Sanitizer) tryHandleMultiLineString(runes []rune, result []rune, idx int) (int, ParserState, bool) {
 	if !s.matchesDocStringStart(runes, idx) {
 		return idx, StateNormal, false
 	}
@@ -388,7 +500,7 @@ func (s *Sanitizer) tryHandleMultiLineString(line string, runes []rune, result [
 	}
 }
 
-func (s *Sanitizer) tryHandleBlockComment(line string, runes []rune, result []rune, idx int) (int, ParserState, bool) {
+func (s *Sanitizer) tryHandleBlockComment(runes []rune, result []rune, idx int) (int, ParserState, bool) {
 	if s.config.BlockCommentStart == "" || !s.matchesAt(runes, idx, s.config.BlockCommentStart) {
 		return idx, StateNormal, false
 	}
@@ -442,6 +554,7 @@ func (s *Sanitizer) CleanLine(line string, state ParserState) (string, ParserSta
 
 	// Size the buffer by rune count, not byte count: a byte-sized buffer would
 	// leave (bytes-runes) trailing spaces on lines containing multibyte runes.
+	// Every handler below works on rune indices only and never sees line.
 	runes := []rune(line)
 	result := make([]rune, len(runes))
 	for i := range result {
@@ -453,41 +566,54 @@ func (s *Sanitizer) CleanLine(line string, state ParserState) (string, ParserSta
 	for idx < len(runes) {
 		switch state {
 		case StateBlockComment:
-			idx, state = s.handleBlockComment(line, runes, result, idx)
+			idx, state = s.handleBlockComment(runes, result, idx)
 			continue
 
 		case StateString:
+			from := idx
 			idx, state = s.handleString(runes, result, idx)
+			s.keepString(runes, result, from, idx)
 			continue
 
 		case StateRawString:
+			from := idx
 			idx, state = s.handleRawString(runes, result, idx)
+			s.keepString(runes, result, from, idx)
 			continue
 
 		case StateCharLiteral:
+			from := idx
 			idx, state = s.handleCharLiteral(runes, result, idx)
+			s.keepString(runes, result, from, idx)
 			continue
 
 		case StateMultiLineString:
-			idx, state = s.handleMultiLineString(line, runes, result, idx)
+			from := idx
+			idx, state = s.handleMultiLineString(runes, result, idx)
+			s.keepString(runes, result, from, idx)
 			continue
 
 		case StateNormal:
 			// Try handlers in priority order
 			var handled bool
+			from := idx
 
 			// 1. Char delimiters (highest priority)
 			if idx, state, handled = s.tryHandleCharDelimiter(runes, result, idx); handled {
+				s.noteString()
+				s.keepString(runes, result, from, idx)
 				continue
 			}
 
 			// 2. Multi-line strings (check before regular strings)
-			if idx, state, handled = s.tryHandleMultiLineString(line, runes, result, idx); handled {
+			if idx, state, handled = s.tryHandleMultiLineString(runes, result, idx); handled {
+				s.noteString()
+				s.keepString(runes, result, from, idx)
 				continue
 			}
 
 			// 3. Block comments
-			if idx, state, handled = s.tryHandleBlockComment(line, runes, result, idx); handled {
+			if idx, state, handled = s.tryHandleBlockComment(runes, result, idx); handled {
 				continue
 			}
 
@@ -496,12 +622,24 @@ func (s *Sanitizer) CleanLine(line string, state ParserState) (string, ParserSta
 				continue
 			}
 
-			// 5. Regular strings and raw strings
+			// 5. Sigils (Elixir ~s(...), ~r/.../)
+			if idx, handled = s.tryHandleSigil(runes, idx); handled {
+				// a sigil heredoc (~s""") is counted by the multi-line handler
+				if !s.matchesDocStringStart(runes, idx) {
+					s.noteString()
+				}
+				s.keepString(runes, result, from, idx)
+				continue
+			}
+
+			// 6. Regular strings and raw strings
 			if state, handled = s.tryHandleRegularStrings(runes, idx); handled {
 				// State changed, continue to next iteration
+				s.noteString()
+				s.keepString(runes, result, idx, idx+1)
 			}
 
-			// 6. Copy character if still in StateNormal
+			// 7. Copy character if still in StateNormal
 			if state == StateNormal && idx < len(result) && idx < len(runes) {
 				result[idx] = runes[idx]
 			}
@@ -512,6 +650,14 @@ func (s *Sanitizer) CleanLine(line string, state ParserState) (string, ParserSta
 	return string(result), state
 }
 
+// keepString copies runes[from:to], consumed by a string handler, to result
+// when the sanitizer keeps string literals
+func (s *Sanitizer) keepString(runes []rune, result []rune, from, to int) {
+	if s.keepStrings {
+		copy(result[from:min(to, len(result))], runes[from:])
+	}
+}
+
 func (s *Sanitizer) CleanLines(lines []string) []string {
 	result := make([]string, len(lines))
 	state := StateNormal
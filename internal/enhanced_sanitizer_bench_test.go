@@ -238,7 +238,7 @@ func BenchmarkHandleBlockComment(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = s.handleBlockComment(line, runes, result, 0)
+		_, _ = s.handleBlockComment(runes, result, 0)
 	}
 }
 
@@ -264,7 +264,7 @@ func BenchmarkTryHandleMultiLineString_Python(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _, _ = s.tryHandleMultiLineString(line, runes, result, 0)
+		_, _, _ = s.tryHandleMultiLineString(runes, result, 0)
 	}
 }
 
@@ -277,6 +277,6 @@ func BenchmarkTryHandleBlockComment_Nested(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _, _ = s.tryHandleBlockComment(line, runes, result, 0)
+		_, _, _ = s.tryHandleBlockComment(runes, result, 0)
 	}
 }
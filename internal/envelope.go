@@ -0,0 +1,55 @@
+// envelope.go - Provenance wrapper for --json output (--envelope)
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EnvelopeTool is the "tool" value of an --envelope document
+const EnvelopeTool = "funcfinder"
+
+// jsonEnvelope records which parser produced the data: the tool, its
+// version, the language and when. "functions" is the plain --json document
+// unchanged, so consumers only have to unwrap one level.
+type jsonEnvelope struct {
+	Tool        string          `json:"tool"`
+	Version     string          `json:"version"`
+	Language    string          `json:"language"`
+	LangKey     string          `json:"lang_key"`
+	GeneratedAt string          `json:"generated_at"`
+	Filename    string          `json:"filename"`
+	Functions   json.RawMessage `json:"functions"`
+	Classes     []jsonClass     `json:"classes,omitempty"`
+}
+
+// jsonClass is one class of FindResult.Classes in an --envelope document
+type jsonClass struct {
+	Name  string `json:"name"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// WrapEnvelope puts functionsJSON (FormatJSON or FormatSelectedJSON output)
+// under "functions" next to the provenance fields and the classes of
+// result. now is stored in UTC as RFC 3339.
+func WrapEnvelope(functionsJSON string, result *FindResult, config *LanguageConfig, now time.Time) (string, error) {
+	env := jsonEnvelope{
+		Tool:        EnvelopeTool,
+		Version:     Version,
+		Language:    config.Name,
+		LangKey:     config.LangKey,
+		GeneratedAt: now.UTC().Format(time.RFC3339),
+		Filename:    result.Filename,
+		Functions:   json.RawMessage(functionsJSON),
+	}
+	for _, cls := range result.Classes {
+		env.Classes = append(env.Classes, jsonClass{Name: cls.Name, Start: cls.Start, End: cls.End})
+	}
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWrapEnvelope_Metadata(t *testing.T) {
+	result := &FindResult{
+		Filename:  "server.go",
+		Functions: []FunctionBounds{{Name: "Run", Start: 7, End: 9, ClassName: "Server"}},
+		Classes:   []ClassBounds{{Name: "Server", Start: 5, End: 5}},
+	}
+	functions, err := FormatJSON(result)
+	if err != nil {
+		t.Fatalf("FormatJSON() error = %v", err)
+	}
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	out, err := WrapEnvelope(functions, result, getGoConfig(t), now)
+	if err != nil {
+		t.Fatalf("WrapEnvelope() error = %v", err)
+	}
+	var env struct {
+		Tool        string                       `json:"tool"`
+		Version     string                       `json:"version"`
+		Language    string                       `json:"language"`
+		LangKey     string                       `json:"lang_key"`
+		GeneratedAt string                       `json:"generated_at"`
+		Filename    string                       `json:"filename"`
+		Functions   map[string]map[string]int    `json:"functions"`
+		Classes     []map[string]json.RawMessage `json:"classes"`
+	}
+	if err := json.Unmarshal([]byte(out), &env); err != nil {
+		t.Fatalf("envelope is not valid JSON: %v\n%s", err, out)
+	}
+
+	if env.Tool != "funcfinder" || env.Version != Version || env.Language != "Go" || env.LangKey != "go" || env.Filename != "server.go" {
+		t.Errorf("metadata = %q %q %q %q %q, want funcfinder %s Go go server.go", env.Tool, env.Version, env.Language, env.LangKey, env.Filename, Version)
+	}
+	if env.GeneratedAt != "2026-03-01T11:30:00Z" {
+		t.Errorf("generated_at = %q, want 2026-03-01T11:30:00Z (UTC)", env.GeneratedAt)
+	}
+	if run := env.Functions["Run"]; run["start"] != 7 || run["end"] != 9 {
+		t.Errorf("functions = %v, want the FormatJSON object with Run 7-9", env.Functions)
+	}
+	if len(env.Classes) != 1 || string(env.Classes[0]["name"]) != `"Server"` {
+		t.Errorf("classes = %v, want [Server]", env.Classes)
+	}
+}
@@ -2,7 +2,11 @@
 package internal
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 )
 
@@ -24,16 +28,108 @@ const (
 	ErrFileRead
 )
 
-// FatalError prints an error message to stderr and exits with code 1
-func FatalError(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
-	os.Exit(1)
+// ConfigError reports an invalid languages.json or an unknown language.
+// Programmatic callers can tell it apart with errors.As; the CLI prints it.
+type ConfigError struct {
+	Kind  ErrorType // ErrConfigLoad or ErrUnsupportedLanguage
+	Lang  string    // language key, empty for errors about the whole file
+	Field string    // languages.json field at fault, e.g. "func_pattern"
+	Msg   string
+	Err   error // underlying error, if any
 }
 
-// FatalErrorWithCode prints an error and exits with specific code
-func FatalErrorWithCode(code int, format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
-	os.Exit(code)
+func (e *ConfigError) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// newPatternError reports a languages.json pattern that does not compile
+func newPatternError(lang, field, msg string, err error) *ConfigError {
+	return &ConfigError{Kind: ErrConfigLoad, Lang: lang, Field: field, Msg: msg, Err: err}
+}
+
+// ParseError reports a source file a finder could not read or parse
+type ParseError struct {
+	Kind ErrorType // ErrFileNotFound, ErrFileRead or ErrParsingFailed
+	File string
+	Msg  string
+	Err  error // underlying error, if any
+}
+
+func (e *ParseError) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// newOpenError reports a source file that could not be opened
+func newOpenError(filename string, err error) *ParseError {
+	kind := ErrFileRead
+	if errors.Is(err, fs.ErrNotExist) {
+		kind = ErrFileNotFound
+	}
+	return &ParseError{Kind: kind, File: filename, Msg: "failed to open file", Err: err}
+}
+
+// newReadError reports a source file that could not be read
+func newReadError(filename string, err error) *ParseError {
+	return &ParseError{Kind: ErrFileRead, File: filename, Msg: "failed to read file", Err: err}
+}
+
+// newFuncRegexError reports a language without a usable func_pattern
+func newFuncRegexError(filename string) *ParseError {
+	return &ParseError{Kind: ErrParsingFailed, File: filename, Msg: "failed to compile function regex"}
+}
+
+// ErrorFormat is how fatal errors are written to stderr (--error-format)
+type ErrorFormat string
+
+// Error formats: text is the "Error: ..." line, json is one
+// {"error":"...","code":N} object per error for tools reading stderr
+const (
+	ErrorFormatText ErrorFormat = "text"
+	ErrorFormatJSON ErrorFormat = "json"
+)
+
+// errorFormat is shared by all commands; set once while parsing flags
+var errorFormat = ErrorFormatText
+
+// SetErrorFormat validates and selects the --error-format value; an empty
+// value means ErrorFormatText
+func SetErrorFormat(s string) error {
+	switch ErrorFormat(s) {
+	case "", ErrorFormatText:
+		errorFormat = ErrorFormatText
+	case ErrorFormatJSON:
+		errorFormat = ErrorFormatJSON
+	default:
+		return fmt.Errorf("invalid --error-format %q (expected text or json)", s)
+	}
+	return nil
+}
+
+// jsonError is the object written for a fatal error with --error-format json
+type jsonError struct {
+	Error string `json:"error"`
+	Code  int    `json:"code"`
+}
+
+// WriteError writes a fatal error in the selected format; code is the exit
+// code the caller is about to exit with
+func WriteError(w io.Writer, code int, msg string) {
+	if errorFormat == ErrorFormatJSON {
+		data, _ := json.Marshal(jsonError{Error: msg, Code: code})
+		fmt.Fprintf(w, "%s\n", data)
+		return
+	}
+	fmt.Fprintf(w, "Error: %s\n", msg)
 }
 
 // WarnError prints a warning message to stderr but continues execution
@@ -46,23 +142,5 @@ func InfoMessage(format string, args ...interface{}) {
 	fmt.Fprintf(os.Stderr, "INFO: "+format+"\n", args...)
 }
 
-// PrintUsage prints usage information and exits
-func PrintUsage(usageFunc func()) {
-	usageFunc()
-	os.Exit(1)
-}
-
 // Version is set at build time via ldflags (-X github.com/ruslano69/funcfinder/internal.Version=...)
 var Version = "dev"
-
-// PrintVersion prints version and exits successfully
-func PrintVersion(toolName string) {
-	fmt.Printf("%s version %s\n", toolName, Version)
-	os.Exit(0)
-}
-
-// FatalErrorMsg prints error message and exits
-func FatalErrorMsg(msg string) {
-	fmt.Fprintf(os.Stderr, "Error: %s\n", msg)
-	os.Exit(1)
-}
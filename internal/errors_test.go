@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetLanguageConfig_UnknownLanguageReturnsConfigError(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	_, err = config.GetLanguageConfig("cobol")
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("GetLanguageConfig(cobol) error = %v (%T), want *ConfigError", err, err)
+	}
+	if cfgErr.Kind != ErrUnsupportedLanguage || cfgErr.Lang != "cobol" {
+		t.Errorf("ConfigError = %+v, want Kind ErrUnsupportedLanguage, Lang cobol", cfgErr)
+	}
+	if got := err.Error(); got != "unsupported language: cobol" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestFindFunctions_MissingFileReturnsParseError(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	for _, lang := range []string{"go", "py", "hs", "ex"} {
+		finder := CreateFinder(config[lang], "", "map", false, false)
+		_, err := finder.FindFunctions(missing)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: error = %v (%T), want *ParseError", lang, err, err)
+			continue
+		}
+		if parseErr.Kind != ErrFileNotFound || parseErr.File != missing {
+			t.Errorf("%s: ParseError = %+v, want Kind ErrFileNotFound for %s", lang, parseErr, missing)
+		}
+	}
+
+	structFinder := NewStructFinderFactory().CreateStructFinder(config["go"], "", true, false)
+	var parseErr *ParseError
+	if _, err := structFinder.FindStructures(missing); !errors.As(err, &parseErr) {
+		t.Errorf("FindStructures error = %v, want *ParseError", err)
+	}
+}
+
+func TestPatternErrorMessage(t *testing.T) {
+	err := newPatternError("go", "func_pattern", "invalid func regex for go", errors.New("missing )"))
+	if got := err.Error(); got != "invalid func regex for go: missing )" {
+		t.Errorf("Error() = %q", got)
+	}
+	if err.Field != "func_pattern" || errors.Unwrap(err) == nil {
+		t.Errorf("ConfigError = %+v", err)
+	}
+}
+
+func TestWriteError_Formats(t *testing.T) {
+	t.Cleanup(func() { SetErrorFormat("") })
+
+	var buf bytes.Buffer
+	WriteError(&buf, 1, "loading config: bad")
+	if got := buf.String(); got != "Error: loading config: bad\n" {
+		t.Errorf("text format = %q", got)
+	}
+
+	if err := SetErrorFormat("json"); err != nil {
+		t.Fatalf("SetErrorFormat(json) error = %v", err)
+	}
+	buf.Reset()
+	WriteError(&buf, 2, "say \"hi\"\nsecond line")
+	if got := buf.String(); got != `{"error":"say \"hi\"\nsecond line","code":2}`+"\n" {
+		t.Errorf("json format = %q", got)
+	}
+
+	if err := SetErrorFormat("yaml"); err == nil {
+		t.Error("SetErrorFormat(yaml) error = nil, want invalid format")
+	}
+	if errorFormat != ErrorFormatJSON {
+		t.Errorf("invalid value changed the format to %q", errorFormat)
+	}
+}
@@ -0,0 +1,154 @@
+// export.go - Detection of exported (public) symbols for --exported-only
+package internal
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExportRule tells how a language marks a symbol as exported
+type ExportRule string
+
+// Export rules used in languages.json (export_rule)
+const (
+	ExportAll           ExportRule = ""              // no visibility rule: every symbol is exported
+	ExportCapitalized   ExportRule = "capitalized"   // Go: name starts with an uppercase letter
+	ExportPubKeyword    ExportRule = "pub"           // Rust: "pub fn", but not "pub(crate) fn"
+	ExportPublicKeyword ExportRule = "public"        // Java/C#: explicit "public" modifier
+	ExportNotPrivate    ExportRule = "not_private"   // Kotlin/Swift/Scala/Groovy/PHP/TS: public unless private/internal/protected
+	ExportNoUnderscore  ExportRule = "no_underscore" // Python/Dart: no leading "_" (Python dunders are public)
+	ExportNotStatic     ExportRule = "not_static"    // C: file-scope "static" has internal linkage
+	ExportAccessLabel   ExportRule = "access_label"  // C++: public:/private: sections, "static" for free functions
+)
+
+var (
+	exportPubRe        = regexp.MustCompile(`^\s*pub\s`)
+	exportPublicRe     = regexp.MustCompile(`\bpublic\b`)
+	exportPrivateRe    = regexp.MustCompile(`\b(?:private|fileprivate|internal|protected)\b`)
+	exportStaticRe     = regexp.MustCompile(`^\s*(?:[\w:<>*&]+\s+)*static\b`)
+	exportAccessRe     = regexp.MustCompile(`^\s*(public|private|protected)\s*:`)
+	exportClassOpenRe  = regexp.MustCompile(`\bclass\b`)
+	exportStructOpenRe = regexp.MustCompile(`\b(?:struct|union)\b`)
+)
+
+// ExportChecker decides whether symbols of one file are exported
+type ExportChecker struct {
+	rule  ExportRule
+	lines []string // sanitized source lines (comments and strings blanked)
+}
+
+// NewExportChecker prepares export detection for a file of the given language.
+// lines is the full file content; symbol lines are 1-based indexes into it.
+func NewExportChecker(config *LanguageConfig, lines []string) *ExportChecker {
+	checker := &ExportChecker{rule: config.ExportRule}
+	if checker.rule != ExportAll {
+		checker.lines = NewSanitizer(config, false).CleanLines(lines)
+	}
+	return checker
+}
+
+// IsExported reports whether the symbol name declared at line is exported
+func (c *ExportChecker) IsExported(name string, line int) bool {
+	// "Point.origin", "Type::method": visibility belongs to the last segment
+	if idx := strings.LastIndexAny(name, ".:"); idx >= 0 {
+		name = name[idx+1:]
+	}
+
+	switch c.rule {
+	case ExportCapitalized:
+		r, _ := utf8.DecodeRuneInString(name)
+		return unicode.IsUpper(r)
+	case ExportNoUnderscore:
+		return !strings.HasPrefix(name, "_") || (strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__") && len(name) > 4)
+	case ExportPubKeyword:
+		return exportPubRe.MatchString(c.signature(name, line))
+	case ExportPublicKeyword:
+		return exportPublicRe.MatchString(c.signature(name, line))
+	case ExportNotPrivate:
+		return !exportPrivateRe.MatchString(c.signature(name, line))
+	case ExportNotStatic:
+		return !exportStaticRe.MatchString(c.signature(name, line))
+	case ExportAccessLabel:
+		return c.accessLabelExported(name, line)
+	}
+	return true
+}
+
+// signature returns the declaration line of name: the first line from line
+// on that mentions it, skipping annotations/attributes placed above it.
+func (c *ExportChecker) signature(name string, line int) string {
+	if line < 1 || line > len(c.lines) {
+		return ""
+	}
+	for i := line - 1; i < len(c.lines) && i < line-1+MaxSignatureLines; i++ {
+		if strings.Contains(c.lines[i], name) {
+			return c.lines[i]
+		}
+	}
+	return c.lines[line-1]
+}
+
+// accessLabelExported walks up from the declaration to the nearest access
+// label at the same brace depth. Reaching the enclosing "{" first means the
+// default visibility: private for class, public for struct/union, and
+// "not static" for free functions.
+func (c *ExportChecker) accessLabelExported(name string, line int) bool {
+	signature := c.signature(name, line)
+	depth := 0
+	for i := line - 2; i >= 0 && i < len(c.lines); i-- {
+		text := c.lines[i]
+		depth -= CountBraces(text)
+		if depth < 0 {
+			switch {
+			case exportClassOpenRe.MatchString(text):
+				return false
+			case exportStructOpenRe.MatchString(text):
+				return true
+			}
+			// namespace or extern "C" block: keep looking outward
+			depth = 0
+			continue
+		}
+		if depth == 0 {
+			if m := exportAccessRe.FindStringSubmatch(text); m != nil {
+				return m[1] == "public"
+			}
+		}
+	}
+	return !exportStaticRe.MatchString(signature)
+}
+
+// FilterExportedFunctions keeps only exported functions
+func FilterExportedFunctions(functions []FunctionBounds, checker *ExportChecker) []FunctionBounds {
+	filtered := make([]FunctionBounds, 0, len(functions))
+	for _, fn := range functions {
+		if checker.IsExported(fn.Name, fn.Start) {
+			filtered = append(filtered, fn)
+		}
+	}
+	return filtered
+}
+
+// FilterExportedTypes keeps only exported types
+func FilterExportedTypes(types []TypeBounds, checker *ExportChecker) []TypeBounds {
+	filtered := make([]TypeBounds, 0, len(types))
+	for _, typ := range types {
+		if checker.IsExported(typ.Name, typ.Start) {
+			filtered = append(filtered, typ)
+		}
+	}
+	return filtered
+}
+
+// FilterExportedClasses keeps only exported classes
+func FilterExportedClasses(classes []ClassBounds, checker *ExportChecker) []ClassBounds {
+	filtered := make([]ClassBounds, 0, len(classes))
+	for _, cls := range classes {
+		if checker.IsExported(cls.Name, cls.Start) {
+			filtered = append(filtered, cls)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func exportedNames(t *testing.T, config *LanguageConfig, code string) []string {
+	t.Helper()
+	lines := strings.Split(code, "\n")
+	finder := NewFinder(config, nil, true, false, false)
+	result, err := finder.FindFunctionsInLines(lines, 1, "sample")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	var names []string
+	for _, fn := range FilterExportedFunctions(result.Functions, NewExportChecker(config, lines)) {
+		names = append(names, fn.Name)
+	}
+	return names
+}
+
+func TestExportedOnly_GoCapitalization(t *testing.T) {
+	code := `package demo
+
+func Public() {}
+
+func private() {}
+
+func (s *Server) Handle() {}
+
+func (s *Server) handle() {}
+`
+	got := strings.Join(exportedNames(t, getGoConfig(t), code), ",")
+	if got != "Public,Handle" {
+		t.Errorf("exported = %q, want Public,Handle", got)
+	}
+
+	checker := NewExportChecker(getGoConfig(t), strings.Split(code, "\n"))
+	types := FilterExportedTypes([]TypeBounds{{Name: "Server", Start: 1}, {Name: "config", Start: 1}}, checker)
+	if len(types) != 1 || types[0].Name != "Server" {
+		t.Errorf("exported types = %+v, want only Server", types)
+	}
+}
+
+func TestExportedOnly_JavaPrivateExcluded(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	code := `public class Service {
+    @Override
+    public String toString() {
+        return "private";
+    }
+
+    private void helper() {
+    }
+
+    protected int count() {
+        return 0;
+    }
+}
+`
+	got := strings.Join(exportedNames(t, config["java"], code), ",")
+	if got != "toString" {
+		t.Errorf("exported = %q, want toString", got)
+	}
+}
+
+func TestExportedOnly_CppAccessLabels(t *testing.T) {
+	code := `class Widget {
+    int hidden()
+    {
+        return 0;
+    }
+public:
+    int visible()
+    {
+        return 1;
+    }
+};
+
+static int internal_helper(void)
+{
+    return 0;
+}
+
+int api(void)
+{
+    return 1;
+}
+`
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	got := strings.Join(exportedNames(t, config["cpp"], code), ",")
+	if got != "visible,api" {
+		t.Errorf("exported = %q, want visible,api", got)
+	}
+}
+
+func TestExportChecker_PythonAndRust(t *testing.T) {
+	py := NewExportChecker(getPyConfig(t), nil)
+	for name, want := range map[string]bool{"run": true, "_helper": false, "__init__": true, "__secret": false} {
+		if got := py.IsExported(name, 1); got != want {
+			t.Errorf("py IsExported(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	rust := NewExportChecker(getRustConfig(t), []string{"pub fn api() {}", "pub(crate) fn internal() {}", "fn private() {}"})
+	for i, want := range []bool{true, false, false} {
+		name := []string{"api", "internal", "private"}[i]
+		if got := rust.IsExported(name, i+1); got != want {
+			t.Errorf("rust IsExported(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
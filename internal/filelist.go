@@ -0,0 +1,41 @@
+// filelist.go - Path lists read from a file or stdin (--files-from)
+package internal
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// ReadFileList reads one path per line, as printed by find or git
+// ls-files. Blank lines and CR line endings are dropped; a path listed
+// twice is kept once, at its first position.
+func ReadFileList(r io.Reader) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		path := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(path) == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// SupportedFiles keeps the paths whose language config detects (by
+// extension or shebang) and returns how many were dropped
+func SupportedFiles(config Config, paths []string) ([]string, int) {
+	kept := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if config.DetectLanguage(path) != nil {
+			kept = append(kept, path)
+		}
+	}
+	return kept, len(paths) - len(kept)
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadFileList(t *testing.T) {
+	got, err := ReadFileList(strings.NewReader("a.go\r\n\n  \nsub/b.py\na.go\nwith space.go\n"))
+	if err != nil {
+		t.Fatalf("ReadFileList() error = %v", err)
+	}
+	want := []string{"a.go", "sub/b.py", "with space.go"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("ReadFileList() = %q, want %q", got, want)
+	}
+}
+
+func TestSupportedFiles(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	kept, skipped := SupportedFiles(config, []string{"a.go", "README.md", "b.py", "Makefile"})
+	if strings.Join(kept, "|") != "a.go|b.py" || skipped != 2 {
+		t.Errorf("SupportedFiles() = %q, %d skipped; want [a.go b.py], 2", kept, skipped)
+	}
+}
@@ -3,19 +3,39 @@ package internal
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"strings"
 )
 
 // FunctionBounds содержит информацию о границах функции
 type FunctionBounds struct {
-	Name       string
-	Start      int      // Номер строки начала (1-based)
-	End        int      // Номер строки конца (1-based)
-	Lines      []string // Тело функции (если extractMode)
-	Decorators []string // Декораторы функции (для Python, TypeScript, Java)
-	ClassName  string   // Имя класса, к которому принадлежит функция
-	Scope      string   // Scope функции (для совместимости)
+	Name            string
+	Start           int        // Номер строки начала (1-based)
+	End             int        // Номер строки конца (1-based)
+	Lines           []string   // Тело функции (если extractMode)
+	Decorators      []string   // Декораторы функции (для Python, TypeScript, Java)
+	ClassName       string     // Имя класса, к которому принадлежит функция
+	Scope           string     // Scope функции (для совместимости)
+	Category        string     // Роль функции для Go: init/test/benchmark/example/fuzz
+	ClosureCount    int        // Число анонимных функций (замыканий) в теле
+	Doc             string     // Документирующий комментарий (--with-docs)
+	IsGenerator     bool       // Генератор: Python yield, JS function*
+	LineStats       *LineStats // Строки кода/комментариев/пустые (--stats)
+	FirstLine       string     // Исходная первая строка функции (--first-line)
+	ParamCount      int        // Число параметров в сигнатуре (--max-params)
+	ReturnCount     int        // Число возвращаемых значений: Go/Python (--returns)
+	IsAbstract      bool       // Объявлен без тела: abstract, метод интерфейса, = 0
+	ScopePath       string     // Путь через точку: Outer.Inner.method (--context-json)
+	IsDeprecated    bool       // Помечена устаревшей: Deprecated:, @Deprecated, [Obsolete] (--deprecated-only)
+	ByteStart       int        // Смещение первого байта строки Start в файле (--byte-offsets)
+	ByteEnd         int        // Смещение после последнего байта строки End (--byte-offsets)
+	StringLiterals  int        // Число строковых литералов в теле (--literals)
+	NumericLiterals int        // Число числовых литералов (magic numbers) в теле (--literals)
+	Kind            string     // KindMacro (#define, --include-macros), KindOperator/KindSpecial (перегрузки, __init__), KindClosure (--flatten); иначе пусто
+	Depth           int        // Глубина вложенности в другие функции (--flatten)
+	Assertions      *int       // Число вызовов assertion_pattern в теле теста (--assertions); nil - не считалось
 }
 
 // ClassBounds содержит информацию о границах класса
@@ -23,6 +43,7 @@ type ClassBounds struct {
 	Name  string
 	Start int
 	End   int
+	Kind  string // Вид типа из --struct/--all: struct, interface, enum...; пусто для class_pattern
 }
 
 // FindResult содержит результат поиска
@@ -30,12 +51,16 @@ type FindResult struct {
 	Functions []FunctionBounds
 	Classes   []ClassBounds
 	Filename  string
+	// Ambiguities - места, где разбор неоднозначен (см. --strict)
+	Ambiguities []ParseAmbiguity
 }
 
 // FunctionContext отслеживает функцию и её глубину вложенности
 type FunctionContext struct {
-	Func  *FunctionBounds
-	Depth int
+	Func    *FunctionBounds
+	Depth   int
+	Opened  bool // Встречена ли открывающая скобка тела
+	Flagged bool // Уже отмечена как AmbiguityNoBrace
 }
 
 // Finder ищет функции в файле
@@ -45,6 +70,13 @@ type Finder struct {
 	funcNames   map[string]bool
 	mapMode     bool
 	extractMode bool
+	noClasses   bool
+	// excludeAnonymous пропускает безымянные функции и IIFE (--exclude-anonymous)
+	excludeAnonymous bool
+	// includeMacros добавляет функциональные макросы C/C++ (--include-macros)
+	includeMacros bool
+	// skipGenerators не помечает генераторы (IsGenerator не нужен выводу)
+	skipGenerators bool
 }
 
 // NewFinder создает новый искатель функций
@@ -67,23 +99,43 @@ func NewFinder(config *LanguageConfig, funcNames []string, mapMode, extractMode,
 func (f *Finder) FindFunctions(filename string) (*FindResult, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, newOpenError(filename, err)
 	}
 	defer file.Close()
-	
-	// Читаем файл построчно
+
+	return f.FindFunctionsInReader(file, filename)
+}
+
+// FindFunctionsInReader ищет функции в исходном коде, прочитанном из r
+func (f *Finder) FindFunctionsInReader(r io.Reader, filename string) (*FindResult, error) {
+	// Читаем построчно
 	var lines []string
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		lines = append(lines, scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, newReadError(filename, err)
+	}
+
+	// .vue/.svelte: разбираются только блоки <script>
+	if IsComponentFile(filename) {
+		return f.findComponentFunctions(lines, filename)
 	}
-	
 	return f.FindFunctionsInLines(lines, 1, filename)
 }
 
+// SetNoClasses отключает поиск классов (--no-classes): ClassName остается
+// пустым, а Classes — nil
+func (f *Finder) SetNoClasses(noClasses bool) {
+	f.noClasses = noClasses
+}
+
+// SetSkipGenerators отключает пометку генераторов: IsGenerator остается false
+func (f *Finder) SetSkipGenerators(skip bool) {
+	f.skipGenerators = skip
+}
+
 // FindFunctionsInLines ищет функции в предварительно прочитанных строках
 // startLine - номер первой строки в lines (1-based) относительно оригинального файла
 func (f *Finder) FindFunctionsInLines(lines []string, startLine int, filename string) (*FindResult, error) {
@@ -97,31 +149,77 @@ func (f *Finder) FindFunctionsInLines(lines []string, startLine int, filename st
 
 	// Если язык поддерживает классы, сначала находим все классы
 	var classes []ClassBounds
-	if f.config.HasClasses() {
-		classes = f.findClassesWithOffset(lines, lineOffset)
+	if f.noClasses {
+		result.Classes = nil
+	} else if f.config.HasClasses() {
+		var ambiguities []ParseAmbiguity
+		classes, ambiguities = f.findClassesWithOffset(lines, lineOffset)
 		result.Classes = classes
+		result.Ambiguities = append(result.Ambiguities, ambiguities...)
 	}
 
-	// Проверяем, поддерживает ли язык вложенные функции
+	// Проверяем, поддерживает ли язык вложенные функции; cleaned
+	// заполняется очищенными строками при поиске и переиспользуется ниже
+	var err error
+	cleaned := make([]string, len(lines))
 	if f.config.SupportsNested {
-		return f.findFunctionsWithNesting(lines, lineOffset, classes, result)
+		result, err = f.findFunctionsWithNesting(lines, lineOffset, classes, result, cleaned)
+	} else {
+		// Старая логика для языков без вложенных функций
+		result, err = f.findFunctionsSimple(lines, lineOffset, classes, result, cleaned)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Ambiguities = append(result.Ambiguities, findOverlaps(result.Functions)...)
+
+	// Функциональные макросы #define NAME(...) (macro_pattern: C, C++)
+	if f.includeMacros {
+		result.Functions = mergeMacros(result.Functions, f.findMacros(lines, lineOffset))
+	}
+	if f.config.BlockEndKeyword != "" {
+		// Тела закрываются ключевым словом (Ruby end), а не скобками —
+		// проверки скобок для таких языков не имеют смысла
+		result.Ambiguities = nil
 	}
 
-	// Старая логика для языков без вложенных функций
-	return f.findFunctionsSimple(lines, lineOffset, classes, result)
+	// Go: помечаем init/Test/Benchmark/Example/Fuzz функции
+	if f.config.LangKey == "go" {
+		tagGoCategories(result.Functions, lines, lineOffset)
+	}
+
+	// Замыкания в телах функций (closure_pattern: Go, JS, TS)
+	if closureRe := f.config.ClosureRegex(); closureRe != nil {
+		countClosures(result.Functions, cleaned, lineOffset, closureRe)
+	}
+
+	// Генераторы (generator_pattern: JS/TS function*)
+	if generatorRe := f.config.GeneratorRegex(); generatorRe != nil && !f.skipGenerators {
+		markGenerators(result.Functions, cleaned, lineOffset, generatorRe)
+	}
+
+	// Декораторы и атрибуты над сигнатурой (decorator_pattern: Rust #[...], Java, C#)
+	if f.config.DecoratorRegex() != nil {
+		attachDecorators(result.Functions, lines, lineOffset, f.config)
+	}
+
+	return result, nil
 }
 
 // findFunctionsSimple - старая логика для языков без вложенных функций
-func (f *Finder) findFunctionsSimple(lines []string, lineOffset int, classes []ClassBounds, result *FindResult) (*FindResult, error) {
+func (f *Finder) findFunctionsSimple(lines []string, lineOffset int, classes []ClassBounds, result *FindResult, cleanedLines []string) (*FindResult, error) {
 	state := StateNormal
 	var currentFunc *FunctionBounds
 	depth := 0
+	opened, flagged := false, false // состояние скобки тела currentFunc (для --strict)
 	funcRegex := f.config.FuncRegex()
+	abstractRe := f.config.AbstractRegex()
 
 	for lineNum, line := range lines {
 		// Очищаем строку от комментариев и литералов
 		cleaned, newState := f.sanitizer.CleanLine(line, state)
 		state = newState
+		cleanedLines[lineNum] = cleaned
 
 		// Если мы внутри функции, отслеживаем баланс скобок
 		if currentFunc != nil {
@@ -132,6 +230,12 @@ func (f *Finder) findFunctionsSimple(lines []string, lineOffset int, classes []C
 			prevDepth := depth
 			hasBrace := strings.Contains(cleaned, "{")
 			depth += CountBraces(cleaned)
+			if hasBrace {
+				opened = true
+			} else if !opened && !flagged && lineNum+1+lineOffset-currentFunc.Start >= MaxSignatureLines {
+				result.Ambiguities = append(result.Ambiguities, noBraceAmbiguity(currentFunc))
+				flagged = true
+			}
 
 			// Функция заканчивается если мы ВЫХОДИМ из тела функции
 			// (prevDepth > 0 && depth == 0) — а не просто depth == 0, что
@@ -158,86 +262,98 @@ func (f *Finder) findFunctionsSimple(lines []string, lineOffset int, classes []C
 				result.Functions = append(result.Functions, *currentFunc)
 				currentFunc = nil
 			}
-		} else {
-			// Ищем начало новой функции
-			matches := funcRegex.FindStringSubmatch(cleaned)
-			if matches != nil {
-				// Извлекаем имя функции
-				funcName := ""
-				// Для JS/TS с поддержкой arrow functions: проверяем группы 3 и 5
-				if len(matches) > 5 {
-					// Группа 3: function declarations (function name, function* name)
-					// Группа 5: arrow functions (const name = ...)
-					if matches[3] != "" {
-						funcName = matches[3]
-					} else if len(matches) > 5 && matches[5] != "" {
-						funcName = matches[5]
-					}
+		} else if abstractRe != nil && abstractRe.MatchString(cleaned) {
+			// Метод без тела ("void f();", "virtual void f() = 0;"): func_pattern
+			// ждёт скобку и его не видит — функция из одной строки
+			if fn, ok := f.abstractFunc(cleaned, abstractRe.FindStringSubmatch(cleaned), lineNum+lineOffset, classes); ok {
+				if f.extractMode {
+					fn.Lines = []string{line}
 				}
-				// Если имя еще не найдено, используем старую логику (последняя группа)
-				if funcName == "" {
-					for i := len(matches) - 1; i >= 1; i-- {
-						if matches[i] != "" {
-							funcName = matches[i]
-							break
-						}
-					}
+				result.Functions = append(result.Functions, fn)
+			}
+		} else {
+			// Ищем начало новой функции. На одной строке может быть несколько
+			// однострочных функций (C: "int a(){return 0;} int b(){return 1;}",
+			// Go: "func a() {}; func b() {}") — после закрывающей скобки тела
+			// поиск продолжается в остатке строки.
+			rest := cleaned
+			for rest != "" {
+				matches := funcRegex.FindStringSubmatch(rest)
+				if matches == nil {
+					break
 				}
+				funcName := operatorName(rest, funcNameFromMatches(matches))
 
 				// Проверяем, нужно ли нам эту функцию
-				if f.mapMode || f.funcNames[funcName] {
-					// Определяем класс, к которому принадлежит функция
-					className := ""
-					if f.config.HasClasses() {
-						className = f.findClassForLine(classes, lineNum+lineOffset)
-					}
-
-					currentFunc = &FunctionBounds{
-						Name:      funcName,
-						Start:     lineNum + 1 + lineOffset, // 1-based + offset
-						Lines:     []string{},
-						ClassName: className,
-						Scope:     className,
-					}
-					if f.extractMode {
-						currentFunc.Lines = append(currentFunc.Lines, line)
-					}
-
-					// depth starts at 0 (nothing was open before this line);
-					// apply this line's own brace delta the same way the
-					// `currentFunc != nil` branch does for every later line,
-					// so a single-line body on the signature line itself
-					// (e.g. Rust `fn helper() -> i32 { 42 }`) closes right
-					// here instead of being mistaken for "no brace yet, wait
-					// for a multiline signature's opening brace".
-					hasBrace := strings.Contains(cleaned, "{")
-					depth = CountBraces(cleaned)
-
-					if depth == 0 && hasBrace {
-						// Однострочная функция: скобка открылась и закрылась
-						// на этой же строке.
-						currentFunc.End = lineNum + 1 + lineOffset
-						result.Functions = append(result.Functions, *currentFunc)
-						currentFunc = nil
-					}
-					// Иначе: скобка открыта и ещё не закрыта (depth > 0), или
-					// скобки нет вовсе (многострочная сигнатура) — в обоих
-					// случаях функция остаётся открытой и продолжается веткой
-					// `currentFunc != nil` на следующих строках.
+				if !(f.mapMode || f.funcNames[funcName]) || controlFlowWords[funcName] {
+					break
 				}
-				// NB: there is no `else` here. A multiline signature opened
-				// above (currentFunc set, depth == 0) is continued by the
-				// `if currentFunc != nil` branch on the following lines, not
-				// here — inside this `else` block currentFunc is always nil.
+				if f.excludeAnonymous && anonymousMatch(funcRegex, rest, funcName) {
+					break
+				}
+				// Определяем класс, к которому принадлежит функция
+				className := ""
+				if f.config.HasClasses() {
+					className = f.findClassForLine(classes, lineNum+lineOffset)
+				}
+				if className == "" {
+					className = qualifiedClass(funcRegex, matches)
+				}
+
+				currentFunc = &FunctionBounds{
+					Name:      funcName,
+					Start:     lineNum + 1 + lineOffset, // 1-based + offset
+					Lines:     []string{},
+					ClassName: className,
+					Scope:     className,
+					Kind:      functionKind(funcName),
+				}
+				if f.extractMode {
+					currentFunc.Lines = append(currentFunc.Lines, line)
+				}
+
+				// Однострочная функция: скобка тела открылась и закрылась на
+				// этой же строке (Rust "fn helper() -> i32 { 42 }") — End == Start
+				if closeIdx := bodyCloseIndex(rest); closeIdx >= 0 {
+					currentFunc.End = lineNum + 1 + lineOffset
+					result.Functions = append(result.Functions, *currentFunc)
+					currentFunc = nil
+					depth = 0
+					rest = strings.TrimLeft(rest[closeIdx+1:], " \t;")
+					continue
+				}
+
+				// depth starts at 0 (nothing was open before this line);
+				// apply the rest of the line's brace delta the same way the
+				// `currentFunc != nil` branch does for every later line.
+				hasBrace := strings.Contains(rest, "{")
+				depth = CountBraces(rest)
+				opened, flagged = hasBrace, false
+
+				if depth == 0 && !hasBrace && f.isExpressionBody(rest) {
+					// Тело — выражение после "=" (Scala "def f(x: Int) = x + 1")
+					currentFunc.End = lineNum + 1 + lineOffset
+					result.Functions = append(result.Functions, *currentFunc)
+					currentFunc = nil
+				}
+				// Иначе: скобка открыта и ещё не закрыта (depth > 0), или
+				// скобки нет вовсе (многострочная сигнатура) — в обоих
+				// случаях функция остаётся открытой и продолжается веткой
+				// `currentFunc != nil` на следующих строках.
+				break
 			}
 		}
 	}
 
+	if currentFunc != nil && (opened || !flagged) {
+		result.Ambiguities = append(result.Ambiguities, openFuncAmbiguity(currentFunc, opened))
+	}
+
 	return result, nil
 }
 
 // findFunctionsWithNesting - новая логика для языков с вложенными функциями
-func (f *Finder) findFunctionsWithNesting(lines []string, lineOffset int, classes []ClassBounds, result *FindResult) (*FindResult, error) {
+func (f *Finder) findFunctionsWithNesting(lines []string, lineOffset int, classes []ClassBounds, result *FindResult, cleanedLines []string) (*FindResult, error) {
 	state := StateNormal
 	funcStack := []*FunctionContext{} // Стек активных функций
 	funcRegex := f.config.FuncRegex()
@@ -246,6 +362,7 @@ func (f *Finder) findFunctionsWithNesting(lines []string, lineOffset int, classe
 		// Очищаем строку от комментариев и литералов
 		cleaned, newState := f.sanitizer.CleanLine(line, state)
 		state = newState
+		cleanedLines[lineNum] = cleaned
 
 		braceDelta := CountBraces(cleaned)
 
@@ -256,68 +373,84 @@ func (f *Finder) findFunctionsWithNesting(lines []string, lineOffset int, classe
 		}
 
 		// 2. Обновляем depth и Lines для ВСЕХ функций в стеке
+		hasBrace := strings.Contains(cleaned, "{")
 		for _, ctx := range funcStack {
 			if f.extractMode {
 				ctx.Func.Lines = append(ctx.Func.Lines, line)
 			}
 			ctx.Depth += braceDelta
+			if hasBrace {
+				ctx.Opened = true
+			} else if !ctx.Opened && !ctx.Flagged && lineNum+1+lineOffset-ctx.Func.Start >= MaxSignatureLines {
+				result.Ambiguities = append(result.Ambiguities, noBraceAmbiguity(ctx.Func))
+				ctx.Flagged = true
+			}
 		}
 
-		// 3. Ищем новые функции на ЛЮБОМ уровне вложенности
-		matches := funcRegex.FindStringSubmatch(cleaned)
-		if matches != nil {
-			// Извлекаем имя функции
-			funcName := ""
-			// Для JS/TS с поддержкой arrow functions: проверяем группы 3 и 5
-			if len(matches) > 5 {
-				if matches[3] != "" {
-					funcName = matches[3]
-				} else if len(matches) > 5 && matches[5] != "" {
-					funcName = matches[5]
-				}
-			}
-			// Если имя еще не найдено, используем старую логику (последняя группа)
-			if funcName == "" {
-				for i := len(matches) - 1; i >= 1; i-- {
-					if matches[i] != "" {
-						funcName = matches[i]
-						break
-					}
-				}
+		// 3. Ищем новые функции на ЛЮБОМ уровне вложенности; после
+		// однострочной функции поиск продолжается в остатке строки
+		// ("func a() {}; func b() {}")
+		rest := cleaned
+		for rest != "" {
+			matches := funcRegex.FindStringSubmatch(rest)
+			if matches == nil {
+				break
 			}
+			funcName := operatorName(rest, funcNameFromMatches(matches))
 
 			// Проверяем, нужно ли нам эту функцию
-			if f.mapMode || f.funcNames[funcName] {
-				// Определяем класс, к которому принадлежит функция
-				className := ""
-				if f.config.HasClasses() {
-					className = f.findClassForLine(classes, lineNum+lineOffset)
-				}
+			if !(f.mapMode || f.funcNames[funcName]) || controlFlowWords[funcName] {
+				break
+			}
+			if f.excludeAnonymous && anonymousMatch(funcRegex, rest, funcName) {
+				break
+			}
+			// Определяем класс, к которому принадлежит функция
+			className := ""
+			if f.config.HasClasses() {
+				className = f.findClassForLine(classes, lineNum+lineOffset)
+			}
+			if className == "" {
+				className = qualifiedClass(funcRegex, matches)
+			}
 
-				newFunc := &FunctionBounds{
-					Name:      funcName,
-					Start:     lineNum + 1 + lineOffset,
-					Lines:     []string{},
-					ClassName: className,
-					Scope:     className,
-				}
-				if f.extractMode {
-					newFunc.Lines = append(newFunc.Lines, line)
-				}
+			newFunc := &FunctionBounds{
+				Name:      funcName,
+				Start:     lineNum + 1 + lineOffset,
+				Lines:     []string{},
+				ClassName: className,
+				Scope:     className,
+				Kind:      functionKind(funcName),
+			}
+			if f.extractMode {
+				newFunc.Lines = append(newFunc.Lines, line)
+			}
 
-				// Если скобки сбалансированы на одной строке ({ ... }) — функция завершена
-				if braceDelta == 0 && strings.Contains(cleaned, "{") {
-					newFunc.End = lineNum + 1 + lineOffset
-					result.Functions = append(result.Functions, *newFunc)
-				} else {
-					// Добавляем новую функцию в стек
-					ctx := &FunctionContext{
-						Func:  newFunc,
-						Depth: braceDelta,
-					}
-					funcStack = append(funcStack, ctx)
+			// Если скобки тела сбалансированы на одной строке ({ ... }) —
+			// функция завершена
+			if closeIdx := bodyCloseIndex(rest); closeIdx >= 0 {
+				newFunc.End = lineNum + 1 + lineOffset
+				result.Functions = append(result.Functions, *newFunc)
+				rest = strings.TrimLeft(rest[closeIdx+1:], " \t;")
+				continue
+			}
+
+			restDelta := CountBraces(rest)
+			restHasBrace := strings.Contains(rest, "{")
+			if restDelta == 0 && !restHasBrace && f.isExpressionBody(rest) {
+				// Тело — выражение после "="
+				newFunc.End = lineNum + 1 + lineOffset
+				result.Functions = append(result.Functions, *newFunc)
+			} else {
+				// Добавляем новую функцию в стек
+				ctx := &FunctionContext{
+					Func:   newFunc,
+					Depth:  restDelta,
+					Opened: restHasBrace,
 				}
+				funcStack = append(funcStack, ctx)
 			}
+			break
 		}
 
 		// 4. Удаляем завершенные функции из стека (в обратном порядке)
@@ -338,9 +471,158 @@ func (f *Finder) findFunctionsWithNesting(lines []string, lineOffset int, classe
 		funcStack = newStack
 	}
 
+	for _, ctx := range funcStack {
+		if ctx.Opened || !ctx.Flagged {
+			result.Ambiguities = append(result.Ambiguities, openFuncAmbiguity(ctx.Func, ctx.Opened))
+		}
+	}
+
 	return result, nil
 }
 
+// funcNameFromMatches извлекает имя функции из совпадения func_pattern
+func funcNameFromMatches(matches []string) string {
+	// Для JS/TS с поддержкой arrow functions: проверяем группы 3 и 5
+	// (function declarations и const name = ...)
+	if len(matches) > 5 {
+		if matches[3] != "" {
+			return matches[3]
+		}
+		if matches[5] != "" {
+			return matches[5]
+		}
+	}
+	// Иначе — последняя непустая группа
+	for i := len(matches) - 1; i >= 1; i-- {
+		if matches[i] != "" {
+			return matches[i]
+		}
+	}
+	return ""
+}
+
+// bodyCloseIndex возвращает байтовый индекс скобки, закрывающей тело,
+// или -1, если тело не закрывается на этой строке (или скобки нет).
+// Тело открывает первая "{" вне круглых и квадратных скобок: "{" в
+// параметрах ("args ...interface{}", JS "function f({a, b}) {") и
+// литералы типов Go "interface{}"/"struct{}" в результате телом не считаются
+func bodyCloseIndex(cleaned string) int {
+	open := bodyOpenIndex(cleaned)
+	if open < 0 {
+		return -1
+	}
+	return matchingBrace(cleaned, open)
+}
+
+// bodyOpenIndex возвращает индекс "{", открывающей тело, или -1
+func bodyOpenIndex(cleaned string) int {
+	parens := 0
+	for i := 0; i < len(cleaned); i++ {
+		switch cleaned[i] {
+		case '(', '[':
+			parens++
+		case ')', ']':
+			if parens > 0 {
+				parens--
+			}
+		case '{':
+			if parens > 0 {
+				continue
+			}
+			word := strings.TrimRight(cleaned[:i], " \t")
+			if !strings.HasSuffix(word, "interface") && !strings.HasSuffix(word, "struct") {
+				return i
+			}
+			// Литерал типа: пропускаем его скобки целиком
+			closeIdx := matchingBrace(cleaned, i)
+			if closeIdx < 0 {
+				return i
+			}
+			i = closeIdx
+		}
+	}
+	return -1
+}
+
+// matchingBrace возвращает индекс "}", закрывающей "{" на позиции open, или -1
+func matchingBrace(cleaned string, open int) int {
+	depth := 0
+	for i := open; i < len(cleaned); i++ {
+		switch cleaned[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isExpressionBody проверяет, что сигнатура не ждёт тела в скобках:
+// Scala "def f(x: Int): Int = x + 1" или абстрактный "def draw(): Unit"
+func (f *Finder) isExpressionBody(cleaned string) bool {
+	re := f.config.ExpressionBodyRegex()
+	return re != nil && re.MatchString(cleaned)
+}
+
+// abstractFunc builds the zero-body function of an abstract_pattern match
+// on the cleaned line lineIdx (0-based, with offset); ok is false when the
+// name is not wanted (search mode, exclude_words) or the line is a statement
+// of an initializer block or lambda rather than a declaration: it starts
+// with an exclude_words keyword ("return compute(x);", "new Foo(x);")
+func (f *Finder) abstractFunc(cleaned string, matches []string, lineIdx int, classes []ClassBounds) (FunctionBounds, bool) {
+	name := operatorName(cleaned, funcNameFromMatches(matches))
+	if !(f.mapMode || f.funcNames[name]) || isExcludedWord(name, f.config.ExcludeWords) {
+		return FunctionBounds{}, false
+	}
+	if words := strings.Fields(cleaned); len(words) > 0 && isExcludedWord(words[0], f.config.ExcludeWords) {
+		return FunctionBounds{}, false
+	}
+	className := ""
+	if f.config.HasClasses() {
+		className = f.findClassForLine(classes, lineIdx)
+	}
+	return FunctionBounds{
+		Name:       name,
+		Start:      lineIdx + 1,
+		End:        lineIdx + 1,
+		Lines:      []string{},
+		ClassName:  className,
+		Scope:      className,
+		Kind:       functionKind(name),
+		IsAbstract: true,
+	}, true
+}
+
+// noBraceAmbiguity reports a signature that was never followed by a body
+func noBraceAmbiguity(fn *FunctionBounds) ParseAmbiguity {
+	return ParseAmbiguity{
+		Kind:    AmbiguityNoBrace,
+		Name:    fn.Name,
+		Line:    fn.Start,
+		Message: fmt.Sprintf("function %s: no opening brace within %d lines", fn.Name, MaxSignatureLines),
+	}
+}
+
+// openFuncAmbiguity reports a function still open at end of input.
+// A body that never opened is a no-brace ambiguity.
+func openFuncAmbiguity(fn *FunctionBounds, opened bool) ParseAmbiguity {
+	if !opened {
+		a := noBraceAmbiguity(fn)
+		a.Message = fmt.Sprintf("function %s: no opening brace before end of input", fn.Name)
+		return a
+	}
+	return ParseAmbiguity{
+		Kind:    AmbiguityUnclosedFunction,
+		Name:    fn.Name,
+		Line:    fn.Start,
+		Message: fmt.Sprintf("function %s: body is not closed before end of input", fn.Name),
+	}
+}
+
 // ParseFuncNames разбирает строку с именами функций через запятую
 func ParseFuncNames(funcStr string) []string {
 	if funcStr == "" {
@@ -358,16 +640,18 @@ func ParseFuncNames(funcStr string) []string {
 }
 // findClasses находит все классы в файле
 func (f *Finder) findClasses(lines []string) []ClassBounds {
-	return f.findClassesWithOffset(lines, 0)
+	classes, _ := f.findClassesWithOffset(lines, 0)
+	return classes
 }
 
 // findClassesWithOffset находит все классы с учетом offset номеров строк
-func (f *Finder) findClassesWithOffset(lines []string, lineOffset int) []ClassBounds {
+// Незакрытый до конца ввода класс возвращается как AmbiguityUnclosedClass.
+func (f *Finder) findClassesWithOffset(lines []string, lineOffset int) ([]ClassBounds, []ParseAmbiguity) {
 	var classes []ClassBounds
 	var currentClass *ClassBounds
 	classRegex := f.config.ClassRegex()
 	if classRegex == nil {
-		return classes
+		return classes, nil
 	}
 
 	state := StateNormal
@@ -405,17 +689,47 @@ func (f *Finder) findClassesWithOffset(lines []string, lineOffset int) []ClassBo
 					Name:  className,
 					Start: lineNum + 1 + lineOffset,
 				}
+				// Пустое тело на той же строке: type Empty struct{}
+				if braceCount > 0 && CountBraces(cleaned) <= 0 {
+					currentClass.End = currentClass.Start
+					classes = append(classes, *currentClass)
+					currentClass = nil
+					classDepth = 0
+				}
 			}
 		}
 	}
 
 	// Если класс не был закрыт до конца файла
+	var ambiguities []ParseAmbiguity
 	if currentClass != nil {
 		currentClass.End = len(lines) + lineOffset
 		classes = append(classes, *currentClass)
+		ambiguities = append(ambiguities, ParseAmbiguity{
+			Kind:    AmbiguityUnclosedClass,
+			Name:    currentClass.Name,
+			Line:    currentClass.Start,
+			Message: fmt.Sprintf("class %s: body is not closed before end of input", currentClass.Name),
+		})
 	}
 
-	return classes
+	return classes, ambiguities
+}
+
+// controlFlowWords - ключевые слова, которые func_pattern принимает за имя
+// функции в строках вида "else if (x < 0)" без тела на той же строке; прочие
+// exclude_words (print, new, delete) бывают законными именами методов
+var controlFlowWords = map[string]bool{"if": true, "for": true, "while": true, "switch": true, "return": true}
+
+// qualifiedClass возвращает класс из квалификатора имени (C++ "Box<T>::get"):
+// первую непустую группу (?P<class>...) в func_pattern
+func qualifiedClass(funcRegex *regexp.Regexp, matches []string) string {
+	for i, name := range funcRegex.SubexpNames() {
+		if name == "class" && matches[i] != "" {
+			return matches[i]
+		}
+	}
+	return ""
 }
 
 // findClassForLine находит класс, которому принадлежит строка
@@ -1,8 +1,36 @@
 package internal
 
+import "io"
+
 // LanguageFinder - интерфейс для парсеров разных языков
 type LanguageFinder interface {
 	FindFunctions(filename string) (*FindResult, error)
+	// FindFunctionsInReader разбирает код из r (архивы, stdin); filename
+	// попадает в FindResult.Filename
+	FindFunctionsInReader(r io.Reader, filename string) (*FindResult, error)
+}
+
+// ClassSkipper реализуют парсеры, умеющие не искать классы (--no-classes)
+type ClassSkipper interface {
+	SetNoClasses(noClasses bool)
+}
+
+// AnonymousSkipper реализуют парсеры, умеющие пропускать безымянные
+// функции и IIFE (--exclude-anonymous)
+type AnonymousSkipper interface {
+	SetExcludeAnonymous(exclude bool)
+}
+
+// GeneratorSkipper реализуют парсеры, умеющие не искать генераторы, когда
+// IsGenerator никто не читает (ни --generators-only, ни JSON-вывод)
+type GeneratorSkipper interface {
+	SetSkipGenerators(skip bool)
+}
+
+// MacroFinder реализуют парсеры, умеющие находить функциональные макросы
+// препроцессора (--include-macros)
+type MacroFinder interface {
+	SetIncludeMacros(include bool)
 }
 
 // CreateFinder создает подходящий парсер в зависимости от языка
@@ -12,8 +40,19 @@ func CreateFinder(config *LanguageConfig, funcNamesStr string, mode string, extr
 		return NewPythonFinder(*config, funcNamesStr, mode, extract)
 	}
 
-	// Для остальных языков (C-подобных со скобками) используем стандартный парсер
 	// Парсим строку с именами функций в массив
 	funcNames := ParseFuncNames(funcNamesStr)
+
+	// Haskell: layout rule, top-level bindings without braces
+	if config.LangKey == "hs" {
+		return NewHaskellFinder(config, funcNames, mode == "map", extract)
+	}
+
+	// Блоки do ... end (Elixir): открываются ключевыми словами, а не скобками
+	if config.BlockEndKeyword != "" && config.BlockOpenRegex() != nil {
+		return NewKeywordFinder(config, funcNames, mode == "map", extract)
+	}
+
+	// Для остальных языков (C-подобных со скобками) используем стандартный парсер
 	return NewFinder(config, funcNames, mode == "map", extract, useRaw)
 }
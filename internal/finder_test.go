@@ -1,8 +1,11 @@
 package internal
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -642,3 +645,126 @@ func another() {
 		}
 	})
 }
+
+func TestFindFunctions_SingleLineBodies(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		lang string
+		code string
+		want []string // name:start-end
+	}{
+		{
+			lang: "go",
+			code: "package p\n\nfunc f() { return }\nfunc a() {}; func b() { _ = 1 }\nfunc g() int {\n\treturn 1\n}\n",
+			want: []string{"f:3-3", "a:4-4", "b:4-4", "g:5-7"},
+		},
+		{
+			lang: "c",
+			code: "int f(){return 0;}\nint a(void) { return 1; } int b(void) { if (x) { return 2; } return 3; }\nint big(int x)\n{\n    return x;\n}\nint main(void) {\n    return f();\n}\n",
+			want: []string{"f:1-1", "a:2-2", "b:2-2", "big:3-6", "main:7-9"},
+		},
+		{
+			// "{" in parameters and type literals doesn't open the body
+			lang: "go",
+			code: "package p\n\nfunc f(args ...interface{}) {\n\t_ = args\n}\nfunc g(m map[string]interface{}, done chan struct{}) {\n\t_ = m\n}\nfunc h() interface{} { return nil }\nfunc k[T interface{ ~int }](x T) struct{} {\n\treturn struct{}{}\n}\n",
+			want: []string{"f:3-5", "g:6-8", "h:9-9", "k:10-12"},
+		},
+		{
+			lang: "js",
+			code: "function f({a, b}) {\n  return a + b;\n}\nfunction g({a} = {}) { return a; }\n",
+			want: []string{"f:1-3", "g:4-4"},
+		},
+	}
+	for _, tt := range tests {
+		finder := NewFinder(config[tt.lang], []string{}, true, false, false)
+		result, err := finder.FindFunctionsInLines(strings.Split(tt.code, "\n"), 1, "test."+tt.lang)
+		if err != nil {
+			t.Fatalf("%s: FindFunctionsInLines() error = %v", tt.lang, err)
+		}
+		var got []string
+		for _, fn := range result.Functions {
+			got = append(got, fmt.Sprintf("%s:%d-%d", fn.Name, fn.Start, fn.End))
+		}
+		sort.Strings(got)
+		want := append([]string(nil), tt.want...)
+		sort.Strings(want)
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("%s: got %v, want %v", tt.lang, got, want)
+		}
+		if len(result.Ambiguities) != 0 {
+			t.Errorf("%s: unexpected ambiguities %+v", tt.lang, result.Ambiguities)
+		}
+	}
+}
+
+func TestFindFunctions_NoClasses(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	code := `public class Greeter {
+    public String greet(String name) {
+        return "hi " + name;
+    }
+
+    private void reset() {
+    }
+}
+`
+	finder := NewFinder(config["java"], nil, true, false, false)
+	finder.SetNoClasses(true)
+	result, err := finder.FindFunctionsInLines(strings.Split(code, "\n"), 1, "Greeter.java")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	if result.Classes != nil {
+		t.Errorf("Classes = %+v, want nil", result.Classes)
+	}
+	if len(result.Functions) != 2 {
+		t.Fatalf("got %d functions, want 2: %+v", len(result.Functions), result.Functions)
+	}
+	for _, fn := range result.Functions {
+		if fn.ClassName != "" {
+			t.Errorf("%s: ClassName = %q, want empty", fn.Name, fn.ClassName)
+		}
+	}
+}
+
+func TestFindFunctions_KeywordNamedMethodsKept(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tests := []struct {
+		lang string
+		code string
+		want string
+	}{
+		{"kotlin", "class Repo {\n    fun data(): Int {\n        return 1\n    }\n}\n", "data"},
+		{"dart", "class Book {\n  void part() {\n    print(1);\n  }\n}\n", "part"},
+		{"php", "<?php\nclass Doc {\n    public function print() {\n        echo 1;\n    }\n}\n", "print"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			langConfig, err := config.GetLanguageConfig(tt.lang)
+			if err != nil {
+				t.Fatalf("GetLanguageConfig() error = %v", err)
+			}
+			result, err := NewFinder(langConfig, nil, true, false, false).FindFunctionsInLines(strings.Split(tt.code, "\n"), 0, "x")
+			if err != nil {
+				t.Fatalf("FindFunctionsInLines() error = %v", err)
+			}
+			var names []string
+			for _, fn := range result.Functions {
+				names = append(names, fn.Name)
+			}
+			if len(names) != 1 || names[0] != tt.want {
+				t.Errorf("functions = %v, want [%s]", names, tt.want)
+			}
+		})
+	}
+}
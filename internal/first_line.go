@@ -0,0 +1,69 @@
+// first_line.go - The opening line of each function as written (--first-line)
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AttachFirstLines sets FunctionBounds.FirstLine from lines, the whole file
+// (Start is a 1-based line number into it)
+func AttachFirstLines(functions []FunctionBounds, lines []string) {
+	for i := range functions {
+		if start := functions[i].Start; start >= 1 && start <= len(lines) {
+			functions[i].FirstLine = lines[start-1]
+		}
+	}
+}
+
+// ReadFirstLines sets FunctionBounds.FirstLine by reading path only up to
+// the last function's first line, so --dir mode never keeps whole files
+func ReadFirstLines(path string, functions []FunctionBounds) error {
+	last := 0
+	for _, fn := range functions {
+		last = max(last, fn.Start)
+	}
+	if last == 0 {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return newOpenError(path, err)
+	}
+	defer file.Close()
+
+	lines := make([]string, 0, last)
+	scanner := bufio.NewScanner(file)
+	for len(lines) < last && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return newReadError(path, err)
+	}
+	AttachFirstLines(functions, lines)
+	return nil
+}
+
+// FormatFirstLines writes "name: start-end: first line" per function
+func FormatFirstLines(result *FindResult) string {
+	var sb strings.Builder
+	for _, fn := range result.Functions {
+		fmt.Fprintf(&sb, "%s: %d-%d: %s\n", fn.Name, fn.Start, fn.End, fn.FirstLine)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// FormatDirResultsFirstLines writes "path:start-end: name: first line" per
+// function of every result
+func FormatDirResultsFirstLines(results []DirResult) string {
+	var sb strings.Builder
+	for _, r := range results {
+		for _, fn := range r.Functions {
+			fmt.Fprintf(&sb, "%s:%d-%d: %s: %s\n", r.Path, fn.Start, fn.End, fn.Name, fn.FirstLine)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const firstLineSample = `package shapes
+
+type Circle struct{ r float64 }
+
+func (c Circle) Area() float64 {
+	return 3.14 * c.r * c.r
+}
+
+func New(r float64) Circle {
+	return Circle{r: r}
+}
+`
+
+func TestReadFirstLines_ExactLines(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "shapes.go")
+	mustWrite(t, path, firstLineSample)
+
+	result, err := NewFinder(config["go"], nil, true, false, false).FindFunctions(path)
+	if err != nil {
+		t.Fatalf("FindFunctions() error = %v", err)
+	}
+	if err := ReadFirstLines(path, result.Functions); err != nil {
+		t.Fatalf("ReadFirstLines() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Area": "func (c Circle) Area() float64 {",
+		"New":  "func New(r float64) Circle {",
+	}
+	for _, fn := range result.Functions {
+		if fn.FirstLine != want[fn.Name] {
+			t.Errorf("%s: FirstLine = %q, want %q", fn.Name, fn.FirstLine, want[fn.Name])
+		}
+	}
+}
+
+func TestAttachFirstLines_IndentedMethod(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	code := `class Greeter {
+    public String greet(String name) {
+        return "hi " + name;
+    }
+}`
+	lines := strings.Split(code, "\n")
+	result, err := NewFinder(config["java"], nil, true, false, false).FindFunctionsInLines(lines, 1, "Greeter.java")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	AttachFirstLines(result.Functions, lines)
+
+	if got, want := FormatFirstLines(result), "greet: 2-4:     public String greet(String name) {"; got != want {
+		t.Errorf("FormatFirstLines() = %q, want %q", got, want)
+	}
+
+	dirOut := FormatDirResultsFirstLines([]DirResult{{Path: "Greeter.java", Functions: result.Functions}})
+	if want := "Greeter.java:2-4: greet:     public String greet(String name) {"; dirOut != want {
+		t.Errorf("FormatDirResultsFirstLines() = %q, want %q", dirOut, want)
+	}
+}
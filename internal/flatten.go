@@ -0,0 +1,180 @@
+// flatten.go - Every function at every nesting level as one flat list (--flatten)
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// KindClosure marks an anonymous function literal added by FlattenFunctions
+const KindClosure = "closure"
+
+// FlattenFunctions returns functions together with the closures in their
+// bodies, every entry with its nesting Depth (0 = not inside another
+// function), ordered by start line with outer entries first. Closures are
+// closure_pattern matches (Go func literals, JS/TS arrows and function
+// expressions); they are named after the entry they are in plus ".funcN",
+// numbered per parent: "Outer.func1", "Outer.func1.func1". A closure whose
+// body doesn't open with "{" on its line (x => x + 1) ends on that line.
+// lines are the whole file and Start/End are 1-based line numbers into it.
+func FlattenFunctions(functions []FunctionBounds, lines []string, config *LanguageConfig) []FunctionBounds {
+	flat := append([]FunctionBounds(nil), functions...)
+	for i := range flat {
+		flat[i].Depth = 0
+		for j, o := range functions {
+			if j != i && encloses(o, flat[i], j < i) {
+				flat[i].Depth++
+			}
+		}
+	}
+
+	if closureRe := config.ClosureRegex(); closureRe != nil {
+		cleaned := NewSanitizer(config, false).CleanLines(lines)
+		for i, fn := range functions {
+			flat = append(flat, findClosures(fn, flat[i].Depth, functions, cleaned, closureRe)...)
+		}
+	}
+
+	sort.SliceStable(flat, func(a, b int) bool {
+		if flat[a].Start != flat[b].Start {
+			return flat[a].Start < flat[b].Start
+		}
+		return flat[a].Depth < flat[b].Depth
+	})
+	return flat
+}
+
+// encloses reports whether outer contains inner; of two functions with the
+// same span the first one (earlier) is the outer one
+func encloses(outer, inner FunctionBounds, earlier bool) bool {
+	if outer.Start > inner.Start || inner.End > outer.End {
+		return false
+	}
+	return outer.Start != inner.Start || outer.End != inner.End || earlier
+}
+
+// findClosures scans the body of fn, after its first "{", for closures.
+// Lines of named functions nested in fn are skipped: their closures are
+// found when that function is scanned.
+func findClosures(fn FunctionBounds, depth int, functions []FunctionBounds, cleaned []string, closureRe *regexp.Regexp) []FunctionBounds {
+	type openClosure struct {
+		idx   int // in closures
+		level int // brace level outside the closure's body
+	}
+	var closures []FunctionBounds
+	var stack []openClosure
+	counts := map[string]int{}
+	bodyStarted := false
+	level := 0
+
+	for lineNum := fn.Start; lineNum <= fn.End && lineNum-1 < len(cleaned); lineNum++ {
+		if lineNum > fn.Start && insideNested(lineNum, fn, functions) {
+			continue
+		}
+		text := cleaned[lineNum-1]
+		offset := 0
+		if !bodyStarted {
+			brace := strings.Index(text, "{")
+			if brace < 0 {
+				continue
+			}
+			bodyStarted = true
+			offset = brace + 1
+		}
+
+		matches := closureRe.FindAllStringIndex(text[offset:], -1)
+		var pending []int // closures waiting for the "{" of their body
+		next := 0
+		for pos := offset; pos < len(text); pos++ {
+			for next < len(matches) && matches[next][0]+offset == pos {
+				parent, parentDepth := fn.Name, depth
+				if len(stack) > 0 {
+					top := closures[stack[len(stack)-1].idx]
+					parent, parentDepth = top.Name, top.Depth
+				}
+				counts[parent]++
+				closures = append(closures, FunctionBounds{
+					Name:  fmt.Sprintf("%s.func%d", parent, counts[parent]),
+					Start: lineNum,
+					End:   lineNum,
+					Lines: []string{},
+					Kind:  KindClosure,
+					Depth: parentDepth + 1,
+				})
+				pending = append(pending, len(closures)-1)
+				next++
+			}
+			switch text[pos] {
+			case '{':
+				if len(pending) > 0 {
+					stack = append(stack, openClosure{idx: pending[len(pending)-1], level: level})
+					pending = pending[:len(pending)-1]
+				}
+				level++
+			case '}':
+				level--
+				if len(stack) > 0 && stack[len(stack)-1].level == level {
+					closures[stack[len(stack)-1].idx].End = lineNum
+					stack = stack[:len(stack)-1]
+				}
+			}
+		}
+	}
+	// A body left open by a truncated function ends with it
+	for _, c := range stack {
+		closures[c.idx].End = fn.End
+	}
+	return closures
+}
+
+// insideNested reports whether line belongs to a named function nested in fn
+func insideNested(line int, fn FunctionBounds, functions []FunctionBounds) bool {
+	for _, o := range functions {
+		if o.Start == fn.Start && o.End == fn.End {
+			continue
+		}
+		if fn.Start <= o.Start && o.End <= fn.End && o.Start <= line && line <= o.End {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatFlat prints one line per entry of FlattenFunctions
+// Example: Outer: 3-12 depth 0
+func FormatFlat(result *FindResult) string {
+	var sb strings.Builder
+	for _, fn := range result.Functions {
+		fmt.Fprintf(&sb, "%s: %d-%d depth %d\n", fn.Name, fn.Start, fn.End, fn.Depth)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// flatJSONFunction is one entry of FormatFlatJSON
+type flatJSONFunction struct {
+	Name  string `json:"name"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Depth int    `json:"depth"`
+	Kind  string `json:"kind,omitempty"`
+	Class string `json:"class,omitempty"`
+}
+
+// FormatFlatJSON is --flatten --json: {"functions": [...]} in the order of
+// FlattenFunctions, so entries with the same name are all kept
+func FormatFlatJSON(result *FindResult) (string, error) {
+	out := struct {
+		Functions []flatJSONFunction `json:"functions"`
+	}{Functions: make([]flatJSONFunction, 0, len(result.Functions))}
+	for _, fn := range result.Functions {
+		out.Functions = append(out.Functions, flatJSONFunction{Name: fn.Name, Start: fn.Start, End: fn.End, Depth: fn.Depth, Kind: fn.Kind, Class: fn.ClassName})
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(data), nil
+}
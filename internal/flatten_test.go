@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// flatEntries flattens the functions of src and renders each entry as
+// "name start-end depth"
+func flatEntries(t *testing.T, config *LanguageConfig, src string) []string {
+	t.Helper()
+	lines := strings.Split(src, "\n")
+	result, err := NewFinder(config, nil, true, false, false).FindFunctionsInLines(lines, 1, "test")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	var entries []string
+	for _, fn := range FlattenFunctions(result.Functions, lines, config) {
+		entries = append(entries, fmt.Sprintf("%s %d-%d %d", fn.Name, fn.Start, fn.End, fn.Depth))
+	}
+	return entries
+}
+
+func TestFlattenFunctions_GoClosures(t *testing.T) {
+	src := `package p
+
+func Outer() {
+	f := func() {
+		_ = "func() {"
+	}
+	g := func(x int) int {
+		return x
+	}
+	f()
+	_ = g
+}
+`
+	got := flatEntries(t, getGoConfig(t), src)
+	want := []string{"Outer 3-12 0", "Outer.func1 4-6 1", "Outer.func2 7-9 1"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("entries:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFlattenFunctions_NestedJSFunctionsAndClosures(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	src := `function outer(items) {
+  const inner = (x) => {
+    return items.map(y => y + x);
+  };
+  items.forEach(function (i) {
+    setTimeout(() => {
+      inner(i);
+    }, 0);
+  });
+}
+`
+	got := flatEntries(t, config["js"], src)
+	want := []string{
+		"outer 1-10 0",
+		"inner 2-4 1",
+		"inner.func1 3-3 2",
+		"outer.func1 5-9 1",
+		"outer.func1.func1 6-8 2",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("entries:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
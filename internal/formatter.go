@@ -19,6 +19,45 @@ func FormatGrepStyle(result *FindResult) string {
 	return strings.Join(parts, "; ") + ";"
 }
 
+// TopLevelSection - заголовок секции функций без класса в FormatGroupedByClass
+const TopLevelSection = "(top-level)"
+
+// FormatGroupedByClass группирует функции по ClassName (--group-by-class):
+// заголовок класса, под ним его методы с отступом, в порядке появления
+// классов; функции без класса идут последней секцией "(top-level)".
+// Пример:
+//
+//	Server:
+//	  Run: 45-78
+//	(top-level):
+//	  main: 1-12
+func FormatGroupedByClass(result *FindResult) string {
+	var order []string
+	groups := make(map[string][]FunctionBounds)
+	for _, fn := range result.Functions {
+		name := fn.ClassName
+		if name == "" {
+			name = TopLevelSection
+		}
+		if _, ok := groups[name]; !ok && name != TopLevelSection {
+			order = append(order, name)
+		}
+		groups[name] = append(groups[name], fn)
+	}
+	if _, ok := groups[TopLevelSection]; ok {
+		order = append(order, TopLevelSection)
+	}
+
+	var sb strings.Builder
+	for _, name := range order {
+		sb.WriteString(name + ":\n")
+		for _, fn := range groups[name] {
+			fmt.Fprintf(&sb, "  %s: %d-%d\n", fn.Name, fn.Start, fn.End)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
 // JSONOutput представляет JSON-вывод
 type JSONOutput map[string]map[string]interface{}
 
@@ -27,15 +66,7 @@ type JSONOutput map[string]map[string]interface{}
 func FormatJSON(result *FindResult) (string, error) {
 	output := make(JSONOutput)
 	for _, fn := range result.Functions {
-		fnData := map[string]interface{}{
-			"start": fn.Start,
-			"end":   fn.End,
-		}
-		// Добавляем декораторы, если они есть
-		if len(fn.Decorators) > 0 {
-			fnData["decorators"] = fn.Decorators
-		}
-		output[fn.Name] = fnData
+		output[fn.Name] = functionJSONData(fn)
 	}
 
 	data, err := json.MarshalIndent(output, "", "  ")
@@ -45,6 +76,110 @@ func FormatJSON(result *FindResult) (string, error) {
 	return string(data), nil
 }
 
+// FormatJSONOrdered форматирует результат в JSON-массив в порядке
+// result.Functions (--sort --json): у объекта по имени порядка нет
+// Пример: {"functions": [{"name": "Handler", "start": 45, "end": 78}]}
+func FormatJSONOrdered(result *FindResult) (string, error) {
+	functions := make([]map[string]interface{}, 0, len(result.Functions))
+	for _, fn := range result.Functions {
+		fnData := functionJSONData(fn)
+		fnData["name"] = fn.Name
+		functions = append(functions, fnData)
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{"functions": functions}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// functionJSONData - поля функции в JSON-выводе (без имени)
+func functionJSONData(fn FunctionBounds) map[string]interface{} {
+	fnData := map[string]interface{}{
+		"start": fn.Start,
+		"end":   fn.End,
+	}
+	// Добавляем декораторы, если они есть
+	if len(fn.Decorators) > 0 {
+		fnData["decorators"] = fn.Decorators
+	}
+	if fn.Kind != "" {
+		fnData["kind"] = fn.Kind
+	}
+	if fn.Category != "" {
+		fnData["category"] = fn.Category
+	}
+	if fn.ClosureCount > 0 {
+		fnData["closure_count"] = fn.ClosureCount
+	}
+	if fn.Doc != "" {
+		fnData["doc"] = fn.Doc
+	}
+	if fn.IsGenerator {
+		fnData["generator"] = true
+	}
+	if fn.LineStats != nil {
+		fnData["line_stats"] = fn.LineStats
+	}
+	if fn.FirstLine != "" {
+		fnData["first_line"] = fn.FirstLine
+	}
+	if fn.ParamCount > 0 {
+		fnData["param_count"] = fn.ParamCount
+	}
+	if fn.ReturnCount > 0 {
+		fnData["return_count"] = fn.ReturnCount
+	}
+	if fn.IsAbstract {
+		fnData["abstract"] = true
+	}
+	if fn.ScopePath != "" {
+		fnData["scope_path"] = fn.ScopePath
+	}
+	if fn.IsDeprecated {
+		fnData["deprecated"] = true
+	}
+	if fn.ByteEnd > 0 {
+		fnData["byte_start"] = fn.ByteStart
+		fnData["byte_end"] = fn.ByteEnd
+	}
+	if fn.StringLiterals > 0 {
+		fnData["string_literals"] = fn.StringLiterals
+	}
+	if fn.NumericLiterals > 0 {
+		fnData["numeric_literals"] = fn.NumericLiterals
+	}
+	if fn.Assertions != nil {
+		fnData["assertions"] = *fn.Assertions
+	}
+	return fnData
+}
+
+// FormatLineStats форматирует таблицу строк кода/комментариев/пустых (--stats)
+// Пример:
+// NAME     LINES  CODE  COMMENT  BLANK
+// Handler  45-78    28        3      3
+func FormatLineStats(result *FindResult) string {
+	nameWidth := len("NAME")
+	for _, fn := range result.Functions {
+		if len(fn.Name) > nameWidth {
+			nameWidth = len(fn.Name)
+		}
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-*s  %-9s  %5s  %7s  %5s\n", nameWidth, "NAME", "LINES", "CODE", "COMMENT", "BLANK")
+	for _, fn := range result.Functions {
+		stats := LineStats{}
+		if fn.LineStats != nil {
+			stats = *fn.LineStats
+		}
+		fmt.Fprintf(&sb, "%-*s  %-9s  %5d  %7d  %5d\n", nameWidth, fn.Name,
+			fmt.Sprintf("%d-%d", fn.Start, fn.End), stats.CodeLines, stats.CommentLines, stats.BlankLines)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
 // FormatExtract форматирует результат с телами функций
 // Пример:
 // // Handler: 45-78
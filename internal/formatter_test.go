@@ -73,6 +73,25 @@ func TestFormatGrepStyle(t *testing.T) {
 	}
 }
 
+func TestFormatGroupedByClass(t *testing.T) {
+	result := &FindResult{Functions: []FunctionBounds{
+		{Name: "main", Start: 1, End: 5},
+		{Name: "Run", Start: 10, End: 20, ClassName: "Server"},
+		{Name: "Fetch", Start: 25, End: 30, ClassName: "Client"},
+		{Name: "helper", Start: 32, End: 34},
+		{Name: "Stop", Start: 40, End: 44, ClassName: "Server"},
+	}}
+
+	want := "Server:\n  Run: 10-20\n  Stop: 40-44\nClient:\n  Fetch: 25-30\n(top-level):\n  main: 1-5\n  helper: 32-34"
+	if got := FormatGroupedByClass(result); got != want {
+		t.Errorf("FormatGroupedByClass() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := FormatGroupedByClass(&FindResult{Functions: []FunctionBounds{{Name: "f", Start: 1, End: 2}}}); got != "(top-level):\n  f: 1-2" {
+		t.Errorf("FormatGroupedByClass() without classes = %q", got)
+	}
+}
+
 func TestFormatJSON(t *testing.T) {
 	tests := []struct {
 		name        string
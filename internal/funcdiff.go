@@ -0,0 +1,159 @@
+// funcdiff.go - Functions changed between two versions of a file (--changed-functions)
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Change statuses of a FunctionChange
+const (
+	ChangeModified  = "modified"  // the normalized body differs
+	ChangeMoved     = "moved"     // same body, other lines
+	ChangeUnchanged = "unchanged" // same body on the same lines
+	ChangeAdded     = "added"     // only in the new version
+	ChangeRemoved   = "removed"   // only in the old version
+)
+
+// FunctionChange is one function of a DiffFunctions result; the line range
+// of the side it is missing from is 0
+type FunctionChange struct {
+	Name      string `json:"name"`
+	ClassName string `json:"class,omitempty"`
+	Status    string `json:"status"`
+	OldStart  int    `json:"old_start,omitempty"`
+	OldEnd    int    `json:"old_end,omitempty"`
+	NewStart  int    `json:"new_start,omitempty"`
+	NewEnd    int    `json:"new_end,omitempty"`
+}
+
+// DiffFunctions pairs the functions of two versions of a file by ClassName
+// and Name (overloads in source order) and compares the bodies, which must
+// have been extracted (fn.Lines), normalized by normalizedBody. Matched
+// functions are modified, moved or unchanged; the rest are added or
+// removed. Changes are in the order of the new version, removed last.
+func DiffFunctions(oldFuncs, newFuncs []FunctionBounds, config *LanguageConfig) []FunctionChange {
+	sanitizer := NewSanitizer(config, false)
+	sanitizer.keepStrings = true
+
+	pending := make(map[string][]FunctionBounds)
+	for _, fn := range oldFuncs {
+		key := fn.ClassName + "." + fn.Name
+		pending[key] = append(pending[key], fn)
+	}
+
+	changes := make([]FunctionChange, 0, len(newFuncs))
+	for _, fn := range newFuncs {
+		change := FunctionChange{Name: fn.Name, ClassName: fn.ClassName, Status: ChangeAdded, NewStart: fn.Start, NewEnd: fn.End}
+		key := fn.ClassName + "." + fn.Name
+		if olds := pending[key]; len(olds) > 0 {
+			old := olds[0]
+			pending[key] = olds[1:]
+			change.OldStart, change.OldEnd = old.Start, old.End
+			switch {
+			case normalizedBody(sanitizer, old.Lines) != normalizedBody(sanitizer, fn.Lines):
+				change.Status = ChangeModified
+			case old.Start != fn.Start || old.End != fn.End:
+				change.Status = ChangeMoved
+			default:
+				change.Status = ChangeUnchanged
+			}
+		}
+		changes = append(changes, change)
+	}
+	for _, fn := range oldFuncs {
+		key := fn.ClassName + "." + fn.Name
+		if olds := pending[key]; len(olds) > 0 && olds[0].Start == fn.Start {
+			pending[key] = olds[1:]
+			changes = append(changes, FunctionChange{Name: fn.Name, ClassName: fn.ClassName, Status: ChangeRemoved, OldStart: fn.Start, OldEnd: fn.End})
+		}
+	}
+	return changes
+}
+
+// normalizedBody joins the body with comments blanked and every run of
+// whitespace collapsed to one space, so reindenting or recommenting a
+// function does not change it. String literals are kept: a changed message
+// is a changed implementation.
+func normalizedBody(sanitizer *Sanitizer, lines []string) string {
+	var fields []string
+	for _, line := range sanitizer.CleanLines(lines) {
+		fields = append(fields, strings.Fields(line)...)
+	}
+	return strings.Join(fields, " ")
+}
+
+// changeOrder is the order of the DiffFunctions statuses in the output,
+// modified first
+var changeOrder = []string{ChangeModified, ChangeMoved, ChangeAdded, ChangeRemoved, ChangeUnchanged}
+
+// jsonChanges is the --json document of FormatFunctionChanges, one array
+// per status in changeOrder
+type jsonChanges struct {
+	Modified  []FunctionChange `json:"modified"`
+	Moved     []FunctionChange `json:"moved"`
+	Added     []FunctionChange `json:"added"`
+	Removed   []FunctionChange `json:"removed"`
+	Unchanged []FunctionChange `json:"unchanged"`
+}
+
+// FormatFunctionChanges prints changes grouped by status, modified
+// functions first. Text lists every group but unchanged, which is only
+// counted; JSON is an object of the groups, each an array.
+func FormatFunctionChanges(changes []FunctionChange, jsonOut bool) (string, error) {
+	groups := make(map[string][]FunctionChange)
+	for _, c := range changes {
+		groups[c.Status] = append(groups[c.Status], c)
+	}
+
+	if jsonOut {
+		group := func(status string) []FunctionChange {
+			return append([]FunctionChange{}, groups[status]...)
+		}
+		out := jsonChanges{
+			Modified:  group(ChangeModified),
+			Moved:     group(ChangeMoved),
+			Added:     group(ChangeAdded),
+			Removed:   group(ChangeRemoved),
+			Unchanged: group(ChangeUnchanged),
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return string(data), nil
+	}
+
+	var sb strings.Builder
+	for _, status := range changeOrder[:len(changeOrder)-1] {
+		if len(groups[status]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s (%d):\n", strings.ToUpper(status[:1])+status[1:], len(groups[status]))
+		for _, c := range groups[status] {
+			fmt.Fprintf(&sb, "  %s: %s\n", changeName(c), changeLines(c))
+		}
+	}
+	fmt.Fprintf(&sb, "Unchanged: %d", len(groups[ChangeUnchanged]))
+	return sb.String(), nil
+}
+
+// changeName is "Class.method", or the bare name of a top-level function
+func changeName(c FunctionChange) string {
+	if c.ClassName != "" {
+		return c.ClassName + "." + c.Name
+	}
+	return c.Name
+}
+
+// changeLines is the new line range, with the old one when it differs
+func changeLines(c FunctionChange) string {
+	switch {
+	case c.NewStart == 0:
+		return fmt.Sprintf("%d-%d", c.OldStart, c.OldEnd)
+	case c.OldStart == 0 || (c.OldStart == c.NewStart && c.OldEnd == c.NewEnd):
+		return fmt.Sprintf("%d-%d", c.NewStart, c.NewEnd)
+	}
+	return fmt.Sprintf("%d-%d (was %d-%d)", c.NewStart, c.NewEnd, c.OldStart, c.OldEnd)
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func findGoBodies(t *testing.T, src string) []FunctionBounds {
+	t.Helper()
+	result, err := NewFinder(getGoConfig(t), nil, true, true, false).FindFunctionsInLines(strings.Split(src, "\n"), 1, "p.go")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	return result.Functions
+}
+
+func TestDiffFunctions_ModifiedMovedUnchanged(t *testing.T) {
+	oldSrc := "package p\n\nfunc Kept() int { return 1 }\n\n// Sum adds\nfunc Sum(a, b int) int {\n\treturn a + b\n}\n\nfunc Greet() string {\n\treturn \"hello\"\n}\n\nfunc Scale(x int) int {\n\treturn x * 2\n}\n\nfunc Gone() {}\n"
+	newSrc := "package p\n\nfunc Kept() int { return 1 }\n\n\n// Sum adds two numbers\nfunc Sum(a, b int) int {\n\t// reindented only\n\t\treturn a  +  b\n}\n\nfunc Greet() string {\n\treturn \"bye\"\n}\n\nfunc Scale(x int) int {\n\treturn x * 3\n}\n\nfunc Fresh() {}\n"
+	config := getGoConfig(t)
+	changes := DiffFunctions(findGoBodies(t, oldSrc), findGoBodies(t, newSrc), config)
+
+	want := map[string]string{
+		"Kept":  ChangeUnchanged,
+		"Sum":   ChangeMoved,
+		"Greet": ChangeModified,
+		"Scale": ChangeModified,
+		"Fresh": ChangeAdded,
+		"Gone":  ChangeRemoved,
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(changes), len(want), changes)
+	}
+	for _, c := range changes {
+		if c.Status != want[c.Name] {
+			t.Errorf("%s: status %q, want %q", c.Name, c.Status, want[c.Name])
+		}
+	}
+}
+
+func TestDiffFunctions_PairsByClassAndName(t *testing.T) {
+	oldFuncs := []FunctionBounds{
+		{Name: "run", ClassName: "A", Start: 2, End: 3, Lines: []string{"def run(self):", "    return 1"}},
+		{Name: "run", ClassName: "B", Start: 5, End: 6, Lines: []string{"def run(self):", "    return 2"}},
+	}
+	newFuncs := []FunctionBounds{
+		{Name: "run", ClassName: "B", Start: 2, End: 3, Lines: []string{"def run(self):", "    return 2"}},
+		{Name: "run", ClassName: "A", Start: 5, End: 6, Lines: []string{"def run(self):", "    return 1"}},
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	for _, c := range DiffFunctions(oldFuncs, newFuncs, config["py"]) {
+		if c.Status != ChangeMoved {
+			t.Errorf("%s.%s: status %q, want moved", c.ClassName, c.Name, c.Status)
+		}
+	}
+}
+
+func TestFormatFunctionChanges_ModifiedFirst(t *testing.T) {
+	changes := []FunctionChange{
+		{Name: "Kept", Status: ChangeUnchanged, OldStart: 1, OldEnd: 1, NewStart: 1, NewEnd: 1},
+		{Name: "Fresh", Status: ChangeAdded, NewStart: 3, NewEnd: 4},
+		{Name: "load", ClassName: "Store", Status: ChangeModified, OldStart: 5, OldEnd: 9, NewStart: 6, NewEnd: 12},
+	}
+	got, err := FormatFunctionChanges(changes, false)
+	if err != nil {
+		t.Fatalf("FormatFunctionChanges() error = %v", err)
+	}
+	want := "Modified (1):\n  Store.load: 6-12 (was 5-9)\nAdded (1):\n  Fresh: 3-4\nUnchanged: 1"
+	if got != want {
+		t.Errorf("FormatFunctionChanges() =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = FormatFunctionChanges(changes, true)
+	if err != nil {
+		t.Fatalf("FormatFunctionChanges(json) error = %v", err)
+	}
+	if !strings.HasPrefix(got, "{\n  \"modified\": [") || !strings.Contains(got, `"removed": []`) {
+		t.Errorf("JSON output =\n%s", got)
+	}
+}
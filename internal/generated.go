@@ -0,0 +1,45 @@
+// generated.go - Detecting generated source files (--ignore-generated)
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// DefaultGeneratedMarkers are the header markers --ignore-generated looks
+// for when no --generated-marker is given: the Go convention ("// Code
+// generated by X. DO NOT EDIT."), Phabricator/Meta "@generated" and the
+// "Generated by" banner of most other generators.
+var DefaultGeneratedMarkers = []string{"Code generated", "DO NOT EDIT", "@generated", "Generated by"}
+
+// generatedHeaderLines is how many leading lines IsGenerated inspects;
+// generators put their banner at the very top, after a shebang or license
+// line at most
+const generatedHeaderLines = 5
+
+// IsGenerated reports whether one of the first lines of data contains any
+// of markers
+func IsGenerated(data []byte, markers []string) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 0; n < generatedHeaderLines && scanner.Scan(); n++ {
+		line := scanner.Text()
+		for _, marker := range markers {
+			if marker != "" && strings.Contains(line, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CountGenerated returns the number of results skipped as generated
+func CountGenerated(results []DirResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Generated {
+			n++
+		}
+	}
+	return n
+}
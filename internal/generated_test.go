@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProcessDirectory_IgnoreGenerated(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "api.pb.go"), "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: api.proto\n\npackage api\n\nfunc (x *Request) Reset() {}\n")
+	mustWrite(t, filepath.Join(tmpDir, "server.go"), "package api\n\nfunc Serve() {}\n")
+
+	dp := NewDirProcessor(config, 2, true, false, "functions")
+	dp.SetGeneratedMarkers(DefaultGeneratedMarkers)
+	results, err := dp.ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+
+	for _, r := range results {
+		generated := filepath.Base(r.Path) == "api.pb.go"
+		if r.Generated != generated {
+			t.Errorf("%s: Generated = %v, want %v", r.Path, r.Generated, generated)
+		}
+		if generated && len(r.Functions) != 0 {
+			t.Errorf("generated file was parsed: %+v", r.Functions)
+		}
+	}
+
+	summary := SummarizeDirResults(results)
+	if summary.TotalFiles != 1 || summary.TotalFunctions != 1 || summary.SkippedGenerated != 1 {
+		t.Errorf("summary = %+v, want 1 file, 1 function, 1 skipped", summary)
+	}
+	if out := FormatDirSummary(summary, false); !strings.Contains(out, "Skipped (generated): 1") {
+		t.Errorf("FormatDirSummary() = %q, want the skipped count", out)
+	}
+}
+
+func TestIsGenerated_HeaderOnly(t *testing.T) {
+	header := "#!/usr/bin/env python3\n# Generated by tool 1.2\n"
+	if !IsGenerated([]byte(header+"def f():\n    pass\n"), DefaultGeneratedMarkers) {
+		t.Error("header marker after a shebang was not detected")
+	}
+
+	// A marker deep in the file is a comment about generated code, not a banner
+	late := "package x\n\n\n\n\n\n// DO NOT EDIT the table below by hand\n"
+	if IsGenerated([]byte(late), DefaultGeneratedMarkers) {
+		t.Error("marker past the header lines was taken for a generated file")
+	}
+
+	if !IsGenerated([]byte("/* autogen: schema v2 */\n"), []string{"autogen:"}) {
+		t.Error("custom marker was not detected")
+	}
+}
@@ -0,0 +1,48 @@
+// generators.go - Detecting generator functions (Python yield, JS function*)
+package internal
+
+import "regexp"
+
+// markGenerators sets IsGenerator for every function with a
+// generator_pattern match in its own lines: lines of functions nested inside
+// it are skipped, so an outer function is not a generator because an inner
+// one yields. cleaned are sanitized lines (strings and comments blanked) of
+// the same slice the functions were found in.
+func markGenerators(functions []FunctionBounds, cleaned []string, lineOffset int, generatorRe *regexp.Regexp) {
+	for i := range functions {
+		fn := &functions[i]
+		for lineNum := fn.Start; lineNum <= fn.End && !fn.IsGenerator; lineNum++ {
+			idx := lineNum - 1 - lineOffset
+			if idx < 0 || idx >= len(cleaned) || inNestedFunction(functions, i, lineNum) {
+				continue
+			}
+			fn.IsGenerator = generatorRe.MatchString(cleaned[idx])
+		}
+	}
+}
+
+// inNestedFunction reports whether lineNum lies in a function nested
+// strictly inside functions[outer]
+func inNestedFunction(functions []FunctionBounds, outer, lineNum int) bool {
+	o := functions[outer]
+	for j, fn := range functions {
+		if j == outer || fn.Start < o.Start || fn.End > o.End || (fn.Start == o.Start && fn.End == o.End) {
+			continue
+		}
+		if lineNum >= fn.Start && lineNum <= fn.End {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterGenerators keeps only generator functions (--generators-only)
+func FilterGenerators(functions []FunctionBounds) []FunctionBounds {
+	filtered := make([]FunctionBounds, 0, len(functions))
+	for _, fn := range functions {
+		if fn.IsGenerator {
+			filtered = append(filtered, fn)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func findGenerators(t *testing.T, lang, code string) map[string]bool {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	finder := CreateFinder(config[lang], "", "map", false, false)
+	result, err := finder.FindFunctionsInReader(strings.NewReader(code), "test."+lang)
+	if err != nil {
+		t.Fatalf("FindFunctionsInReader() error = %v", err)
+	}
+	generators := make(map[string]bool)
+	for _, fn := range result.Functions {
+		generators[fn.Name] = fn.IsGenerator
+	}
+	return generators
+}
+
+func TestMarkGenerators_Python(t *testing.T) {
+	code := `def count(n):
+    for i in range(n):
+        yield i
+
+def regular(n):
+    s = "yield"  # yield
+    def inner():
+        yield from range(n)
+    return list(inner())
+`
+	got := findGenerators(t, "py", code)
+	want := map[string]bool{"count": true, "regular": false, "inner": true}
+	for name, isGen := range want {
+		if gen, ok := got[name]; !ok || gen != isGen {
+			t.Errorf("%s: IsGenerator = %v (found %v), want %v", name, gen, ok, isGen)
+		}
+	}
+}
+
+func TestMarkGenerators_JavaScript(t *testing.T) {
+	code := `function* ids() {
+  yield 1;
+}
+
+export async function *pages(url) {
+  yield await fetch(url);
+}
+
+function plain(a) {
+  return a * 2;
+}
+`
+	got := findGenerators(t, "js", code)
+	want := map[string]bool{"ids": true, "pages": true, "plain": false}
+	for name, isGen := range want {
+		if gen, ok := got[name]; !ok || gen != isGen {
+			t.Errorf("%s: IsGenerator = %v (found %v), want %v", name, gen, ok, isGen)
+		}
+	}
+
+	filtered := FilterGenerators([]FunctionBounds{{Name: "a", IsGenerator: true}, {Name: "b"}})
+	if len(filtered) != 1 || filtered[0].Name != "a" {
+		t.Errorf("FilterGenerators() = %+v, want only a", filtered)
+	}
+}
+
+func TestSetSkipGenerators(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	code := map[string]string{"js": "function* gen() {\n  yield 1;\n}\n", "py": "def gen():\n    yield 1\n"}
+	for lang, src := range code {
+		finder := CreateFinder(config[lang], "", "map", false, false)
+		finder.(GeneratorSkipper).SetSkipGenerators(true)
+		result, err := finder.FindFunctionsInReader(strings.NewReader(src), "test."+lang)
+		if err != nil {
+			t.Fatalf("%s: FindFunctionsInReader() error = %v", lang, err)
+		}
+		if len(result.Functions) != 1 || result.Functions[0].IsGenerator {
+			t.Errorf("%s: SetSkipGenerators(true): functions = %+v, want gen without IsGenerator", lang, result.Functions)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// gitchanged.go - Files changed since a git ref (--since)
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ChangedFiles returns the absolute paths of files changed between ref and
+// HEAD ("git diff --name-only ref...HEAD") in the repository containing
+// dir. The error covers everything that keeps git from answering: git not
+// installed, dir outside a work tree, an unknown ref. A ref starting with
+// "-" is rejected before git sees it, so it can't pass as an option.
+func ChangedFiles(dir, ref string) (map[string]bool, error) {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid git ref %q", ref)
+	}
+	top, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	top = strings.TrimSpace(top)
+	if _, err := runGit(dir, "rev-parse", "--verify", "--end-of-options", ref+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown git ref %q: %w", ref, err)
+	}
+
+	// -z: names are NUL-terminated and not quoted, whatever they contain
+	out, err := runGit(dir, "diff", "--name-only", "-z", ref+"...HEAD", "--")
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[string]bool)
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			changed[filepath.Join(top, filepath.FromSlash(name))] = true
+		}
+	}
+	return changed, nil
+}
+
+// runGit runs git in dir and returns its stdout; stderr goes into the error
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
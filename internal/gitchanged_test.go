@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fakeGit puts a "git" script on PATH that reports root as the work tree,
+// accepts every ref and prints names as the output of git diff -z
+func fakeGit(t *testing.T, root string, names ...string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake git is a shell script")
+	}
+	bin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"case \"$3\" in\n" +
+		"rev-parse) echo '" + root + "' ;;\n" +
+		"diff) printf '%s\\0' '" + strings.Join(names, "' '") + "' ;;\n" +
+		"*) exit 1 ;;\n" +
+		"esac\n"
+	if err := os.WriteFile(filepath.Join(bin, "git"), []byte(script), 0755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestProcessDirectory_SinceChangedFiles(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("EvalSymlinks() error = %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "pkg"), 0755); err != nil {
+		t.Fatalf("Mkdir() error = %v", err)
+	}
+	mustWrite(t, filepath.Join(root, "main.go"), "package main\n\nfunc main() {}\n")
+	mustWrite(t, filepath.Join(root, "pkg", "a.go"), "package pkg\n\nfunc A() {}\n")
+	mustWrite(t, filepath.Join(root, "pkg", "b.go"), "package pkg\n\nfunc B() {}\n")
+	// Deleted and unsupported files in the diff are simply never walked
+	fakeGit(t, root, "pkg/a.go", "README.md", "gone.go", "docs/my notes.txt")
+
+	changed, err := ChangedFiles(filepath.Join(root, "pkg"), "main")
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	if !changed[filepath.Join(root, "pkg", "a.go")] || !changed[filepath.Join(root, "docs", "my notes.txt")] || len(changed) != 4 {
+		t.Fatalf("ChangedFiles() = %v", changed)
+	}
+
+	dp := NewDirProcessor(config, 2, true, false, "functions")
+	dp.SetOnlyFiles(changed)
+	results, err := dp.ProcessDirectory(root)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+	var got []string
+	for _, r := range results {
+		rel, _ := filepath.Rel(root, r.Path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+	if strings.Join(got, ",") != "pkg/a.go" {
+		t.Errorf("scanned %v, want only pkg/a.go", got)
+	}
+}
+
+func TestChangedFiles_NoGit(t *testing.T) {
+	// no git on PATH stands in for every case git cannot answer
+	t.Setenv("PATH", t.TempDir())
+	if _, err := ChangedFiles(t.TempDir(), "main"); err == nil {
+		t.Error("ChangedFiles() error = nil without git, want error")
+	}
+}
+
+func TestChangedFiles_RejectsOptionLikeRef(t *testing.T) {
+	root := t.TempDir()
+	fakeGit(t, root)
+	for _, ref := range []string{"--output=/tmp/x", "-p", ""} {
+		if _, err := ChangedFiles(root, ref); err == nil || !strings.Contains(err.Error(), "invalid git ref") {
+			t.Errorf("ChangedFiles(%q) error = %v, want invalid git ref", ref, err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// gitexcludes.go - Ignore sources of git besides .gitignore files (--gitignore-mode)
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GitignoreMode selects the ignore sources of a --dir scan
+type GitignoreMode string
+
+// Gitignore modes: repo reads the .gitignore files of the tree (the
+// default), global the excludes git keeps outside them
+// ($GIT_DIR/info/exclude and core.excludesFile), all both, like git
+// itself, and none neither (--no-gitignore)
+const (
+	GitignoreRepo   GitignoreMode = "repo"
+	GitignoreGlobal GitignoreMode = "global"
+	GitignoreAll    GitignoreMode = "all"
+	GitignoreNone   GitignoreMode = "none"
+)
+
+// ParseGitignoreMode validates a --gitignore-mode value; an empty value
+// means GitignoreRepo
+func ParseGitignoreMode(s string) (GitignoreMode, error) {
+	switch GitignoreMode(s) {
+	case "":
+		return GitignoreRepo, nil
+	case GitignoreRepo, GitignoreGlobal, GitignoreAll, GitignoreNone:
+		return GitignoreMode(s), nil
+	}
+	return "", fmt.Errorf("invalid --gitignore-mode %q (expected repo, global, all or none)", s)
+}
+
+// GitignoreFiles reports whether the mode reads .gitignore files
+func (m GitignoreMode) GitignoreFiles() bool {
+	return m == GitignoreRepo || m == GitignoreAll
+}
+
+// GitExcludes reports whether the mode reads info/exclude and core.excludesFile
+func (m GitignoreMode) GitExcludes() bool {
+	return m == GitignoreGlobal || m == GitignoreAll
+}
+
+// AddGitExcludes loads $GIT_DIR/info/exclude and the global excludes file
+// (core.excludesFile, by default $XDG_CONFIG_HOME/git/ignore) of the
+// repository containing the matcher root. Their patterns are relative to
+// the repository top level, which may be above the root. Outside a
+// repository, and for missing files, nothing is loaded.
+func (m *IgnoreMatcher) AddGitExcludes() {
+	absRoot, err := filepath.Abs(m.root)
+	if err != nil {
+		return
+	}
+	top, gitDir := findGitDir(absRoot)
+	if top == "" {
+		return
+	}
+	prefix, err := filepath.Rel(top, absRoot)
+	if err != nil {
+		return
+	}
+	if prefix = filepath.ToSlash(prefix); prefix == "." {
+		prefix = ""
+	}
+
+	for _, path := range []string{filepath.Join(gitDir, "info", "exclude"), globalExcludesFile(absRoot)} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		first := len(m.patterns)
+		m.parsePatterns(string(data), "")
+		for i := first; i < len(m.patterns); i++ {
+			m.patterns[i].prefix = prefix
+		}
+	}
+}
+
+// findGitDir walks up from dir to the work tree containing it and returns
+// the top level and the git directory; a ".git" file (worktrees,
+// submodules) points to the git directory with "gitdir: <path>"
+func findGitDir(dir string) (top, gitDir string) {
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		if info, err := os.Stat(dotGit); err == nil {
+			if info.IsDir() {
+				return dir, dotGit
+			}
+			if data, err := os.ReadFile(dotGit); err == nil {
+				if path, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:"); ok {
+					path = strings.TrimSpace(path)
+					if !filepath.IsAbs(path) {
+						path = filepath.Join(dir, path)
+					}
+					return dir, path
+				}
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// globalExcludesFile is git's core.excludesFile for the repository at dir,
+// or its default when unset or when git can't be run
+func globalExcludesFile(dir string) string {
+	if out, err := runGit(dir, "config", "--path", "--get", "core.excludesFile"); err == nil && strings.TrimSpace(out) != "" {
+		return strings.TrimSpace(out)
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "git", "ignore")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "git", "ignore")
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTree creates files (relative paths) under root
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+}
+
+func TestAddGitExcludes_InfoExclude(t *testing.T) {
+	repo := t.TempDir()
+	// no global excludes file
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	writeTree(t, repo, map[string]string{
+		".git/info/exclude": "# local excludes\nscratch/\n/pkg/gen.go\n",
+		"pkg/.gitignore":    "",
+	})
+
+	// scanned from the top level and from a subdirectory
+	m := &IgnoreMatcher{root: repo}
+	m.AddGitExcludes()
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"scratch", true, true},
+		{"pkg/scratch", true, true},
+		{"pkg/gen.go", false, true},
+		{"gen.go", false, false},
+		{"pkg/main.go", false, false},
+	}
+	for _, c := range cases {
+		if got := m.Matches(c.path, c.isDir); got != c.want {
+			t.Errorf("root: Matches(%q) = %v, want %v", c.path, got, c.want)
+		}
+	}
+
+	sub := &IgnoreMatcher{root: filepath.Join(repo, "pkg")}
+	sub.AddGitExcludes()
+	if !sub.Matches("gen.go", false) || !sub.Matches("scratch", true) || sub.Matches("main.go", false) {
+		t.Error("pkg: /pkg/gen.go and scratch/ should be ignored relative to the repository top level")
+	}
+}
+
+func TestAddGitExcludes_GlobalFileAndModes(t *testing.T) {
+	repo := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	writeTree(t, xdg, map[string]string{"git/ignore": "*.bak.go\n"})
+	writeTree(t, repo, map[string]string{
+		".git/info/exclude": "local.go\n",
+		".gitignore":        "tracked_ignore.go\n",
+		"main.go":           "package main\n\nfunc main() {}\n",
+		"local.go":          "package main\n\nfunc Local() {}\n",
+		"old.bak.go":        "package main\n\nfunc Old() {}\n",
+		"tracked_ignore.go": "package main\n\nfunc T() {}\n",
+	})
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		mode GitignoreMode
+		want int // files scanned
+	}{
+		{GitignoreRepo, 3},
+		{GitignoreGlobal, 2},
+		{GitignoreAll, 1},
+		{GitignoreNone, 4},
+	} {
+		dp := NewDirProcessor(config, 1, true, tt.mode.GitignoreFiles(), "functions")
+		dp.SetGitExcludes(tt.mode.GitExcludes())
+		jobs, err := dp.collectFiles(repo)
+		if err != nil {
+			t.Fatalf("%s: collectFiles() error = %v", tt.mode, err)
+		}
+		if len(jobs) != tt.want {
+			t.Errorf("%s: %d files scanned, want %d", tt.mode, len(jobs), tt.want)
+		}
+	}
+
+	if _, err := ParseGitignoreMode("local"); err == nil {
+		t.Error("ParseGitignoreMode(\"local\") accepted an unknown mode")
+	}
+}
@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func getGroovyConfig(t *testing.T) *LanguageConfig {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	groovy := config["groovy"]
+	if groovy == nil {
+		t.Fatal("groovy config not found")
+	}
+	return groovy
+}
+
+const groovySample = `plugins {
+    id 'java'
+}
+
+task copyDocs(type: Copy) {
+    from 'src/docs'
+}
+
+tasks.register('hello') {
+    doLast {
+        println "Hello ${project.name} from $version {"
+    }
+}
+
+def versionName(String base) {
+    def notes = """
+    closing } inside ${base}
+    """
+    return "${base}-${'}'}"
+}
+
+class Helper {
+    private static String greet(String name) {
+        return 'hi ' + name + '}'
+    }
+
+    void run() { println greet('x') }
+}
+`
+
+func findGroovy(t *testing.T) map[string]FunctionBounds {
+	t.Helper()
+	finder := NewFinder(getGroovyConfig(t), nil, true, false, false)
+	result, err := finder.FindFunctionsInLines(strings.Split(groovySample, "\n"), 1, "build.gradle")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	byName := make(map[string]FunctionBounds)
+	for _, fn := range result.Functions {
+		byName[fn.Name] = fn
+	}
+	return byName
+}
+
+func TestGroovyDefMethod(t *testing.T) {
+	fn, ok := findGroovy(t)["versionName"]
+	if !ok {
+		t.Fatal("versionName not found")
+	}
+	if fn.Start != 15 || fn.End != 20 {
+		t.Errorf("versionName: got %d-%d, want 15-20", fn.Start, fn.End)
+	}
+	if fn.ClassName != "" {
+		t.Errorf("versionName: ClassName = %q, want none", fn.ClassName)
+	}
+}
+
+func TestGroovyTypedMethodInClass(t *testing.T) {
+	byName := findGroovy(t)
+	for name, want := range map[string][2]int{"greet": {23, 25}, "run": {27, 27}} {
+		fn, ok := byName[name]
+		if !ok {
+			t.Errorf("%s not found", name)
+			continue
+		}
+		if fn.Start != want[0] || fn.End != want[1] {
+			t.Errorf("%s: got %d-%d, want %d-%d", name, fn.Start, fn.End, want[0], want[1])
+		}
+		if fn.ClassName != "Helper" {
+			t.Errorf("%s: ClassName = %q, want Helper", name, fn.ClassName)
+		}
+	}
+}
+
+func TestGroovyGradleBlocksAreNotFunctions(t *testing.T) {
+	byName := findGroovy(t)
+	if len(byName) != 3 {
+		t.Errorf("got functions %v, want versionName, greet and run only", byName)
+	}
+	for _, name := range []string{"plugins", "copyDocs", "register", "hello", "doLast"} {
+		if _, ok := byName[name]; ok {
+			t.Errorf("Gradle block %s reported as a function", name)
+		}
+	}
+}
+
+func TestGroovySanitizerStrings(t *testing.T) {
+	s := NewSanitizer(getGroovyConfig(t), false)
+
+	tests := []struct {
+		line string
+		want int // brace delta after sanitizing
+	}{
+		{`println "total: $n {"`, 0},
+		{`def m = "${lookup("k")} {"`, 0},
+		{`def q = 'single { quoted' ; if (ok) {`, 1},
+		{`def t = '''triple { quoted''' + """gstring { ${x}"""`, 0},
+	}
+	for _, tt := range tests {
+		cleaned, state := s.CleanLine(tt.line, StateNormal)
+		if got := CountBraces(cleaned); got != tt.want || state != StateNormal {
+			t.Errorf("CleanLine(%q) = %q (state %v), brace delta %d, want %d", tt.line, cleaned, state, got, tt.want)
+		}
+	}
+}
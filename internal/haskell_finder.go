@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// HaskellFinder finds top-level bindings in Haskell source using the layout
+// rule instead of braces. A binding starts with a lowercase identifier in
+// column 0 and owns every following indented (or blank) line — its guards,
+// continuation lines and where block. Consecutive groups for the same name
+// (type signature plus one or more equations) are merged into one function.
+type HaskellFinder struct {
+	config      *LanguageConfig
+	sanitizer   *Sanitizer
+	funcNames   map[string]bool
+	mapMode     bool
+	extractMode bool
+}
+
+// NewHaskellFinder creates a finder for layout-based Haskell source
+func NewHaskellFinder(config *LanguageConfig, funcNames []string, mapMode, extractMode bool) *HaskellFinder {
+	nameMap := make(map[string]bool)
+	for _, name := range funcNames {
+		nameMap[name] = true
+	}
+
+	return &HaskellFinder{
+		config:      config,
+		sanitizer:   NewSanitizer(config, false),
+		funcNames:   nameMap,
+		mapMode:     mapMode,
+		extractMode: extractMode,
+	}
+}
+
+// FindFunctions finds top-level bindings in a Haskell file
+func (hf *HaskellFinder) FindFunctions(filename string) (*FindResult, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, newOpenError(filename, err)
+	}
+	defer file.Close()
+
+	return hf.FindFunctionsInReader(file, filename)
+}
+
+// FindFunctionsInReader finds top-level bindings in Haskell source read from r
+func (hf *HaskellFinder) FindFunctionsInReader(r io.Reader, filename string) (*FindResult, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, newReadError(filename, err)
+	}
+
+	return hf.FindFunctionsInLines(lines, 1, filename)
+}
+
+// FindFunctionsInLines finds top-level bindings in pre-read lines.
+// startLine is the 1-based number of lines[0] in the original file.
+func (hf *HaskellFinder) FindFunctionsInLines(lines []string, startLine int, filename string) (*FindResult, error) {
+	regex := hf.config.FuncRegex()
+	if regex == nil {
+		return nil, newFuncRegexError(filename)
+	}
+
+	excluded := make(map[string]bool, len(hf.config.ExcludeWords))
+	for _, w := range hf.config.ExcludeWords {
+		excluded[w] = true
+	}
+
+	// Blank out comments and string literals so "--" or "=" inside them
+	// never affects layout or binding detection.
+	cleaned := make([]string, len(lines))
+	state := StateNormal
+	for i, line := range lines {
+		cleaned[i], state = hf.sanitizer.CleanLine(line, state)
+	}
+
+	var all []FunctionBounds
+	lastDecl := "" // name of the previous top-level declaration ("" if not a binding)
+
+	for i := 0; i < len(cleaned); i++ {
+		line := cleaned[i]
+		if strings.TrimSpace(line) == "" || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+
+		// The group spans every following indented or blank line
+		end := i
+		for j := i + 1; j < len(cleaned); j++ {
+			next := cleaned[j]
+			if strings.TrimSpace(next) == "" {
+				continue
+			}
+			if next[0] != ' ' && next[0] != '\t' {
+				break
+			}
+			end = j
+		}
+
+		matches := regex.FindStringSubmatch(line)
+		name := ""
+		if matches != nil && len(matches) > 1 {
+			name = matches[1]
+		}
+
+		group := strings.Join(cleaned[i:end+1], "\n")
+		if name == "" || excluded[name] || !(strings.Contains(group, "=") || strings.Contains(group, "::")) {
+			lastDecl = ""
+			i = end
+			continue
+		}
+
+		if name == lastDecl && len(all) > 0 {
+			// Another clause (or the equations after a signature) of the same binding
+			all[len(all)-1].End = end + startLine
+		} else {
+			all = append(all, FunctionBounds{
+				Name:  name,
+				Start: i + startLine,
+				End:   end + startLine,
+			})
+		}
+		lastDecl = name
+		i = end
+	}
+
+	functions := make([]FunctionBounds, 0, len(all))
+	for _, fn := range all {
+		if !hf.mapMode && !hf.funcNames[fn.Name] {
+			continue
+		}
+		if hf.extractMode {
+			fn.Lines = lines[fn.Start-startLine : fn.End-startLine+1]
+		}
+		functions = append(functions, fn)
+	}
+
+	return &FindResult{
+		Functions: functions,
+		Classes:   []ClassBounds{},
+		Filename:  filename,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"os"
+	"testing"
+)
+
+func getHsConfig(t *testing.T) *LanguageConfig {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	hsConfig := config["hs"]
+	if hsConfig == nil {
+		t.Fatal("Haskell config not found")
+	}
+	return hsConfig
+}
+
+func TestHaskellFinder_GuardsAndWhere(t *testing.T) {
+	code := `module Main where
+
+import Data.List (sort)
+
+-- | Classify a number
+classify :: Int -> String
+classify n
+  | n < 0     = "negative"
+  | n == 0    = "zero"
+  | otherwise = big n
+  where
+    big x = "positive " ++ show x
+
+data Color = Red | Green
+
+main :: IO ()
+main = putStrLn (classify 3)
+`
+	tmpfile := createTempFile(t, code, "test_*.hs")
+	defer os.Remove(tmpfile)
+
+	finder := CreateFinder(getHsConfig(t), "", "map", false, false)
+	result, err := finder.FindFunctions(tmpfile)
+	if err != nil {
+		t.Fatalf("FindFunctions() error = %v", err)
+	}
+
+	want := []FunctionBounds{
+		{Name: "classify", Start: 6, End: 12},
+		{Name: "main", Start: 16, End: 17},
+	}
+	if len(result.Functions) != len(want) {
+		t.Fatalf("got %d functions, want %d: %+v", len(result.Functions), len(want), result.Functions)
+	}
+	for i, w := range want {
+		got := result.Functions[i]
+		if got.Name != w.Name || got.Start != w.Start || got.End != w.End {
+			t.Errorf("function %d = %s %d-%d, want %s %d-%d", i, got.Name, got.Start, got.End, w.Name, w.Start, w.End)
+		}
+	}
+}
+
+func TestHaskellFinder_MergesClauses(t *testing.T) {
+	lines := []string{
+		"fact :: Integer -> Integer",
+		"fact 0 = 1",
+		"fact n = n * fact (n - 1)",
+		"",
+		"{- block comment",
+		"   fact = broken -}",
+		"double x = x * 2",
+	}
+
+	finder := NewHaskellFinder(getHsConfig(t), []string{"fact"}, false, true)
+	result, err := finder.FindFunctionsInLines(lines, 10, "test.hs")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	if len(result.Functions) != 1 {
+		t.Fatalf("got %d functions, want 1: %+v", len(result.Functions), result.Functions)
+	}
+	fn := result.Functions[0]
+	if fn.Name != "fact" || fn.Start != 10 || fn.End != 12 {
+		t.Errorf("got %s %d-%d, want fact 10-12", fn.Name, fn.Start, fn.End)
+	}
+	if len(fn.Lines) != 3 {
+		t.Errorf("extracted %d lines, want 3", len(fn.Lines))
+	}
+}
@@ -0,0 +1,127 @@
+// import_collector.go - Canonical import lists (stat -normalize-imports)
+package internal
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// goBlockImportRe matches one entry of a Go import block, with optional
+	// alias ("f", "_" or ".")
+	goBlockImportRe = regexp.MustCompile(`^\s*(?:[\w.]+\s+)?"([^"]+)"`)
+	// pyFromImportRe splits "from pkg import a, b as c" into pkg and the names
+	pyFromImportRe = regexp.MustCompile(`^\s*from\s+(\S+)\s+import\s+(.*)$`)
+	// pyImportRe matches "import a.b as c, d"
+	pyImportRe = regexp.MustCompile(`^\s*import\s+(.+)$`)
+)
+
+// ImportCollector gathers the imports of a file line by line, in canonical
+// form: aliases are dropped so every import is named by its package path,
+// and Imports returns the paths sorted and deduplicated.
+//
+// Go entries of an "import (" block count with or without an alias, so
+// `f "fmt"` and `"fmt"` are both "fmt". Python "from pkg import name" is
+// reported as "pkg.name", the same as "import pkg.name": the collector can't
+// tell a submodule from an attribute, so "from os import getcwd" also gives
+// "os.getcwd". "from pkg import *" is "pkg". Other languages keep the
+// import_pattern groups.
+type ImportCollector struct {
+	config   *LanguageConfig
+	importRe *regexp.Regexp
+	inBlock  bool   // inside a Go "import (" block
+	pyFrom   string // package of a "from pkg import (" list spanning lines
+	seen     map[string]bool
+}
+
+// NewImportCollector creates a collector for files of config's language
+func NewImportCollector(config *LanguageConfig) *ImportCollector {
+	return &ImportCollector{config: config, importRe: config.ImportRegex(), seen: make(map[string]bool)}
+}
+
+// Add inspects one line. cleaned is the sanitized line: a line that is all
+// comment or string content is no import, except the quoted entries of a Go
+// import block.
+func (c *ImportCollector) Add(line, cleaned string) {
+	trimmed := strings.TrimSpace(cleaned)
+	switch {
+	case c.inBlock:
+		if strings.HasPrefix(trimmed, ")") {
+			c.inBlock = false
+		} else if m := goBlockImportRe.FindStringSubmatch(line); m != nil {
+			c.add(m[1])
+		}
+		return
+	case c.pyFrom != "":
+		c.addPyNames(c.pyFrom, cleaned)
+		if strings.Contains(cleaned, ")") {
+			c.pyFrom = ""
+		}
+		return
+	case trimmed == "":
+		return
+	case c.config.MultiLineBlock != "" && strings.HasPrefix(trimmed, c.config.MultiLineBlock):
+		c.inBlock = true
+		return
+	}
+
+	if c.config.LangKey == "py" {
+		if m := pyFromImportRe.FindStringSubmatch(cleaned); m != nil {
+			c.addPyNames(m[1], m[2])
+			if strings.Contains(m[2], "(") && !strings.Contains(m[2], ")") {
+				c.pyFrom = m[1]
+			}
+		} else if m := pyImportRe.FindStringSubmatch(cleaned); m != nil {
+			for _, part := range strings.Split(m[1], ",") {
+				if fields := strings.Fields(part); len(fields) > 0 {
+					c.add(fields[0])
+				}
+			}
+		}
+		return
+	}
+
+	if c.importRe == nil {
+		return
+	}
+	if m := c.importRe.FindStringSubmatch(line); m != nil {
+		for _, group := range m[1:] {
+			c.add(group)
+		}
+	}
+}
+
+// addPyNames adds pkg.name for every name of a from-import list
+func (c *ImportCollector) addPyNames(pkg, list string) {
+	list = strings.NewReplacer("(", " ", ")", " ", "\\", " ").Replace(list)
+	for _, part := range strings.Split(list, ",") {
+		fields := strings.Fields(part)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "*":
+			c.add(pkg)
+		case strings.HasSuffix(pkg, "."):
+			// relative: "from . import x" is ".x", "from .. import x" is "..x"
+			c.add(pkg + fields[0])
+		default:
+			c.add(pkg + "." + fields[0])
+		}
+	}
+}
+
+func (c *ImportCollector) add(path string) {
+	if path != "" {
+		c.seen[path] = true
+	}
+}
+
+// Imports returns the collected import paths, sorted
+func (c *ImportCollector) Imports() []string {
+	imports := make([]string, 0, len(c.seen))
+	for path := range c.seen {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	return imports
+}
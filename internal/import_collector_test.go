@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// collectImports feeds src through an ImportCollector the way stat does
+func collectImports(t *testing.T, config *LanguageConfig, src string) []string {
+	t.Helper()
+	lines := strings.Split(src, "\n")
+	cleaned := NewSanitizer(config, false).CleanLines(lines)
+	c := NewImportCollector(config)
+	for i, line := range lines {
+		c.Add(line, cleaned[i])
+	}
+	return c.Imports()
+}
+
+func TestImportCollector_GoAliases(t *testing.T) {
+	src := "package p\n\n" +
+		"import f \"fmt\"\n\n" +
+		"import (\n" +
+		"\t\"strings\"\n" +
+		"\tstr \"strings\"\n" +
+		"\t_ \"embed\"\n" +
+		"\t. \"math\"\n" +
+		"\t// \"commented/out\"\n" +
+		"\t\"fmt\"\n" +
+		")\n\n" +
+		"var s = \"import \\\"fake\\\"\"\n"
+	got := collectImports(t, getGoConfig(t), src)
+	want := []string{"embed", "fmt", "math", "strings"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Imports() = %v, want %v", got, want)
+	}
+}
+
+func TestImportCollector_PythonFromImport(t *testing.T) {
+	src := "import os.path\n" +
+		"from os import path\n" +
+		"import sys, json as js\n" +
+		"from collections import OrderedDict as OD, defaultdict\n" +
+		"from typing import (\n" +
+		"    List,\n" +
+		"    Dict,\n" +
+		")\n" +
+		"from . import util\n" +
+		"from pkg import *\n" +
+		"\"\"\"\n" +
+		"import not_an_import\n" +
+		"\"\"\"\n" +
+		"def f():\n" +
+		"    import re  # a local import still counts\n"
+	got := collectImports(t, getPyConfig(t), src)
+	want := []string{".util", "collections.OrderedDict", "collections.defaultdict", "json", "os.path", "pkg", "re", "sys", "typing.Dict", "typing.List"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Imports() = %v, want %v", got, want)
+	}
+}
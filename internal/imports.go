@@ -0,0 +1,67 @@
+// imports.go - The file's import block for extracted functions (--extract-with-imports)
+package internal
+
+import "strings"
+
+// ImportBlock returns the import lines of a file as written: every line
+// matching import_pattern and, for languages with a multi_line_block (Go
+// "import ("), the whole block through its closing ")". Only the file
+// header is scanned: the first function or class ends it, so imports local
+// to a function body are not picked up. Lines inside comments and strings
+// never count.
+func ImportBlock(lines []string, config *LanguageConfig) []string {
+	importRe := config.ImportRegex()
+	if importRe == nil && config.MultiLineBlock == "" {
+		return nil
+	}
+	funcRe, classRe := config.FuncRegex(), config.ClassRegex()
+	cleaned := NewSanitizer(config, false).CleanLines(lines)
+
+	var block []string
+	for i := 0; i < len(cleaned); i++ {
+		trimmed := strings.TrimSpace(cleaned[i])
+		if trimmed == "" {
+			continue
+		}
+		if (funcRe != nil && funcRe.MatchString(cleaned[i])) || (classRe != nil && classRe.MatchString(cleaned[i])) {
+			break
+		}
+
+		end := -1
+		switch {
+		case config.MultiLineBlock != "" && strings.HasPrefix(trimmed, config.MultiLineBlock):
+			end = i
+			for end < len(cleaned)-1 && !strings.HasPrefix(strings.TrimSpace(cleaned[end]), ")") {
+				end++
+			}
+		case importRe != nil && importRe.MatchString(lines[i]):
+			// "from x import (" continues until the parenthesis closes
+			end = i
+			for parens := strings.Count(cleaned[i], "(") - strings.Count(cleaned[i], ")"); parens > 0 && end < len(cleaned)-1; {
+				end++
+				parens += strings.Count(cleaned[end], "(") - strings.Count(cleaned[end], ")")
+			}
+		}
+		if end >= 0 {
+			block = append(block, lines[i:end+1]...)
+			i = end
+		}
+	}
+	return block
+}
+
+// AttachImports prepends imports and a blank separator line to the
+// extracted body (fn.Lines) of every function; Start and End still refer
+// to the function itself
+func AttachImports(functions []FunctionBounds, imports []string) {
+	if len(imports) == 0 {
+		return
+	}
+	for i := range functions {
+		fn := &functions[i]
+		body := make([]string, 0, len(imports)+1+len(fn.Lines))
+		body = append(body, imports...)
+		body = append(body, "")
+		fn.Lines = append(body, fn.Lines...)
+	}
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func extractWithImports(t *testing.T, lang, name, src, funcName string) string {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	mustWrite(t, path, src)
+
+	lc := config[lang]
+	result, err := CreateFinder(lc, funcName, "func", true, false).FindFunctions(path)
+	if err != nil {
+		t.Fatalf("FindFunctions() error = %v", err)
+	}
+	if len(result.Functions) != 1 {
+		t.Fatalf("found %d functions, want 1", len(result.Functions))
+	}
+	AttachImports(result.Functions, ImportBlock(strings.Split(src, "\n"), lc))
+	return FormatExtract(result)
+}
+
+func TestExtractWithImports_Go(t *testing.T) {
+	src := `// Package greet says hello.
+package greet
+
+import (
+	"fmt"
+	"os" // for Args
+)
+
+import "strings"
+
+func Hello() {
+	fmt.Println(strings.Join(os.Args, " "))
+}
+`
+	got := extractWithImports(t, "go", "greet.go", src, "Hello")
+	want := `// Hello: 11-13
+import (
+	"fmt"
+	"os" // for Args
+)
+import "strings"
+
+func Hello() {
+	fmt.Println(strings.Join(os.Args, " "))
+}`
+	if got != want {
+		t.Errorf("extract =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExtractWithImports_Python(t *testing.T) {
+	src := `"""Module docstring.
+import not_an_import
+"""
+import os
+from typing import (
+    List,
+)
+
+
+def cwd() -> List[str]:
+    import json
+    return [os.getcwd()]
+`
+	got := extractWithImports(t, "py", "util.py", src, "cwd")
+	wantPrefix := "import os\nfrom typing import (\n    List,\n)\n\ndef cwd() -> List[str]:\n"
+	if _, body, _ := strings.Cut(got, "\n"); !strings.HasPrefix(body, wantPrefix) {
+		t.Errorf("extract =\n%s\nwant body starting with\n%s", got, wantPrefix)
+	}
+	if strings.Contains(got, "not_an_import") {
+		t.Errorf("docstring line taken as an import:\n%s", got)
+	}
+}
+
+func TestImportBlock_NoImports(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if got := ImportBlock([]string{"package x", "", "func A() {}"}, config["go"]); len(got) != 0 {
+		t.Errorf("ImportBlock() = %q, want none", got)
+	}
+	functions := []FunctionBounds{{Name: "A", Lines: []string{"func A() {}"}}}
+	AttachImports(functions, nil)
+	if len(functions[0].Lines) != 1 {
+		t.Errorf("AttachImports(nil) changed the body: %q", functions[0].Lines)
+	}
+}
@@ -0,0 +1,245 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// KeywordFinder finds functions in languages whose blocks are opened by
+// keywords and closed by BlockEndKeyword (Elixir do ... end) instead of
+// braces. Every block_open_pattern match opens a block and every end
+// keyword closes one; a function or module ends on the line that closes
+// the block opened by its own header. A header without an opening keyword
+// (Elixir "def name, do: expr", a bodiless function head) is a single-line
+// function.
+type KeywordFinder struct {
+	config      *LanguageConfig
+	sanitizer   *Sanitizer
+	funcNames   map[string]bool
+	mapMode     bool
+	extractMode bool
+	noClasses   bool
+	endRe       *regexp.Regexp
+}
+
+// keywordBlock is a function or module whose body is still open
+type keywordBlock struct {
+	fn    *FunctionBounds // nil for modules
+	class *ClassBounds    // nil for functions
+	base  int             // block depth before the header
+}
+
+// NewKeywordFinder creates a finder for keyword-delimited blocks
+func NewKeywordFinder(config *LanguageConfig, funcNames []string, mapMode, extractMode bool) *KeywordFinder {
+	nameMap := make(map[string]bool)
+	for _, name := range funcNames {
+		nameMap[name] = true
+	}
+
+	return &KeywordFinder{
+		config:      config,
+		sanitizer:   NewSanitizer(config, false),
+		funcNames:   nameMap,
+		mapMode:     mapMode,
+		extractMode: extractMode,
+		endRe:       regexp.MustCompile(`\b` + regexp.QuoteMeta(config.BlockEndKeyword) + `\b`),
+	}
+}
+
+// FindFunctions finds functions in a file
+func (kf *KeywordFinder) FindFunctions(filename string) (*FindResult, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, newOpenError(filename, err)
+	}
+	defer file.Close()
+
+	return kf.FindFunctionsInReader(file, filename)
+}
+
+// FindFunctionsInReader finds functions in source read from r
+func (kf *KeywordFinder) FindFunctionsInReader(r io.Reader, filename string) (*FindResult, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, newReadError(filename, err)
+	}
+
+	return kf.FindFunctionsInLines(lines, 1, filename)
+}
+
+// SetNoClasses turns off module discovery (--no-classes)
+func (kf *KeywordFinder) SetNoClasses(noClasses bool) {
+	kf.noClasses = noClasses
+}
+
+// FindFunctionsInLines finds functions in pre-read lines.
+// startLine is the 1-based number of lines[0] in the original file.
+func (kf *KeywordFinder) FindFunctionsInLines(lines []string, startLine int, filename string) (*FindResult, error) {
+	funcRe := kf.config.FuncRegex()
+	if funcRe == nil {
+		return nil, newFuncRegexError(filename)
+	}
+	classRe := kf.config.ClassRegex()
+	if kf.noClasses {
+		classRe = nil
+	}
+	cleaned := kf.sanitizer.CleanLines(lines)
+
+	result := &FindResult{
+		Filename:  filename,
+		Functions: []FunctionBounds{},
+		Classes:   []ClassBounds{},
+	}
+	var all []FunctionBounds
+	var stack []keywordBlock
+	depth := 0
+
+	for i := 0; i < len(cleaned); i++ {
+		line := cleaned[i]
+
+		if m := matchName(classRe, line); m != "" {
+			stack = append(stack, keywordBlock{class: &ClassBounds{Name: m, Start: i + startLine}, base: depth})
+		} else if m := matchName(funcRe, line); m != "" && !isExcludedWord(m, kf.config.ExcludeWords) {
+			fn := FunctionBounds{Name: m, Start: i + startLine, ClassName: innermostClass(stack)}
+			fn.Scope = fn.ClassName
+			headerEnd := kf.headerEnd(cleaned, i)
+			header := strings.Join(cleaned[i:headerEnd+1], "\n")
+			if kf.countOpens(header) == 0 {
+				// "def name, do: expr" or a bodiless function head
+				fn.End = headerEnd + startLine
+				all = append(all, fn)
+				i = headerEnd
+				continue
+			}
+			stack = append(stack, keywordBlock{fn: &fn, base: depth})
+		}
+
+		depth += kf.countOpens(line) - kf.countEnds(line)
+		for len(stack) > 0 && depth <= stack[len(stack)-1].base {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if top.fn != nil {
+				top.fn.End = i + startLine
+				all = append(all, *top.fn)
+			} else {
+				top.class.End = i + startLine
+				result.Classes = append(result.Classes, *top.class)
+			}
+		}
+		if depth < 0 {
+			depth = 0
+		}
+	}
+
+	// Blocks still open at end of input
+	last := len(cleaned) - 1 + startLine
+	for j := len(stack) - 1; j >= 0; j-- {
+		if fn := stack[j].fn; fn != nil {
+			fn.End = last
+			all = append(all, *fn)
+			result.Ambiguities = append(result.Ambiguities, openFuncAmbiguity(fn, true))
+		} else {
+			class := stack[j].class
+			class.End = last
+			result.Classes = append(result.Classes, *class)
+			result.Ambiguities = append(result.Ambiguities, ParseAmbiguity{
+				Kind:    AmbiguityUnclosedClass,
+				Name:    class.Name,
+				Line:    class.Start,
+				Message: fmt.Sprintf("class %s: body is not closed before end of input", class.Name),
+			})
+		}
+	}
+	if kf.noClasses {
+		result.Classes = nil
+	}
+
+	sort.SliceStable(all, func(a, b int) bool { return all[a].Start < all[b].Start })
+	sort.SliceStable(result.Classes, func(a, b int) bool { return result.Classes[a].Start < result.Classes[b].Start })
+	for _, fn := range all {
+		if !kf.mapMode && !kf.funcNames[fn.Name] {
+			continue
+		}
+		if kf.extractMode {
+			fn.Lines = lines[fn.Start-startLine : fn.End-startLine+1]
+		}
+		result.Functions = append(result.Functions, fn)
+	}
+	return result, nil
+}
+
+// headerEnd returns the last line of a function header starting at i: the
+// header continues while parentheses are open or the line ends with ","
+// (Elixir "def name(x),\n do: x").
+func (kf *KeywordFinder) headerEnd(cleaned []string, i int) int {
+	parens := 0
+	for j := i; j < len(cleaned) && j < i+MaxSignatureLines; j++ {
+		parens += strings.Count(cleaned[j], "(") - strings.Count(cleaned[j], ")")
+		if parens <= 0 && !strings.HasSuffix(strings.TrimSpace(cleaned[j]), ",") {
+			return j
+		}
+	}
+	return i
+}
+
+// countOpens counts block openers, skipping keyword syntax ("do:") and
+// atoms or fields (":do", ".do")
+func (kf *KeywordFinder) countOpens(line string) int {
+	openRe := kf.config.BlockOpenRegex()
+	if openRe == nil {
+		return 0
+	}
+	return countKeywordMatches(line, openRe)
+}
+
+// countEnds counts end keywords, with the same exclusions as countOpens
+func (kf *KeywordFinder) countEnds(line string) int {
+	return countKeywordMatches(line, kf.endRe)
+}
+
+func countKeywordMatches(line string, re *regexp.Regexp) int {
+	count := 0
+	for _, loc := range re.FindAllStringIndex(line, -1) {
+		if loc[0] > 0 && (line[loc[0]-1] == ':' || line[loc[0]-1] == '.') {
+			continue
+		}
+		if loc[1] < len(line) && strings.ContainsRune(":?!", rune(line[loc[1]])) {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// matchName returns the last non-empty group of re matched against line
+func matchName(re *regexp.Regexp, line string) string {
+	if re == nil {
+		return ""
+	}
+	matches := re.FindStringSubmatch(line)
+	for i := len(matches) - 1; i >= 1; i-- {
+		if matches[i] != "" {
+			return matches[i]
+		}
+	}
+	return ""
+}
+
+// innermostClass returns the name of the innermost open module
+func innermostClass(stack []keywordBlock) string {
+	for j := len(stack) - 1; j >= 0; j-- {
+		if stack[j].class != nil {
+			return stack[j].class.Name
+		}
+	}
+	return ""
+}
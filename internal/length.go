@@ -0,0 +1,33 @@
+// length.go - Functions longer than a physical line limit (--warn-length)
+package internal
+
+// LongFunction is a function of Path whose span exceeds the limit by Over lines
+type LongFunction struct {
+	Path   string
+	Name   string
+	Start  int
+	Length int
+	Over   int
+}
+
+// FunctionLength is the physical span of fn in lines, blank and comment
+// lines included (End-Start+1)
+func FunctionLength(fn FunctionBounds) int {
+	return fn.End - fn.Start + 1
+}
+
+// LongFunctions returns the functions of path longer than limit lines, in
+// order; a limit of 0 or less turns the check off. Only bounds are used, so
+// it works without --extract.
+func LongFunctions(path string, functions []FunctionBounds, limit int) []LongFunction {
+	if limit <= 0 {
+		return nil
+	}
+	var long []LongFunction
+	for _, fn := range functions {
+		if n := FunctionLength(fn); n > limit {
+			long = append(long, LongFunction{Path: path, Name: fn.Name, Start: fn.Start, Length: n, Over: n - limit})
+		}
+	}
+	return long
+}
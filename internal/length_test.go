@@ -0,0 +1,22 @@
+package internal
+
+import "testing"
+
+func TestLongFunctions_Limit(t *testing.T) {
+	functions := []FunctionBounds{
+		{Name: "under", Start: 1, End: 9},
+		{Name: "at", Start: 11, End: 20},
+		{Name: "over", Start: 22, End: 32},
+	}
+
+	long := LongFunctions("a.go", functions, 10)
+	if len(long) != 1 {
+		t.Fatalf("LongFunctions() = %+v, want only over", long)
+	}
+	if want := (LongFunction{Path: "a.go", Name: "over", Start: 22, Length: 11, Over: 1}); long[0] != want {
+		t.Errorf("LongFunctions()[0] = %+v, want %+v", long[0], want)
+	}
+	if long := LongFunctions("a.go", functions, 0); long != nil {
+		t.Errorf("LongFunctions() with limit 0 = %+v, want nil", long)
+	}
+}
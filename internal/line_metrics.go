@@ -0,0 +1,134 @@
+// line_metrics.go - Code/comment/blank line classification shared by stat and complexity
+package internal
+
+import "strings"
+
+// LineKind is the classification of a single source line
+type LineKind int
+
+const (
+	LineBlank LineKind = iota
+	LineComment
+	LineCode
+)
+
+// LineMetrics holds line counts of a file or a function body
+type LineMetrics struct {
+	Total   int
+	Code    int
+	Comment int
+	Blank   int
+}
+
+// LineClassifier classifies lines one at a time, carrying the sanitizer
+// state across lines so block comments and multi-line strings are tracked.
+//
+// A line is code when anything is left after the sanitizer blanks strings
+// and comments. A line that is blanked completely is a comment when it is
+// part of a comment (or a docstring, or the shebang) and code when only
+// string content was on it (a line inside a multi-line string literal).
+type LineClassifier struct {
+	config      *LanguageConfig
+	sanitizer   *Sanitizer
+	state       ParserState
+	inDocstring bool
+	lineNum     int
+}
+
+// NewLineClassifier creates a classifier positioned at the start of a file
+func NewLineClassifier(config *LanguageConfig) *LineClassifier {
+	return &LineClassifier{config: config, sanitizer: NewSanitizer(config, false)}
+}
+
+// Classify returns the sanitized line and its kind
+func (c *LineClassifier) Classify(line string) (string, LineKind) {
+	c.lineNum++
+	inComment := commentStates[c.state] || c.inDocstring
+	inString := stringStates[c.state]
+	cleaned, state := c.sanitizer.CleanLine(line, c.state)
+	c.state = state
+
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return cleaned, LineBlank
+	}
+	if strings.TrimSpace(cleaned) != "" {
+		c.inDocstring = false
+		return cleaned, LineCode
+	}
+
+	kind := LineCode
+	switch {
+	case inComment:
+		kind = LineComment
+	case c.lineNum == 1 && strings.HasPrefix(trimmed, "#!"):
+		kind = LineComment
+	case inString:
+		// content or closing delimiter of a multi-line string
+	case c.startsComment(trimmed):
+		kind = LineComment
+	case c.startsDocstring(trimmed):
+		kind = LineComment
+		c.inDocstring = true
+	}
+	if c.inDocstring && c.state == StateNormal {
+		c.inDocstring = false
+	}
+	return "", kind
+}
+
+// startsComment reports whether a line opens with a line or block comment
+func (c *LineClassifier) startsComment(trimmed string) bool {
+	return (c.config.LineComment != "" && strings.HasPrefix(trimmed, c.config.LineComment)) ||
+		(c.config.BlockCommentStart != "" && strings.HasPrefix(trimmed, c.config.BlockCommentStart))
+}
+
+// startsDocstring reports whether a line opens with a docstring marker
+// (Python triple quotes)
+func (c *LineClassifier) startsDocstring(trimmed string) bool {
+	trimmed = strings.TrimLeft(trimmed, "rRuUbB")
+	for _, marker := range c.config.DocStringMarkers {
+		if strings.HasPrefix(trimmed, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// LineStats is the code/comment/blank breakdown of a function body (--stats)
+type LineStats struct {
+	CodeLines    int `json:"code_lines"`
+	CommentLines int `json:"comment_lines"`
+	BlankLines   int `json:"blank_lines"`
+}
+
+// AttachLineStats sets LineStats for every function from its extracted
+// body (fn.Lines); functions found without extract mode are left alone
+func AttachLineStats(functions []FunctionBounds, config *LanguageConfig) {
+	for i := range functions {
+		fn := &functions[i]
+		if len(fn.Lines) == 0 {
+			continue
+		}
+		m := CountLineMetrics(fn.Lines, config)
+		fn.LineStats = &LineStats{CodeLines: m.Code, CommentLines: m.Comment, BlankLines: m.Blank}
+	}
+}
+
+// CountLineMetrics classifies lines as code, comment or blank using the
+// language's sanitizer (see LineClassifier)
+func CountLineMetrics(lines []string, config *LanguageConfig) LineMetrics {
+	c := NewLineClassifier(config)
+	m := LineMetrics{Total: len(lines)}
+	for _, line := range lines {
+		switch _, kind := c.Classify(line); kind {
+		case LineBlank:
+			m.Blank++
+		case LineComment:
+			m.Comment++
+		default:
+			m.Code++
+		}
+	}
+	return m
+}
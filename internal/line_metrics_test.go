@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountLineMetrics_InlineAndBlockComments(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	code := `#include <stdio.h>
+
+/*
+ * Block comment
+ */
+int add(int a, int b) { // inline comment
+    char c = '/';        /* trailing block */
+    const char *s = "// not a comment";
+    /* one-line block */
+    return a + b;
+}
+`
+	lines := strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+	got := CountLineMetrics(lines, config["c"])
+	want := LineMetrics{Total: 11, Code: 6, Comment: 4, Blank: 1}
+	if got != want {
+		t.Errorf("CountLineMetrics() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCountLineMetrics_PythonDocstringAndStrings(t *testing.T) {
+	code := `#!/usr/bin/env python3
+def f():
+    """
+    Docstring lines are comments.
+    """
+    text = """
+data, not a comment
+"""
+    # a comment
+    return text
+`
+	lines := strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+	got := CountLineMetrics(lines, getPyConfig(t))
+	want := LineMetrics{Total: 10, Code: 5, Comment: 5}
+	if got != want {
+		t.Errorf("CountLineMetrics() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLineClassifier_ReturnsSanitizedLine(t *testing.T) {
+	c := NewLineClassifier(getGoConfig(t))
+	cleaned, kind := c.Classify(`x := "a{" // b{`)
+	if kind != LineCode || strings.Contains(cleaned, "{") {
+		t.Errorf("Classify() = %q, %v", cleaned, kind)
+	}
+}
+
+func TestAttachLineStats_BlockCommentAndBlankLines(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	code := `package main
+
+func Handle() {
+	/* a block comment
+	   over two lines */
+	x := 1
+
+	// line comment
+	_ = x
+
+}
+
+func Empty() {}
+`
+	finder := NewFinder(config["go"], nil, true, true, false)
+	result, err := finder.FindFunctionsInReader(strings.NewReader(code), "stats.go")
+	if err != nil {
+		t.Fatalf("FindFunctionsInReader() error = %v", err)
+	}
+	AttachLineStats(result.Functions, config["go"])
+
+	want := map[string]LineStats{
+		"Handle": {CodeLines: 4, CommentLines: 3, BlankLines: 2},
+		"Empty":  {CodeLines: 1},
+	}
+	if len(result.Functions) != len(want) {
+		t.Fatalf("got %d functions, want %d", len(result.Functions), len(want))
+	}
+	for _, fn := range result.Functions {
+		if fn.LineStats == nil {
+			t.Errorf("%s: LineStats not set", fn.Name)
+			continue
+		}
+		if *fn.LineStats != want[fn.Name] {
+			t.Errorf("%s: LineStats = %+v, want %+v", fn.Name, *fn.LineStats, want[fn.Name])
+		}
+	}
+
+	out := FormatLineStats(result)
+	if !strings.Contains(out, "COMMENT") || !strings.Contains(out, "3-11") {
+		t.Errorf("FormatLineStats() =\n%s", out)
+	}
+}
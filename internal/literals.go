@@ -0,0 +1,62 @@
+// literals.go - String and numeric literal counts per function (--literals)
+package internal
+
+import "regexp"
+
+// numericLiteral matches one number in sanitized code: decimal, hex, octal
+// or binary, with separators (1_000), a fraction, an exponent (1.5e-3) and
+// a type suffix (10u, 2.0f, 5i64). \b keeps digits of identifiers (x2) out.
+var numericLiteral = regexp.MustCompile(`\b\d(?:[eE][+-]\d|\.\d|\w)*`)
+
+// LiteralCounts are the literals found in a piece of code
+type LiteralCounts struct {
+	Strings int // string, char and sigil literals
+	Numbers int // numeric literals, 0 and 1 included
+}
+
+// noteString counts a string literal opened by CleanLine in counting mode
+func (s *Sanitizer) noteString() {
+	if s.countStrings {
+		s.stringCount++
+	}
+}
+
+// CountLiterals sanitizes lines and reports the literals it blanked
+// instead of discarding them: every string literal opened outside a
+// comment counts once, however many lines it spans, and numbers are
+// matched on the sanitized text, so those in comments and strings are
+// left out.
+func (s *Sanitizer) CountLiterals(lines []string) LiteralCounts {
+	s.countStrings, s.stringCount = true, 0
+	defer func() { s.countStrings = false }()
+
+	counts := LiteralCounts{}
+	for _, line := range s.CleanLines(lines) {
+		counts.Numbers += len(numericLiteral.FindAllStringIndex(line, -1))
+	}
+	counts.Strings = s.stringCount
+	return counts
+}
+
+// AttachLiteralCounts sets StringLiterals and NumericLiterals for every
+// function from lines Start..End of lines (the whole file); nested functions
+// also count toward their parent. A Python docstring is documentation, not a
+// literal, and is not counted.
+func AttachLiteralCounts(functions []FunctionBounds, lines []string, config *LanguageConfig) {
+	sanitizer := NewSanitizer(config, false)
+	for i := range functions {
+		fn := &functions[i]
+		if fn.Start < 1 || fn.Start > len(lines) {
+			continue
+		}
+		end := min(max(fn.End, fn.Start), len(lines))
+		counts := sanitizer.CountLiterals(lines[fn.Start-1 : end])
+		if config.IndentBased && counts.Strings > 0 {
+			// Only the function's own docstring, inside its lines
+			if doc, docLine := docstringAt(lines, fn.Start, config); doc != "" && docLine >= fn.Start && docLine <= end {
+				counts.Strings--
+			}
+		}
+		fn.StringLiterals, fn.NumericLiterals = counts.Strings, counts.Numbers
+	}
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttachLiteralCounts_Go(t *testing.T) {
+	src := "package p\n\n// Retry \"twice\" after 30 seconds\nfunc Retry() string {\n\tname := `job` + \"-\" // \"suffix\" 5\n\t/* \"disabled\" 99\n\t   timeout := 60 */\n\tn := 3 * 0x10\n\treturn name + 'x' + string(rune(n))\n}\n"
+	config := getGoConfig(t)
+	lines := strings.Split(src, "\n")
+	result, err := NewFinder(config, nil, true, false, false).FindFunctionsInLines(lines, 1, "p.go")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	AttachLiteralCounts(result.Functions, lines, config)
+
+	fn := result.Functions[0]
+	if fn.StringLiterals != 3 || fn.NumericLiterals != 2 {
+		t.Errorf("Retry: strings = %d, numbers = %d, want 3 and 2", fn.StringLiterals, fn.NumericLiterals)
+	}
+}
+
+func TestAttachLiteralCounts_PythonSkipsDocstring(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	py := config["py"]
+	src := "def greet(name):\n    \"\"\"Say hello 2 times.\"\"\"\n    # \"ignored\" 7\n    msg = 'hello, ' + f\"{name}\" + \"\"\"!\n\"\"\"\n    return msg * 2 + 1.5  # 10\n"
+	functions := findPython(t, py, src, false)
+	AttachLiteralCounts(functions, strings.Split(src, "\n"), py)
+
+	if len(functions) != 1 {
+		t.Fatalf("found %d functions, want 1", len(functions))
+	}
+	if fn := functions[0]; fn.StringLiterals != 3 || fn.NumericLiterals != 2 {
+		t.Errorf("greet: strings = %d, numbers = %d, want 3 and 2", fn.StringLiterals, fn.NumericLiterals)
+	}
+}
+
+func TestAttachLiteralCounts_PythonOneLineDefKeepsItsString(t *testing.T) {
+	py := getPyConfig(t)
+	src := "def a(): return \"x\"\n\ndef b():\n    \"\"\"Doc.\"\"\"\n    return 1\n"
+	functions := findPython(t, py, src, false)
+	AttachLiteralCounts(functions, strings.Split(src, "\n"), py)
+
+	strs := map[string]int{}
+	for _, fn := range functions {
+		strs[fn.Name] = fn.StringLiterals
+	}
+	if strs["a"] != 1 || strs["b"] != 0 {
+		t.Errorf("string literals = %v, want a: 1, b: 0", strs)
+	}
+}
+
+func TestNumericLiteral(t *testing.T) {
+	tests := []struct {
+		code string
+		want []string
+	}{
+		{"x2 = y3 + 4", []string{"4"}},
+		{"a = 1.5e-3 * 2", []string{"1.5e-3", "2"}},
+		{"mask := 0xFF_FF | 0b101", []string{"0xFF_FF", "0b101"}},
+		{"let n = 10u32 + 2.0f", []string{"10u32", "2.0f"}},
+		{"i = 1+2", []string{"1", "2"}},
+	}
+	for _, tt := range tests {
+		got := numericLiteral.FindAllString(tt.code, -1)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("numericLiteral in %q = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
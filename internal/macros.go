@@ -0,0 +1,76 @@
+// macros.go - C/C++ function-like preprocessor macros (--include-macros)
+package internal
+
+import (
+	"sort"
+	"strings"
+)
+
+// KindMacro is the FunctionBounds.Kind of a function-like macro
+const KindMacro = "macro"
+
+// SetIncludeMacros reports function-like macros ("#define MAX(a, b) ...")
+// as functions with Kind "macro" (--include-macros); languages without a
+// macro_pattern are not affected
+func (f *Finder) SetIncludeMacros(include bool) {
+	f.includeMacros = include
+}
+
+// findMacros returns the macro_pattern matches of lines. A macro spans its
+// "\" continuation lines, so End is the first line without one. lines are
+// matched sanitized: a #define inside a block comment is not a macro, and
+// the "#" line itself is not a comment to the sanitizer.
+func (f *Finder) findMacros(lines []string, lineOffset int) []FunctionBounds {
+	macroRe := f.config.MacroRegex()
+	if macroRe == nil {
+		return nil
+	}
+	cleaned := f.sanitizer.CleanLines(lines)
+	var macros []FunctionBounds
+	for i := 0; i < len(cleaned); i++ {
+		m := macroRe.FindStringSubmatch(cleaned[i])
+		if m == nil || !(f.mapMode || f.funcNames[m[1]]) {
+			continue
+		}
+		start := i
+		for i+1 < len(lines) && strings.HasSuffix(strings.TrimRight(lines[i], " \t"), `\`) {
+			i++
+		}
+		macro := FunctionBounds{Name: m[1], Start: start + 1 + lineOffset, End: i + 1 + lineOffset, Kind: KindMacro}
+		if f.extractMode {
+			macro.Lines = append([]string{}, lines[start:i+1]...)
+		}
+		macros = append(macros, macro)
+	}
+	return macros
+}
+
+// mergeMacros adds macros to functions in line order. A func_pattern match
+// on a macro's continuation lines ("int get_x(void) { \") is part of the
+// macro body and is dropped.
+func mergeMacros(functions, macros []FunctionBounds) []FunctionBounds {
+	if len(macros) == 0 {
+		return functions
+	}
+	merged := make([]FunctionBounds, 0, len(functions)+len(macros))
+	for _, fn := range functions {
+		if !insideMacro(fn.Start, macros) {
+			merged = append(merged, fn)
+		}
+	}
+	merged = append(merged, macros...)
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Start < merged[j].Start
+	})
+	return merged
+}
+
+// insideMacro reports whether line is one of the lines of a macro
+func insideMacro(line int, macros []FunctionBounds) bool {
+	for _, m := range macros {
+		if line >= m.Start && line <= m.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+const macroSource = `#include <stdio.h>
+
+#define MAX(a,b) ((a)>(b)?(a):(b))
+#define PI 3.14
+/* #define HIDDEN(x) x */
+#define DEFINE_GETTER(name) \
+    int get_##name(void) { \
+        return name; \
+    }
+
+int main(void) {
+    return MAX(1, 2);
+}
+`
+
+func findCMacros(t *testing.T, include bool) []FunctionBounds {
+	t.Helper()
+	finder := NewFinder(getCConfig(t), nil, true, true, false)
+	finder.SetIncludeMacros(include)
+	result, err := finder.FindFunctionsInLines(strings.Split(macroSource, "\n"), 1, "m.c")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	return result.Functions
+}
+
+func TestIncludeMacros_SingleAndContinuedLines(t *testing.T) {
+	functions := findCMacros(t, true)
+
+	want := []FunctionBounds{
+		{Name: "MAX", Start: 3, End: 3, Kind: KindMacro},
+		{Name: "DEFINE_GETTER", Start: 6, End: 9, Kind: KindMacro},
+		{Name: "main", Start: 11, End: 13},
+	}
+	if len(functions) != len(want) {
+		t.Fatalf("found %d functions, want %d: %+v", len(functions), len(want), functions)
+	}
+	for i, w := range want {
+		fn := functions[i]
+		if fn.Name != w.Name || fn.Start != w.Start || fn.End != w.End || fn.Kind != w.Kind {
+			t.Errorf("functions[%d] = %s %d-%d kind %q, want %s %d-%d kind %q", i, fn.Name, fn.Start, fn.End, fn.Kind, w.Name, w.Start, w.End, w.Kind)
+		}
+	}
+	if got := len(functions[1].Lines); got != 4 {
+		t.Errorf("DEFINE_GETTER body has %d lines, want the 4 continued lines", got)
+	}
+}
+
+func TestIncludeMacros_OffByDefault(t *testing.T) {
+	for _, fn := range findCMacros(t, false) {
+		if fn.Kind == KindMacro {
+			t.Errorf("macro %s reported without --include-macros", fn.Name)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// methods.go - Methods of the types found by --struct (--with-methods)
+package internal
+
+import "strings"
+
+// AttachMethods sets Methods of every type to the functions whose
+// ClassName names it, in order. A ClassName may be qualified ("Outer.Inner",
+// "ns::Box"); its last part is matched. When several types share the name
+// (nested classes of different outer classes), the innermost type whose
+// lines contain the method wins; a method defined outside every such type
+// (Go receivers, C++ "Box::get" after the class) goes to the first one.
+func AttachMethods(types []TypeBounds, functions []FunctionBounds) {
+	for _, fn := range functions {
+		if fn.ClassName == "" {
+			continue
+		}
+		name := fn.ClassName
+		if i := strings.LastIndexAny(name, ".:"); i >= 0 {
+			name = name[i+1:]
+		}
+
+		owner := -1
+		for i, t := range types {
+			if t.Name != name {
+				continue
+			}
+			inside := fn.Start >= t.Start && fn.Start <= t.End
+			switch {
+			case owner < 0:
+				owner = i
+			case inside && (!containsLine(types[owner], fn.Start) || t.End-t.Start < types[owner].End-types[owner].Start):
+				owner = i
+			}
+		}
+		if owner >= 0 {
+			types[owner].Methods = append(types[owner].Methods, fn)
+		}
+	}
+}
+
+// containsLine reports whether line is within t
+func containsLine(t TypeBounds, line int) bool {
+	return line >= t.Start && line <= t.End
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttachMethods_JavaClassOutline(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	java := config["java"]
+	src := `public class Counter {
+    private int count;
+    private final String name;
+
+    public Counter(String name) {
+        this.name = name;
+    }
+
+    public void increment() {
+        count++;
+    }
+
+    public int get() {
+        return this.count;
+    }
+}
+`
+	lines := strings.Split(src, "\n")
+	structs, err := NewStructFinderFactory().CreateStructFinder(java, "", true, false).FindStructuresInLines(lines, 1, "Counter.java")
+	if err != nil {
+		t.Fatalf("FindStructuresInLines() error = %v", err)
+	}
+	funcs, err := NewFinder(java, nil, true, false, false).FindFunctionsInLines(lines, 1, "Counter.java")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	AttachMethods(structs.Types, funcs.Functions)
+
+	want := "Counter: 1-16; fields: count, name; methods: Counter, increment, get"
+	if got := FormatStructMap(structs); got != want {
+		t.Errorf("FormatStructMap() = %q, want %q", got, want)
+	}
+	if m := structs.Types[0].Methods[2]; m.Start != 13 || m.End != 15 {
+		t.Errorf("get: %d-%d, want 13-15", m.Start, m.End)
+	}
+}
+
+func TestAttachMethods_PicksEnclosingTypeOfSameName(t *testing.T) {
+	types := []TypeBounds{
+		{Name: "Node", Start: 1, End: 10},
+		{Name: "Node", Start: 12, End: 20},
+		{Name: "Tree", Start: 22, End: 30},
+	}
+	functions := []FunctionBounds{
+		{Name: "a", ClassName: "Node", Start: 14, End: 15},
+		{Name: "b", ClassName: "ns::Tree", Start: 40, End: 42},
+		{Name: "c", Start: 50, End: 51},
+	}
+	AttachMethods(types, functions)
+
+	if len(types[0].Methods) != 0 || len(types[1].Methods) != 1 || types[1].Methods[0].Name != "a" {
+		t.Errorf("Node methods = %v / %v, want a in the second Node", types[0].Methods, types[1].Methods)
+	}
+	if len(types[2].Methods) != 1 || types[2].Methods[0].Name != "b" {
+		t.Errorf("Tree methods = %v, want b", types[2].Methods)
+	}
+}
@@ -0,0 +1,50 @@
+// mirror_output.go - One JSON file per source file in a tree mirroring --dir (--split-output)
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MirrorPath is where the JSON of a file at relPath (relative to the scanned
+// root) goes under outDir: the same path with ".json" appended, so
+// "pkg/a.go" and "pkg/a.py" don't collide
+func MirrorPath(outDir, relPath string) string {
+	return filepath.Join(outDir, relPath+".json")
+}
+
+// WriteMirroredOutput writes every parsed file of results as its own --json
+// file object ({"path", "functions", "classes"}) under outDir, at
+// MirrorPath of its path relative to rootDir; subdirectories are created as
+// needed. Files without symbols are written too, so a per-file cache can
+// tell "nothing found" from "not scanned"; files that failed to parse or
+// were skipped as generated are not. Returns the number of files written.
+func WriteMirroredOutput(results []DirResult, outDir, rootDir string) (int, error) {
+	written := 0
+	for _, r := range results {
+		if r.Error != nil || r.Generated {
+			continue
+		}
+		relPath, err := filepath.Rel(rootDir, r.Path)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return written, fmt.Errorf("%s is outside %s", r.Path, rootDir)
+		}
+
+		data, err := json.MarshalIndent(toJSONFile(r), "", "  ")
+		if err != nil {
+			return written, fmt.Errorf("marshaling %s: %w", r.Path, err)
+		}
+		target := MirrorPath(outDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return written, fmt.Errorf("creating output directory: %w", err)
+		}
+		if err := os.WriteFile(target, append(data, '\n'), 0644); err != nil {
+			return written, fmt.Errorf("writing %s: %w", target, err)
+		}
+		written++
+	}
+	return written, nil
+}
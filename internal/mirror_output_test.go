@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteMirroredOutput_SkipsFailedAndRejectsOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	outDir := t.TempDir()
+	results := []DirResult{
+		{Path: filepath.Join(root, "a", "x.go"), Functions: []FunctionBounds{{Name: "X", Start: 3, End: 3}}},
+		{Path: filepath.Join(root, "broken.go"), Error: os.ErrPermission},
+		{Path: filepath.Join(root, "gen.go"), Generated: true},
+	}
+
+	written, err := WriteMirroredOutput(results, outDir, root)
+	if err != nil || written != 1 {
+		t.Fatalf("WriteMirroredOutput() = %d, %v; want 1, nil", written, err)
+	}
+	data, err := os.ReadFile(MirrorPath(outDir, filepath.Join("a", "x.go")))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"name": "X"`) {
+		t.Errorf("a/x.go.json = %s, want function X", data)
+	}
+	for _, name := range []string{"broken.go.json", "gen.go.json"} {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err == nil {
+			t.Errorf("%s written for a file that was not parsed", name)
+		}
+	}
+
+	outside := []DirResult{{Path: filepath.Join(filepath.Dir(root), "other.go")}}
+	if _, err := WriteMirroredOutput(outside, outDir, root); err == nil {
+		t.Error("WriteMirroredOutput() accepted a file outside the root")
+	}
+}
@@ -0,0 +1,107 @@
+// nested_fields.go - Fields of nested anonymous structs and unions (--fields-depth)
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// namedNestedHeader is a member whose type is an inline struct, written
+	// name first: Go "Inner struct", "Items []struct", "Ptr *struct"
+	namedNestedHeader = regexp.MustCompile(`^([\p{L}_][\p{L}\p{Nd}_]*)\s+((?:\[[^\]]*\]|\*|map\[[^\]]*\])*(?:struct|union|interface))$`)
+	// cNestedHeader is a C/C++ nested type, named after its closing brace:
+	// "union", "struct tag", "typedef struct"
+	cNestedHeader = regexp.MustCompile(`^(?:typedef\s+)?(struct|union|class|enum)(?:\s+([\p{L}_][\p{L}\p{Nd}_]*))?$`)
+	// nestedDeclarator is the member name after a closing brace: "} value;"
+	nestedDeclarator = regexp.MustCompile(`^\s*}\s*\**\s*([\p{L}_][\p{L}\p{Nd}_]*)`)
+)
+
+// fieldScope is one brace level of a type body while findNestedFields
+// scans it
+type fieldScope struct {
+	member FieldBounds   // the nested member itself; Name may come from the closing line
+	tag    string        // C struct tag, the prefix when no member name follows
+	depth  int           // brace depth inside the scope
+	body   bool          // method or initializer body: nothing inside is a field
+	fields []FieldBounds // fields found so far, names relative to the scope
+}
+
+// findNestedFields lists the fields of a brace type body, recursing into
+// nested struct/union members up to f.fieldsDepth levels. Fields of a
+// nested member are prefixed with its name ("Inner.X"); an anonymous C11
+// struct or union adds none, as its fields are accessed directly.
+func (f *StructFinder) findNestedFields(lines []string, typeBounds *TypeBounds, lineOffset int, fieldRegex *regexp.Regexp) []FieldBounds {
+	stack := []*fieldScope{{depth: 1}}
+	state := StateNormal
+	depth := 0
+
+	for lineNum := typeBounds.Start - 1 - lineOffset; lineNum < len(lines) && lineNum < typeBounds.End-1-lineOffset; lineNum++ {
+		cleaned, newState := f.sanitizer.CleanLine(lines[lineNum], state)
+		state = newState
+		lineDepth := depth
+		delta := CountBraces(cleaned)
+		depth += delta
+		line := lineNum + 1 + lineOffset
+		top := stack[len(stack)-1]
+		trimmed := strings.TrimSpace(cleaned)
+
+		if lineDepth > 0 && lineDepth == top.depth && !top.body && !strings.HasPrefix(trimmed, "}") {
+			if delta > 0 {
+				stack = append(stack, f.openFieldScope(trimmed, line, depth))
+				continue
+			}
+			if m := fieldRegex.FindStringSubmatch(cleaned); len(m) >= 2 && len(stack) <= f.fieldsDepth {
+				name := m[1]
+				if name != "" && !isLikelyMethod(name, cleaned) && !isExcludedWord(name, f.config.ExcludeWords) {
+					field := FieldBounds{Name: name, Line: line}
+					if len(m) >= 3 {
+						field.Type = strings.TrimSpace(m[2])
+					}
+					top.fields = append(top.fields, field)
+				}
+			}
+		}
+
+		for len(stack) > 1 && depth < stack[len(stack)-1].depth {
+			closed := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			parent := stack[len(stack)-1]
+			if closed.body {
+				continue
+			}
+			if closed.member.Name == "" {
+				if m := nestedDeclarator.FindStringSubmatch(cleaned); m != nil {
+					closed.member.Name = m[1]
+				}
+			}
+			prefix := closed.member.Name
+			if prefix != "" && len(stack) <= f.fieldsDepth {
+				parent.fields = append(parent.fields, closed.member)
+			} else if prefix == "" {
+				prefix = closed.tag
+			}
+			for _, field := range closed.fields {
+				if prefix != "" {
+					field.Name = prefix + "." + field.Name
+				}
+				parent.fields = append(parent.fields, field)
+			}
+		}
+	}
+
+	return stack[0].fields
+}
+
+// openFieldScope starts the scope opened by header, a body line up to its
+// "{": a nested struct/union member, or else a method or initializer body
+func (f *StructFinder) openFieldScope(header string, line, depth int) *fieldScope {
+	header = strings.TrimSpace(strings.SplitN(header, "{", 2)[0])
+	if m := namedNestedHeader.FindStringSubmatch(header); m != nil && !isExcludedWord(m[1], f.config.ExcludeWords) {
+		return &fieldScope{member: FieldBounds{Name: m[1], Type: m[2], Line: line}, depth: depth}
+	}
+	if m := cNestedHeader.FindStringSubmatch(header); m != nil {
+		return &fieldScope{member: FieldBounds{Type: strings.TrimPrefix(header, "typedef "), Line: line}, tag: m[2], depth: depth}
+	}
+	return &fieldScope{depth: depth, body: true}
+}
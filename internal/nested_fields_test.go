@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+const nestedGoStruct = `package p
+
+type Outer struct {
+	A int
+	Inner struct {
+		X int
+		Deep struct {
+			Y string
+		}
+	}
+	B string
+}
+`
+
+func nestedFieldNames(t *testing.T, lang, src string, depth int) []string {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	finder := NewStructFinder(config[lang], "", true)
+	finder.SetFieldsDepth(depth)
+	result, err := finder.FindStructuresInLines(strings.Split(src, "\n"), 1, "test")
+	if err != nil {
+		t.Fatalf("FindStructuresInLines() error = %v", err)
+	}
+	if len(result.Types) != 1 {
+		t.Fatalf("found %d types, want 1", len(result.Types))
+	}
+	var names []string
+	for _, field := range result.Types[0].Fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+func TestFieldsDepth_GoEmbeddedAnonymousStruct(t *testing.T) {
+	tests := []struct {
+		depth int
+		want  string
+	}{
+		{1, "A Inner B"},
+		{2, "A Inner Inner.X Inner.Deep B"},
+		{3, "A Inner Inner.X Inner.Deep Inner.Deep.Y B"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(nestedFieldNames(t, "go", nestedGoStruct, tt.depth), " "); got != tt.want {
+			t.Errorf("--fields-depth %d: fields = %q, want %q", tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestFieldsDepth_NestedTypeAndLines(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	finder := NewStructFinder(config["go"], "", true)
+	finder.SetFieldsDepth(2)
+	result, _ := finder.FindStructuresInLines(strings.Split(nestedGoStruct, "\n"), 1, "test")
+	got := map[string]FieldBounds{}
+	for _, field := range result.Types[0].Fields {
+		got[field.Name] = field
+	}
+	if f := got["Inner"]; f.Type != "struct" || f.Line != 5 {
+		t.Errorf("Inner = %+v, want type struct on line 5", f)
+	}
+	if f := got["Inner.X"]; f.Type != "int" || f.Line != 6 {
+		t.Errorf("Inner.X = %+v, want type int on line 6", f)
+	}
+}
+
+func TestFieldsDepth_CNamedAndAnonymousUnions(t *testing.T) {
+	src := `class Shape {
+public:
+    int width;
+    int area() const {
+        int tmp = width;
+        return tmp;
+    }
+    union {
+        int i;
+        float f;
+    } value;
+    struct {
+        int x;
+    };
+};
+`
+	got := strings.Join(nestedFieldNames(t, "cpp", src, 2), " ")
+	if want := "width value value.i value.f x"; got != want {
+		t.Errorf("fields = %q, want %q (member name from the closing line, anonymous struct promoted, method body skipped)", got, want)
+	}
+}
+
+func TestFieldsDepth_ZeroKeepsFlatListing(t *testing.T) {
+	if got := strings.Join(nestedFieldNames(t, "go", nestedGoStruct, 0), " "); got != "A X Y B" {
+		t.Errorf("fields = %q, want the flat listing %q", got, "A X Y B")
+	}
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func getNimConfig(t *testing.T) *LanguageConfig {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	return config["nim"]
+}
+
+const nimSample = `import std/strutils
+
+type
+  Point* = object
+    x*, y*: float
+    label: string
+  Shape = ref object of RootObj
+    name*: string
+
+proc area*(s: Shape,
+           scale: float): float =
+  let note = """
+a proc foo() = inside a string
+"""
+  result = 0.0
+  for i in 0..3:
+    result += scale
+proc twice(x: int): int = x * 2
+method draw(s: Shape) {.base.} =
+  echo &"shape {s.name}"
+  #[ proc hidden() =
+  ]#
+  if s.name.len > 0:
+    echo "named"
+proc forward(x: int): int
+`
+
+func TestPythonFinder_NimProcs(t *testing.T) {
+	finder := CreateFinder(getNimConfig(t), "", "map", false, false)
+	result, err := finder.FindFunctionsInReader(strings.NewReader(nimSample), "shapes.nim")
+	if err != nil {
+		t.Fatalf("FindFunctionsInReader failed: %v", err)
+	}
+
+	want := []struct {
+		name       string
+		start, end int
+	}{
+		{"area", 10, 17},
+		{"twice", 18, 18},
+		{"draw", 19, 24},
+		{"forward", 25, 25},
+	}
+	if len(result.Functions) != len(want) {
+		t.Fatalf("got %d functions, want %d: %+v", len(result.Functions), len(want), result.Functions)
+	}
+	for i, w := range want {
+		fn := result.Functions[i]
+		if fn.Name != w.name || fn.Start != w.start || fn.End != w.end {
+			t.Errorf("Functions[%d] = %s %d-%d, want %s %d-%d", i, fn.Name, fn.Start, fn.End, w.name, w.start, w.end)
+		}
+	}
+}
+
+func TestPythonStructFinder_NimObjects(t *testing.T) {
+	finder := NewStructFinderFactory().CreateStructFinder(getNimConfig(t), "", true, false)
+	result, err := finder.FindStructuresInLines(strings.Split(nimSample, "\n"), 1, "shapes.nim")
+	if err != nil {
+		t.Fatalf("FindStructuresInLines failed: %v", err)
+	}
+
+	if len(result.Types) != 2 {
+		t.Fatalf("got %d types, want 2: %+v", len(result.Types), result.Types)
+	}
+	point := result.Types[0]
+	if point.Name != "Point" || point.Kind != "object" || point.Start != 4 || point.End != 6 {
+		t.Errorf("Types[0] = %s %s %d-%d, want Point object 4-6", point.Name, point.Kind, point.Start, point.End)
+	}
+	// "x*, y*: float" declares two fields
+	var fields []string
+	for _, f := range point.Fields {
+		fields = append(fields, f.Name+": "+f.Type)
+	}
+	if got := strings.Join(fields, ", "); got != "x: float, y: float, label: string" {
+		t.Errorf("Point fields = %s, want x: float, y: float, label: string", got)
+	}
+	if shape := result.Types[1]; shape.Name != "Shape" || len(shape.Fields) != 1 || shape.Fields[0].Name != "name" {
+		t.Errorf("Types[1] = %+v, want Shape with field name", shape)
+	}
+}
@@ -0,0 +1,64 @@
+// operators.go - Operator overloads and special methods (Kind "operator"/"special")
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kinds of operator overloads ("operator==", Python "__eq__") and of the
+// other Python special methods ("__init__", "__repr__")
+const (
+	KindOperator = "operator"
+	KindSpecial  = "special"
+)
+
+// conversionOperator matches a C++/C# conversion operator or operator
+// new/delete, which func_pattern captures by the word after "operator"
+// ("explicit operator bool()" gives "bool")
+var conversionOperator = regexp.MustCompile(`\boperator\s+(\w+)\s*\(`)
+
+// pythonOperators are the dunder methods that implement an operator:
+// comparison, arithmetic (with their reflected __r*__ and in-place __i*__
+// forms), unary, subscription, membership and call
+var pythonOperators = func() map[string]bool {
+	ops := map[string]bool{}
+	for _, name := range strings.Fields("eq ne lt le gt ge neg pos abs invert getitem setitem delitem contains call") {
+		ops["__"+name+"__"] = true
+	}
+	for _, name := range strings.Fields("add sub mul matmul truediv floordiv mod divmod pow lshift rshift and xor or") {
+		ops["__"+name+"__"], ops["__r"+name+"__"], ops["__i"+name+"__"] = true, true, true
+	}
+	return ops
+}()
+
+// operatorName normalizes the name func_pattern matched on the cleaned
+// line: "operator ==" (C#) becomes "operator==", and the target type of a
+// conversion operator becomes "operator bool"
+func operatorName(line, name string) string {
+	if rest, ok := strings.CutPrefix(name, "operator"); ok && rest != "" && !isIdentByte(rest[0]) {
+		return "operator" + strings.TrimSpace(rest)
+	}
+	if strings.Contains(line, "operator") {
+		for _, m := range conversionOperator.FindAllStringSubmatch(line, -1) {
+			if m[1] == name {
+				return "operator " + name
+			}
+		}
+	}
+	return name
+}
+
+// functionKind is the Kind of a function named name: KindOperator for an
+// operator overload, KindSpecial for another Python dunder method, "" else
+func functionKind(name string) string {
+	switch {
+	case strings.HasPrefix(name, "operator") && len(name) > len("operator") && !isIdentByte(name[len("operator")]):
+		return KindOperator
+	case pythonOperators[name]:
+		return KindOperator
+	case len(name) > 4 && strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__"):
+		return KindSpecial
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// namesAndKinds renders functions as "name/kind"
+func namesAndKinds(functions []FunctionBounds) string {
+	var parts []string
+	for _, fn := range functions {
+		parts = append(parts, fmt.Sprintf("%s/%s", fn.Name, fn.Kind))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestOperatorOverloads_CppAndCSharp(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tests := []struct {
+		lang, src, want string
+	}{
+		{"cpp", `class Vec {
+public:
+    bool operator==(const Vec& o) const {
+        return true;
+    }
+    int& operator[](int i) {
+        return x;
+    }
+    int operator()(int a) {
+        return a;
+    }
+    explicit operator bool() const {
+        return true;
+    }
+    void reset() {}
+};
+
+Vec Vec::operator+(const Vec& o) const {
+    return o;
+}
+`, "operator==/operator operator[]/operator operator()/operator operator bool/operator reset/ operator+/operator"},
+		{"cs", `public struct Money
+{
+    public static bool operator ==(Money a, Money b)
+    {
+        return true;
+    }
+
+    public static implicit operator decimal(Money m)
+    {
+        return 0;
+    }
+}
+`, "operator==/operator operator decimal/operator"},
+	}
+	for _, tt := range tests {
+		result, err := NewFinder(config[tt.lang], nil, true, false, false).FindFunctionsInLines(strings.Split(tt.src, "\n"), 1, "op."+tt.lang)
+		if err != nil {
+			t.Fatalf("%s: FindFunctionsInLines() error = %v", tt.lang, err)
+		}
+		if got := namesAndKinds(result.Functions); got != tt.want {
+			t.Errorf("%s: functions = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestOperatorOverloads_PythonDunders(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	src := "class Vec:\n    def __init__(self):\n        pass\n\n    def __eq__(self, other):\n        return True\n\n    def __radd__(self, other):\n        return self\n\n    def norm(self):\n        return 0\n"
+	functions := findPython(t, config["py"], src, false)
+
+	if got, want := namesAndKinds(functions), "__init__/special __eq__/operator __radd__/operator norm/"; got != want {
+		t.Errorf("functions = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+// parallel_file.go - One large file scanned in chunks by several workers (--parallel-file)
+package internal
+
+import (
+	"runtime"
+	"sync"
+)
+
+// MinParallelChunkLines is the smallest region a worker is given: below it
+// goroutine overhead outweighs the scan
+const MinParallelChunkLines = 500
+
+// ParallelFileSupported reports whether FindFunctionsParallel can split
+// files of config's language. Regions are cut where the brace depth returns
+// to zero, which only separates functions in brace languages whose
+// functions don't nest.
+func ParallelFileSupported(config *LanguageConfig) bool {
+	return !config.SupportsNested && !config.IndentBased && config.BlockEndKeyword == "" && config.LangKey != "hs"
+}
+
+// splitTopLevelRegions cuts lines into about chunks regions of whole
+// top-level definitions and returns their [start, end) line indexes. A cut
+// is only made after a line that brings the brace depth back to zero
+// outside comments and strings, so a signature whose brace opens on the
+// next line (Allman style) and a class body stay in one region. A file
+// that is one class body is a single region.
+func splitTopLevelRegions(lines []string, sanitizer *Sanitizer, chunks int) [][2]int {
+	return cutTopLevelRegions(lines, sanitizer, max(len(lines)/max(chunks, 1), MinParallelChunkLines))
+}
+
+// cutTopLevelRegions cuts lines after every line that closes a top-level
+// block once the current region has at least target lines
+func cutTopLevelRegions(lines []string, sanitizer *Sanitizer, target int) [][2]int {
+	var regions [][2]int
+	state := StateNormal
+	depth, start := 0, 0
+	for i, line := range lines {
+		cleaned, newState := sanitizer.CleanLine(line, state)
+		state = newState
+		prevDepth := depth
+		depth += CountBraces(cleaned)
+		if depth < 0 {
+			depth = 0
+		}
+		if prevDepth > 0 && depth == 0 && state == StateNormal && i+1-start >= target {
+			regions = append(regions, [2]int{start, i + 1})
+			start = i + 1
+		}
+	}
+	if start < len(lines) || len(regions) == 0 {
+		regions = append(regions, [2]int{start, len(lines)})
+	}
+	return regions
+}
+
+// FindFunctionsParallel finds the functions of lines like
+// FindFunctionsInLines(lines, 1, filename), with the file split into
+// top-level regions that workers scan in parallel (0 = one per CPU).
+// Functions, classes and ambiguities are merged in file order. Languages
+// ParallelFileSupported rejects, and files too small to split, are scanned
+// serially.
+func (f *Finder) FindFunctionsParallel(lines []string, filename string, workers int) (*FindResult, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers == 1 || !ParallelFileSupported(f.config) || len(lines) < 2*MinParallelChunkLines {
+		return f.FindFunctionsInLines(lines, 1, filename)
+	}
+
+	regions := splitTopLevelRegions(lines, f.sanitizer, workers*4)
+	results := make([]*FindResult, len(regions))
+	errs := make([]error, len(regions))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(regions)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r := regions[i]
+				results[i], errs[i] = f.FindFunctionsInLines(lines[r[0]:r[1]], r[0]+1, filename)
+			}
+		}()
+	}
+	for i := range regions {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	merged := &FindResult{Filename: filename, Functions: []FunctionBounds{}}
+	if !f.noClasses {
+		merged.Classes = []ClassBounds{}
+	}
+	for i, r := range results {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		merged.Functions = append(merged.Functions, r.Functions...)
+		if !f.noClasses {
+			merged.Classes = append(merged.Classes, r.Classes...)
+		}
+		merged.Ambiguities = append(merged.Ambiguities, r.Ambiguities...)
+	}
+	return merged, nil
+}
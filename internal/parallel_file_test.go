@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// syntheticCppSource builds n functions in mixed brace styles, with braces
+// in comments and strings and a class every 50 functions
+func syntheticCppSource(n int) []string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		if i%50 == 0 {
+			fmt.Fprintf(&sb, "class Widget%d\n{\npublic:\n    int get() const { return v_; }\n    int v_;\n};\n\n", i)
+		}
+		if i%2 == 0 {
+			fmt.Fprintf(&sb, "int func%d(int x)\n{\n    if (x > %d) {\n        return x;\n    }\n    /* } */ return \"}\"[0];\n}\n\n", i, i)
+		} else {
+			fmt.Fprintf(&sb, "static void func%d(const char *s) {\n    // {\n    puts(s);\n}\n\n", i)
+		}
+	}
+	return strings.Split(sb.String(), "\n")
+}
+
+func newCppMapFinder(t testing.TB) *Finder {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	return NewFinder(config["cpp"], nil, true, true, false)
+}
+
+func TestFindFunctionsParallel_MatchesSerial(t *testing.T) {
+	lines := syntheticCppSource(2000)
+	finder := newCppMapFinder(t)
+
+	serial, err := finder.FindFunctionsInLines(lines, 1, "big.cpp")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	parallel, err := finder.FindFunctionsParallel(lines, "big.cpp", 4)
+	if err != nil {
+		t.Fatalf("FindFunctionsParallel() error = %v", err)
+	}
+
+	if len(serial.Functions) < 2000 {
+		t.Fatalf("serial scan found %d functions, want at least 2000", len(serial.Functions))
+	}
+	if !reflect.DeepEqual(parallel.Functions, serial.Functions) {
+		t.Errorf("parallel functions differ from serial: %d vs %d", len(parallel.Functions), len(serial.Functions))
+	}
+	if !reflect.DeepEqual(parallel.Classes, serial.Classes) {
+		t.Errorf("parallel classes = %d, serial %d", len(parallel.Classes), len(serial.Classes))
+	}
+	if len(parallel.Ambiguities) != len(serial.Ambiguities) {
+		t.Errorf("parallel ambiguities = %v, serial %v", parallel.Ambiguities, serial.Ambiguities)
+	}
+}
+
+func TestSplitTopLevelRegions_CutsAtDepthZero(t *testing.T) {
+	lines := syntheticCppSource(1000)
+	finder := newCppMapFinder(t)
+	regions := splitTopLevelRegions(lines, finder.sanitizer, 8)
+	if len(regions) < 2 {
+		t.Fatalf("regions = %v, want the file split", regions)
+	}
+	next := 0
+	for _, r := range regions {
+		if r[0] != next {
+			t.Fatalf("regions = %v: gap or overlap at %d", regions, next)
+		}
+		if r[0] > 0 && !strings.HasPrefix(strings.TrimSpace(lines[r[0]-1]), "}") {
+			t.Errorf("region starting at line %d follows %q, not a closing brace", r[0]+1, lines[r[0]-1])
+		}
+		next = r[1]
+	}
+	if next != len(lines) {
+		t.Errorf("regions end at %d, want %d", next, len(lines))
+	}
+}
+
+func TestFindFunctionsParallel_NestedLanguageIsSerial(t *testing.T) {
+	src := strings.Repeat("func a() {\n\tfunc b() {}\n}\n", 500)
+	finder := NewFinder(getGoConfig(t), nil, true, false, false)
+	lines := strings.Split(src, "\n")
+	serial, _ := finder.FindFunctionsInLines(lines, 1, "p.go")
+	parallel, err := finder.FindFunctionsParallel(lines, "p.go", 4)
+	if err != nil {
+		t.Fatalf("FindFunctionsParallel() error = %v", err)
+	}
+	if !reflect.DeepEqual(parallel, serial) {
+		t.Error("Go (supports_nested) was not scanned serially")
+	}
+}
+
+func BenchmarkFindFunctions_SerialLargeFile(b *testing.B) {
+	lines := syntheticCppSource(5000)
+	finder := newCppMapFinder(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		finder.FindFunctionsInLines(lines, 1, "big.cpp")
+	}
+}
+
+func BenchmarkFindFunctions_ParallelLargeFile(b *testing.B) {
+	lines := syntheticCppSource(5000)
+	finder := newCppMapFinder(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		finder.FindFunctionsParallel(lines, "big.cpp", 4)
+	}
+}
@@ -0,0 +1,127 @@
+// params.go - Parameter counts of function signatures (--max-params)
+package internal
+
+import "strings"
+
+// AttachParamCounts sets FunctionBounds.ParamCount for every function.
+// lines are the whole file and Start is a 1-based line number into it.
+func AttachParamCounts(functions []FunctionBounds, lines []string, config *LanguageConfig) {
+	cleaned := NewSanitizer(config, false).CleanLines(lines)
+	for i := range functions {
+		fn := &functions[i]
+		if fn.Start >= 1 && fn.Start <= len(cleaned) {
+			fn.ParamCount = CountParams(cleaned[fn.Start-1:], fn.Name)
+		}
+	}
+}
+
+// FilterByMaxParams keeps functions with more than maxParams parameters
+func FilterByMaxParams(functions []FunctionBounds, maxParams int) []FunctionBounds {
+	filtered := []FunctionBounds{}
+	for _, fn := range functions {
+		if fn.ParamCount > maxParams {
+			filtered = append(filtered, fn)
+		}
+	}
+	return filtered
+}
+
+// CountParams counts the parameters of the signature starting at lines[0]
+// (sanitized, so strings and comments can't add commas). The parameter list
+// is the parenthesized group after name, past any generic or template
+// arguments (Go "Map[K, V](", Java "<T, U> T f("), or the first group when
+// name is not on the line (Go method receivers come before the name). It
+// may continue over following lines. Top-level commas separate parameters:
+// commas nested in (), [], {} or <> (func types, generics) don't count, and
+// an empty list, "(void)" or a trailing comma adds nothing.
+func CountParams(lines []string, name string) int {
+	if len(lines) == 0 {
+		return 0
+	}
+	pos := 0
+	if name != "" {
+		if idx := indexName(lines[0], name); idx >= 0 {
+			pos = idx + len(name)
+		}
+	}
+
+	depth := 0       // nesting inside the parameter list
+	count := 0       // parameters closed by a top-level comma
+	current := false // non-space text since the last comma
+	var list strings.Builder
+	for i, line := range lines {
+		if i >= MaxSignatureLines {
+			break
+		}
+		if i > 0 {
+			pos = 0
+		}
+		for j := pos; j < len(line); j++ {
+			c := line[j]
+			if depth == 0 {
+				if c == '(' {
+					depth = 1
+				}
+				continue
+			}
+			switch c {
+			case '(', '[', '{':
+				depth++
+			case '<':
+				// Go "<-chan" and "chan<-" are channel directions, not brackets
+				if j+1 >= len(line) || line[j+1] != '-' {
+					depth++
+				}
+			case ')', ']', '}':
+				depth--
+			case '>':
+				// "->" (Rust/TS return types) and "=>" are not brackets
+				if j == 0 || (line[j-1] != '-' && line[j-1] != '=') {
+					depth--
+				}
+			case ',':
+				if depth == 1 {
+					count++
+					current = false
+					list.Reset()
+					continue
+				}
+			}
+			if depth == 0 {
+				if current && strings.TrimSpace(list.String()) != "void" {
+					count++
+				}
+				return count
+			}
+			if c != ' ' && c != '\t' {
+				current = true
+			}
+			list.WriteByte(c)
+		}
+	}
+	if current {
+		count++
+	}
+	return count
+}
+
+// indexName returns the index of name as a whole identifier in line, or -1
+func indexName(line, name string) int {
+	for from := 0; from < len(line); {
+		idx := strings.Index(line[from:], name)
+		if idx < 0 {
+			return -1
+		}
+		idx += from
+		end := idx + len(name)
+		if (idx == 0 || !isIdentByte(line[idx-1])) && (end == len(line) || !isIdentByte(line[end])) {
+			return idx
+		}
+		from = idx + 1
+	}
+	return -1
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 0x80 || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountParams(t *testing.T) {
+	tests := []struct {
+		name string
+		sig  string
+		fn   string
+		want int
+	}{
+		{"three params", "func Three(a, b int, c string) {", "Three", 3},
+		{"no params", "func None() {", "None", 0},
+		{"func type param", "func Apply(xs []int, f func(int, int) (int, error)) {", "Apply", 2},
+		{"go receiver", "func (r *Repo) Find(ctx context.Context, id int) (*User, error) {", "Find", 2},
+		{"go generics", "func Map[K comparable, V any](m map[K]V, f func(K, V) bool) {", "Map", 2},
+		{"multiline", "func Multi(\n\tctx context.Context,\n\tm map[string]int,\n\tf func(int, int) error,\n) error {", "Multi", 3},
+		{"java generics", "public <K, V> Map<K, V> zip(List<K> keys, Map<String, V> values) {", "zip", 2},
+		{"c void", "int main(void) {", "main", 0},
+		{"rust return type", "fn apply(f: impl Fn(i32, i32) -> i32, x: i32) -> i32 {", "apply", 2},
+		{"string default", `def greet(name, sep=", ", *args):`, "greet", 3},
+		{"go channel directions", "func Pipe(in <-chan int, out chan<- int, n int) {", "Pipe", 3},
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	s := NewSanitizer(config["py"], false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := s.CleanLines(strings.Split(tt.sig, "\n"))
+			if got := CountParams(lines, tt.fn); got != tt.want {
+				t.Errorf("CountParams(%q) = %d, want %d", tt.sig, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachParamCounts_FilterByMaxParams(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	code := `package p
+
+func Three(a, b int, c string) {}
+
+func Multi(
+	ctx context.Context,
+	// a comment, with commas, in the list
+	callback func(int, string) error,
+) {
+}
+
+func One(x int) {}
+`
+	lines := strings.Split(code, "\n")
+	result, err := NewFinder(config["go"], nil, true, false, false).FindFunctionsInLines(lines, 1, "p.go")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	AttachParamCounts(result.Functions, lines, config["go"])
+
+	got := make(map[string]int)
+	for _, fn := range FilterByMaxParams(result.Functions, 1) {
+		got[fn.Name] = fn.ParamCount
+	}
+	if len(got) != 2 || got["Three"] != 3 || got["Multi"] != 2 {
+		t.Errorf("FilterByMaxParams(1) = %v, want Three:3 Multi:2", got)
+	}
+}
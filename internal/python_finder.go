@@ -1,18 +1,21 @@
 package internal
 
 import (
-	"fmt"
+	"io"
 	"os"
 	"strings"
 )
 
-// PythonFinder - парсер для Python с поддержкой отступов и декораторов
+// PythonFinder - парсер для языков с блоками на отступах (Python, Nim)
+// с поддержкой декораторов
 type PythonFinder struct {
 	config          LanguageConfig
 	funcNames       map[string]bool
 	mode            string
 	extract         bool
 	decoratorWindow *DecoratorWindow
+	noClasses       bool
+	skipGenerators  bool // IsGenerator не нужен выводу
 }
 
 // NewPythonFinder создает новый парсер для Python
@@ -35,27 +38,57 @@ func NewPythonFinder(config LanguageConfig, funcNames string, mode string, extra
 	}
 }
 
+// SetNoClasses отключает поиск классов (--no-classes): ClassName остается
+// пустым, а Classes — nil
+func (pf *PythonFinder) SetNoClasses(noClasses bool) {
+	pf.noClasses = noClasses
+}
+
+// SetSkipGenerators отключает пометку генераторов: IsGenerator остается false
+func (pf *PythonFinder) SetSkipGenerators(skip bool) {
+	pf.skipGenerators = skip
+}
+
 // FindFunctions находит функции в Python файле, используя анализ отступов
 func (pf *PythonFinder) FindFunctions(filename string) (*FindResult, error) {
-	content, err := os.ReadFile(filename)
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, newOpenError(filename, err)
+	}
+	defer file.Close()
+
+	return pf.FindFunctionsInReader(file, filename)
+}
+
+// FindFunctionsInReader находит функции в Python коде, прочитанном из r
+func (pf *PythonFinder) FindFunctionsInReader(r io.Reader, filename string) (*FindResult, error) {
+	content, err := io.ReadAll(r)
 	if err != nil {
-		return nil, err
+		return nil, newReadError(filename, err)
 	}
 
 	lines := strings.Split(string(content), "\n")
 	functions := make([]FunctionBounds, 0)
 
+	// Finder may be reused across files (DirProcessor caches it per worker)
+	pf.decoratorWindow.Clear()
+
 	regex := pf.config.FuncRegex()
 	if regex == nil {
-		return nil, fmt.Errorf("failed to compile function regex")
+		return nil, newFuncRegexError(filename)
 	}
 
+	// Границы блоков ищем по очищенным строкам: строки и комментарии
+	// (в том числе строки многострочных литералов) не влияют на отступы
+	cleaned := NewSanitizer(&pf.config, false).CleanLines(lines)
+	blockSuffix := pf.config.BlockSuffix()
+
 	for i := 0; i < len(lines); i++ {
 		line := lines[i]
 		pf.decoratorWindow.Add(line, i+1)
 
-		// Проверяем, начинается ли функция
-		matches := regex.FindStringSubmatch(line)
+		// Проверяем, начинается ли функция (def внутри строки - не функция)
+		matches := regex.FindStringSubmatch(cleaned[i])
 		if matches == nil {
 			continue
 		}
@@ -87,27 +120,24 @@ func (pf *PythonFinder) FindFunctions(filename string) (*FindResult, error) {
 			startLine = firstDecoratorLine
 		}
 
-		// Находим конец сигнатуры функции (может быть multiline)
-		// Ищем строку с ':' в конце
-		signatureEnd := i
-		for j := i; j < len(lines); j++ {
-			trimmed := strings.TrimSpace(lines[j])
-			if strings.HasSuffix(trimmed, ":") {
-				signatureEnd = j
-				break
-			}
-		}
+		// Находим конец сигнатуры функции (может быть multiline):
+		// строка, на которой закрываются все скобки
+		signatureEnd := headerEnd(cleaned, i)
 
 		// Находим конец функции на основе отступов
-		funcIndent := GetIndentLevel(lines[signatureEnd])
+		funcIndent := GetIndentLevel(lines[i])
 		endLine := signatureEnd + 1
 
+		// Заголовок без суффикса блока - однострочная функция
+		// ("def f(): return 1", "proc f(): int = 42") или объявление
+		hasBody := strings.HasSuffix(strings.TrimSpace(cleaned[signatureEnd]), blockSuffix)
+
 		// Ищем конец функции
-		for j := signatureEnd + 1; j < len(lines); j++ {
-			currentLine := lines[j]
+		for j := signatureEnd + 1; hasBody && j < len(lines); j++ {
+			currentLine := cleaned[j]
 
 			// Пропускаем пустые строки и комментарии
-			if IsEmptyOrComment(currentLine, "#") {
+			if IsEmptyOrComment(currentLine, pf.config.LineComment) {
 				endLine = j + 1
 				continue
 			}
@@ -134,6 +164,7 @@ func (pf *PythonFinder) FindFunctions(filename string) (*FindResult, error) {
 			End:        endLine,
 			Lines:      body,
 			Decorators: decorators,
+			Kind:       functionKind(funcName),
 		}
 
 		functions = append(functions, function)
@@ -146,8 +177,69 @@ func (pf *PythonFinder) FindFunctions(filename string) (*FindResult, error) {
 		}
 	}
 
-	return &FindResult{
+	// Генераторы: yield в собственном теле функции
+	if generatorRe := pf.config.GeneratorRegex(); generatorRe != nil && !pf.skipGenerators {
+		markGenerators(functions, cleaned, 0, generatorRe)
+	}
+
+	result := &FindResult{
 		Functions: functions,
 		Filename:  filename,
-	}, nil
+	}
+	// Классы (и вложенные): границы - по областям AnalyzePythonScopes над
+	// очищенными строками ("class X:" внутри строки - не класс),
+	// методы получают ClassName ближайшего объемлющего класса
+	if pf.config.ClassRegex() != nil && !pf.noClasses {
+		scopes, err := analyzeCleanPythonScopes(cleaned)
+		if err != nil {
+			return nil, newReadError(filename, err)
+		}
+		result.Classes = pythonClasses(scopes)
+		assignPythonClasses(result.Functions, result.Classes)
+	}
+	return result, nil
+}
+
+// pythonClasses - классы среди областей AnalyzePythonScopes, по строке начала
+func pythonClasses(scopes []PythonScope) []ClassBounds {
+	classes := []ClassBounds{}
+	for _, scope := range scopes {
+		if scope.Kind == "class" {
+			classes = append(classes, ClassBounds{Name: scope.Name, Start: scope.StartLine, End: scope.EndLine})
+		}
+	}
+	return classes
+}
+
+// assignPythonClasses задает ClassName и Scope функциям внутри классов:
+// классы вложены друг в друга, поэтому выбирается самый внутренний
+func assignPythonClasses(functions []FunctionBounds, classes []ClassBounds) {
+	for i := range functions {
+		fn := &functions[i]
+		best := -1
+		for j, class := range classes {
+			if fn.Start > class.Start && fn.Start <= class.End && (best < 0 || class.Start > classes[best].Start) {
+				best = j
+			}
+		}
+		if best >= 0 {
+			fn.ClassName = classes[best].Name
+			fn.Scope = classes[best].Name
+		}
+	}
+}
+
+// headerEnd возвращает последнюю строку заголовка, начатого в строке i:
+// строку, где закрыты все скобки. Если скобки не закрываются за
+// MaxSignatureLines строк, заголовок считается однострочным.
+func headerEnd(cleaned []string, i int) int {
+	depth := 0
+	for j := i; j < len(cleaned) && j < i+MaxSignatureLines; j++ {
+		depth += strings.Count(cleaned[j], "(") + strings.Count(cleaned[j], "[") + strings.Count(cleaned[j], "{")
+		depth -= strings.Count(cleaned[j], ")") + strings.Count(cleaned[j], "]") + strings.Count(cleaned[j], "}")
+		if depth <= 0 {
+			return j
+		}
+	}
+	return i
 }
@@ -1,7 +1,9 @@
 package internal
 
 import (
+	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -486,6 +488,101 @@ func TestPythonFinder_NestedFunctions(t *testing.T) {
 	}
 }
 
+func TestPythonFinder_ClassesGroupMethodsInTree(t *testing.T) {
+	src := `import os
+
+class A:
+    def one(self):
+        return 1
+
+    def two(self):
+        return 2
+
+class B(A):
+    x = 1
+
+    def three(self):
+        return 3
+
+def free():
+    return 0
+`
+	pf := NewPythonFinder(*getPyConfig(t), "", "map", false)
+	result, err := pf.FindFunctionsInReader(strings.NewReader(src), "two.py")
+	if err != nil {
+		t.Fatalf("FindFunctionsInReader() error = %v", err)
+	}
+
+	jsonStr, err := TreeToJSON(result, false)
+	if err != nil {
+		t.Fatalf("TreeToJSON() error = %v", err)
+	}
+	var tree TreeOutput
+	if err := json.Unmarshal([]byte(jsonStr), &tree); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+
+	type class struct {
+		name       string
+		start, end int
+		methods    string
+	}
+	want := []class{{"A", 3, 9, "one two"}, {"B", 10, 15, "three"}}
+	if len(tree.Classes) != len(want) {
+		t.Fatalf("got %d classes, want %d: %s", len(tree.Classes), len(want), jsonStr)
+	}
+	for i, c := range tree.Classes {
+		var methods []string
+		for _, m := range c.Methods {
+			methods = append(methods, m.Name)
+		}
+		got := class{c.Name, c.Start, c.End, strings.Join(methods, " ")}
+		if got != want[i] {
+			t.Errorf("class %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+	if len(tree.Functions) != 1 || tree.Functions[0].Name != "free" {
+		t.Errorf("top-level functions = %+v, want only free", tree.Functions)
+	}
+
+	pf.SetNoClasses(true)
+	result, err = pf.FindFunctionsInReader(strings.NewReader(src), "two.py")
+	if err != nil {
+		t.Fatalf("FindFunctionsInReader() error = %v", err)
+	}
+	if len(result.Classes) != 0 {
+		t.Errorf("SetNoClasses(true): got %d classes, want 0", len(result.Classes))
+	}
+}
+
+func TestPythonFinder_ClassInStringIsNotAClass(t *testing.T) {
+	src := `class C:
+    def m(self):
+        s = """
+class X:
+    def fake(self):
+"""
+        return s
+
+    def k(self):
+        return 1
+`
+	result, err := NewPythonFinder(*getPyConfig(t), "", "map", false).FindFunctionsInReader(strings.NewReader(src), "c.py")
+	if err != nil {
+		t.Fatalf("FindFunctionsInReader() error = %v", err)
+	}
+	if len(result.Classes) != 1 || result.Classes[0].Name != "C" || result.Classes[0].Start != 1 || result.Classes[0].End != 10 {
+		t.Fatalf("classes = %+v, want only C 1-10", result.Classes)
+	}
+	var names []string
+	for _, fn := range result.Functions {
+		names = append(names, fn.ClassName+"."+fn.Name)
+	}
+	if got := strings.Join(names, " "); got != "C.m C.k" {
+		t.Errorf("functions = %q, want %q", got, "C.m C.k")
+	}
+}
+
 // Helper function to create temporary file with content
 func createTempFile(t *testing.T, content string, pattern string) string {
 	t.Helper()
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"bufio"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -36,19 +37,36 @@ func AnalyzePythonScopes(filePath string) ([]PythonScope, error) {
 		return nil, err
 	}
 	defer file.Close()
+	return analyzePythonScopes(file)
+}
+
+// analyzeCleanPythonScopes is analyzePythonScopes over lines already
+// cleaned by the Sanitizer, so "class X:" inside a string opens no scope
+func analyzeCleanPythonScopes(cleaned []string) ([]PythonScope, error) {
+	return analyzePythonScopes(strings.NewReader(strings.Join(cleaned, "\n")))
+}
 
+// analyzePythonScopes is AnalyzePythonScopes over source read from r
+func analyzePythonScopes(r io.Reader) ([]PythonScope, error) {
 	// Use pointers for heap-allocated scopes that can be updated
 	scopeStack := []*PythonScope{} // stack of parent scopes (pointers to heap)
 	allScopes := []*PythonScope{}  // track all scopes we created
 	currentIndent := 0
 	lineNum := 0
-	pendingDecorators := 0 // count of decorators before current def/class
+	decoratorStart := 0  // line of the first decorator before current def/class
+	decoratorParens := 0 // unclosed parentheses of a multiline decorator
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		lineNum++
 		line := scanner.Text()
 
+		// Continuation of a decorator whose arguments span several lines
+		if decoratorParens > 0 {
+			decoratorParens += pythonParenDelta(line)
+			continue
+		}
+
 		// Skip empty lines
 		trimmed := strings.TrimSpace(line)
 		if trimmed == "" {
@@ -66,9 +84,12 @@ func AnalyzePythonScopes(filePath string) ([]PythonScope, error) {
 			continue
 		}
 
-		// Handle decorators: count them, they don't end scopes
+		// Handle decorators: remember where they start, they don't end scopes
 		if strings.HasPrefix(trimmed, "@") {
-			pendingDecorators++
+			if decoratorStart == 0 {
+				decoratorStart = lineNum
+			}
+			decoratorParens = max(pythonParenDelta(trimmed), 0)
 			continue
 		}
 
@@ -78,10 +99,16 @@ func AnalyzePythonScopes(filePath string) ([]PythonScope, error) {
 		isClass := strings.HasPrefix(trimmed, "class ")
 
 		if isDef || isClass {
+			// The new scope starts at its first decorator
+			startLine := lineNum
+			if decoratorStart != 0 {
+				startLine = decoratorStart
+			}
+
 			// Pop scopes that ended before this line
 			for len(scopeStack) > 0 && indent <= scopeStack[len(scopeStack)-1].StartIndent {
 				closed := scopeStack[len(scopeStack)-1]
-				closed.EndLine = lineNum - 1
+				closed.EndLine = startLine - 1
 				scopeStack = scopeStack[:len(scopeStack)-1]
 			}
 
@@ -97,13 +124,17 @@ func AnalyzePythonScopes(filePath string) ([]PythonScope, error) {
 				scopePtr.Name = parts[0]
 				scopePtr.Kind = "function"
 			} else {
-				parts := strings.SplitN(trimmed[6:], ":", 2)
-				scopePtr.Name = parts[0]
+				// "class B(A):", "class Box[T]:" - the name ends before the bases
+				name := strings.TrimSpace(trimmed[6:])
+				if i := strings.IndexAny(name, "([: \t"); i >= 0 {
+					name = name[:i]
+				}
+				scopePtr.Name = name
 				scopePtr.Kind = "class"
 			}
-			scopePtr.StartLine = lineNum - pendingDecorators
+			scopePtr.StartLine = startLine
 			scopePtr.StartIndent = indent
-			pendingDecorators = 0 // reset after creating scope
+			decoratorStart = 0 // reset after creating scope
 
 			// Set parent
 			if len(scopeStack) > 0 {
@@ -159,6 +190,33 @@ func AnalyzePythonScopes(filePath string) ([]PythonScope, error) {
 	return result, scanner.Err()
 }
 
+// pythonParenDelta returns opened minus closed brackets on a line, ignoring
+// string literals and comments
+func pythonParenDelta(line string) int {
+	delta := 0
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return delta
+		case c == '(' || c == '[' || c == '{':
+			delta++
+		case c == ')' || c == ']' || c == '}':
+			delta--
+		}
+	}
+	return delta
+}
+
 // ValidateAndFixLineRange performs Pass 2: validates and adjusts line range
 func ValidateAndFixLineRange(scopes []PythonScope, requestedStart, requestedEnd int) (int, int, []LineAdjustment) {
 	adjustments := []LineAdjustment{}
@@ -285,6 +343,11 @@ func ValidateAndFixLineRange(scopes []PythonScope, requestedStart, requestedEnd
 
 // FormatLineAdjustmentReport creates a human-readable report of adjustments
 func FormatLineAdjustmentReport(adjustments []LineAdjustment, originalStart, originalEnd, fixedStart, fixedEnd int) string {
+	return formatAdjustmentReport("|           PYTHON LINES RANGE ADJUSTMENT REPORT                  |", adjustments, originalStart, originalEnd, fixedStart, fixedEnd)
+}
+
+// formatAdjustmentReport renders the adjustment box under the given title line
+func formatAdjustmentReport(title string, adjustments []LineAdjustment, originalStart, originalEnd, fixedStart, fixedEnd int) string {
 	if len(adjustments) == 0 {
 		return ""
 	}
@@ -292,7 +355,7 @@ func FormatLineAdjustmentReport(adjustments []LineAdjustment, originalStart, ori
 	var sb strings.Builder
 	sb.WriteString("\n")
 	sb.WriteString("+------------------------------------------------------------------+\n")
-	sb.WriteString("|           PYTHON LINES RANGE ADJUSTMENT REPORT                  |\n")
+	sb.WriteString(title + "\n")
 	sb.WriteString("+------------------------------------------------------------------+\n")
 	sb.WriteString("| Requested range: " + formatLineRange(originalStart, originalEnd) + padToLen(formatLineRange(originalStart, originalEnd), 55) + "|\n")
 	sb.WriteString("| Adjusted range:  " + formatLineRange(fixedStart, fixedEnd) + padToLen(formatLineRange(fixedStart, fixedEnd), 55) + "|\n")
@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyzePythonScopes_Decorators(t *testing.T) {
+	code := `import functools
+
+
+@functools.cache
+def single():
+    return 1
+
+
+@app.route(
+    "/path",
+    methods=["GET"],
+)
+@login_required
+def multi():
+    return 2
+
+
+class Handler:
+    @staticmethod
+    @retry(times=3,
+           on=(IOError, "closing ) in a string"))
+    def method():
+        pass
+`
+	path := filepath.Join(t.TempDir(), "views.py")
+	mustWrite(t, path, code)
+
+	scopes, err := AnalyzePythonScopes(path)
+	if err != nil {
+		t.Fatalf("AnalyzePythonScopes() error = %v", err)
+	}
+
+	want := map[string][2]int{
+		"single":  {4, 8},
+		"multi":   {9, 17},
+		"Handler": {18, 23},
+		"method":  {19, 23},
+	}
+	if len(scopes) != len(want) {
+		t.Fatalf("got %d scopes, want %d: %+v", len(scopes), len(want), scopes)
+	}
+	for _, s := range scopes {
+		w, ok := want[s.Name]
+		if !ok {
+			t.Errorf("unexpected scope %q", s.Name)
+			continue
+		}
+		if s.StartLine != w[0] || s.EndLine != w[1] {
+			t.Errorf("%s: got %d-%d, want %d-%d", s.Name, s.StartLine, s.EndLine, w[0], w[1])
+		}
+	}
+}
@@ -1,10 +1,10 @@
 // python_struct_finder.go - Find Python data types and classes
 // Supports: class, dataclass, NamedTuple, TypedDict, Enum, attrs
+// Other indent-based languages (Nim) use their struct_type_patterns
 package internal
 
 import (
 	"bufio"
-	"fmt"
 	"os"
 	"regexp"
 	"strings"
@@ -37,7 +37,7 @@ func NewPythonStructFinder(config LanguageConfig, typeNamesStr string, mapMode,
 func (f *PythonStructFinder) FindStructures(filename string) (*StructFindResult, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, newOpenError(filename, err)
 	}
 	defer file.Close()
 
@@ -47,7 +47,7 @@ func (f *PythonStructFinder) FindStructures(filename string) (*StructFindResult,
 		lines = append(lines, scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, newReadError(filename, err)
 	}
 
 	return f.FindStructuresInLines(lines, 1, filename)
@@ -62,13 +62,19 @@ func (f *PythonStructFinder) FindStructuresInLines(lines []string, startLine int
 		Types:    []TypeBounds{},
 	}
 
+	// Python patterns are built in; other languages bring their own
+	findTypes, findFields := f.findAllTypes, f.findFieldsForType
+	if f.config.LangKey != "py" {
+		findTypes, findFields = f.findConfiguredTypes, f.findConfiguredFields
+	}
+
 	// Find all class/type definitions
-	types := f.findAllTypes(lines, lineOffset)
+	types := findTypes(lines, lineOffset)
 
 	// For each type, find its fields
 	for i := range types {
 		typeBounds := &types[i]
-		fields := f.findFieldsForType(lines, typeBounds, lineOffset)
+		fields := findFields(lines, typeBounds, lineOffset)
 		typeBounds.Fields = fields
 		result.Types = append(result.Types, *typeBounds)
 	}
@@ -103,9 +109,11 @@ var (
 	protocolPattern = regexp.MustCompile(`^\s*class\s+(\w+)\s*\(\s*Protocol\s*\)\s*:`)
 )
 
-// findAllTypes finds all type definitions in Python file
+// findAllTypes finds all type definitions in Python file, on sanitized
+// lines: a "class X:" in a string is not a type
 func (f *PythonStructFinder) findAllTypes(lines []string, lineOffset int) []TypeBounds {
 	var types []TypeBounds
+	lines = NewSanitizer(&f.config, false).CleanLines(lines)
 
 	for lineNum, line := range lines {
 		trimmed := strings.TrimSpace(line)
@@ -277,6 +285,84 @@ func (f *PythonStructFinder) findFieldsForType(lines []string, typeBounds *TypeB
 	return fields
 }
 
+// findConfiguredTypes finds types with the language's struct_type_patterns
+// (Nim "Point* = object"); the body is the block indented below the header
+func (f *PythonStructFinder) findConfiguredTypes(lines []string, lineOffset int) []TypeBounds {
+	var types []TypeBounds
+	kinds := f.config.StructPatternKinds()
+	cleaned := NewSanitizer(&f.config, false).CleanLines(lines)
+
+	for lineNum, line := range cleaned {
+		for _, kind := range kinds {
+			typeName := matchName(f.config.GetStructPattern(kind), line)
+			if typeName == "" {
+				continue
+			}
+			if f.mapMode || f.typeNames[typeName] {
+				types = append(types, TypeBounds{
+					Name:   typeName,
+					Kind:   kind,
+					Start:  lineNum + 1 + lineOffset,
+					End:    f.findTypeEnd(cleaned, lineNum, lineOffset),
+					Fields: []FieldBounds{},
+				})
+			}
+			break
+		}
+	}
+
+	return types
+}
+
+// findConfiguredFields matches field_pattern against the lines one level
+// below the type header; deeper lines (nested cases, inline objects) are
+// skipped. A declaration of several names (Nim "x*, y*: float") gives one
+// field per name, all with the same type.
+func (f *PythonStructFinder) findConfiguredFields(lines []string, typeBounds *TypeBounds, lineOffset int) []FieldBounds {
+	fieldRe := f.config.GetFieldPattern()
+	if fieldRe == nil {
+		return []FieldBounds{}
+	}
+
+	fields := []FieldBounds{}
+	cleaned := NewSanitizer(&f.config, false).CleanLines(lines)
+	startIdx := typeBounds.Start - 1 - lineOffset
+	memberIndent := -1
+
+	for lineNum := startIdx + 1; lineNum < typeBounds.End-lineOffset && lineNum < len(cleaned); lineNum++ {
+		line := cleaned[lineNum]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := GetIndentLevel(line)
+		if memberIndent < 0 {
+			memberIndent = indent
+		}
+		if indent != memberIndent {
+			continue
+		}
+		loc := fieldRe.FindStringSubmatchIndex(line)
+		if loc == nil || len(loc) < 6 || loc[2] < 0 || loc[4] < loc[2] {
+			continue
+		}
+		fieldType := strings.TrimSpace(line[loc[4]:loc[5]])
+		// The names are everything from group 1 up to the ':' before the type
+		names := strings.TrimSpace(line[loc[2]:loc[4]])
+		names = strings.TrimSpace(strings.TrimSuffix(names, ":"))
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.TrimSuffix(strings.TrimSpace(name), "*"); name != "" {
+				fields = append(fields, FieldBounds{
+					Name: name,
+					Type: fieldType,
+					Line: lineNum + 1 + lineOffset,
+				})
+			}
+		}
+	}
+
+	return fields
+}
+
 // Pattern to detect special class types from bases
 func detectPythonClassKind(bases string) string {
 	basesLower := strings.ToLower(bases)
@@ -0,0 +1,166 @@
+// returns.go - Number of values a function returns (--returns)
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pyReturnPattern matches a Python return statement with a value
+var pyReturnPattern = regexp.MustCompile(`^\s*return\s+(\S.*)$`)
+
+// AttachReturnCounts sets FunctionBounds.ReturnCount for Go and Python
+// functions; other languages keep 0. lines are the whole file and Start is a
+// 1-based line number into it.
+//
+// Go counts are read from the signature. Python has no declared result
+// arity, so the count is a heuristic over the body, which must have been
+// extracted (fn.Lines): the widest "return a, b" statement wins, a function
+// that only returns one value (or a tuple held in a variable) counts 1.
+func AttachReturnCounts(functions []FunctionBounds, lines []string, config *LanguageConfig) {
+	sanitizer := NewSanitizer(config, false)
+	switch config.LangKey {
+	case "go":
+		cleaned := sanitizer.CleanLines(lines)
+		for i := range functions {
+			fn := &functions[i]
+			if fn.Start >= 1 && fn.Start <= len(cleaned) {
+				fn.ReturnCount = CountGoReturns(cleaned[fn.Start-1:], fn.Name)
+			}
+		}
+	case "py":
+		for i := range functions {
+			fn := &functions[i]
+			if len(fn.Lines) == 0 {
+				continue
+			}
+			body := sanitizer.CleanLines(fn.Lines)
+			for k, line := range body {
+				if inNestedFunction(functions, i, fn.Start+k) {
+					continue
+				}
+				if pyReturnPattern.MatchString(line) {
+					m := pyReturnPattern.FindStringSubmatch(fillBlanked(fn.Lines[k], line))
+					fn.ReturnCount = max(fn.ReturnCount, countPythonReturnValues(m[1]))
+				}
+			}
+		}
+	}
+}
+
+// CountGoReturns counts the results of the Go signature starting at
+// lines[0] (sanitized): 0 without a result list, 1 for a single type, the
+// number of comma-separated entries of a parenthesized list otherwise, so
+// "(int, error)" and "(n, m int)" both count 2
+func CountGoReturns(lines []string, name string) int {
+	if len(lines) > MaxSignatureLines {
+		lines = lines[:MaxSignatureLines]
+	}
+	sig := strings.Join(lines, " ")
+	pos := 0
+	if name != "" {
+		if idx := indexName(sig, name); idx >= 0 {
+			pos = idx + len(name)
+		}
+	}
+
+	// Skip the parameter list; generic parameters before it have no '('
+	open := strings.IndexByte(sig[pos:], '(')
+	if open < 0 {
+		return 0
+	}
+	end := closingBracket(sig, pos+open)
+	if end < 0 {
+		return 0
+	}
+
+	rest := strings.TrimSpace(sig[end+1:])
+	if !strings.HasPrefix(rest, "(") {
+		// "int {", "error" or nothing before the body brace
+		if rest == "" || rest[0] == '{' {
+			return 0
+		}
+		return 1
+	}
+	closing := closingBracket(rest, 0)
+	if closing < 0 {
+		return 0
+	}
+	return countTopLevelItems(rest[1:closing])
+}
+
+// fillBlanked puts an 'x' wherever the sanitizer blanked a character of
+// raw, so a string literal still counts as a value ("return 1, 's'") while
+// its commas don't. A cleaned line of different length is returned as is.
+func fillBlanked(raw, cleaned string) string {
+	if len(raw) != len(cleaned) {
+		return cleaned
+	}
+	b := []byte(cleaned)
+	for i := range b {
+		if b[i] == ' ' && raw[i] != ' ' && raw[i] != '\t' {
+			b[i] = 'x'
+		}
+	}
+	return string(b)
+}
+
+// countPythonReturnValues counts the values of a return expression: "a, b"
+// and "(a, b)" are two, "f(a, b)" and "[a, b]" are one
+func countPythonReturnValues(expr string) int {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "(") && closingBracket(expr, 0) == len(expr)-1 {
+		if n := countTopLevelItems(expr[1 : len(expr)-1]); n > 1 {
+			return n
+		}
+		return 1
+	}
+	return max(countTopLevelItems(expr), 1)
+}
+
+// closingBracket returns the index of the bracket closing s[open], or -1
+func closingBracket(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// countTopLevelItems counts the non-empty comma-separated entries of list
+// that are not nested in brackets
+func countTopLevelItems(list string) int {
+	count, depth := 0, 0
+	current := false
+	for i := 0; i < len(list); i++ {
+		switch c := list[i]; c {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				if current {
+					count++
+				}
+				current = false
+				continue
+			}
+		case ' ', '\t':
+			continue
+		}
+		current = true
+	}
+	if current {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountGoReturns(t *testing.T) {
+	tests := []struct {
+		name string
+		sig  string
+		fn   string
+		want int
+	}{
+		{"value and error", "func Load(path string) (*Config, error) {", "Load", 2},
+		{"single value", "func Name() string {", "Name", 1},
+		{"no results", "func Run(ctx context.Context) {", "Run", 0},
+		{"named results", "func (r *Reader) Split(s string) (head, tail string) {", "Split", 2},
+		{"func type result", "func Handler() func(int, int) (int, error) {", "Handler", 1},
+		{"generic map result", "func Keys[K comparable, V any](m map[K]V) []K {", "Keys", 1},
+		{"multiline result list", "func Parse(\n\tsrc []byte,\n) (\n\tmap[string]int,\n\t[]string,\n\terror,\n) {", "Parse", 3},
+		{"interface method", "\tRead(p []byte) (n int, err error)", "Read", 2},
+	}
+	s := NewSanitizer(getGoConfig(t), false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := s.CleanLines(strings.Split(tt.sig, "\n"))
+			if got := CountGoReturns(lines, tt.fn); got != tt.want {
+				t.Errorf("CountGoReturns(%q) = %d, want %d", tt.sig, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachReturnCounts_Go(t *testing.T) {
+	src := "package p\n\nfunc Open(name string) (T, error) {\n\treturn T{}, nil\n}\n\nfunc Size() int { return 0 }\n"
+	config := getGoConfig(t)
+	result, err := NewFinder(config, nil, true, false, false).FindFunctionsInLines(strings.Split(src, "\n"), 1, "p.go")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines() error = %v", err)
+	}
+	AttachReturnCounts(result.Functions, strings.Split(src, "\n"), config)
+
+	got := map[string]int{}
+	for _, fn := range result.Functions {
+		got[fn.Name] = fn.ReturnCount
+	}
+	if got["Open"] != 2 || got["Size"] != 1 {
+		t.Errorf("ReturnCount = %v, want Open 2, Size 1", got)
+	}
+}
+
+func TestAttachReturnCounts_PythonHeuristic(t *testing.T) {
+	src := "def pair():\n    return 1, \"a, b\"\n\ndef wrapped():\n    return (min(1, 2), 3, 4)\n\ndef single():\n    t = (1, 2)\n    return t  # one name, arity unknown\n\ndef outer():\n    def inner():\n        return 1, 2\n    return inner\n\ndef bare():\n    return\n"
+	lines := strings.Split(src, "\n")
+	config := getPyConfig(t)
+	functions := findPython(t, config, src, true)
+	AttachReturnCounts(functions, lines, config)
+
+	want := map[string]int{"pair": 2, "wrapped": 3, "single": 1, "outer": 1, "inner": 2, "bare": 0}
+	for _, fn := range functions {
+		if fn.ReturnCount != want[fn.Name] {
+			t.Errorf("%s ReturnCount = %d, want %d", fn.Name, fn.ReturnCount, want[fn.Name])
+		}
+	}
+
+	// Without extracted bodies Python functions are not inspected
+	functions = findPython(t, config, src, false)
+	AttachReturnCounts(functions, lines, config)
+	for _, fn := range functions {
+		if fn.ReturnCount != 0 {
+			t.Errorf("%s ReturnCount = %d without bodies, want 0", fn.Name, fn.ReturnCount)
+		}
+	}
+}
+
+func findPython(t *testing.T, config *LanguageConfig, src string, extract bool) []FunctionBounds {
+	t.Helper()
+	result, err := NewPythonFinder(*config, "", "map", extract).FindFunctionsInReader(strings.NewReader(src), "p.py")
+	if err != nil {
+		t.Fatalf("FindFunctionsInReader() error = %v", err)
+	}
+	return result.Functions
+}
@@ -126,3 +126,71 @@ func TestCleanLine_NonASCII_MultilineDocstringClosesAcrossMultibyte(t *testing.T
 		t.Errorf("code after multibyte docstring was blanked: %q", lastClean)
 	}
 }
+
+// runeColumn returns the rune index of sub in s, or -1
+func runeColumn(s, sub string) int {
+	i := strings.Index(s, sub)
+	if i < 0 {
+		return -1
+	}
+	return utf8.RuneCountInString(s[:i])
+}
+
+func TestCleanLine_NonASCII_CodeKeepsRuneColumns(t *testing.T) {
+	goCfg := getGoConfig(t)
+	// CJK and emoji inside comments and strings are blanked one space per
+	// rune, so code after them stays in the same rune column as in the input
+	cases := []struct {
+		line   string
+		gone   string // literal or comment text that must be blanked
+		intact string // code that must survive at its original column
+	}{
+		{`a := "日本語テキスト" + b`, "日本語", "+ b"},
+		{`/* 注释 🚀 */ call(x)`, "注释", "call(x)"},
+		{`x := f("😀😀", '字') /* 末尾 */ + y`, "😀", "+ y"},
+		{`s := "中文 \"引号\" 文本"; t := g()`, "引号", "t := g()"},
+	}
+	for _, tt := range cases {
+		clean, state := NewSanitizer(goCfg, false).CleanLine(tt.line, StateNormal)
+		if state != StateNormal {
+			t.Errorf("%q: state = %v, want StateNormal", tt.line, state)
+		}
+		if strings.Contains(clean, tt.gone) {
+			t.Errorf("%q: %q not blanked: %q", tt.line, tt.gone, clean)
+		}
+		if got, want := runeColumn(clean, tt.intact), runeColumn(tt.line, tt.intact); got != want {
+			t.Errorf("%q: %q at rune column %d, want %d (clean %q)", tt.line, tt.intact, got, want, clean)
+		}
+	}
+}
+
+func TestCleanLines_NonASCII_MultilineCommentAndDocstring(t *testing.T) {
+	goLines := []string{
+		`/* 多行注释 🚀`,
+		`   第二行 */ func 関数() {`,
+		`	return "完了"`,
+		`}`,
+	}
+	clean := NewSanitizer(getGoConfig(t), false).CleanLines(goLines)
+	if strings.Contains(clean[1], "第二行") {
+		t.Errorf("comment continuation not blanked: %q", clean[1])
+	}
+	if got, want := runeColumn(clean[1], "func 関数() {"), runeColumn(goLines[1], "func 関数() {"); got != want {
+		t.Errorf("code after comment close at rune column %d, want %d: %q", got, want, clean[1])
+	}
+	if strings.TrimSpace(clean[2]) != `return` {
+		t.Errorf("string with CJK not blanked: %q", clean[2])
+	}
+
+	pyLines := []string{
+		`def 処理():`,
+		`    """説明 🚀 """ ; value = compute()`,
+	}
+	clean = NewSanitizer(getPyConfig(t), false).CleanLines(pyLines)
+	if clean[0] != pyLines[0] {
+		t.Errorf("multibyte def line altered: %q", clean[0])
+	}
+	if got, want := runeColumn(clean[1], "value = compute()"), runeColumn(pyLines[1], "value = compute()"); got != want {
+		t.Errorf("code after single-line docstring at rune column %d, want %d: %q", got, want, clean[1])
+	}
+}
@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func getScalaConfig(t *testing.T) *LanguageConfig {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	scala := config["scala"]
+	if scala == nil {
+		t.Fatal("scala config not found")
+	}
+	return scala
+}
+
+const scalaSample = `case class User(
+  id: Long,
+  name: String = "anon",
+  tags: Map[String, Int]
+)
+
+trait Shape {
+  def area(): Double
+  def scale(k: Double): Shape
+}
+
+object MathUtils {
+  def add(a: Int, b: Int): Int = a + b
+
+  def swap[T](xs: Array[T], i: Int, j: Int): Unit = {
+    val tmp = xs(i)
+    xs(i) = xs(j)
+    xs(j) = tmp
+  }
+
+  def curried(a: Int)(b: Int): Int =
+    a * b
+}
+`
+
+func findScala(t *testing.T) map[string]FunctionBounds {
+	t.Helper()
+	finder := NewFinder(getScalaConfig(t), nil, true, false, false)
+	result, err := finder.FindFunctionsInLines(strings.Split(scalaSample, "\n"), 1, "sample.scala")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	byName := make(map[string]FunctionBounds)
+	for _, fn := range result.Functions {
+		byName[fn.Name] = fn
+	}
+	return byName
+}
+
+func TestScalaBracedDef(t *testing.T) {
+	swap, ok := findScala(t)["swap"]
+	if !ok {
+		t.Fatal("swap not found")
+	}
+	if swap.Start != 15 || swap.End != 19 {
+		t.Errorf("swap: got %d-%d, want 15-19", swap.Start, swap.End)
+	}
+	if swap.ClassName != "MathUtils" {
+		t.Errorf("swap: ClassName = %q, want MathUtils", swap.ClassName)
+	}
+}
+
+func TestScalaExpressionBodiedDef(t *testing.T) {
+	byName := findScala(t)
+
+	// Expression bodies and abstract declarations end on their own line
+	for name, line := range map[string]int{"add": 13, "area": 8, "scale": 9} {
+		fn, ok := byName[name]
+		if !ok {
+			t.Errorf("%s not found", name)
+			continue
+		}
+		if fn.Start != line || fn.End != line {
+			t.Errorf("%s: got %d-%d, want %d-%d", name, fn.Start, fn.End, line, line)
+		}
+	}
+
+	// A trailing "=" has no brace to wait for either: the function is
+	// reported on its signature line instead of staying open to EOF
+	if fn, ok := byName["curried"]; !ok || fn.Start != 21 || fn.End != 21 {
+		t.Errorf("curried: got %+v, want 21-21", fn)
+	}
+}
+
+func TestScalaCaseClassFields(t *testing.T) {
+	finder := NewStructFinder(getScalaConfig(t), "", true)
+	result, err := finder.FindStructuresInLines(strings.Split(scalaSample, "\n"), 1, "sample.scala")
+	if err != nil {
+		t.Fatalf("FindStructuresInLines failed: %v", err)
+	}
+
+	var user *TypeBounds
+	for i := range result.Types {
+		if result.Types[i].Name == "User" {
+			user = &result.Types[i]
+		}
+	}
+	if user == nil {
+		t.Fatalf("case class User not found, got %+v", result.Types)
+	}
+
+	want := []FieldBounds{
+		{Name: "id", Type: "Long", Line: 2},
+		{Name: "name", Type: "String", Line: 3},
+		{Name: "tags", Type: "Map[String, Int]", Line: 4},
+	}
+	if len(user.Fields) != len(want) {
+		t.Fatalf("User fields = %+v, want %+v", user.Fields, want)
+	}
+	for i, field := range want {
+		if user.Fields[i] != field {
+			t.Errorf("field %d = %+v, want %+v", i, user.Fields[i], field)
+		}
+	}
+}
+
+func TestScalaSanitizerInterpolators(t *testing.T) {
+	s := NewSanitizer(getScalaConfig(t), false)
+
+	tests := []struct {
+		line string
+		want int // brace delta after sanitizing
+	}{
+		{`println(s"total: $n {")`, 0},
+		{`val m = s"${lookup("k")} {"`, 0},
+		{`log(f"$x%.2f {") ; if (ok) {`, 1},
+	}
+	for _, tt := range tests {
+		cleaned, state := s.CleanLine(tt.line, StateNormal)
+		if got := CountBraces(cleaned); got != tt.want || state != StateNormal {
+			t.Errorf("CleanLine(%q) = %q (state %v), brace delta %d, want %d", tt.line, cleaned, state, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// scopepath.go - Dotted enclosing scope paths of functions (--context-json)
+package internal
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ScopeSeparator joins the names of a ScopePath
+const ScopeSeparator = "."
+
+// AttachScopePaths sets FunctionBounds.ScopePath to the dotted chain of
+// enclosing classes and functions ending with the function's own name, e.g.
+// "Outer.Inner.method" or "Server.Run.helper". Classes are found again here,
+// nested ones included (FindResult.Classes only holds top-level classes).
+// A ClassName no enclosing class explains (Go receivers, C++ "Box::get"
+// defined outside the class) is put right before the name. lines are the
+// whole file.
+func AttachScopePaths(functions []FunctionBounds, lines []string, config *LanguageConfig) {
+	var classes []ClassBounds
+	if classRe := config.ClassRegex(); classRe != nil && !config.IndentBased {
+		classes = nestedClasses(NewSanitizer(config, false).CleanLines(lines), config)
+	}
+
+	// Outer functions first, so an enclosing function's path is known
+	// before the functions nested in it
+	order := make([]int, len(functions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		fa, fb := functions[order[a]], functions[order[b]]
+		if fa.Start != fb.Start {
+			return fa.Start < fb.Start
+		}
+		return fa.End > fb.End
+	})
+
+	for _, i := range order {
+		fn := &functions[i]
+		var names []string
+		// innermost enclosing function: the path continues from its path
+		outer := -1
+		for _, j := range order {
+			o := functions[j]
+			if j != i && o.ScopePath != "" && o.Start <= fn.Start && fn.End <= o.End && (o.Start != fn.Start || o.End != fn.End) {
+				outer = j
+			}
+		}
+		for _, cls := range classes {
+			if cls.Start <= fn.Start && fn.End <= cls.End && (outer < 0 || cls.Start > functions[outer].Start) {
+				names = append(names, cls.Name)
+			}
+		}
+		if outer >= 0 {
+			names = append([]string{functions[outer].ScopePath}, names...)
+		} else if fn.ClassName != "" && !slices.Contains(names, fn.ClassName) {
+			names = append(names, fn.ClassName)
+		}
+		fn.ScopePath = strings.Join(append(names, fn.Name), ScopeSeparator)
+	}
+}
+
+// AttachPythonScopePaths sets ScopePath from the parent chain of the
+// AnalyzePythonScopes scope of each function: "Outer.Inner.method",
+// "outer.inner". Functions without a matching scope get their bare name.
+func AttachPythonScopePaths(functions []FunctionBounds, scopes []PythonScope) {
+	for i := range functions {
+		fn := &functions[i]
+		fn.ScopePath = fn.Name
+		if scope := pythonScopeOf(scopes, *fn); scope != nil {
+			fn.ScopePath = pythonScopePath(scope)
+		}
+	}
+}
+
+// pythonScopeOf returns the innermost function scope named like fn that
+// contains its first line. Scopes start at their first decorator, as
+// FunctionBounds do.
+func pythonScopeOf(scopes []PythonScope, fn FunctionBounds) *PythonScope {
+	var best *PythonScope
+	for i := range scopes {
+		s := &scopes[i]
+		if s.Kind != "function" || strings.TrimSpace(s.Name) != fn.Name || s.StartLine > fn.Start || s.EndLine < fn.Start {
+			continue
+		}
+		if best == nil || s.StartLine > best.StartLine {
+			best = s
+		}
+	}
+	return best
+}
+
+// pythonScopePath joins the names from the outermost parent down to scope.
+// Class names are cut at "(" ("class B(Base):" is "B").
+func pythonScopePath(scope *PythonScope) string {
+	var names []string
+	for s := scope; s != nil; s = s.Parent {
+		name, _, _ := strings.Cut(s.Name, "(")
+		names = append(names, strings.TrimSpace(name))
+	}
+	for l, r := 0, len(names)-1; l < r; l, r = l+1, r-1 {
+		names[l], names[r] = names[r], names[l]
+	}
+	return strings.Join(names, ScopeSeparator)
+}
+
+// nestedClasses returns every class_pattern match of the cleaned lines with
+// the line its brace body closes on, classes nested in classes or functions
+// included. A header whose brace doesn't open within MaxSignatureLines is
+// dropped.
+func nestedClasses(cleaned []string, config *LanguageConfig) []ClassBounds {
+	type openClass struct {
+		bounds ClassBounds
+		base   int // brace depth before the header
+		opened bool
+	}
+	var classes []ClassBounds
+	var stack []*openClass
+	classRe := config.ClassRegex()
+	depth := 0
+	for lineNum, line := range cleaned {
+		if m := classRe.FindStringSubmatch(line); m != nil && len(m) >= 2 && m[1] != "" {
+			stack = append(stack, &openClass{bounds: ClassBounds{Name: m[1], Start: lineNum + 1}, base: depth})
+		}
+		hasBrace := strings.Contains(line, "{")
+		depth += CountBraces(line)
+
+		kept := stack[:0]
+		for _, c := range stack {
+			switch {
+			case !c.opened && hasBrace && depth <= c.base && c.bounds.Start == lineNum+1:
+				// "class Empty {}" on one line
+				c.bounds.End = lineNum + 1
+				classes = append(classes, c.bounds)
+				continue
+			case !c.opened && depth > c.base:
+				c.opened = true
+			case c.opened && depth <= c.base:
+				c.bounds.End = lineNum + 1
+				classes = append(classes, c.bounds)
+				continue
+			case !c.opened && lineNum+1-c.bounds.Start >= MaxSignatureLines:
+				continue
+			}
+			kept = append(kept, c)
+		}
+		stack = kept
+	}
+	sort.SliceStable(classes, func(a, b int) bool { return classes[a].Start < classes[b].Start })
+	return classes
+}
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAttachScopePaths_NestedClassMethod(t *testing.T) {
+	code := `public class Outer {
+    static class Inner {
+        void method() {
+        }
+
+        class Deepest {
+            void leaf() {
+            }
+        }
+    }
+
+    void top() {
+    }
+}`
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	lines := strings.Split(code, "\n")
+	result, err := NewFinder(config["java"], nil, true, false, false).FindFunctionsInLines(lines, 1, "Outer.java")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	AttachScopePaths(result.Functions, lines, config["java"])
+
+	want := map[string]string{"method": "Outer.Inner.method", "leaf": "Outer.Inner.Deepest.leaf", "top": "Outer.top"}
+	for _, fn := range result.Functions {
+		if fn.ScopePath != want[fn.Name] {
+			t.Errorf("%s ScopePath = %q, want %q", fn.Name, fn.ScopePath, want[fn.Name])
+		}
+		delete(want, fn.Name)
+	}
+	if len(want) > 0 {
+		t.Errorf("functions not found: %v", want)
+	}
+}
+
+func TestAttachScopePaths_GoReceiverAndNestedFunc(t *testing.T) {
+	code := "package p\n\nfunc (s *Server) Run() {\n\tfunc helper() {}\n}\n"
+	lines := strings.Split(code, "\n")
+	config := getGoConfig(t)
+	result, err := NewFinder(config, nil, true, false, false).FindFunctionsInLines(lines, 1, "p.go")
+	if err != nil {
+		t.Fatalf("FindFunctionsInLines failed: %v", err)
+	}
+	AttachScopePaths(result.Functions, lines, config)
+
+	got := map[string]string{}
+	for _, fn := range result.Functions {
+		got[fn.Name] = fn.ScopePath
+	}
+	if got["Run"] != "Server.Run" || got["helper"] != "Server.Run.helper" {
+		t.Errorf("ScopePath = %v, want Run: Server.Run, helper: Server.Run.helper", got)
+	}
+}
+
+func TestAttachPythonScopePaths_NestedFunction(t *testing.T) {
+	code := "class Outer:\n" +
+		"    class Inner(Base):\n" +
+		"        @staticmethod\n" +
+		"        def method():\n" +
+		"            def helper():\n" +
+		"                pass\n" +
+		"            return helper\n" +
+		"\n" +
+		"def outer():\n" +
+		"    def inner():\n" +
+		"        pass\n" +
+		"    return inner\n"
+	path := filepath.Join(t.TempDir(), "scopes.py")
+	if err := os.WriteFile(path, []byte(code), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	result, err := NewPythonFinder(*getPyConfig(t), "", "map", false).FindFunctions(path)
+	if err != nil {
+		t.Fatalf("FindFunctions() error = %v", err)
+	}
+	scopes, err := AnalyzePythonScopes(path)
+	if err != nil {
+		t.Fatalf("AnalyzePythonScopes() error = %v", err)
+	}
+	AttachPythonScopePaths(result.Functions, scopes)
+
+	want := map[string]string{
+		"method": "Outer.Inner.method",
+		"helper": "Outer.Inner.method.helper",
+		"outer":  "outer",
+		"inner":  "outer.inner",
+	}
+	for _, fn := range result.Functions {
+		if fn.ScopePath != want[fn.Name] {
+			t.Errorf("%s ScopePath = %q, want %q", fn.Name, fn.ScopePath, want[fn.Name])
+		}
+	}
+	if len(result.Functions) != len(want) {
+		t.Errorf("found %d functions, want %d", len(result.Functions), len(want))
+	}
+}
@@ -0,0 +1,74 @@
+// selector.go - Selecting functions by "Class/method" path (--get)
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Selector picks functions by name and, optionally, by class
+type Selector struct {
+	Class string // empty: any class, or none
+	Name  string
+}
+
+// ParseSelector parses a --get value: "Class/method" or a bare "name".
+// Only the last "/" separates the name, so a nested class keeps its own
+// qualifier ("Outer.Inner/method").
+func ParseSelector(s string) (Selector, error) {
+	s = strings.TrimSpace(s)
+	class, name := "", s
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		class, name = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+		if class == "" {
+			return Selector{}, fmt.Errorf("invalid --get selector %q (empty class before \"/\")", s)
+		}
+	}
+	if name == "" {
+		return Selector{}, fmt.Errorf("invalid --get selector %q (expected Class/name or name)", s)
+	}
+	return Selector{Class: class, Name: name}, nil
+}
+
+// Matches reports whether fn is selected: the names are equal and, when
+// the selector has a class, so is fn.ClassName
+func (s Selector) Matches(fn FunctionBounds) bool {
+	return fn.Name == s.Name && (s.Class == "" || fn.ClassName == s.Class)
+}
+
+// FilterBySelector keeps the functions s matches; overloads and
+// same-named functions of different classes all stay
+func FilterBySelector(functions []FunctionBounds, s Selector) []FunctionBounds {
+	filtered := make([]FunctionBounds, 0, 1)
+	for _, fn := range functions {
+		if s.Matches(fn) {
+			filtered = append(filtered, fn)
+		}
+	}
+	return filtered
+}
+
+// jsonSelected is one --get match in --json output
+type jsonSelected struct {
+	Name  string   `json:"name"`
+	Class string   `json:"class,omitempty"`
+	Start int      `json:"start"`
+	End   int      `json:"end"`
+	Lines []string `json:"lines,omitempty"`
+}
+
+// FormatSelectedJSON writes the --get matches as a JSON array. Unlike
+// FormatJSON it is not keyed by name, so overloads are all kept; "lines"
+// holds the body in extract mode.
+func FormatSelectedJSON(result *FindResult) (string, error) {
+	out := make([]jsonSelected, 0, len(result.Functions))
+	for _, fn := range result.Functions {
+		out = append(out, jsonSelected{Name: fn.Name, Class: fn.ClassName, Start: fn.Start, End: fn.End, Lines: fn.Lines})
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(data), nil
+}
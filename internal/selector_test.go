@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const selectorSample = `package server
+
+type Server struct{}
+
+func (s *Server) Run() error {
+	return nil
+}
+
+func (c *Client) Run() error {
+	return nil
+}
+
+func Run() {}
+
+func Serve(addr string) error {
+	return nil
+}
+`
+
+func selectFunctions(t *testing.T, selector string) []FunctionBounds {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "server.go")
+	mustWrite(t, path, selectorSample)
+
+	result, err := NewFinder(config["go"], nil, true, true, false).FindFunctions(path)
+	if err != nil {
+		t.Fatalf("FindFunctions() error = %v", err)
+	}
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		t.Fatalf("ParseSelector(%q) error = %v", selector, err)
+	}
+	return FilterBySelector(result.Functions, sel)
+}
+
+func TestFilterBySelector_ClassMethod(t *testing.T) {
+	got := selectFunctions(t, "Server/Run")
+	if len(got) != 1 {
+		t.Fatalf("Server/Run matched %d functions, want 1: %+v", len(got), got)
+	}
+	if got[0].ClassName != "Server" || got[0].Start != 5 || got[0].End != 7 {
+		t.Errorf("Server/Run = %s.%s %d-%d, want Server.Run 5-7", got[0].ClassName, got[0].Name, got[0].Start, got[0].End)
+	}
+	if len(got[0].Lines) != 3 || got[0].Lines[0] != "func (s *Server) Run() error {" {
+		t.Errorf("Server/Run body = %q", got[0].Lines)
+	}
+}
+
+func TestFilterBySelector_BareName(t *testing.T) {
+	got := selectFunctions(t, "Serve")
+	if len(got) != 1 || got[0].Name != "Serve" || got[0].ClassName != "" || got[0].Start != 15 {
+		t.Fatalf("Serve = %+v, want the top-level function at line 15", got)
+	}
+
+	// A bare name is ambiguous across receivers: every match is returned
+	if got := selectFunctions(t, "Run"); len(got) != 3 {
+		t.Errorf("Run matched %d functions, want 3", len(got))
+	}
+}
+
+func TestParseSelector_Invalid(t *testing.T) {
+	for _, s := range []string{"", "Server/", "/Run", "  "} {
+		if _, err := ParseSelector(s); err == nil {
+			t.Errorf("ParseSelector(%q) error = nil, want error", s)
+		}
+	}
+	sel, err := ParseSelector("Outer.Inner/method")
+	if err != nil || sel != (Selector{Class: "Outer.Inner", Name: "method"}) {
+		t.Errorf("ParseSelector(Outer.Inner/method) = %+v, %v", sel, err)
+	}
+}
@@ -0,0 +1,106 @@
+// shebang.go - Language detection for extensionless scripts by their "#!" line
+package internal
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxShebangLen bounds how much of a file is read looking for "#!"
+const maxShebangLen = 256
+
+// ShebangInterpreters maps every interpreter name of the config
+// (shebang_interpreters) to its language key
+func (c Config) ShebangInterpreters() map[string]string {
+	interpreters := make(map[string]string)
+	for _, langConf := range c {
+		for _, name := range langConf.ShebangInterpreters {
+			interpreters[name] = langConf.LangKey
+		}
+	}
+	return interpreters
+}
+
+// GetLanguageByShebang returns the configuration for the interpreter named
+// by a "#!" line: "#!/usr/bin/python3", "#!/usr/bin/env node" or
+// "#!/usr/bin/env -S python3 -u". A versioned name (python3.12) falls back
+// to its base name (python3, then python). Returns nil when the line is not
+// a shebang or the interpreter is unknown.
+func (c Config) GetLanguageByShebang(line string) *LanguageConfig {
+	name := shebangInterpreter(line)
+	if name == "" {
+		return nil
+	}
+	interpreters := c.ShebangInterpreters()
+	for name != "" {
+		if key, ok := interpreters[name]; ok {
+			return c[key]
+		}
+		trimmed := strings.TrimRight(name, "0123456789.")
+		if trimmed == name {
+			break
+		}
+		name = trimmed
+	}
+	return nil
+}
+
+// shebangInterpreter returns the interpreter file name of a "#!" line,
+// skipping "env" and its options
+func shebangInterpreter(line string) string {
+	rest, ok := strings.CutPrefix(line, "#!")
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return ""
+	}
+	name := filepath.Base(fields[0])
+	if name != "env" {
+		return name
+	}
+	for _, f := range fields[1:] {
+		if strings.HasPrefix(f, "-") || strings.Contains(f, "=") {
+			continue
+		}
+		return filepath.Base(f)
+	}
+	return ""
+}
+
+// DetectLanguage returns the configuration for a file by its extension or,
+// for a file without one, by its shebang line. A .vue/.svelte component is
+// JavaScript or, with <script lang="ts">, TypeScript. Files with an unknown
+// extension are not opened, so scanning a tree doesn't read every asset.
+func (c Config) DetectLanguage(path string) *LanguageConfig {
+	if langConf := c.GetLanguageByExtension(path); langConf != nil {
+		return langConf
+	}
+	if IsComponentFile(path) {
+		return c.detectComponentLanguage(path)
+	}
+	if filepath.Ext(path) != "" {
+		return nil
+	}
+	return c.GetLanguageByShebang(readShebangLine(path))
+}
+
+// readShebangLine returns the first line of path if it starts with "#!"
+func readShebangLine(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, maxShebangLen)
+	head, _ := reader.Peek(maxShebangLen)
+	line, _, _ := strings.Cut(string(head), "\n")
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	return strings.TrimSuffix(line, "\r")
+}
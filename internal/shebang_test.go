@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetLanguageByShebang(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tests := []struct {
+		line string
+		want string // LangKey, "" for no match
+	}{
+		{"#!/usr/bin/env python3", "py"},
+		{"#!/usr/bin/python3.12 -u", "py"},
+		{"#!/usr/bin/env -S python3 -u", "py"},
+		{"#!/usr/bin/node", "js"},
+		{"#!/usr/bin/env ruby", "ruby"},
+		{"#!/bin/bash", ""},
+		{"# not a shebang", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if lc := config.GetLanguageByShebang(tt.line); lc != nil {
+			got = lc.LangKey
+		}
+		if got != tt.want {
+			t.Errorf("GetLanguageByShebang(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestDetectLanguage_ExtensionlessPythonScript(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tmpDir := t.TempDir()
+	script := filepath.Join(tmpDir, "deploy")
+	mustWrite(t, script, "#!/usr/bin/env python3\ndef deploy():\n    pass\n")
+	mustWrite(t, filepath.Join(tmpDir, "notes.txt"), "#!/usr/bin/env python3\n")
+
+	if lc := config.DetectLanguage(script); lc != config["py"] {
+		t.Fatalf("DetectLanguage(deploy) = %v, want the Python config", lc)
+	}
+	// Unknown extensions are not sniffed
+	if lc := config.DetectLanguage(filepath.Join(tmpDir, "notes.txt")); lc != nil {
+		t.Errorf("DetectLanguage(notes.txt) = %s, want nil", lc.LangKey)
+	}
+
+	dp := NewDirProcessor(config, 1, true, false, "functions")
+	results, err := dp.ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+	if len(results) != 1 || len(results[0].Functions) != 1 || results[0].Functions[0].Name != "deploy" {
+		t.Errorf("ProcessDirectory() = %+v, want deploy from the extensionless script", results)
+	}
+}
@@ -0,0 +1,56 @@
+// sortfuncs.go - Ordering and truncating function lists (--sort, --top)
+package internal
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Function orders accepted by --sort
+const (
+	SortLine = "line" // source order, the default
+	SortSize = "size" // longest first (End-Start line span)
+	SortName = "name" // alphabetical
+)
+
+// ParseSortOrder validates a --sort value; an empty value means SortLine
+func ParseSortOrder(s string) (string, error) {
+	switch s {
+	case "", SortLine:
+		return SortLine, nil
+	case SortSize, SortName:
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid --sort order %q (expected line, size or name)", s)
+}
+
+// SortFunctions orders functions in place. Ties keep source order, so
+// equally long functions are listed top to bottom.
+func SortFunctions(functions []FunctionBounds, order string) {
+	var less func(a, b FunctionBounds) bool
+	switch order {
+	case SortSize:
+		less = func(a, b FunctionBounds) bool { return a.End-a.Start > b.End-b.Start }
+	case SortName:
+		less = func(a, b FunctionBounds) bool { return a.Name < b.Name }
+	default:
+		less = func(a, b FunctionBounds) bool { return false }
+	}
+	sort.SliceStable(functions, func(i, j int) bool {
+		if less(functions[i], functions[j]) {
+			return true
+		}
+		if less(functions[j], functions[i]) {
+			return false
+		}
+		return functions[i].Start < functions[j].Start
+	})
+}
+
+// TopFunctions returns the first n functions; n <= 0 keeps all of them
+func TopFunctions(functions []FunctionBounds, n int) []FunctionBounds {
+	if n <= 0 || n >= len(functions) {
+		return functions
+	}
+	return functions[:n]
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sortSample = `package sizes
+
+func Small() {}
+
+func Large() {
+	a := 1
+	b := 2
+	c := 3
+	_ = a + b + c
+}
+
+func Medium() {
+	return
+}
+
+func Huge() {
+	a := 1
+	b := 2
+	c := 3
+	d := 4
+	_ = a + b + c + d
+}
+
+func Other() {
+	return
+}
+`
+
+func sortedNames(functions []FunctionBounds) string {
+	names := make([]string, len(functions))
+	for i, fn := range functions {
+		names[i] = fn.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestTopFunctions_LargestBySize(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "sizes.go")
+	mustWrite(t, path, sortSample)
+	result, err := NewFinder(config["go"], nil, true, false, false).FindFunctions(path)
+	if err != nil {
+		t.Fatalf("FindFunctions() error = %v", err)
+	}
+
+	SortFunctions(result.Functions, SortSize)
+	// Medium and Other are equally long: source order breaks the tie
+	if got := sortedNames(result.Functions); got != "Huge,Large,Medium,Other,Small" {
+		t.Errorf("size order = %s", got)
+	}
+
+	result.Functions = TopFunctions(result.Functions, 2)
+	if got := sortedNames(result.Functions); got != "Huge,Large" {
+		t.Errorf("--top 2 = %s, want Huge,Large", got)
+	}
+	if got := FormatGrepStyle(result); got != "Huge: 16-22; Large: 5-10;" {
+		t.Errorf("grep output = %q", got)
+	}
+}
+
+func TestSortFunctions_NameAndLine(t *testing.T) {
+	functions := []FunctionBounds{{Name: "b", Start: 1}, {Name: "c", Start: 5}, {Name: "a", Start: 9}}
+	SortFunctions(functions, SortName)
+	if got := sortedNames(functions); got != "a,b,c" {
+		t.Errorf("name order = %s", got)
+	}
+	SortFunctions(functions, SortLine)
+	if got := sortedNames(functions); got != "b,c,a" {
+		t.Errorf("line order = %s", got)
+	}
+	if got := TopFunctions(functions, 10); len(got) != 3 {
+		t.Errorf("TopFunctions(10) kept %d of 3", len(got))
+	}
+	if _, err := ParseSortOrder("lines"); err == nil {
+		t.Error("ParseSortOrder(lines) error = nil")
+	}
+}
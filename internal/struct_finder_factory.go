@@ -4,7 +4,6 @@ package internal
 
 import (
 	"bufio"
-	"fmt"
 	"os"
 	"strings"
 )
@@ -116,7 +115,7 @@ func NewHybridStructFinder(config *LanguageConfig, typeNamesStr string, mapMode,
 func (f *HybridStructFinder) FindStructures(filename string) (*StructFindResult, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, newOpenError(filename, err)
 	}
 	defer file.Close()
 
@@ -126,7 +125,7 @@ func (f *HybridStructFinder) FindStructures(filename string) (*StructFindResult,
 		lines = append(lines, scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, newReadError(filename, err)
 	}
 
 	return f.FindStructuresInLines(lines, 1, filename)
@@ -163,6 +162,7 @@ func (f *HybridStructFinder) findAllTypes(lines []string, lineOffset int) []Type
 	depth := 0
 
 	structPatterns := f.config.GetStructPatterns()
+	kinds := f.config.StructPatternKinds()
 
 	for lineNum, line := range lines {
 		cleaned, newState := f.sanitizer.CleanLine(line, state)
@@ -179,7 +179,8 @@ func (f *HybridStructFinder) findAllTypes(lines []string, lineOffset int) []Type
 			}
 		} else {
 			// Look for new type definition
-			for typeKind, pattern := range structPatterns {
+			for _, typeKind := range kinds {
+				pattern := structPatterns[typeKind]
 				matches := pattern.FindStringSubmatch(cleaned)
 				if matches != nil {
 					typeName := ""
@@ -249,15 +250,23 @@ func (f *HybridStructFinder) findFieldsForType(lines []string, typeBounds *TypeB
 	}
 
 	state := StateNormal
+	// Members sit directly in the type body: lines of method bodies and
+	// nested object literals ("{ a: 1 }") are not fields
+	depth := 0
 
 	for lineNum := typeBounds.Start - 1 - lineOffset; lineNum < len(lines) && lineNum < typeBounds.End-1-lineOffset; lineNum++ {
 		line := lines[lineNum]
 		cleaned, newState := f.sanitizer.CleanLine(line, state)
 		state = newState
+		lineDepth := depth
+		depth += CountBraces(cleaned)
 
 		if IsEmptyOrComment(cleaned, f.config.LineComment) {
 			continue
 		}
+		if !f.config.IndentBased && lineDepth != 1 {
+			continue
+		}
 
 		if f.config.IndentBased {
 			indent := GetIndentLevel(line)
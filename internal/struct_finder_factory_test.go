@@ -266,58 +266,74 @@ class After {}
 	}
 }
 
-func TestHybridStructFinder_FieldsTypeBeforeNameSyntaxDetected(t *testing.T) {
-	// field_pattern expects "[modifiers] Type fieldName ;" ordering. This is
-	// not idiomatic TypeScript (which uses "fieldName: Type"), but it is what
-	// the configured regex actually matches — this test pins that behavior.
-	tsConfig := getTSConfig(t)
-	code := `class Config {
-    public string Host;
-    private number Port;
-}
-`
+// tsFields returns "name:type" of every field of the only type in code
+func tsFields(t *testing.T, code string) (TypeBounds, []string) {
+	t.Helper()
 	factory := NewStructFinderFactory()
-	finder := factory.CreateStructFinder(tsConfig, "", true, false)
+	finder := factory.CreateStructFinder(getTSConfig(t), "", true, false)
 
-	result, err := finder.FindStructuresInLines(strings.Split(code, "\n"), 1, "config.ts")
+	result, err := finder.FindStructuresInLines(strings.Split(code, "\n"), 1, "types.ts")
 	if err != nil {
 		t.Fatalf("FindStructuresInLines() error = %v", err)
 	}
 	if len(result.Types) != 1 {
 		t.Fatalf("got %d types, want 1", len(result.Types))
 	}
-	fields := result.Types[0].Fields
-	names := map[string]bool{}
-	for _, f := range fields {
-		names[f.Name] = true
-	}
-	if !names["Host"] || !names["Port"] {
-		t.Errorf("expected fields Host and Port, got %+v", fields)
+	var fields []string
+	for _, f := range result.Types[0].Fields {
+		fields = append(fields, f.Name+":"+f.Type)
 	}
+	return result.Types[0], fields
 }
 
-func TestHybridStructFinder_FieldsIdiomaticColonSyntaxNotDetected(t *testing.T) {
-	// Documents the inverse of the above: idiomatic TS "name: Type;" field
-	// syntax does NOT match field_pattern, so interfaces/classes written the
-	// normal TypeScript way currently report zero fields.
-	tsConfig := getTSConfig(t)
-	code := `interface User {
+func TestHybridStructFinder_TSInterfaceFields(t *testing.T) {
+	// Idiomatic "name: Type;" members, optional and readonly ones included;
+	// interface methods are not fields, function-typed properties are
+	typ, fields := tsFields(t, `export interface User {
     id: number;
-    name: string;
+    name?: string;
+    readonly tags: string[];
+    greet(msg: string): void;
+    onClick?: (e: Event) => void;
 }
-`
-	factory := NewStructFinderFactory()
-	finder := factory.CreateStructFinder(tsConfig, "", true, false)
-
-	result, err := finder.FindStructuresInLines(strings.Split(code, "\n"), 1, "user.ts")
-	if err != nil {
-		t.Fatalf("FindStructuresInLines() error = %v", err)
+`)
+	if typ.Kind != "interface" {
+		t.Errorf("Kind = %q, want interface", typ.Kind)
 	}
-	if len(result.Types) != 1 {
-		t.Fatalf("got %d types, want 1", len(result.Types))
+	want := "id:number,name:string,tags:string[],onClick:(e: Event) => void"
+	if got := strings.Join(fields, ","); got != want {
+		t.Errorf("fields = %s, want %s", got, want)
 	}
-	if len(result.Types[0].Fields) != 0 {
-		t.Errorf("expected 0 fields detected for colon-syntax interface, got %+v", result.Types[0].Fields)
+}
+
+func TestHybridStructFinder_TSTypeAliasMembers(t *testing.T) {
+	// The "type X = {" header is not a member; locals of method bodies in
+	// classes are not fields either
+	typ, fields := tsFields(t, `export type Point = {
+    x: number;
+    y: number;
+};
+`)
+	if typ.Kind != "type_alias" || typ.Name != "Point" {
+		t.Errorf("type = %s [%s], want Point [type_alias]", typ.Name, typ.Kind)
+	}
+	if got := strings.Join(fields, ","); got != "x:number,y:number" {
+		t.Errorf("fields = %s, want x:number,y:number", got)
+	}
+
+	_, fields = tsFields(t, `class Counter {
+    private count: number = 0;
+    inc(): number {
+        const next: number = this.count + 1;
+        this.log({
+            value: next,
+        });
+        return next;
+    }
+}
+`)
+	if got := strings.Join(fields, ","); got != "count:number" {
+		t.Errorf("class fields = %s, want count:number", got)
 	}
 }
 
@@ -129,3 +129,60 @@ func TestStructFinderMain(t *testing.T) {
 	// This test would run the actual findstruct command
 	// Skip for now as it requires proper module setup
 }
+
+func TestStructFinder_ExtraTypeKind(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	javaConfig := config["java"]
+	kind, pattern, err := ParseExtraType(`entity:^@Entity\s+class\s+({IDENT}+)`)
+	if err != nil {
+		t.Fatalf("ParseExtraType() error = %v", err)
+	}
+	if err := javaConfig.AddStructPattern(kind, pattern); err != nil {
+		t.Fatalf("AddStructPattern() error = %v", err)
+	}
+	if kinds := javaConfig.StructPatternKinds(); kinds[0] != "entity" {
+		t.Errorf("StructPatternKinds() = %v, want entity first", kinds)
+	}
+
+	lines := []string{
+		"@Entity class User {",
+		"    private String name;",
+		"}",
+		"",
+		"public class Plain {",
+		"}",
+	}
+	finder := NewStructFinderFactory().CreateStructFinder(javaConfig, "", true, false)
+	result, err := finder.FindStructuresInLines(lines, 1, "User.java")
+	if err != nil {
+		t.Fatalf("FindStructuresInLines() error = %v", err)
+	}
+	if len(result.Types) != 2 {
+		t.Fatalf("got %d types, want 2: %+v", len(result.Types), result.Types)
+	}
+	if got := result.Types[0]; got.Name != "User" || got.Kind != "entity" || got.Start != 1 || got.End != 3 {
+		t.Errorf("User = %+v, want entity 1-3", got)
+	}
+	if got := result.Types[1]; got.Name != "Plain" || got.Kind != "class" {
+		t.Errorf("Plain = %+v, want class", got)
+	}
+}
+
+func TestParseExtraType_Invalid(t *testing.T) {
+	for _, spec := range []string{"entity", ":^x", "entity:"} {
+		if _, _, err := ParseExtraType(spec); err == nil {
+			t.Errorf("ParseExtraType(%q) = nil error", spec)
+		}
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if err := config["go"].AddStructPattern("broken", "(unclosed"); err == nil {
+		t.Error("AddStructPattern() accepted an invalid regex")
+	}
+}
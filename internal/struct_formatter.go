@@ -22,7 +22,15 @@ func FormatStructMap(result *StructFindResult) string {
 				fieldNames[i] = f.Name
 			}
 			part += fmt.Sprintf("; fields: %s", strings.Join(fieldNames, ", "))
-		} else {
+		}
+		if len(t.Methods) > 0 {
+			methodNames := make([]string, len(t.Methods))
+			for i, m := range t.Methods {
+				methodNames[i] = m.Name
+			}
+			part += fmt.Sprintf("; methods: %s", strings.Join(methodNames, ", "))
+		}
+		if len(t.Fields) == 0 && len(t.Methods) == 0 {
 			part += ";"
 		}
 		parts = append(parts, part)
@@ -55,15 +63,24 @@ func formatStructTreeLine(t TypeBounds, depth int) string {
 
 	line := fmt.Sprintf("%s%s%s (%d-%d) [%s]", indent, prefix, t.Name, t.Start, t.End, t.Kind)
 
-	// Add fields
+	// Add fields, then methods
+	last := len(t.Fields) + len(t.Methods) - 1
 	for i, f := range t.Fields {
 		fieldIndent := strings.Repeat("│   ", depth+1)
 		fieldPrefix := "├── "
-		if i == len(t.Fields)-1 {
+		if i == last {
 			fieldPrefix = "└── "
 		}
 		line += fmt.Sprintf("\n%s%s%s %s: %d", fieldIndent, fieldPrefix, f.Name, f.Type, f.Line)
 	}
+	for i, m := range t.Methods {
+		methodIndent := strings.Repeat("│   ", depth+1)
+		methodPrefix := "├── "
+		if len(t.Fields)+i == last {
+			methodPrefix = "└── "
+		}
+		line += fmt.Sprintf("\n%s%smethod %s (%d-%d)", methodIndent, methodPrefix, m.Name, m.Start, m.End)
+	}
 
 	return line
 }
@@ -76,12 +93,19 @@ func FormatStructJSON(result *StructFindResult) (string, error) {
 		Line int    `json:"line"`
 	}
 
+	type JSONMethod struct {
+		Name  string `json:"name"`
+		Start int    `json:"start"`
+		End   int    `json:"end"`
+	}
+
 	type JSONType struct {
 		Name    string     `json:"name"`
 		Kind    string     `json:"kind"`
 		Start   int        `json:"start"`
 		End     int        `json:"end"`
 		Fields  []JSONField `json:"fields,omitempty"`
+		Methods []JSONMethod `json:"methods,omitempty"`
 	}
 
 	types := make([]JSONType, len(result.Types))
@@ -101,6 +125,9 @@ func FormatStructJSON(result *StructFindResult) (string, error) {
 			End:    t.End,
 			Fields: fields,
 		}
+		for _, m := range t.Methods {
+			types[i].Methods = append(types[i].Methods, JSONMethod{Name: m.Name, Start: m.Start, End: m.End})
+		}
 	}
 
 	output := struct {
@@ -136,3 +163,69 @@ func FormatStructExtract(result *StructFindResult, lines []string) string {
 
 	return strings.Join(parts, "\n\n")
 }
+
+// FormatStructFieldTypes lists every field of every type as
+// "TypeName.FieldName: FieldType", one per line (--field-types-only)
+func FormatStructFieldTypes(result *StructFindResult) string {
+	var lines []string
+	for _, t := range result.Types {
+		for _, f := range t.Fields {
+			lines = append(lines, fmt.Sprintf("%s.%s: %s", t.Name, f.Name, f.Type))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatStructFieldTypesJSON is the JSON form of FormatStructFieldTypes
+func FormatStructFieldTypesJSON(result *StructFindResult) (string, error) {
+	type JSONFieldType struct {
+		Type      string `json:"type"`
+		Field     string `json:"field"`
+		FieldType string `json:"field_type"`
+		Line      int    `json:"line"`
+	}
+
+	fields := []JSONFieldType{}
+	for _, t := range result.Types {
+		for _, f := range t.Fields {
+			fields = append(fields, JSONFieldType{Type: t.Name, Field: f.Name, FieldType: f.Type, Line: f.Line})
+		}
+	}
+
+	data, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// FormatStructTypesOnly lists type names and kinds without fields as
+// "TypeName: kind", one per line (--types-only)
+func FormatStructTypesOnly(result *StructFindResult) string {
+	lines := make([]string, len(result.Types))
+	for i, t := range result.Types {
+		lines[i] = fmt.Sprintf("%s: %s", t.Name, t.Kind)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatStructTypesOnlyJSON is the JSON form of FormatStructTypesOnly
+func FormatStructTypesOnlyJSON(result *StructFindResult) (string, error) {
+	type JSONTypeName struct {
+		Name  string `json:"name"`
+		Kind  string `json:"kind"`
+		Start int    `json:"start"`
+		End   int    `json:"end"`
+	}
+
+	types := make([]JSONTypeName, len(result.Types))
+	for i, t := range result.Types {
+		types[i] = JSONTypeName{Name: t.Name, Kind: t.Kind, Start: t.Start, End: t.End}
+	}
+
+	data, err := json.MarshalIndent(types, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
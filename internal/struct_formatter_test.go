@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const fieldTypesSample = `package shop
+
+type Item struct {
+	ID    int
+	Name  string
+	Price float64
+}
+
+type Catalog interface {
+	Find(id int) Item
+}
+`
+
+func findSampleTypes(t *testing.T) *StructFindResult {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	finder := NewStructFinder(config["go"], "", true)
+	result, err := finder.FindStructuresInLines(strings.Split(fieldTypesSample, "\n"), 1, "shop.go")
+	if err != nil {
+		t.Fatalf("FindStructuresInLines() error = %v", err)
+	}
+	return result
+}
+
+func TestFormatStructFieldTypes(t *testing.T) {
+	result := findSampleTypes(t)
+
+	want := "Item.ID: int\nItem.Name: string\nItem.Price: float64"
+	if got := FormatStructFieldTypes(result); got != want {
+		t.Errorf("FormatStructFieldTypes() =\n%s\nwant\n%s", got, want)
+	}
+
+	out, err := FormatStructFieldTypesJSON(result)
+	if err != nil {
+		t.Fatalf("FormatStructFieldTypesJSON() error = %v", err)
+	}
+	var fields []struct {
+		Type      string `json:"type"`
+		Field     string `json:"field"`
+		FieldType string `json:"field_type"`
+		Line      int    `json:"line"`
+	}
+	if err := json.Unmarshal([]byte(out), &fields); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(fields) != 3 || fields[2].Type != "Item" || fields[2].Field != "Price" || fields[2].FieldType != "float64" || fields[2].Line != 6 {
+		t.Errorf("FormatStructFieldTypesJSON() = %+v", fields)
+	}
+}
+
+func TestFormatStructTypesOnly(t *testing.T) {
+	result := findSampleTypes(t)
+
+	want := "Item: struct\nCatalog: interface"
+	if got := FormatStructTypesOnly(result); got != want {
+		t.Errorf("FormatStructTypesOnly() =\n%s\nwant\n%s", got, want)
+	}
+
+	out, err := FormatStructTypesOnlyJSON(result)
+	if err != nil {
+		t.Fatalf("FormatStructTypesOnlyJSON() error = %v", err)
+	}
+	if strings.Contains(out, "fields") || !strings.Contains(out, `"kind": "interface"`) {
+		t.Errorf("FormatStructTypesOnlyJSON() = %s", out)
+	}
+}
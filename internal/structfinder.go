@@ -4,9 +4,10 @@ package internal
 
 import (
 	"bufio"
-	"fmt"
 	"os"
+	"regexp"
 	"strings"
+	"unicode"
 )
 
 // TypeBounds contains information about a type definition
@@ -19,6 +20,7 @@ type TypeBounds struct {
 	ParentType     string        // Parent type if nested
 	ParentLine     int           // Line of parent type definition
 	StartLineIndent int          // Indentation level of type start (for indent-based)
+	Methods        []FunctionBounds // Methods of the type (--with-methods, see AttachMethods)
 }
 
 // FieldBounds contains information about a field/member in a type
@@ -40,6 +42,9 @@ type StructFinder struct {
 	sanitizer *Sanitizer
 	typeNames map[string]bool
 	mapMode   bool
+	// fieldsDepth > 0 recurses into nested struct/union members (see
+	// SetFieldsDepth); 0 lists every body line matching field_pattern flat
+	fieldsDepth int
 }
 
 // NewStructFinder creates a new struct finder
@@ -57,11 +62,19 @@ func NewStructFinder(config *LanguageConfig, typeNamesStr string, mapMode bool)
 	}
 }
 
+// SetFieldsDepth lists fields of nested anonymous structs and unions down
+// to depth levels (1 = direct members only) with dotted names
+// ("Inner.X"), and skips method bodies (--fields-depth). 0 keeps the flat
+// listing. Only brace languages are recursed.
+func (f *StructFinder) SetFieldsDepth(depth int) {
+	f.fieldsDepth = depth
+}
+
 // FindStructures finds all types in the file
 func (f *StructFinder) FindStructures(filename string) (*StructFindResult, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, newOpenError(filename, err)
 	}
 	defer file.Close()
 
@@ -71,7 +84,7 @@ func (f *StructFinder) FindStructures(filename string) (*StructFindResult, error
 		lines = append(lines, scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, newReadError(filename, err)
 	}
 
 	return f.FindStructuresInLines(lines, 1, filename)
@@ -109,6 +122,7 @@ func (f *StructFinder) findAllTypes(lines []string, lineOffset int) []TypeBounds
 
 	// Use struct patterns from config if available, otherwise fall back to classRegex
 	structPatterns := f.config.GetStructPatterns()
+	kinds := f.config.StructPatternKinds()
 	hasStructPatterns := len(structPatterns) > 0
 
 	for lineNum, line := range lines {
@@ -151,7 +165,8 @@ func (f *StructFinder) findAllTypes(lines []string, lineOffset int) []TypeBounds
 			
 			if hasStructPatterns {
 				// Use new struct patterns for type detection
-				for typeKind, pattern := range structPatterns {
+				for _, typeKind := range kinds {
+					pattern := structPatterns[typeKind]
 					matches := pattern.FindStringSubmatch(cleaned)
 					if matches != nil {
 						// Extract type name (last non-empty group)
@@ -325,7 +340,9 @@ func determineTypeKind(pattern string) string {
 
 // findFieldsForType finds all fields/members in a type definition
 func (f *StructFinder) findFieldsForType(lines []string, typeBounds *TypeBounds, lineOffset int) []FieldBounds {
-	var fields []FieldBounds
+	// Constructor parameters (Scala case classes) come first: they are
+	// declared on the header line, before any body fields
+	fields := f.findParamFields(lines, typeBounds, lineOffset)
 
 	// Get the field pattern from config
 	fieldRegex := f.config.GetFieldPattern()
@@ -333,6 +350,9 @@ func (f *StructFinder) findFieldsForType(lines []string, typeBounds *TypeBounds,
 	if fieldRegex == nil {
 		return fields // No pattern configured
 	}
+	if f.fieldsDepth > 0 && !f.config.IndentBased && f.config.BlockEndKeyword == "" {
+		return append(fields, f.findNestedFields(lines, typeBounds, lineOffset, fieldRegex)...)
+	}
 
 	state := StateNormal
 
@@ -379,8 +399,89 @@ func (f *StructFinder) findFieldsForType(lines []string, typeBounds *TypeBounds,
 	return fields
 }
 
+// paramFieldRegex matches a "name: Type" constructor parameter with optional
+// modifiers, val/var and default value
+var paramFieldRegex = regexp.MustCompile(`^(?:@[\w.]+\s+)*(?:(?:private|protected|override|implicit|final)\s+)*(?:(?:val|var)\s+)?([\p{L}_][\p{L}\p{Nd}_]*)\s*:\s*([^=]+?)\s*(?:=.*)?$`)
+
+// findParamFields extracts constructor parameters from the type header when
+// the language has a param_fields_pattern. The parameter list may span
+// several lines; each parameter gets the line on which it starts.
+func (f *StructFinder) findParamFields(lines []string, typeBounds *TypeBounds, lineOffset int) []FieldBounds {
+	headerRe := f.config.GetParamFieldsPattern()
+	start := typeBounds.Start - 1 - lineOffset
+	if headerRe == nil || start < 0 || start >= len(lines) {
+		return nil
+	}
+
+	cleaned, state := f.sanitizer.CleanLine(lines[start], StateNormal)
+	loc := headerRe.FindStringIndex(cleaned)
+	if loc == nil {
+		return nil
+	}
+
+	var fields []FieldBounds
+	var current strings.Builder
+	currentLine := start
+	depth := 0 // nesting of (), [] and {} inside the parameter list
+	flush := func() {
+		param := strings.TrimSpace(current.String())
+		current.Reset()
+		if m := paramFieldRegex.FindStringSubmatch(param); m != nil {
+			fields = append(fields, FieldBounds{
+				Name: m[1],
+				Type: strings.TrimSpace(m[2]),
+				Line: currentLine + 1 + lineOffset,
+			})
+		}
+	}
+
+	text := cleaned[loc[1]:]
+	for lineNum := start; lineNum < len(lines); lineNum++ {
+		if lineNum > start {
+			text, state = f.sanitizer.CleanLine(lines[lineNum], state)
+		}
+		for _, ch := range text {
+			if current.Len() == 0 && !unicode.IsSpace(ch) {
+				currentLine = lineNum
+			}
+			switch ch {
+			case '(', '[', '{':
+				depth++
+			case ']', '}':
+				depth--
+			case ')':
+				if depth == 0 {
+					flush()
+					return fields
+				}
+				depth--
+			case ',':
+				if depth == 0 {
+					flush()
+					continue
+				}
+			}
+			if current.Len() > 0 || !unicode.IsSpace(ch) {
+				current.WriteRune(ch)
+			}
+		}
+		if current.Len() > 0 {
+			current.WriteByte(' ')
+		}
+	}
+
+	return fields
+}
+
 // isLikelyMethod checks if the declaration looks like a method/function
 func isLikelyMethod(name string, line string) bool {
+	// A type annotation right after the name is a field, even of a function
+	// type: "onClick?: (e: Event) => void" (TS), "val f: (Int) -> Unit"
+	if idx := strings.Index(line, name); idx >= 0 && !strings.ContainsAny(line[:idx], "()") {
+		if rest := strings.TrimLeft(line[idx+len(name):], "?! \t"); strings.HasPrefix(rest, ":") {
+			return false
+		}
+	}
 	// Functions have parentheses, fields don't
 	return strings.Contains(line, "(") || strings.Contains(line, ")")
 }
@@ -0,0 +1,161 @@
+// summary.go - Aggregate statistics for directory scans (--summary-only)
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LangSummary holds per-language totals of a directory scan
+type LangSummary struct {
+	LangKey   string `json:"lang"`
+	Files     int    `json:"files"`
+	Functions int    `json:"functions"`
+	Classes   int    `json:"classes"`
+}
+
+// DirSummary holds the aggregate totals of a directory scan
+type DirSummary struct {
+	TotalFiles     int           `json:"total_files"`
+	TotalFunctions int           `json:"total_functions"`
+	TotalClasses   int           `json:"total_classes"`
+	Languages      []LangSummary `json:"languages"`
+	// Files left out as generated (--ignore-generated)
+	SkippedGenerated int `json:"skipped_generated,omitempty"`
+	// Files over the --max-functions limit
+	MaxFunctions int             `json:"max_functions,omitempty"`
+	Oversized    []OversizedFile `json:"oversized_files,omitempty"`
+}
+
+// OversizedFile is a file defining more functions and types than
+// --max-functions allows, a candidate for splitting
+type OversizedFile struct {
+	Path      string `json:"path"`
+	Functions int    `json:"functions"`
+	Classes   int    `json:"classes"`
+}
+
+// Count is the number of functions and types the limit is compared with
+func (f OversizedFile) Count() int {
+	return f.Functions + f.Classes
+}
+
+// OversizedFiles returns the files of results with more than limit
+// functions and classes/types together, in result order. Generated files
+// are not counted; a limit of 0 or less disables the check.
+func OversizedFiles(results []DirResult, limit int) []OversizedFile {
+	if limit <= 0 {
+		return nil
+	}
+	var oversized []OversizedFile
+	for _, r := range results {
+		f := OversizedFile{Path: r.Path, Functions: len(r.Functions), Classes: len(r.Classes)}
+		if !r.Generated && f.Count() > limit {
+			oversized = append(oversized, f)
+		}
+	}
+	return oversized
+}
+
+// SummarizeDirResults computes totals and a per-LangKey breakdown.
+// Languages are sorted by function count (descending), then by key.
+func SummarizeDirResults(results []DirResult) DirSummary {
+	summary := DirSummary{Languages: []LangSummary{}}
+	byLang := make(map[string]*LangSummary)
+
+	for _, r := range results {
+		if r.Generated {
+			summary.SkippedGenerated++
+			continue
+		}
+		summary.TotalFiles++
+		summary.TotalFunctions += len(r.Functions)
+		summary.TotalClasses += len(r.Classes)
+
+		key := r.LangKey
+		if key == "" {
+			key = "unknown"
+		}
+		ls := byLang[key]
+		if ls == nil {
+			ls = &LangSummary{LangKey: key}
+			byLang[key] = ls
+		}
+		ls.Files++
+		ls.Functions += len(r.Functions)
+		ls.Classes += len(r.Classes)
+	}
+
+	for _, ls := range byLang {
+		summary.Languages = append(summary.Languages, *ls)
+	}
+	sort.Slice(summary.Languages, func(i, j int) bool {
+		a, b := summary.Languages[i], summary.Languages[j]
+		if a.Functions != b.Functions {
+			return a.Functions > b.Functions
+		}
+		return a.LangKey < b.LangKey
+	})
+
+	return summary
+}
+
+// FormatDirSummary renders a summary as a text table or JSON
+func FormatDirSummary(summary DirSummary, jsonOut bool) string {
+	if jsonOut {
+		b, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return "{}"
+		}
+		return string(b)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Files: %d\n", summary.TotalFiles)
+	fmt.Fprintf(&sb, "Functions: %d\n", summary.TotalFunctions)
+	fmt.Fprintf(&sb, "Classes/types: %d\n", summary.TotalClasses)
+	if summary.SkippedGenerated > 0 {
+		fmt.Fprintf(&sb, "Skipped (generated): %d\n", summary.SkippedGenerated)
+	}
+
+	if len(summary.Languages) > 0 {
+		sb.WriteString("\n")
+		writeLanguageTable(&sb, summary.Languages)
+	}
+
+	if len(summary.Oversized) > 0 {
+		fmt.Fprintf(&sb, "\nOversized files (more than %d functions/types): %d\n", summary.MaxFunctions, len(summary.Oversized))
+		for _, f := range summary.Oversized {
+			fmt.Fprintf(&sb, "  %s: %d functions, %d classes/types\n", f.Path, f.Functions, f.Classes)
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// LanguageStats returns the per-language breakdown of SummarizeDirResults
+// keyed by LangKey (--profile-languages)
+func LanguageStats(results []DirResult) map[string]LangSummary {
+	stats := make(map[string]LangSummary)
+	for _, ls := range SummarizeDirResults(results).Languages {
+		stats[ls.LangKey] = ls
+	}
+	return stats
+}
+
+// FormatLanguageStats renders the --profile-languages table printed after
+// the regular directory output
+func FormatLanguageStats(results []DirResult) string {
+	var sb strings.Builder
+	writeLanguageTable(&sb, SummarizeDirResults(results).Languages)
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func writeLanguageTable(sb *strings.Builder, languages []LangSummary) {
+	fmt.Fprintf(sb, "%-10s %8s %10s %8s\n", "LANG", "FILES", "FUNCTIONS", "CLASSES")
+	for _, ls := range languages {
+		fmt.Fprintf(sb, "%-10s %8d %10d %8d\n", ls.LangKey, ls.Files, ls.Functions, ls.Classes)
+	}
+}
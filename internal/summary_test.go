@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeDirResults_LanguageBreakdown(t *testing.T) {
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "a.go"), "package a\n\nfunc A() {\n}\n\nfunc B() {\n}\n")
+	mustWrite(t, filepath.Join(tmpDir, "b.go"), "package a\n\nfunc C() {\n}\n")
+	mustWrite(t, filepath.Join(tmpDir, "c.py"), "def d():\n    pass\n")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	results, err := NewDirProcessor(config, 2, true, false, "functions").ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+
+	summary := SummarizeDirResults(results)
+	if summary.TotalFiles != 3 || summary.TotalFunctions != 4 {
+		t.Fatalf("totals = %d files, %d functions; want 3, 4", summary.TotalFiles, summary.TotalFunctions)
+	}
+
+	files, funcs := 0, 0
+	for _, ls := range summary.Languages {
+		files += ls.Files
+		funcs += ls.Functions
+	}
+	if files != summary.TotalFiles || funcs != summary.TotalFunctions {
+		t.Errorf("breakdown sums to %d files, %d functions; want %d, %d",
+			files, funcs, summary.TotalFiles, summary.TotalFunctions)
+	}
+	if len(summary.Languages) != 2 || summary.Languages[0].LangKey != "go" || summary.Languages[0].Functions != 3 {
+		t.Errorf("Languages = %+v, want go(3) first then py", summary.Languages)
+	}
+
+	text := FormatDirSummary(summary, false)
+	for _, name := range []string{"A", "B", "C", "d"} {
+		if strings.Contains(text, ": "+name+"\n") || strings.HasSuffix(text, ": "+name) {
+			t.Errorf("summary contains per-function line for %s:\n%s", name, text)
+		}
+	}
+	if strings.Contains(text, ".go:") || strings.Contains(text, ".py:") {
+		t.Errorf("summary contains per-file listing:\n%s", text)
+	}
+	if !strings.Contains(text, "Functions: 4") {
+		t.Errorf("summary missing totals:\n%s", text)
+	}
+
+	var decoded DirSummary
+	if err := json.Unmarshal([]byte(FormatDirSummary(summary, true)), &decoded); err != nil {
+		t.Fatalf("JSON summary invalid: %v", err)
+	}
+	if decoded.TotalFunctions != 4 || len(decoded.Languages) != 2 {
+		t.Errorf("decoded summary = %+v", decoded)
+	}
+}
+
+func TestLanguageStats_GoAndPython(t *testing.T) {
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "a.go"), "package a\n\ntype T struct {\n}\n\nfunc A() {\n}\n\nfunc B() {\n}\n")
+	if err := os.Mkdir(filepath.Join(tmpDir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, filepath.Join(tmpDir, "sub", "b.go"), "package sub\n\nfunc C() {\n}\n")
+	mustWrite(t, filepath.Join(tmpDir, "c.py"), "def d():\n    pass\n\n\ndef e():\n    pass\n")
+	mustWrite(t, filepath.Join(tmpDir, "empty.py"), "x = 1\n")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	results, err := NewDirProcessor(config, 2, true, false, "all").ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+
+	stats := LanguageStats(results)
+	want := map[string]LangSummary{
+		"go": {LangKey: "go", Files: 2, Functions: 3, Classes: 1},
+		"py": {LangKey: "py", Files: 2, Functions: 2},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("LanguageStats() = %+v, want %+v", stats, want)
+	}
+	for lang, w := range want {
+		if stats[lang] != w {
+			t.Errorf("stats[%s] = %+v, want %+v", lang, stats[lang], w)
+		}
+	}
+
+	var out struct {
+		LanguageStats map[string]LangSummary `json:"language_stats"`
+	}
+	if err := json.Unmarshal([]byte(FormatDirResultsJSONWithLanguageStats(results)), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if out.LanguageStats["py"] != want["py"] {
+		t.Errorf("language_stats.py = %+v, want %+v", out.LanguageStats["py"], want["py"])
+	}
+	if strings.Contains(AggregateDirResults(results, true, false, false), "language_stats") {
+		t.Error("plain --json output contains language_stats")
+	}
+}
+
+func TestOversizedFiles_MaxFunctions(t *testing.T) {
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "god.go"), "package a\n\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n\nfunc D() {}\n")
+	mustWrite(t, filepath.Join(tmpDir, "small.go"), "package a\n\nfunc E() {}\n")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	results, err := NewDirProcessor(config, 2, true, false, "functions").ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+
+	oversized := OversizedFiles(results, 3)
+	if len(oversized) != 1 || filepath.Base(oversized[0].Path) != "god.go" || oversized[0].Count() != 4 {
+		t.Fatalf("OversizedFiles(3) = %+v, want god.go with 4", oversized)
+	}
+	if got := OversizedFiles(results, 4); len(got) != 0 {
+		t.Errorf("OversizedFiles(4) = %+v, want none (the limit itself is allowed)", got)
+	}
+	if got := OversizedFiles(results, 0); got != nil {
+		t.Errorf("OversizedFiles(0) = %+v, want nil (check off)", got)
+	}
+
+	summary := SummarizeDirResults(results)
+	summary.MaxFunctions, summary.Oversized = 3, oversized
+	var decoded struct {
+		MaxFunctions int             `json:"max_functions"`
+		Oversized    []OversizedFile `json:"oversized_files"`
+	}
+	if err := json.Unmarshal([]byte(FormatDirSummary(summary, true)), &decoded); err != nil {
+		t.Fatalf("summary JSON: %v", err)
+	}
+	if decoded.MaxFunctions != 3 || len(decoded.Oversized) != 1 || decoded.Oversized[0].Functions != 4 {
+		t.Errorf("summary JSON = %+v, want one oversized_files entry with 4 functions", decoded)
+	}
+	if text := FormatDirSummary(summary, false); !strings.Contains(text, "Oversized files (more than 3 functions/types): 1\n  "+oversized[0].Path+": 4 functions, 0 classes/types") {
+		t.Errorf("summary text lacks the violation:\n%s", text)
+	}
+}
@@ -0,0 +1,175 @@
+// symbol_index.go - One flat JSON index of every symbol under --dir (--index)
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SymbolIndex is the --index document: all functions and types of a scan
+// in one flat list, for loading into an editor's "jump to symbol"
+type SymbolIndex struct {
+	Root         string        `json:"root"`
+	TotalFiles   int           `json:"total_files"`
+	TotalSymbols int           `json:"total_symbols"`
+	Symbols      []IndexSymbol `json:"symbols"`
+}
+
+// IndexSymbol is one entry of SymbolIndex. Every field is always present.
+type IndexSymbol struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`     // function, method, class, struct, interface, enum, macro, operator, ...
+	Path     string `json:"path"`     // relative to Root, "/"-separated
+	Language string `json:"language"` // language key
+	Line     int    `json:"line"`     // line of the name (1-based), past decorators
+	Col      int    `json:"col"`      // byte column of the name on Line (1-based), 0 if not found
+	EndLine  int    `json:"end_line"` // last line of the body
+	Scope    string `json:"scope_path"`
+}
+
+// BuildSymbolIndex flattens results into a SymbolIndex with paths relative
+// to rootDir. Each file is read again to place names and compute scope
+// paths (AttachScopePaths; Python: AnalyzePythonScopes). Files that failed
+// to parse or were skipped as generated are left out. Symbols are ordered
+// by path, line and column.
+func BuildSymbolIndex(results []DirResult, config Config, rootDir string) (*SymbolIndex, error) {
+	index := &SymbolIndex{Root: rootDir, Symbols: []IndexSymbol{}}
+	for _, r := range results {
+		if r.Error != nil || r.Generated {
+			continue
+		}
+		relPath, err := filepath.Rel(rootDir, r.Path)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s is outside %s", r.Path, rootDir)
+		}
+		symbols, err := indexFile(r, config, filepath.ToSlash(relPath))
+		if err != nil {
+			return nil, err
+		}
+		index.Symbols = append(index.Symbols, symbols...)
+		index.TotalFiles++
+	}
+	sort.SliceStable(index.Symbols, func(a, b int) bool {
+		sa, sb := index.Symbols[a], index.Symbols[b]
+		if sa.Path != sb.Path {
+			return sa.Path < sb.Path
+		}
+		if sa.Line != sb.Line {
+			return sa.Line < sb.Line
+		}
+		return sa.Col < sb.Col
+	})
+	index.TotalSymbols = len(index.Symbols)
+	return index, nil
+}
+
+// indexFile returns the symbols of one parsed file
+func indexFile(r DirResult, config Config, relPath string) ([]IndexSymbol, error) {
+	data, err := os.ReadFile(r.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	lines, err := scanSourceLines(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", r.Path, err)
+	}
+
+	functions := append([]FunctionBounds(nil), r.Functions...)
+	if langConfig, err := config.GetLanguageConfig(r.LangKey); err == nil {
+		if langConfig.LangKey == "py" {
+			scopes, err := analyzeCleanPythonScopes(NewSanitizer(langConfig, false).CleanLines(lines))
+			if err != nil {
+				return nil, fmt.Errorf("analyzing Python scopes of %s: %w", r.Path, err)
+			}
+			AttachPythonScopePaths(functions, scopes)
+		} else {
+			AttachScopePaths(functions, lines, langConfig)
+		}
+	}
+
+	var symbols []IndexSymbol
+	add := func(name, kind, scope string, start, end int) {
+		line, col := locateName(lines, name, start)
+		if scope == "" {
+			scope = name
+		}
+		symbols = append(symbols, IndexSymbol{Name: name, Kind: kind, Path: relPath, Language: r.LangKey, Line: line, Col: col, EndLine: end, Scope: scope})
+	}
+	for _, fn := range functions {
+		kind := fn.Kind
+		if kind == "" {
+			kind = "function"
+			if fn.ClassName != "" {
+				kind = "method"
+			}
+		}
+		add(fn.Name, kind, fn.ScopePath, fn.Start, fn.End)
+	}
+	for _, c := range r.Classes {
+		kind := c.Kind
+		if kind == "" {
+			kind = "class"
+		}
+		add(c.Name, kind, classScopePath(c, r.Classes), c.Start, c.End)
+	}
+	return symbols, nil
+}
+
+// classScopePath joins the names of the classes enclosing c, outermost
+// first, and c's own name: "A.B" for class B nested in A
+func classScopePath(c ClassBounds, classes []ClassBounds) string {
+	var outer []ClassBounds
+	for _, o := range classes {
+		if o.Start <= c.Start && c.End <= o.End && (o.Start != c.Start || o.End != c.End) {
+			outer = append(outer, o)
+		}
+	}
+	sort.SliceStable(outer, func(a, b int) bool { return outer[a].Start < outer[b].Start })
+	names := make([]string, 0, len(outer)+1)
+	for _, o := range outer {
+		names = append(names, o.Name)
+	}
+	return strings.Join(append(names, c.Name), ".")
+}
+
+// locateName finds name as a whole identifier in the MaxSignatureLines
+// lines from start (decorators and attributes come first) and returns its
+// line and 1-based byte column; not found, start and 0
+func locateName(lines []string, name string, start int) (int, int) {
+	if name == "" {
+		return start, 0
+	}
+	for i := start - 1; i >= 0 && i < len(lines) && i < start-1+MaxSignatureLines; i++ {
+		line := lines[i]
+		for from := 0; ; {
+			at := strings.Index(line[from:], name)
+			if at < 0 {
+				break
+			}
+			at += from
+			end := at + len(name)
+			if (at == 0 || !isIdentByte(line[at-1])) && (end == len(line) || !isIdentByte(line[end])) {
+				return i + 1, at + 1
+			}
+			from = at + 1
+		}
+	}
+	return start, 0
+}
+
+// WriteSymbolIndex writes index as indented JSON to path
+func WriteSymbolIndex(index *SymbolIndex, path string) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling index: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
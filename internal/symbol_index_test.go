@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBuildSymbolIndex_TwoFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"pkg/server.go": "package pkg\n\ntype Server struct {\n\tName string\n}\n\nfunc (s *Server) Run() {\n}\n\nfunc New() *Server {\n\treturn nil\n}\n",
+		"app.py":        "class App:\n    @property\n    def name(self):\n        return 1\n\n\ndef main():\n    pass\n",
+	})
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	results, err := NewDirProcessor(config, 1, true, false, "all").ProcessDirectory(root)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+
+	index, err := BuildSymbolIndex(results, config, root)
+	if err != nil {
+		t.Fatalf("BuildSymbolIndex() error = %v", err)
+	}
+	var got []string
+	for _, s := range index.Symbols {
+		got = append(got, fmt.Sprintf("%s %s %s %d:%d-%d %s", s.Path, s.Kind, s.Name, s.Line, s.Col, s.EndLine, s.Scope))
+	}
+	want := []string{
+		"app.py class App 1:7-6 App",
+		"app.py method name 3:9-6 App.name",
+		"app.py function main 7:5-9 main",
+		"pkg/server.go struct Server 3:6-5 Server",
+		"pkg/server.go method Run 7:18-8 Server.Run",
+		"pkg/server.go function New 10:6-12 New",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("symbols:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if index.TotalFiles != 2 || index.TotalSymbols != len(want) {
+		t.Errorf("totals = %d files, %d symbols, want 2 and %d", index.TotalFiles, index.TotalSymbols, len(want))
+	}
+}
+
+func TestBuildSymbolIndex_NestedPythonClasses(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"m.py": "class A:\n    class B:\n        def n(self):\n            return \"\"\"\nclass Fake:\n\"\"\"\n\n    def o(self):\n        pass\n",
+	})
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	results, err := NewDirProcessor(config, 1, true, false, "all").ProcessDirectory(root)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+	index, err := BuildSymbolIndex(results, config, root)
+	if err != nil {
+		t.Fatalf("BuildSymbolIndex() error = %v", err)
+	}
+
+	// Fake is inside a string: no symbol
+	var got []string
+	for _, s := range index.Symbols {
+		got = append(got, s.Kind+" "+s.Scope)
+	}
+	want := []string{"class A", "class A.B", "method A.B.n", "method A.o"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("symbols = %v, want %v", got, want)
+	}
+}
+
+func TestLocateName_WholeIdentifier(t *testing.T) {
+	lines := []string{"@cached", "def run_all(run):", "    pass"}
+	if line, col := locateName(lines, "run", 1); line != 2 || col != 13 {
+		t.Errorf("locateName(run) = %d:%d, want 2:13", line, col)
+	}
+	if line, col := locateName(lines, "missing", 1); line != 1 || col != 0 {
+		t.Errorf("locateName(missing) = %d:%d, want 1:0", line, col)
+	}
+}
@@ -0,0 +1,77 @@
+// template_formatter.go - User-defined output lines via text/template (--format)
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// TemplateRecord is the data a --format template is executed with, once per function
+type TemplateRecord struct {
+	Path      string
+	Name      string
+	Start     int
+	End       int
+	ClassName string
+	Signature string // Declaration up to the body, from the function's source lines
+}
+
+// ParseOutputTemplate parses a --format template and checks that it only
+// references TemplateRecord fields, so typos fail at startup rather than
+// after a long scan.
+func ParseOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("format").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, TemplateRecord{}); err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// FormatWithTemplate renders one template line per function of result
+func FormatWithTemplate(tmpl *template.Template, result *FindResult) (string, error) {
+	var lines []string
+	for _, fn := range result.Functions {
+		line, err := executeRecord(tmpl, result.Filename, fn)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// FormatDirResultsTemplate renders one template line per function across all files
+func FormatDirResultsTemplate(tmpl *template.Template, results []DirResult) (string, error) {
+	var lines []string
+	for _, r := range results {
+		for _, fn := range r.Functions {
+			line, err := executeRecord(tmpl, r.Path, fn)
+			if err != nil {
+				return "", err
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func executeRecord(tmpl *template.Template, path string, fn FunctionBounds) (string, error) {
+	var sb strings.Builder
+	record := TemplateRecord{
+		Path:      path,
+		Name:      fn.Name,
+		Start:     fn.Start,
+		End:       fn.End,
+		ClassName: fn.ClassName,
+		Signature: strings.TrimSpace(extractSignatureFromLines(fn.Lines)),
+	}
+	if err := tmpl.Execute(&sb, record); err != nil {
+		return "", fmt.Errorf("executing --format template for %s: %w", fn.Name, err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatWithTemplate(t *testing.T) {
+	result := &FindResult{
+		Filename: "shapes.java",
+		Functions: []FunctionBounds{
+			{Name: "area", Start: 2, End: 4, ClassName: "Circle", Lines: []string{"    public double area() {", "        return r * r;", "    }"}},
+			{Name: "main", Start: 10, End: 12},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "path and start",
+			template: "{{.Path}}:{{.Start}} {{.Name}}",
+			expected: "shapes.java:2 area\nshapes.java:10 main",
+		},
+		{
+			name:     "class name with fallback",
+			template: `{{if .ClassName}}{{.ClassName}}.{{end}}{{.Name}} {{.Start}}-{{.End}}`,
+			expected: "Circle.area 2-4\nmain 10-12",
+		},
+		{
+			name:     "signature",
+			template: "{{.Name}}|{{.Signature}}",
+			expected: "area|public double area()\nmain|",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseOutputTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseOutputTemplate() error = %v", err)
+			}
+			got, err := FormatWithTemplate(tmpl, result)
+			if err != nil {
+				t.Fatalf("FormatWithTemplate() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseOutputTemplate_Errors(t *testing.T) {
+	for _, text := range []string{"{{.Name", "{{.Nmae}}"} {
+		_, err := ParseOutputTemplate(text)
+		if err == nil {
+			t.Errorf("ParseOutputTemplate(%q) expected error", text)
+			continue
+		}
+		if !strings.Contains(err.Error(), "invalid --format template") {
+			t.Errorf("error %q lacks context", err)
+		}
+	}
+}
+
+func TestFormatDirResultsTemplate(t *testing.T) {
+	tmpl, err := ParseOutputTemplate("{{.Path}}:{{.Start}} {{.Name}}")
+	if err != nil {
+		t.Fatalf("ParseOutputTemplate() error = %v", err)
+	}
+	results := []DirResult{
+		{Path: "a.go", Functions: []FunctionBounds{{Name: "A", Start: 3}}},
+		{Path: "b.go", Functions: []FunctionBounds{{Name: "B", Start: 7}}},
+	}
+	got, err := FormatDirResultsTemplate(tmpl, results)
+	if err != nil {
+		t.Fatalf("FormatDirResultsTemplate() error = %v", err)
+	}
+	if got != "a.go:3 A\nb.go:7 B" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFormatDirResultsTemplate_SignatureFromDirScan(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := "package p\n\nfunc Foo(x int) error {\n\treturn nil\n}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "foo.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tmpl, err := ParseOutputTemplate("{{.Name}}|{{.Signature}}")
+	if err != nil {
+		t.Fatalf("ParseOutputTemplate() error = %v", err)
+	}
+
+	dp := NewDirProcessor(config, 1, false, false, "functions")
+	dp.SetSignatures(true)
+	results, err := dp.ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+	got, err := FormatDirResultsTemplate(tmpl, results)
+	if err != nil {
+		t.Fatalf("FormatDirResultsTemplate() error = %v", err)
+	}
+	if got != "Foo|Foo(x int) error" {
+		t.Errorf("got %q, want %q", got, "Foo|Foo(x int) error")
+	}
+}
@@ -0,0 +1,40 @@
+// testfiles.go - Test file detection (--exclude-tests, --tests-only)
+package internal
+
+import "path/filepath"
+
+// TestFileMode selects which files a directory scan keeps by the language's
+// test_file_patterns
+type TestFileMode int
+
+const (
+	// TestFilesAll keeps every file (the default)
+	TestFilesAll TestFileMode = iota
+	// TestFilesExclude drops test files (--exclude-tests)
+	TestFilesExclude
+	// TestFilesOnly keeps only test files (--tests-only)
+	TestFilesOnly
+)
+
+// IsTestFile reports whether the base name of path matches one of the
+// language's test_file_patterns ("*_test.go", "test_*.py", "*.spec.ts")
+func (lc *LanguageConfig) IsTestFile(path string) bool {
+	base := filepath.Base(path)
+	for _, pattern := range lc.TestFilePatterns {
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Keeps reports whether a file of language lc passes the mode
+func (m TestFileMode) Keeps(lc *LanguageConfig, path string) bool {
+	switch m {
+	case TestFilesExclude:
+		return !lc.IsTestFile(path)
+	case TestFilesOnly:
+		return lc.IsTestFile(path)
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -13,6 +14,7 @@ const (
 	NodeTypeFunction TreeNodeType = iota
 	NodeTypeClass
 	NodeTypeRoot
+	NodeTypeField
 )
 
 // TreeNode представляет узел в дереве функций
@@ -25,6 +27,7 @@ type TreeNode struct {
 	Depth    int
 	IsLast   bool
 	Lines    []string
+	TypeName string // Тип поля (для NodeTypeField)
 }
 
 // BuildTree строит дерево функций и классов
@@ -33,7 +36,7 @@ func BuildTree(result *FindResult) []*TreeNode {
 
 	// Если есть классы, строим дерево с классами
 	if len(result.Classes) > 0 {
-		rootNodes = buildClassTree(result)
+		rootNodes = buildClassTree(result, nil)
 	} else {
 		// Иначе просто показываем функции
 		rootNodes = buildFunctionTree(result.Functions)
@@ -45,8 +48,54 @@ func BuildTree(result *FindResult) []*TreeNode {
 	return rootNodes
 }
 
-// buildClassTree строит дерево с классами как родительскими узлами
-func buildClassTree(result *FindResult) []*TreeNode {
+// BuildUnifiedTree строит одно дерево для режима --all: классы и типы
+// (без дублей) сверху, под каждым - его поля и методы (по ClassName)
+// в порядке строк, затем функции верхнего уровня
+func BuildUnifiedTree(funcResult *FindResult, structResult *StructFindResult) []*TreeNode {
+	merged := MergeStructClasses(funcResult, structResult)
+	sort.SliceStable(merged.Classes, func(i, j int) bool { return merged.Classes[i].Start < merged.Classes[j].Start })
+
+	fields := make(map[string][]FieldBounds)
+	if structResult != nil {
+		for _, t := range structResult.Types {
+			key := fmt.Sprintf("%s:%d", t.Name, t.Start)
+			fields[key] = append(fields[key], t.Fields...)
+		}
+	}
+
+	var rootNodes []*TreeNode
+	if len(merged.Classes) > 0 {
+		rootNodes = buildClassTree(merged, fields)
+	} else {
+		rootNodes = buildFunctionTree(merged.Functions)
+	}
+	setLastFlags(rootNodes)
+	return rootNodes
+}
+
+// MergeStructClasses добавляет типы из struct finder к классам FindResult
+// (без дублей по имени и строке начала)
+func MergeStructClasses(funcResult *FindResult, structResult *StructFindResult) *FindResult {
+	merged := *funcResult
+	merged.Classes = append([]ClassBounds{}, funcResult.Classes...)
+	if structResult == nil {
+		return &merged
+	}
+	seen := make(map[string]bool, len(merged.Classes))
+	for _, c := range merged.Classes {
+		seen[fmt.Sprintf("%s:%d", c.Name, c.Start)] = true
+	}
+	for _, t := range structResult.Types {
+		if !seen[fmt.Sprintf("%s:%d", t.Name, t.Start)] {
+			merged.Classes = append(merged.Classes, ClassBounds{Name: t.Name, Start: t.Start, End: t.End})
+		}
+	}
+	return &merged
+}
+
+// buildClassTree строит дерево с классами как родительскими узлами.
+// fields (ключ "Name:Start") добавляет поля типов рядом с методами.
+func buildClassTree(result *FindResult, fields map[string][]FieldBounds) []*TreeNode {
 	var rootNodes []*TreeNode
 
 	// Сначала создаем узлы классов
@@ -72,6 +121,26 @@ func buildClassTree(result *FindResult) []*TreeNode {
 			classNode.Children = buildFunctionTree(methods)
 		}
 
+		// Поля - рядом с методами, всё по номеру строки
+		if classFields := fields[fmt.Sprintf("%s:%d", class.Name, class.Start)]; len(classFields) > 0 {
+			for _, field := range classFields {
+				if insideFunction(field.Line, methods) {
+					continue // локальная переменная метода, а не поле
+				}
+				classNode.Children = append(classNode.Children, &TreeNode{
+					Name:     field.Name,
+					Type:     NodeTypeField,
+					Start:    field.Line,
+					End:      field.Line,
+					Children: []*TreeNode{},
+					TypeName: field.Type,
+				})
+			}
+			sort.SliceStable(classNode.Children, func(i, j int) bool {
+				return classNode.Children[i].Start < classNode.Children[j].Start
+			})
+		}
+
 		rootNodes = append(rootNodes, classNode)
 	}
 
@@ -90,6 +159,16 @@ func buildClassTree(result *FindResult) []*TreeNode {
 	return rootNodes
 }
 
+// insideFunction сообщает, попадает ли строка в тело одной из функций
+func insideFunction(line int, functions []FunctionBounds) bool {
+	for _, fn := range functions {
+		if line > fn.Start && line <= fn.End {
+			return true
+		}
+	}
+	return false
+}
+
 // buildFunctionTree строит дерево из списка функций
 func buildFunctionTree(functions []FunctionBounds) []*TreeNode {
 	if len(functions) == 0 {
@@ -177,7 +256,16 @@ func setLastFlags(nodes []*TreeNode) {
 
 // FormatTree форматирует результат в древовидном формате
 func FormatTree(result *FindResult, showTypes bool) string {
-	treeNodes := BuildTree(result)
+	return formatTreeNodes(BuildTree(result), showTypes)
+}
+
+// FormatUnifiedTree форматирует объединённое дерево --all --unified-tree
+func FormatUnifiedTree(funcResult *FindResult, structResult *StructFindResult, showTypes bool) string {
+	return formatTreeNodes(BuildUnifiedTree(funcResult, structResult), showTypes)
+}
+
+// formatTreeNodes форматирует готовое дерево
+func formatTreeNodes(treeNodes []*TreeNode, showTypes bool) string {
 	if len(treeNodes) == 0 {
 		return ""
 	}
@@ -243,6 +331,8 @@ func formatNode(node *TreeNode, showTypes bool, ancestorLast []bool) string {
 		if node.Depth > 0 {
 			prefix = "method "
 		}
+	case NodeTypeField:
+		prefix = "field "
 	}
 
 	// Форматируем строку функции/класса
@@ -255,6 +345,12 @@ func formatNode(node *TreeNode, showTypes bool, ancestorLast []bool) string {
 
 // formatFunctionLine форматирует строку с информацией о функции или классе
 func formatFunctionLine(node *TreeNode, showTypes bool) string {
+	if node.Type == NodeTypeField {
+		if node.TypeName != "" {
+			return fmt.Sprintf("%s %s (%d)", node.Name, node.TypeName, node.Start)
+		}
+		return fmt.Sprintf("%s (%d)", node.Name, node.Start)
+	}
 	if showTypes && node.Type == NodeTypeFunction {
 		signature := extractSignatureFromLines(node.Lines)
 		if signature != "" {
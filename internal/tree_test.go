@@ -324,7 +324,7 @@ func TestBuildClassTree(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := buildClassTree(tt.result)
+			result := buildClassTree(tt.result, nil)
 			if len(result) != tt.expected {
 				t.Errorf("buildClassTree() returned %d nodes, want %d", len(result), tt.expected)
 			}
@@ -658,3 +658,59 @@ func TestFormatFunctionLine(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildUnifiedTree(t *testing.T) {
+	funcResult := &FindResult{
+		Classes: []ClassBounds{{Name: "Account", Start: 1, End: 20}},
+		Functions: []FunctionBounds{
+			{Name: "Withdraw", ClassName: "Account", Start: 12, End: 18},
+			{Name: "Deposit", ClassName: "Account", Start: 5, End: 10},
+			{Name: "main", Start: 22, End: 25},
+		},
+	}
+	structResult := &StructFindResult{
+		Types: []TypeBounds{{
+			Name: "Account", Kind: "class", Start: 1, End: 20,
+			Fields: []FieldBounds{
+				{Name: "balance", Type: "int", Line: 3},
+				{Name: "owner", Type: "String", Line: 11},
+				{Name: "tmp", Type: "int", Line: 14}, // local inside Withdraw
+			},
+		}},
+	}
+
+	nodes := BuildUnifiedTree(funcResult, structResult)
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 root nodes (class + main), got %d", len(nodes))
+	}
+	if nodes[0].Type != NodeTypeClass || nodes[0].Name != "Account" {
+		t.Fatalf("First root should be class Account, got %+v", nodes[0])
+	}
+
+	want := []struct {
+		name string
+		typ  TreeNodeType
+	}{
+		{"balance", NodeTypeField},
+		{"Deposit", NodeTypeFunction},
+		{"owner", NodeTypeField},
+		{"Withdraw", NodeTypeFunction},
+	}
+	children := nodes[0].Children
+	if len(children) != len(want) {
+		t.Fatalf("Expected %d children, got %d", len(want), len(children))
+	}
+	for i, w := range want {
+		if children[i].Name != w.name || children[i].Type != w.typ {
+			t.Errorf("child %d = %s (type %d), want %s (type %d)", i, children[i].Name, children[i].Type, w.name, w.typ)
+		}
+	}
+	if !children[len(children)-1].IsLast {
+		t.Error("last child should have IsLast set")
+	}
+
+	out := FormatUnifiedTree(funcResult, structResult, false)
+	if !strings.Contains(out, "├── field balance int (3)") || !strings.Contains(out, "└── method Withdraw (12-18)") {
+		t.Errorf("unexpected unified tree output:\n%s", out)
+	}
+}
@@ -0,0 +1,73 @@
+// visibility.go - Public/private symbol counts (--visibility-summary)
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// VisibilityCount is the number of exported and unexported symbols of one kind
+type VisibilityCount struct {
+	Public  int `json:"public"`
+	Private int `json:"private"`
+}
+
+// VisibilitySummary counts functions and classes/types by ExportChecker
+// verdict. Languages without an export_rule report everything as public.
+type VisibilitySummary struct {
+	Functions VisibilityCount `json:"functions"`
+	Types     VisibilityCount `json:"types"`
+}
+
+// CountVisibility classifies every function and class with checker
+func CountVisibility(functions []FunctionBounds, classes []ClassBounds, checker *ExportChecker) VisibilitySummary {
+	var v VisibilitySummary
+	for _, fn := range functions {
+		v.Functions.add(checker.IsExported(fn.Name, fn.Start))
+	}
+	for _, cls := range classes {
+		v.Types.add(checker.IsExported(cls.Name, cls.Start))
+	}
+	return v
+}
+
+func (c *VisibilityCount) add(exported bool) {
+	if exported {
+		c.Public++
+	} else {
+		c.Private++
+	}
+}
+
+// Add accumulates o into v
+func (v *VisibilitySummary) Add(o VisibilitySummary) {
+	v.Functions.Public += o.Functions.Public
+	v.Functions.Private += o.Functions.Private
+	v.Types.Public += o.Types.Public
+	v.Types.Private += o.Types.Private
+}
+
+// SummarizeVisibility totals the per-file counts of DirResult.Visibility
+func SummarizeVisibility(results []DirResult) VisibilitySummary {
+	var total VisibilitySummary
+	for _, r := range results {
+		if r.Visibility != nil {
+			total.Add(*r.Visibility)
+		}
+	}
+	return total
+}
+
+// FormatVisibilitySummary writes "functions: N public / M private" and the
+// same line for types, or the summary as JSON
+func FormatVisibilitySummary(v VisibilitySummary, jsonOut bool) string {
+	if jsonOut {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return "{}"
+		}
+		return string(b)
+	}
+	return fmt.Sprintf("functions: %d public / %d private\ntypes: %d public / %d private",
+		v.Functions.Public, v.Functions.Private, v.Types.Public, v.Types.Private)
+}
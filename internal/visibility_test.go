@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const visibilitySample = `package store
+
+type Store struct{}
+
+type entry struct{}
+
+func New() *Store {
+	return &Store{}
+}
+
+func (s *Store) Get(key string) string {
+	return s.lookup(key)
+}
+
+func (s *Store) lookup(key string) string {
+	return ""
+}
+
+func helper() {}
+`
+
+func TestCountVisibility_GoMix(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "store.go")
+	mustWrite(t, path, visibilitySample)
+
+	result, err := NewFinder(config["go"], nil, true, false, false).FindFunctions(path)
+	if err != nil {
+		t.Fatalf("FindFunctions() error = %v", err)
+	}
+	checker := NewExportChecker(config["go"], strings.Split(visibilitySample, "\n"))
+	got := CountVisibility(result.Functions, result.Classes, checker)
+
+	want := VisibilitySummary{
+		Functions: VisibilityCount{Public: 2, Private: 2},
+		Types:     VisibilityCount{Public: 1, Private: 1},
+	}
+	if got != want {
+		t.Errorf("CountVisibility() = %+v, want %+v", got, want)
+	}
+	if text := FormatVisibilitySummary(got, false); text != "functions: 2 public / 2 private\ntypes: 1 public / 1 private" {
+		t.Errorf("FormatVisibilitySummary() = %q", text)
+	}
+}
+
+func TestProcessDirectory_Visibility(t *testing.T) {
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tmpDir := t.TempDir()
+	mustWrite(t, filepath.Join(tmpDir, "store.go"), visibilitySample)
+	mustWrite(t, filepath.Join(tmpDir, "util.go"), "package store\n\nfunc Join() {}\n")
+
+	dp := NewDirProcessor(config, 2, true, false, "functions")
+	dp.SetVisibility(true)
+	results, err := dp.ProcessDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("ProcessDirectory() error = %v", err)
+	}
+
+	got := SummarizeVisibility(results)
+	want := VisibilitySummary{
+		Functions: VisibilityCount{Public: 3, Private: 2},
+		Types:     VisibilityCount{Public: 1, Private: 1},
+	}
+	if got != want {
+		t.Errorf("SummarizeVisibility() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+import std/[os, strutils]
+
+type
+  Point* = object
+    x*, y*: float
+    label: string = "origin"
+
+  Color = enum
+    red, green
+
+  Shape = ref object of RootObj
+    name*: string
+
+proc area*(s: Shape): float =
+  ## Doc comment
+  let text = """
+not code
+"""
+  result = 0.0
+
+func double(x: int): int = x * 2
+
+method draw(s: Shape) {.base.} =
+  echo &"shape {s.name}"
+  for i in 0..3:
+    echo i
+
+iterator items(p: Point): float =
+  yield p.x
+  yield p.y
+
+template `+=`(a, b: untyped) =
+  a = a + b
+
+proc forward(x: int): int